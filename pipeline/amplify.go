@@ -0,0 +1,272 @@
+package main
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+
+	"golang.org/x/exp/maps"
+)
+
+type AmplifyFunction struct {
+	Name           string
+	Runtime        string
+	Handler        string
+	MemoryMB       int
+	TimeoutSeconds int
+	InvocationType FaaSInvocationType
+	Attributes     map[string]string
+	Path           string
+	Line           int
+}
+
+type amplifyTrigger struct {
+	invocationType FaaSInvocationType
+	attribute      string
+	value          string
+}
+
+// NOTE: the first trigger determines the invocation type like for other functions handling several triggers
+func (function *AmplifyFunction) addTriggers(triggers []amplifyTrigger) {
+	for _, trigger := range triggers {
+		if function.InvocationType == FaaSInvocationTypeUnknown {
+			function.InvocationType = trigger.invocationType
+		}
+		if trigger.value == "" {
+			continue
+		}
+		if function.Attributes == nil {
+			function.Attributes = make(map[string]string)
+		}
+		if function.Attributes[trigger.attribute] != "" {
+			function.Attributes[trigger.attribute] += ","
+		}
+		function.Attributes[trigger.attribute] += trigger.value
+	}
+}
+
+// NOTE: the categories of backend-config.json whose resources invoke the functions they depend on, e.g. an API
+// Gateway REST API of the `api` category or the Cognito triggers of the `auth` category
+var amplifyDependentInvocationTypes = map[string]FaaSInvocationType{
+	"API Gateway": FaaSInvocationTypeHTTP,
+	"Cognito":     FaaSInvocationTypeOther,
+	"S3":          FaaSInvocationTypeOther,
+}
+
+var amplifyGraphQLFunctionDirectiveRegexp = regexp.MustCompile(`@function\s*\(\s*name\s*:\s*"([\w-]+?)(?:-\$\{env\})?"`)
+
+// NOTE: Amplify Gen 1 projects list their functions in amplify/backend/backend-config.json, each function has its
+// CloudFormation template and parameters in amplify/backend/function/<name>, e.g.
+// `"function": { "hello": { "service": "Lambda", "providerPlugin": "awscloudformation" } }`
+func LoadAmplifyBackendFunctions(backendConfigFile TextFile, files []TextFile) []AmplifyFunction {
+	functions := make([]AmplifyFunction, 0)
+
+	backendConfig, err := LoadJsonFromBytes([]byte(backendConfigFile.Content))
+	if err != nil {
+		fmt.Printf("error loading amplify backend config: %v\n", err)
+		return functions
+	}
+	backendFunctions, err := JsonResolveMap(backendConfig, []string{"function"})
+	if err != nil {
+		return functions
+	}
+	backendDirectory := path.Dir(backendConfigFile.Path)
+
+	filesByPath := make(map[string]TextFile, len(files))
+	for _, file := range files {
+		filesByPath[file.Path] = file
+	}
+
+	// NOTE: AppSync resolvers refer to functions by their name in every environment, e.g. `@function(name: "hello-${env}")`
+	graphQLFunctions := make([]string, 0)
+	for _, file := range files {
+		if !strings.HasPrefix(file.Path, path.Join(backendDirectory, "api")+"/") || path.Ext(file.Path) != ".graphql" {
+			continue
+		}
+		for _, match := range amplifyGraphQLFunctionDirectiveRegexp.FindAllStringSubmatch(file.Content, -1) {
+			graphQLFunctions = append(graphQLFunctions, match[1])
+		}
+	}
+
+	names := maps.Keys(backendFunctions)
+	slices.Sort(names)
+
+	for _, name := range names {
+		if service, _ := JsonResolveString(backendFunctions[name], []string{"service"}); service != "Lambda" {
+			continue
+		}
+
+		function := AmplifyFunction{
+			Name:           name,
+			InvocationType: FaaSInvocationTypeUnknown,
+			MemoryMB:       -1,
+			TimeoutSeconds: -1,
+			Path:           backendConfigFile.Path,
+			Line:           YamlKeyLine([]byte(backendConfigFile.Content), []string{"function", name}),
+		}
+		functionDirectory := path.Join(backendDirectory, "function", name)
+		triggers := make([]amplifyTrigger, 0)
+
+		for _, category := range []string{"api", "auth", "storage"} {
+			resources, err := JsonResolveMap(backendConfig, []string{category})
+			if err != nil {
+				continue
+			}
+			resourceNames := maps.Keys(resources)
+			slices.Sort(resourceNames)
+			for _, resourceName := range resourceNames {
+				service, _ := JsonResolveString(resources[resourceName], []string{"service"})
+				invocationType, ok := amplifyDependentInvocationTypes[service]
+				if !ok {
+					continue
+				}
+				dependencies, _ := JsonResolveArray(resources[resourceName], []string{"dependsOn"})
+				for _, dependency := range dependencies {
+					dependencyCategory, _ := JsonResolveString(dependency, []string{"category"})
+					dependencyName, _ := JsonResolveString(dependency, []string{"resourceName"})
+					if dependencyCategory == "function" && dependencyName == name {
+						triggers = append(triggers, amplifyTrigger{invocationType, category, resourceName})
+					}
+				}
+			}
+		}
+
+		if slices.Contains(graphQLFunctions, name) {
+			triggers = append(triggers, amplifyTrigger{FaaSInvocationTypeGraphQL, "api", ""})
+		}
+
+		// NOTE: scheduled functions keep their expression in the `CloudWatchRule` parameter, which is "NONE" otherwise
+		if parametersFile, ok := filesByPath[path.Join(functionDirectory, "parameters.json")]; ok {
+			if parameters, err := LoadJsonFromBytes([]byte(parametersFile.Content)); err == nil {
+				if schedule, err := JsonResolveString(parameters, []string{"CloudWatchRule"}); err == nil && schedule != "NONE" {
+					triggers = append(triggers, amplifyTrigger{FaaSInvocationTypeSchedule, "schedule", schedule})
+				}
+			}
+		}
+
+		if templateFile, ok := filesByPath[path.Join(functionDirectory, name+"-cloudformation-template.json")]; ok {
+			if template, err := LoadJsonFromBytes([]byte(templateFile.Content)); err == nil {
+				function.Runtime, _ = JsonResolveString(template, []string{"Resources", "LambdaFunction", "Properties", "Runtime"})
+				function.Handler, _ = JsonResolveString(template, []string{"Resources", "LambdaFunction", "Properties", "Handler"})
+				if memorySize, err := JsonResolveInt(template, []string{"Resources", "LambdaFunction", "Properties", "MemorySize"}); err == nil {
+					function.MemoryMB = memorySize
+				}
+				if timeout, err := JsonResolveInt(template, []string{"Resources", "LambdaFunction", "Properties", "Timeout"}); err == nil {
+					function.TimeoutSeconds = timeout
+				}
+
+				// NOTE: DynamoDB stream and Kinesis triggers are added to the template as event source mappings
+				resources, _ := JsonResolveMap(template, []string{"Resources"})
+				for _, resource := range resources {
+					if resourceType, _ := JsonResolveString(resource, []string{"Type"}); resourceType == "AWS::Lambda::EventSourceMapping" {
+						triggers = append(triggers, amplifyTrigger{FaaSInvocationTypeOther, "eventSources", ""})
+						break
+					}
+				}
+			}
+		}
+
+		function.addTriggers(triggers)
+		functions = append(functions, function)
+	}
+
+	return functions
+}
+
+// NOTE: Amplify Gen 2 defines functions in TypeScript with `defineFunction` of @aws-amplify/backend, e.g.
+// `export const hello = defineFunction({ name: "hello", entry: "./handler.ts", schedule: "every 1h" })`, the name
+// defaults to the directory of the definition and the entry to handler.ts next to it
+func ParseAmplifyFunctionDefinitions(module *JsModule) []AmplifyFunction {
+	functions := make([]AmplifyFunction, 0)
+
+	for _, call := range module.Calls {
+		if !module.CallsImport(call, "@aws-amplify/backend", "defineFunction") {
+			continue
+		}
+
+		function := AmplifyFunction{
+			Name:           path.Base(path.Dir(module.Path)),
+			Handler:        path.Join(path.Dir(module.Path), "handler.ts"),
+			InvocationType: FaaSInvocationTypeUnknown,
+			MemoryMB:       -1,
+			TimeoutSeconds: -1,
+			Path:           module.Path,
+			Line:           call.Line,
+		}
+
+		if options := call.Argument(0); options != nil && options.Kind == JsValueObject {
+			if name, ok := options.StringProperty("name"); ok {
+				function.Name = name
+			}
+			if entry, ok := options.StringProperty("entry"); ok {
+				function.Handler = path.Join(path.Dir(module.Path), entry)
+			}
+			if timeout := options.Property("timeoutSeconds"); timeout != nil && timeout.Kind == JsValueNumber {
+				if timeoutSeconds, ok := ParseDurationSeconds(timeout.Text); ok {
+					function.TimeoutSeconds = timeoutSeconds
+				}
+			}
+			if memory := options.Property("memoryMB"); memory != nil && memory.Kind == JsValueNumber {
+				if memoryMB, ok := ParseMemorySizeMB(memory.Text); ok {
+					function.MemoryMB = memoryMB
+				}
+			}
+			if runtime := options.Property("runtime"); runtime != nil && runtime.Kind == JsValueNumber {
+				function.Runtime = fmt.Sprintf("nodejs%s.x", runtime.Text)
+			}
+			if schedule := options.Property("schedule"); schedule != nil {
+				function.addTriggers([]amplifyTrigger{{FaaSInvocationTypeSchedule, "schedule", strings.Join(jsStrings(*schedule), ",")}})
+			}
+		}
+
+		functions = append(functions, function)
+	}
+
+	return functions
+}
+
+// NOTE: the variable a definition is assigned to, which other resources use to refer to the function
+func amplifyFunctionVariable(module *JsModule, function AmplifyFunction) string {
+	for _, declaration := range module.Declarations {
+		if declaration.Value.Kind == JsValueCall && declaration.Value.Call.Line == function.Line {
+			return declaration.Name
+		}
+	}
+	return ""
+}
+
+// NOTE: functions are resolvers of the data schema, e.g. `a.handler.function(hello)`, or triggers of auth and storage,
+// e.g. `defineAuth({ triggers: { preSignUp: hello } })` or `defineStorage({ triggers: { onUpload: hello } })`
+func amplifyFunctionReferences(modules []*JsModule) map[string][]amplifyTrigger {
+	references := make(map[string][]amplifyTrigger)
+
+	for _, module := range modules {
+		if !module.ImportsModule("@aws-amplify/backend") {
+			continue
+		}
+		for _, call := range module.Calls {
+			switch {
+			case call.CalleeEndsWith("handler", "function"):
+				if argument := call.Argument(0); argument != nil && argument.Kind == JsValueIdentifier {
+					references[argument.Text] = append(references[argument.Text], amplifyTrigger{FaaSInvocationTypeGraphQL, "data", ""})
+				}
+			case module.CallsImport(call, "@aws-amplify/backend", "defineAuth") || module.CallsImport(call, "@aws-amplify/backend", "defineStorage"):
+				options := call.Argument(0)
+				if options == nil || options.Property("triggers") == nil {
+					continue
+				}
+				for _, trigger := range options.Property("triggers").Properties {
+					variable := trigger.Key
+					if trigger.Value.Kind == JsValueIdentifier {
+						variable = trigger.Value.Text
+					}
+					references[variable] = append(references[variable], amplifyTrigger{FaaSInvocationTypeOther, "triggers", trigger.Key})
+				}
+			}
+		}
+	}
+
+	return references
+}
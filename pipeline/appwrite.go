@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+type AppwriteFunction struct {
+	Id             string
+	Name           string
+	Runtime        string
+	Entrypoint     string
+	Schedule       string
+	Events         []string
+	TimeoutSeconds int
+	Line           int
+}
+
+// NOTE: the `functions` of appwrite.json created by the Appwrite CLI, e.g.
+// `{ "$id": "hello", "name": "Hello", "runtime": "node-18.0", "path": "functions/hello", "entrypoint": "src/main.js" }`
+func LoadAppwriteFunctions(file TextFile) []AppwriteFunction {
+	functions := make([]AppwriteFunction, 0)
+
+	appwriteConfig, err := LoadJsonFromJsoncBytes([]byte(file.Content))
+	if err != nil {
+		fmt.Printf("error loading appwrite config: %v\n", err)
+		return functions
+	}
+	appwriteFunctions, err := JsonResolveArray(appwriteConfig, []string{"functions"})
+	if err != nil {
+		return functions
+	}
+
+	for _, appwriteFunction := range appwriteFunctions {
+		function := AppwriteFunction{
+			Events:         jsonStrings(appwriteFunction, []string{"events"}),
+			TimeoutSeconds: -1,
+			Line:           -1,
+		}
+		function.Id, _ = JsonResolveString(appwriteFunction, []string{"$id"})
+		function.Name, _ = JsonResolveString(appwriteFunction, []string{"name"})
+		function.Runtime, _ = JsonResolveString(appwriteFunction, []string{"runtime"})
+		function.Schedule, _ = JsonResolveString(appwriteFunction, []string{"schedule"})
+		if timeout, err := JsonResolveInt(appwriteFunction, []string{"timeout"}); err == nil {
+			function.TimeoutSeconds = timeout
+		}
+
+		functionDirectory, _ := JsonResolveString(appwriteFunction, []string{"path"})
+		if entrypoint, err := JsonResolveString(appwriteFunction, []string{"entrypoint"}); err == nil {
+			function.Entrypoint = path.Join(path.Dir(file.Path), functionDirectory, entrypoint)
+		}
+
+		if function.Id != "" {
+			if index := strings.Index(file.Content, fmt.Sprintf("%q", function.Id)); index != -1 {
+				function.Line = strings.Count(file.Content[:index], "\n") + 1
+			}
+		}
+
+		functions = append(functions, function)
+	}
+
+	return functions
+}
+
+// NOTE: functions run on their CRON schedule, on platform events like `users.*.create` and on executions through the
+// API or their domain otherwise
+func (function AppwriteFunction) Trigger() (FaaSInvocationType, map[string]string) {
+	switch {
+	case function.Schedule != "":
+		return FaaSInvocationTypeSchedule, map[string]string{"schedule": function.Schedule}
+	case len(function.Events) > 0:
+		return FaaSInvocationTypeOther, map[string]string{"events": strings.Join(function.Events, ",")}
+	default:
+		return FaaSInvocationTypeHTTP, nil
+	}
+}
@@ -0,0 +1,267 @@
+package main
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+)
+
+type ArchiveLimits struct {
+	MaxArchiveSizeBytes   int64
+	MaxFiles              int
+	MaxExtractedSizeBytes int64
+	MaxCompressionRatio   uint64
+	// NOTE: small files such as lockfiles compress extremely well, the ratio is only checked above this size
+	MinCompressionRatioCheckBytes uint64
+}
+
+type SkippedArchiveEntry struct {
+	Name   string
+	Reason string
+}
+
+// NOTE: a nil filter extracts the whole archive
+type ArchiveEntryFilter struct {
+	IncludePatterns    []string
+	ExcludeDirectories []string
+	MaxFileSizeBytes   int64
+	SkipBinaryFiles    bool
+}
+
+type ArchiveExtraction struct {
+	SkippedEntries     []SkippedArchiveEntry
+	NumExtractedFiles  int
+	NumFilteredEntries int
+	ExtractedSizeBytes int64
+}
+
+const binarySniffSizeBytes = 8000
+
+func (filter *ArchiveEntryFilter) Matches(relativePath string, sizeBytes uint64) bool {
+	if filter == nil {
+		return true
+	}
+
+	slashPath := filepath.ToSlash(relativePath)
+	// NOTE: same substring semantics as LoadTextFiles, the leading slash lets entries like "/dist/" match top-level directories
+	if checkForKeywords("/"+slashPath, filter.ExcludeDirectories) > 0 {
+		return false
+	}
+	if filter.MaxFileSizeBytes > 0 && sizeBytes > uint64(filter.MaxFileSizeBytes) {
+		return false
+	}
+
+	for _, includePattern := range filter.IncludePatterns {
+		if matches, err := doublestar.Match(includePattern, slashPath); err == nil && matches {
+			return true
+		}
+	}
+	return false
+}
+
+func isBinaryContent(content []byte) bool {
+	return bytes.IndexByte(content, 0) != -1
+}
+
+func archiveEntryPath(name string) (string, bool) {
+	if strings.HasPrefix(name, "/") || strings.HasPrefix(name, "\\") {
+		return "", false
+	}
+
+	// NOTE: GitHub archives wrap all files in a single top-level directory named after the repository and commit
+	nameParts := strings.SplitN(name, "/", 2)
+	if len(nameParts) != 2 {
+		return "", false
+	}
+
+	relativePath := nameParts[1]
+	if relativePath == "" {
+		return "", true
+	}
+	if strings.Contains(relativePath, "\\") || strings.HasPrefix(relativePath, "/") {
+		return "", false
+	}
+
+	relativePath = filepath.FromSlash(relativePath)
+	if !filepath.IsLocal(relativePath) {
+		return "", false
+	}
+
+	return filepath.Clean(relativePath), true
+}
+
+func Unzip(
+	archivePath string,
+	dest string,
+	limits ArchiveLimits,
+	filter *ArchiveEntryFilter,
+) (ArchiveExtraction, error) {
+	var result ArchiveExtraction
+
+	r, err := zip.OpenReader(archivePath)
+	if err != nil {
+		return result, err
+	}
+	defer func() {
+		if err := r.Close(); err != nil {
+			panic(err)
+		}
+	}()
+
+	if limits.MaxFiles > 0 && len(r.File) > limits.MaxFiles {
+		return result, fmt.Errorf("archive contains %d files, exceeding the limit of %d", len(r.File), limits.MaxFiles)
+	}
+
+	if limits.MaxExtractedSizeBytes > 0 {
+		var declaredSize uint64
+		for _, f := range r.File {
+			declaredSize += f.UncompressedSize64
+		}
+		if declaredSize > uint64(limits.MaxExtractedSizeBytes) {
+			return result, fmt.Errorf("archive extracts to %d bytes, exceeding the limit of %d bytes", declaredSize, limits.MaxExtractedSizeBytes)
+		}
+	}
+
+	dest, err = filepath.Abs(dest)
+	if err != nil {
+		return result, err
+	}
+	if err := os.MkdirAll(dest, 0755); err != nil {
+		return result, err
+	}
+
+	result.SkippedEntries = make([]SkippedArchiveEntry, 0)
+
+	// Closure to address file descriptors issue with all the deferred .Close() methods
+	extractAndWriteFile := func(f *zip.File, outPath string) (bool, error) {
+		rc, err := f.Open()
+		if err != nil {
+			return false, err
+		}
+		defer func() {
+			if err := rc.Close(); err != nil {
+				panic(err)
+			}
+		}()
+
+		src := bufio.NewReaderSize(rc, binarySniffSizeBytes)
+		if filter != nil && filter.SkipBinaryFiles {
+			head, err := src.Peek(binarySniffSizeBytes)
+			if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+				return false, err
+			}
+			if isBinaryContent(head) {
+				return false, nil
+			}
+		}
+
+		// NOTE: only the executable bit is kept, everything else in the archive's mode is untrusted
+		mode := os.FileMode(0644)
+		if f.Mode()&0111 != 0 {
+			mode = 0755
+		}
+
+		outFile, err := os.OpenFile(outPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
+		if err != nil {
+			return false, err
+		}
+		defer func() {
+			if err := outFile.Close(); err != nil {
+				panic(err)
+			}
+		}()
+
+		// NOTE: the declared sizes can be forged, so the limit is enforced on the bytes actually written
+		var limitedSrc io.Reader = src
+		if limits.MaxExtractedSizeBytes > 0 {
+			limitedSrc = io.LimitReader(src, limits.MaxExtractedSizeBytes-result.ExtractedSizeBytes+1)
+		}
+
+		written, err := io.Copy(outFile, limitedSrc)
+		result.ExtractedSizeBytes += written
+		if err != nil {
+			return false, err
+		}
+		if limits.MaxExtractedSizeBytes > 0 && result.ExtractedSizeBytes > limits.MaxExtractedSizeBytes {
+			return false, fmt.Errorf("archive extracts to more than the limit of %d bytes", limits.MaxExtractedSizeBytes)
+		}
+
+		return true, nil
+	}
+
+	for _, f := range r.File {
+		relativePath, ok := archiveEntryPath(f.Name)
+		if !ok {
+			result.SkippedEntries = append(result.SkippedEntries, SkippedArchiveEntry{Name: f.Name, Reason: "path escapes destination"})
+			continue
+		}
+		if relativePath == "" {
+			continue
+		}
+
+		outPath := filepath.Join(dest, relativePath)
+		if outPath != dest && !strings.HasPrefix(outPath, dest+string(os.PathSeparator)) {
+			result.SkippedEntries = append(result.SkippedEntries, SkippedArchiveEntry{Name: f.Name, Reason: "path escapes destination"})
+			continue
+		}
+
+		switch {
+		case f.Mode()&os.ModeSymlink != 0:
+			result.SkippedEntries = append(result.SkippedEntries, SkippedArchiveEntry{Name: f.Name, Reason: "symlink"})
+			continue
+		case f.FileInfo().IsDir():
+			// NOTE: directories are created on demand in sparse mode, so excluded ones don't show up empty
+			if filter != nil {
+				continue
+			}
+			if err := os.MkdirAll(outPath, 0755); err != nil {
+				result.SkippedEntries = append(result.SkippedEntries, SkippedArchiveEntry{Name: f.Name, Reason: "parent is not a directory"})
+			}
+			continue
+		case !f.Mode().IsRegular():
+			result.SkippedEntries = append(result.SkippedEntries, SkippedArchiveEntry{Name: f.Name, Reason: "not a regular file"})
+			continue
+		}
+
+		if !filter.Matches(relativePath, f.UncompressedSize64) {
+			result.NumFilteredEntries++
+			continue
+		}
+
+		if limits.MaxCompressionRatio > 0 && f.UncompressedSize64 > limits.MinCompressionRatioCheckBytes {
+			if f.CompressedSize64 == 0 || f.UncompressedSize64/f.CompressedSize64 > limits.MaxCompressionRatio {
+				result.SkippedEntries = append(result.SkippedEntries, SkippedArchiveEntry{Name: f.Name, Reason: "compression ratio"})
+				continue
+			}
+		}
+
+		// NOTE: an earlier entry may have placed a file where this entry's parent directories belong
+		if err := os.MkdirAll(filepath.Dir(outPath), 0755); err != nil {
+			result.SkippedEntries = append(result.SkippedEntries, SkippedArchiveEntry{Name: f.Name, Reason: "parent is not a directory"})
+			continue
+		}
+		if info, err := os.Lstat(outPath); err == nil && !info.Mode().IsRegular() {
+			result.SkippedEntries = append(result.SkippedEntries, SkippedArchiveEntry{Name: f.Name, Reason: "not a regular file"})
+			continue
+		}
+
+		extracted, err := extractAndWriteFile(f, outPath)
+		if err != nil {
+			return result, err
+		}
+		if extracted {
+			result.NumExtractedFiles++
+		} else {
+			result.NumFilteredEntries++
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type testArchiveEntry struct {
+	Name    string
+	Content string
+	Mode    os.FileMode
+}
+
+func writeTestArchive(t *testing.T, entries []testArchiveEntry) string {
+	t.Helper()
+
+	var buffer bytes.Buffer
+	writer := zip.NewWriter(&buffer)
+	for _, entry := range entries {
+		header := &zip.FileHeader{Name: entry.Name, Method: zip.Deflate}
+		mode := entry.Mode
+		if mode == 0 {
+			mode = 0644
+		}
+		header.SetMode(mode)
+		entryWriter, err := writer.CreateHeader(header)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := entryWriter.Write([]byte(entry.Content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	archivePath := filepath.Join(t.TempDir(), "archive.zip")
+	if err := os.WriteFile(archivePath, buffer.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return archivePath
+}
+
+func skippedArchiveEntryReason(extraction ArchiveExtraction, name string) (string, bool) {
+	for _, entry := range extraction.SkippedEntries {
+		if entry.Name == name {
+			return entry.Reason, true
+		}
+	}
+	return "", false
+}
+
+func TestUnzipSkipsMaliciousEntries(t *testing.T) {
+	tests := []struct {
+		name   string
+		entry  testArchiveEntry
+		reason string
+	}{
+		{"zip slip", testArchiveEntry{Name: "repo-abc/../../escaped.txt", Content: "escaped"}, "path escapes destination"},
+		{"nested zip slip", testArchiveEntry{Name: "repo-abc/src/../../../escaped.txt", Content: "escaped"}, "path escapes destination"},
+		{"absolute path", testArchiveEntry{Name: "/etc/escaped.txt", Content: "escaped"}, "path escapes destination"},
+		{"absolute path below wrapper", testArchiveEntry{Name: "repo-abc//etc/escaped.txt", Content: "escaped"}, "path escapes destination"},
+		{"backslash path", testArchiveEntry{Name: "repo-abc/..\\..\\escaped.txt", Content: "escaped"}, "path escapes destination"},
+		{"symlink", testArchiveEntry{Name: "repo-abc/link", Content: "/etc/passwd", Mode: os.ModeSymlink | 0777}, "symlink"},
+		{"compression ratio", testArchiveEntry{Name: "repo-abc/bomb.txt", Content: strings.Repeat("0", 1<<20)}, "compression ratio"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			archivePath := writeTestArchive(t, []testArchiveEntry{
+				{Name: "repo-abc/", Mode: os.ModeDir | 0755},
+				{Name: "repo-abc/index.js", Content: "exports.handler = async () => {};"},
+				test.entry,
+			})
+			root := t.TempDir()
+			dest := filepath.Join(root, "dest")
+
+			extraction, err := Unzip(archivePath, dest, ArchiveLimits{
+				MaxCompressionRatio:           100,
+				MinCompressionRatioCheckBytes: 1024,
+			}, nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			reason, ok := skippedArchiveEntryReason(extraction, test.entry.Name)
+			if !ok {
+				t.Fatalf("entry %q was not reported as skipped, got %+v", test.entry.Name, extraction.SkippedEntries)
+			}
+			if reason != test.reason {
+				t.Errorf("expected reason %q, got %q", test.reason, reason)
+			}
+			if extraction.NumExtractedFiles != 1 {
+				t.Errorf("expected 1 extracted file, got %d", extraction.NumExtractedFiles)
+			}
+
+			if _, err := os.Stat(filepath.Join(root, "escaped.txt")); err == nil {
+				t.Errorf("entry was written outside of the destination")
+			}
+			for _, path := range []string{"link", "bomb.txt", "etc/escaped.txt"} {
+				if _, err := os.Lstat(filepath.Join(dest, path)); err == nil {
+					t.Errorf("skipped entry was written to %s", path)
+				}
+			}
+		})
+	}
+}
+
+func TestUnzipRejectsArchivesOverLimits(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []testArchiveEntry
+		limits  ArchiveLimits
+	}{
+		{
+			"total size",
+			[]testArchiveEntry{
+				{Name: "repo-abc/a.txt", Content: strings.Repeat("a", 64)},
+				{Name: "repo-abc/b.txt", Content: strings.Repeat("b", 64)},
+			},
+			ArchiveLimits{MaxExtractedSizeBytes: 100},
+		},
+		{
+			"file count",
+			[]testArchiveEntry{
+				{Name: "repo-abc/a.txt", Content: "a"},
+				{Name: "repo-abc/b.txt", Content: "b"},
+				{Name: "repo-abc/c.txt", Content: "c"},
+			},
+			ArchiveLimits{MaxFiles: 2},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			archivePath := writeTestArchive(t, test.entries)
+			dest := filepath.Join(t.TempDir(), "dest")
+
+			extraction, err := Unzip(archivePath, dest, test.limits, nil)
+			if err == nil {
+				t.Fatalf("expected the archive to be rejected")
+			}
+			if extraction.NumExtractedFiles != 0 {
+				t.Errorf("expected no extracted files, got %d", extraction.NumExtractedFiles)
+			}
+			if entries, err := os.ReadDir(dest); err == nil && len(entries) > 0 {
+				t.Errorf("expected nothing to be written, found %d entries", len(entries))
+			}
+		})
+	}
+}
+
+func TestUnzipFiltersDeniedEntries(t *testing.T) {
+	archivePath := writeTestArchive(t, []testArchiveEntry{
+		{Name: "repo-abc/index.js", Content: "exports.handler = async () => {};"},
+		{Name: "repo-abc/node_modules/dependency/index.js", Content: "module.exports = {};"},
+		{Name: "repo-abc/logo.png", Content: "png"},
+	})
+	dest := filepath.Join(t.TempDir(), "dest")
+
+	extraction, err := Unzip(archivePath, dest, ArchiveLimits{}, &ArchiveEntryFilter{
+		IncludePatterns:    []string{"**/*.js"},
+		ExcludeDirectories: []string{"/node_modules/"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if extraction.NumExtractedFiles != 1 {
+		t.Errorf("expected 1 extracted file, got %d", extraction.NumExtractedFiles)
+	}
+	if extraction.NumFilteredEntries != 2 {
+		t.Errorf("expected 2 filtered entries, got %d", extraction.NumFilteredEntries)
+	}
+	for _, path := range []string{"node_modules/dependency/index.js", "logo.png"} {
+		if _, err := os.Stat(filepath.Join(dest, path)); err == nil {
+			t.Errorf("denied entry was written to %s", path)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dest, "index.js")); err != nil {
+		t.Errorf("allowed entry was not written: %v", err)
+	}
+}
@@ -0,0 +1,228 @@
+package main
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// NOTE: constructs take their props after the scope and id in CDK, e.g. `new lambda.Function(this, "id", { ... })`,
+// and after the name in SST v3, e.g. `new sst.aws.Function("name", { ... })`
+func awsCDKConstructProps(module *JsModule, call JsCall) (JsValue, bool) {
+	for _, argument := range call.Arguments {
+		if argument := module.ResolveValue(argument); argument.Kind == JsValueObject {
+			return argument, true
+		}
+	}
+	return JsValue{}, false
+}
+
+// NOTE: paths are given as strings or joined with the directory of the stack, e.g.
+// `path.join(__dirname, "../lambda")`, `__dirname` is dropped as handlers are resolved relative to the stack anyway
+func awsCDKPath(module *JsModule, value JsValue) (string, bool) {
+	value = module.ResolveValue(value)
+	switch value.Kind {
+	case JsValueString:
+		return value.Text, true
+	case JsValueCall:
+		if value.Call == nil || !slices.Contains([]string{"join", "resolve"}, value.Call.CalleeName()) {
+			return "", false
+		}
+		parts := make([]string, 0)
+		for _, argument := range value.Call.Arguments {
+			argument := module.ResolveValue(argument)
+			switch {
+			case argument.Kind == JsValueString:
+				parts = append(parts, argument.Text)
+			case argument.Kind == JsValueIdentifier && argument.Text == "__dirname":
+				continue
+			default:
+				return "", false
+			}
+		}
+		return path.Join(parts...), len(parts) > 0
+	}
+	return "", false
+}
+
+var sstDurationRegexp = regexp.MustCompile(`^(\d+)\s*(second|minute|hour)s?$`)
+
+// NOTE: CDK durations are built like `Duration.seconds(30)` or `cdk.Duration.minutes(1)`, SST takes strings like
+// "30 seconds" or plain numbers of seconds
+func awsCDKDurationSeconds(module *JsModule, value JsValue) (int, bool) {
+	value = module.ResolveValue(value)
+	switch value.Kind {
+	case JsValueNumber:
+		seconds, err := strconv.Atoi(value.Text)
+		return seconds, err == nil
+	case JsValueString:
+		match := sstDurationRegexp.FindStringSubmatch(strings.TrimSpace(value.Text))
+		if match == nil {
+			return 0, false
+		}
+		seconds, _ := strconv.Atoi(match[1])
+		switch match[2] {
+		case "minute":
+			seconds *= 60
+		case "hour":
+			seconds *= 60 * 60
+		}
+		return seconds, true
+	case JsValueCall:
+		if value.Call == nil || len(value.Call.Callee) < 2 || value.Call.Callee[len(value.Call.Callee)-2] != "Duration" {
+			return 0, false
+		}
+		amount := value.Call.Argument(0)
+		if amount == nil || amount.Kind != JsValueNumber {
+			return 0, false
+		}
+		seconds, err := strconv.Atoi(amount.Text)
+		if err != nil {
+			return 0, false
+		}
+		switch value.Call.CalleeName() {
+		case "seconds":
+			return seconds, true
+		case "minutes":
+			return seconds * 60, true
+		case "hours":
+			return seconds * 60 * 60, true
+		}
+	}
+	return 0, false
+}
+
+// NOTE: CDK runtimes are constants like `lambda.Runtime.NODEJS_18_X` or `Runtime.PROVIDED_AL2023`, which are named
+// "nodejs18.x" and "provided.al2023" by Lambda, SST takes the names directly
+func awsCDKRuntime(module *JsModule, value JsValue) (string, bool) {
+	value = module.ResolveValue(value)
+	switch value.Kind {
+	case JsValueString:
+		return value.Text, true
+	case JsValueIdentifier:
+		parts := strings.Split(value.Text, ".")
+		if len(parts) < 2 || parts[len(parts)-2] != "Runtime" {
+			return "", false
+		}
+		constant := strings.Split(strings.ToLower(parts[len(parts)-1]), "_")
+		if len(constant) == 1 {
+			return constant[0], true
+		}
+		if _, err := strconv.Atoi(constant[1]); err != nil {
+			return strings.Join(constant, "."), true
+		}
+		return constant[0] + strings.Join(constant[1:], "."), true
+	}
+	return "", false
+}
+
+// NOTE: e.g. `lambda.Architecture.ARM_64` in CDK, "arm_64" in SST v2 and "arm64" in SST v3
+func awsCDKArchitecture(module *JsModule, value JsValue) (string, bool) {
+	value = module.ResolveValue(value)
+	architecture := ""
+	switch value.Kind {
+	case JsValueString:
+		architecture = value.Text
+	case JsValueIdentifier:
+		parts := strings.Split(value.Text, ".")
+		if len(parts) < 2 || parts[len(parts)-2] != "Architecture" {
+			return "", false
+		}
+		architecture = parts[len(parts)-1]
+	default:
+		return "", false
+	}
+	architecture = strings.ToLower(architecture)
+	if architecture == "arm_64" {
+		architecture = "arm64"
+	}
+	return architecture, true
+}
+
+func awsCDKInt(module *JsModule, value *JsValue) (int, bool) {
+	if value == nil {
+		return 0, false
+	}
+	resolved := module.ResolveValue(*value)
+	if resolved.Kind != JsValueNumber {
+		return 0, false
+	}
+	number, err := strconv.Atoi(resolved.Text)
+	return number, err == nil
+}
+
+// NOTE: the configuration of functions shares the keys of CDK and SST v2, e.g. `memorySize: 512` and
+// `reservedConcurrentExecutions: 5`, SST v3 names them `memory: "512 MB"` and `concurrency: { reserved: 5 }`
+func applyAWSCDKFunctionProps(module *JsModule, props JsValue, function *RepositoryFaaSFunctionData) {
+	if runtime := props.Property("runtime"); runtime != nil {
+		if runtime, ok := awsCDKRuntime(module, *runtime); ok {
+			function.Runtime = runtime
+		}
+	}
+	if handler, ok := props.StringProperty("handler"); ok {
+		function.Handler = handler
+	}
+	// NOTE: the handler of CDK functions is relative to their code asset, `lambda.Code.fromAsset("lambda")`, Node.js
+	// functions are bundled from an entry file exporting "handler" by default
+	if code := props.Property("code"); code != nil && function.Handler != "" {
+		if code := module.ResolveValue(*code); code.Kind == JsValueCall && code.Call != nil && code.Call.CalleeName() == "fromAsset" {
+			if asset := code.Call.Argument(0); asset != nil {
+				if assetPath, ok := awsCDKPath(module, *asset); ok {
+					function.Handler = path.Join(assetPath, function.Handler)
+				}
+			}
+		}
+	}
+	if entry := props.Property("entry"); entry != nil {
+		if entryPath, ok := awsCDKPath(module, *entry); ok {
+			handler := "handler"
+			if function.Handler != "" {
+				handler = function.Handler
+			}
+			function.Handler = fmt.Sprintf("%s:%s", entryPath, handler)
+		}
+	}
+	for _, key := range []string{"memorySize", "memory"} {
+		memory := props.Property(key)
+		if memory == nil {
+			continue
+		}
+		if memoryMB, ok := awsCDKInt(module, memory); ok {
+			function.MemoryMB = memoryMB
+		} else if memory := module.ResolveValue(*memory); memory.Kind == JsValueString {
+			if memoryMB, ok := ParseMemorySizeMB(memory.Text); ok {
+				function.MemoryMB = memoryMB
+			}
+		}
+	}
+	if timeout := props.Property("timeout"); timeout != nil {
+		if timeoutSeconds, ok := awsCDKDurationSeconds(module, *timeout); ok {
+			function.TimeoutSeconds = timeoutSeconds
+		}
+	}
+	if architecture := props.Property("architecture"); architecture != nil {
+		if architecture, ok := awsCDKArchitecture(module, *architecture); ok {
+			function.Architecture = architecture
+		}
+	}
+	if reservedConcurrency, ok := awsCDKInt(module, props.Property("reservedConcurrentExecutions")); ok {
+		function.ReservedConcurrency = reservedConcurrency
+	}
+	if currentVersionOptions := props.Property("currentVersionOptions"); currentVersionOptions != nil {
+		if provisionedConcurrency, ok := awsCDKInt(module, module.ResolveValue(*currentVersionOptions).Property("provisionedConcurrentExecutions")); ok {
+			function.ProvisionedConcurrency = provisionedConcurrency
+		}
+	}
+	if concurrency := props.Property("concurrency"); concurrency != nil {
+		concurrency := module.ResolveValue(*concurrency)
+		if reservedConcurrency, ok := awsCDKInt(module, concurrency.Property("reserved")); ok {
+			function.ReservedConcurrency = reservedConcurrency
+		}
+		if provisionedConcurrency, ok := awsCDKInt(module, concurrency.Property("provisioned")); ok {
+			function.ProvisionedConcurrency = provisionedConcurrency
+		}
+	}
+}
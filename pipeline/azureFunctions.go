@@ -0,0 +1,334 @@
+package main
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// NOTE: binding types of function.json (v3 programming model and other languages) and trigger types of app.generic
+var azureFunctionsBindingTriggers = map[string]FaaSInvocationType{
+	"httpTrigger":          FaaSInvocationTypeHTTP,
+	"timerTrigger":         FaaSInvocationTypeSchedule,
+	"queueTrigger":         FaaSInvocationTypeQueue,
+	"eventHubTrigger":      FaaSInvocationTypeTopic,
+	"eventGridTrigger":     FaaSInvocationTypeTopic,
+	"kafkaTrigger":         FaaSInvocationTypeTopic,
+	"rabbitMQTrigger":      FaaSInvocationTypeQueue,
+	"blobTrigger":          FaaSInvocationTypeOther,
+	"cosmosDBTrigger":      FaaSInvocationTypeOther,
+	"sqlTrigger":           FaaSInvocationTypeOther,
+	"signalRTrigger":       FaaSInvocationTypeWebsocket,
+	"webPubSubTrigger":     FaaSInvocationTypeWebsocket,
+	"orchestrationTrigger": FaaSInvocationTypeOther,
+	"activityTrigger":      FaaSInvocationTypeOther,
+	"entityTrigger":        FaaSInvocationTypeOther,
+}
+
+// NOTE: options of triggers kept as attributes of the function, e.g. the route of an HTTP trigger
+var azureFunctionsTriggerAttributes = []string{
+	"route", "methods", "authLevel", "schedule", "queueName", "topicName", "subscriptionName", "eventHubName",
+	"path", "databaseName", "containerName", "collectionName", "hub", "connection",
+}
+
+func azureFunctionsBindingTriggerInvocationType(bindingType string) FaaSInvocationType {
+	if invocationType, ok := azureFunctionsBindingTriggers[bindingType]; ok {
+		return invocationType
+	}
+	return FaaSInvocationTypeOther
+}
+
+// NOTE: attributes of a JS options object that are strings or arrays of strings, which covers all trigger options above
+func jsAttributes(value JsValue, keys []string) map[string]string {
+	attributes := make(map[string]string)
+	for _, key := range keys {
+		property := value.Property(key)
+		if property == nil {
+			continue
+		}
+		if values := jsStrings(*property); len(values) > 0 {
+			attributes[key] = strings.Join(values, ",")
+		}
+	}
+	return attributes
+}
+
+type AzureFunctionsHostConfig struct {
+	// NOTE: -1 for unlimited or unset timeouts
+	FunctionTimeoutSeconds int
+	ExtensionBundle        string
+	// NOTE: the language worker of the function app, e.g. "node" or "python", from `FUNCTIONS_WORKER_RUNTIME` of
+	//       local.settings.json next to host.json, which is often not committed
+	Runtime string
+}
+
+// NOTE: host.json configures all functions of a function app, i.e. all functions in its directory
+func LoadAzureFunctionsHostConfigs(files []TextFile) map[string]AzureFunctionsHostConfig {
+	hostConfigs := make(map[string]AzureFunctionsHostConfig)
+	for _, file := range files {
+		if path.Base(file.Path) != "host.json" {
+			continue
+		}
+		hostJson, err := LoadJsonFromBytes([]byte(file.Content))
+		if err != nil {
+			continue
+		}
+		if _, err := JsonResolve(hostJson, []string{"version"}); err != nil {
+			continue
+		}
+
+		hostConfig := AzureFunctionsHostConfig{FunctionTimeoutSeconds: -1}
+		if functionTimeout, err := JsonResolveString(hostJson, []string{"functionTimeout"}); err == nil {
+			if functionTimeoutSeconds, ok := parseTimeSpanSeconds(functionTimeout); ok {
+				hostConfig.FunctionTimeoutSeconds = functionTimeoutSeconds
+			}
+		}
+		if extensionBundleId, err := JsonResolveString(hostJson, []string{"extensionBundle", "id"}); err == nil {
+			hostConfig.ExtensionBundle = extensionBundleId
+			if extensionBundleVersion, err := JsonResolveString(hostJson, []string{"extensionBundle", "version"}); err == nil {
+				hostConfig.ExtensionBundle = fmt.Sprintf("%s@%s", extensionBundleId, extensionBundleVersion)
+			}
+		}
+		hostConfigs[path.Dir(file.Path)] = hostConfig
+	}
+
+	for _, file := range files {
+		if path.Base(file.Path) != "local.settings.json" {
+			continue
+		}
+		hostConfig, ok := hostConfigs[path.Dir(file.Path)]
+		if !ok {
+			continue
+		}
+		localSettingsJson, err := LoadJsonFromJsoncBytes([]byte(file.Content))
+		if err != nil {
+			continue
+		}
+		if runtime, err := JsonResolveString(localSettingsJson, []string{"Values", "FUNCTIONS_WORKER_RUNTIME"}); err == nil {
+			hostConfig.Runtime = runtime
+			hostConfigs[path.Dir(file.Path)] = hostConfig
+		}
+	}
+	return hostConfigs
+}
+
+// NOTE: returns the host config of the nearest function app containing the file
+func azureFunctionsHostConfig(hostConfigs map[string]AzureFunctionsHostConfig, filePath string) (AzureFunctionsHostConfig, bool) {
+	for directory := path.Dir(filePath); ; directory = path.Dir(directory) {
+		if hostConfig, ok := hostConfigs[directory]; ok {
+			return hostConfig, true
+		}
+		if directory == "." || directory == "/" {
+			return AzureFunctionsHostConfig{}, false
+		}
+	}
+}
+
+var timeSpanRegexp = regexp.MustCompile(`^(?:(\d+)\.)?(\d{1,2}):(\d{2}):(\d{2})(?:\.\d+)?$`)
+
+// NOTE: .NET time spans like "00:05:00" or "1.00:00:00", "-1" stands for an unlimited timeout
+func parseTimeSpanSeconds(timeSpan string) (int, bool) {
+	match := timeSpanRegexp.FindStringSubmatch(strings.TrimSpace(timeSpan))
+	if match == nil {
+		return 0, false
+	}
+	seconds := 0
+	for index, factor := range []int{24 * 60 * 60, 60 * 60, 60, 1} {
+		if match[index+1] == "" {
+			continue
+		}
+		value, err := strconv.Atoi(match[index+1])
+		if err != nil {
+			return 0, false
+		}
+		seconds += value * factor
+	}
+	return seconds, true
+}
+
+// NOTE: decorators of the v2 programming model for Python, e.g. @app.route(route="a") or @bp.timer_trigger(...)
+var azureFunctionsPythonTriggers = map[string]FaaSInvocationType{
+	"route":                           FaaSInvocationTypeHTTP,
+	"http_type":                       FaaSInvocationTypeHTTP,
+	"timer_trigger":                   FaaSInvocationTypeSchedule,
+	"schedule":                        FaaSInvocationTypeSchedule,
+	"queue_trigger":                   FaaSInvocationTypeQueue,
+	"service_bus_queue_trigger":       FaaSInvocationTypeQueue,
+	"service_bus_topic_trigger":       FaaSInvocationTypeTopic,
+	"event_hub_message_trigger":       FaaSInvocationTypeTopic,
+	"event_grid_trigger":              FaaSInvocationTypeTopic,
+	"kafka_trigger":                   FaaSInvocationTypeTopic,
+	"blob_trigger":                    FaaSInvocationTypeOther,
+	"cosmos_db_trigger":               FaaSInvocationTypeOther,
+	"cosmos_db_trigger_v3":            FaaSInvocationTypeOther,
+	"sql_trigger":                     FaaSInvocationTypeOther,
+	"orchestration_trigger":           FaaSInvocationTypeOther,
+	"activity_trigger":                FaaSInvocationTypeOther,
+	"entity_trigger":                  FaaSInvocationTypeOther,
+	"warm_up_trigger":                 FaaSInvocationTypeOther,
+	"dapr_service_invocation_trigger": FaaSInvocationTypeHTTP,
+	"generic_trigger":                 FaaSInvocationTypeUnknown,
+}
+
+var azureFunctionsPythonTriggerAttributes = map[string]string{
+	"route":             "route",
+	"methods":           "methods",
+	"auth_level":        "authLevel",
+	"schedule":          "schedule",
+	"queue_name":        "queueName",
+	"topic_name":        "topicName",
+	"subscription_name": "subscriptionName",
+	"event_hub_name":    "eventHubName",
+	"path":              "path",
+	"database_name":     "databaseName",
+	"container_name":    "containerName",
+	"connection":        "connection",
+}
+
+var azureFunctionsPythonImportRegexp = regexp.MustCompile(`(?m)^\s*(?:import\s+azure\.(?:functions|durable_functions)\b|from\s+azure(?:\.functions|\.durable_functions)?\s+import\b)`)
+var azureFunctionsPythonAppRegexp = regexp.MustCompile(`(?m)^\s*(\w+)\s*=\s*(?:\w+\.)*(FunctionApp|AsgiFunctionApp|WsgiFunctionApp|Blueprint|DFApp)\(`)
+var pythonDecoratorRegexp = regexp.MustCompile(`(?m)^[ \t]*@(\w+)\.(\w+)`)
+var pythonDefRegexp = regexp.MustCompile(`(?m)^[ \t]*(?:async[ \t]+)?def[ \t]+(\w+)`)
+var pythonStringRegexp = regexp.MustCompile(`"([^"]*)"|'([^']*)'`)
+var pythonKeywordArgumentRegexp = regexp.MustCompile(`(\w+)\s*=\s*("[^"]*"|'[^']*'|\[[^\]]*\]|[\w.]+)`)
+
+type AzureFunctionsPythonFunction struct {
+	Name           string
+	Handler        string
+	InvocationType FaaSInvocationType
+	Attributes     map[string]string
+	Line           int
+}
+
+// NOTE: functions are the decorated definitions of a FunctionApp or Blueprint, the ASGI and WSGI apps wrap a whole web
+// framework app into a single HTTP function
+func ParseAzureFunctionsPython(content string) []AzureFunctionsPythonFunction {
+	functions := make([]AzureFunctionsPythonFunction, 0)
+	if !azureFunctionsPythonImportRegexp.MatchString(content) {
+		return functions
+	}
+
+	apps := make(map[string]string)
+	for _, match := range azureFunctionsPythonAppRegexp.FindAllStringSubmatchIndex(content, -1) {
+		name, kind := content[match[2]:match[3]], content[match[4]:match[5]]
+		apps[name] = kind
+		if kind == "AsgiFunctionApp" || kind == "WsgiFunctionApp" {
+			functions = append(functions, AzureFunctionsPythonFunction{
+				Name:           name,
+				Handler:        name,
+				InvocationType: FaaSInvocationTypeHTTP,
+				Attributes:     map[string]string{},
+				Line:           strings.Count(content[:match[0]], "\n") + 1,
+			})
+		}
+	}
+	if len(apps) == 0 {
+		return functions
+	}
+
+	// NOTE: the decorators of a function are grouped by the definition following them
+	decorated := make(map[int]*AzureFunctionsPythonFunction)
+	definitionOffsets := make([]int, 0)
+	for _, match := range pythonDecoratorRegexp.FindAllStringSubmatchIndex(content, -1) {
+		app, decorator := content[match[2]:match[3]], content[match[4]:match[5]]
+		if _, ok := apps[app]; !ok {
+			continue
+		}
+
+		arguments := ""
+		if match[1] < len(content) && content[match[1]] == '(' {
+			arguments = balancedParentheses(content[match[1]:])
+		}
+		definition := pythonDefRegexp.FindStringSubmatchIndex(content[match[1]+len(arguments):])
+		if definition == nil {
+			continue
+		}
+		definitionOffset := match[1] + len(arguments) + definition[0]
+
+		function, ok := decorated[definitionOffset]
+		if !ok {
+			function = &AzureFunctionsPythonFunction{
+				Handler:        content[match[1]+len(arguments)+definition[2] : match[1]+len(arguments)+definition[3]],
+				InvocationType: FaaSInvocationTypeUnknown,
+				Attributes:     make(map[string]string),
+				Line:           strings.Count(content[:match[0]], "\n") + 1,
+			}
+			decorated[definitionOffset] = function
+			definitionOffsets = append(definitionOffsets, definitionOffset)
+		}
+
+		keywordArguments := make(map[string]string)
+		for _, keywordArgument := range pythonKeywordArgumentRegexp.FindAllStringSubmatch(arguments, -1) {
+			values := pythonStringRegexp.FindAllStringSubmatch(keywordArgument[2], -1)
+			if len(values) == 0 {
+				keywordArguments[keywordArgument[1]] = keywordArgument[2]
+				continue
+			}
+			texts := make([]string, 0, len(values))
+			for _, value := range values {
+				texts = append(texts, value[1]+value[2])
+			}
+			keywordArguments[keywordArgument[1]] = strings.Join(texts, ",")
+		}
+
+		if decorator == "function_name" && keywordArguments["name"] != "" {
+			function.Name = keywordArguments["name"]
+		}
+		if invocationType, ok := azureFunctionsPythonTriggers[decorator]; ok {
+			function.InvocationType = invocationType
+			if decorator == "generic_trigger" {
+				function.InvocationType = azureFunctionsBindingTriggerInvocationType(keywordArguments["type"])
+			}
+			for keywordArgumentName, attributeName := range azureFunctionsPythonTriggerAttributes {
+				if value, ok := keywordArguments[keywordArgumentName]; ok {
+					function.Attributes[attributeName] = value
+				}
+			}
+		}
+	}
+	for _, definitionOffset := range definitionOffsets {
+		function := decorated[definitionOffset]
+		if function.Name == "" {
+			function.Name = function.Handler
+		}
+		functions = append(functions, *function)
+	}
+
+	// NOTE: decorators without a trigger, e.g. of helper functions, do not define a function
+	result := make([]AzureFunctionsPythonFunction, 0, len(functions))
+	for _, function := range functions {
+		if function.InvocationType != FaaSInvocationTypeUnknown || len(function.Attributes) > 0 {
+			result = append(result, function)
+		}
+	}
+	return result
+}
+
+// NOTE: returns the text within the parentheses the text starts with, strings are skipped
+func balancedParentheses(text string) string {
+	depth := 0
+	quote := byte(0)
+	for index := 0; index < len(text); index++ {
+		character := text[index]
+		switch {
+		case quote != 0:
+			if character == '\\' {
+				index++
+			} else if character == quote {
+				quote = 0
+			}
+		case character == '"' || character == '\'':
+			quote = character
+		case character == '(':
+			depth++
+		case character == ')':
+			depth--
+			if depth == 0 {
+				return text[:index+1]
+			}
+		}
+	}
+	return text
+}
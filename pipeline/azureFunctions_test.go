@@ -0,0 +1,94 @@
+package main
+
+import (
+	"maps"
+	"testing"
+)
+
+func TestScanAzureFunctionsDetectsRegistrations(t *testing.T) {
+	findings := scanTestRepository(t, "azure_functions_framework", map[string]string{
+		"node/host.json":           `{ "version": "2.0", "functionTimeout": "00:05:00" }`,
+		"node/local.settings.json": `{ "IsEncrypted": false, "Values": { "FUNCTIONS_WORKER_RUNTIME": "node" } }`,
+		"node/src/functions/index.ts": `import { app } from "@azure/functions";
+
+app.http("hello", {
+  methods: ["GET", "POST"],
+  route: "hello/{name}",
+  authLevel: "anonymous",
+  handler: hello,
+});
+app.timer("nightly", { schedule: "0 0 3 * * *", handler: async () => {} });
+app.serviceBusTopic("orders", { topicName: "orders", subscriptionName: "all", connection: "Bus", handler: onOrder });
+app.generic("rabbit", { trigger: { type: "rabbitMQTrigger", name: "message" }, handler: onMessage });
+// app.http("disabled", { handler: disabled });
+`,
+		"python/host.json": `{ "version": "2.0" }`,
+		"python/function_app.py": `import azure.functions as func
+
+app = func.FunctionApp()
+
+@app.function_name(name="HttpHello")
+@app.route(route="hello", methods=["GET"], auth_level=func.AuthLevel.ANONYMOUS)
+def hello(req: func.HttpRequest) -> func.HttpResponse:
+    return func.HttpResponse("hello")
+
+@app.timer_trigger(schedule="0 */5 * * * *", arg_name="timer")
+def cleanup(timer: func.TimerRequest) -> None:
+    pass
+
+@app.queue_trigger(arg_name="message", queue_name="jobs", connection="Storage")
+async def work(message: func.QueueMessage) -> None:
+    pass
+
+def helper():
+    pass
+`,
+		"legacy/Queue/function.json": `{
+  "scriptFile": "../dist/index.js",
+  "bindings": [{ "type": "queueTrigger", "direction": "in", "name": "message", "queueName": "legacy" }]
+}`,
+	}, "@azure/functions")
+
+	tests := []struct {
+		name           string
+		invocationType FaaSInvocationType
+		attributes     map[string]string
+		timeoutSeconds int
+		runtime        string
+		line           int
+	}{
+		{"hello", FaaSInvocationTypeHTTP, map[string]string{"methods": "GET,POST", "route": "hello/{name}", "authLevel": "anonymous"}, 300, "node", 3},
+		{"nightly", FaaSInvocationTypeSchedule, map[string]string{"schedule": "0 0 3 * * *"}, 300, "node", 9},
+		{"orders", FaaSInvocationTypeTopic, map[string]string{"topicName": "orders", "subscriptionName": "all", "connection": "Bus"}, 300, "node", 10},
+		{"rabbit", FaaSInvocationTypeQueue, map[string]string{}, 300, "node", 11},
+		{"HttpHello", FaaSInvocationTypeHTTP, map[string]string{"route": "hello", "methods": "GET", "authLevel": "func.AuthLevel.ANONYMOUS"}, -1, "", 5},
+		{"cleanup", FaaSInvocationTypeSchedule, map[string]string{"schedule": "0 */5 * * * *"}, -1, "", 10},
+		{"work", FaaSInvocationTypeQueue, map[string]string{"queueName": "jobs", "connection": "Storage"}, -1, "", 14},
+		{"Queue", FaaSInvocationTypeQueue, map[string]string{"queueName": "legacy"}, -1, "", -1},
+	}
+
+	functions := testFunctionsByName(t, findings)
+	if len(functions) != len(tests) {
+		t.Errorf("expected %d functions, got %d", len(tests), len(functions))
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			function, ok := functions[test.name]
+			if !ok {
+				t.Fatalf("function %s was not found", test.name)
+			}
+			if function.InvocationType != test.invocationType {
+				t.Errorf("expected invocation type %s, got %s", test.invocationType, function.InvocationType)
+			}
+			if !maps.Equal(function.Attributes, test.attributes) {
+				t.Errorf("expected attributes %v, got %v", test.attributes, function.Attributes)
+			}
+			if function.TimeoutSeconds != test.timeoutSeconds || function.Runtime != test.runtime {
+				t.Errorf("expected %d s and runtime %q of the host, got %d s and %q", test.timeoutSeconds, test.runtime, function.TimeoutSeconds, function.Runtime)
+			}
+			if function.SourceFileLine != test.line {
+				t.Errorf("expected line %d, got %d", test.line, function.SourceFileLine)
+			}
+		})
+	}
+}
@@ -0,0 +1,572 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+
+	"golang.org/x/exp/maps"
+	"gopkg.in/yaml.v3"
+)
+
+type CloudFormationResource struct {
+	LogicalId  string
+	Type       string
+	Properties map[string]interface{}
+	Line       int
+}
+
+type CloudFormationTemplate struct {
+	Path string
+
+	Resources  map[string]CloudFormationResource
+	Parameters map[string]interface{}
+	Mappings   map[string]interface{}
+	Globals    map[string]interface{}
+}
+
+var cloudFormationResourceTypeRegexp = regexp.MustCompile(`^(?:AWS|Alexa|Custom)::`)
+
+// NOTE: converts the short form of intrinsic functions to the long form, e.g. `!GetAtt A.Arn` to `{"Fn::GetAtt": ["A", "Arn"]}`,
+// aliases are expanded unless they are part of their own anchor, which yaml.Unmarshal rejects as well
+func cloudFormationYamlNodeToJson(node *yaml.Node, expandingAnchors map[*yaml.Node]bool) (interface{}, error) {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) == 0 {
+			return nil, nil
+		}
+		return cloudFormationYamlNodeToJson(node.Content[0], expandingAnchors)
+	case yaml.AliasNode:
+		return cloudFormationYamlNodeToJson(node.Alias, expandingAnchors)
+	}
+
+	// NOTE: aliases point to the node of their anchor, which contains itself if it is reached again while expanding it
+	if node.Anchor != "" {
+		if expandingAnchors[node] {
+			return nil, fmt.Errorf("anchor %s contains itself at line %d", node.Anchor, node.Line)
+		}
+		expandingAnchors[node] = true
+		defer delete(expandingAnchors, node)
+	}
+
+	if strings.HasPrefix(node.Tag, "!") && !strings.HasPrefix(node.Tag, "!!") {
+		untagged := *node
+		untagged.Tag = ""
+		value, err := cloudFormationYamlNodeToJson(&untagged, expandingAnchors)
+		if err != nil {
+			return nil, err
+		}
+
+		name := strings.TrimPrefix(node.Tag, "!")
+		switch name {
+		case "Ref", "Condition":
+			return map[string]interface{}{name: value}, nil
+		case "GetAtt":
+			if valueString, ok := value.(string); ok {
+				logicalId, attribute, _ := strings.Cut(valueString, ".")
+				value = []interface{}{logicalId, attribute}
+			}
+		}
+		return map[string]interface{}{"Fn::" + name: value}, nil
+	}
+
+	switch node.Kind {
+	case yaml.MappingNode:
+		result := make(map[string]interface{}, len(node.Content)/2)
+		for index := 0; index+1 < len(node.Content); index += 2 {
+			value, err := cloudFormationYamlNodeToJson(node.Content[index+1], expandingAnchors)
+			if err != nil {
+				return nil, err
+			}
+			result[node.Content[index].Value] = value
+		}
+		return result, nil
+	case yaml.SequenceNode:
+		result := make([]interface{}, 0, len(node.Content))
+		for _, child := range node.Content {
+			value, err := cloudFormationYamlNodeToJson(child, expandingAnchors)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, value)
+		}
+		return result, nil
+	default:
+		var value interface{}
+		if err := node.Decode(&value); err != nil {
+			return node.Value, nil
+		}
+		return value, nil
+	}
+}
+
+// NOTE: returns nil without an error for YAML/JSON files that are not CloudFormation templates
+func LoadCloudFormationTemplate(file TextFile) (*CloudFormationTemplate, error) {
+	var templateJson interface{}
+	var err error
+	switch file.Extension {
+	case ".yaml", ".yml":
+		decoder := yaml.NewDecoder(bytes.NewReader([]byte(file.Content)))
+		var document yaml.Node
+		if err := decoder.Decode(&document); err != nil {
+			return nil, err
+		}
+		var nextDocument yaml.Node
+		if err := decoder.Decode(&nextDocument); err == nil {
+			return nil, nil
+		}
+		templateJson, err = cloudFormationYamlNodeToJson(&document, make(map[*yaml.Node]bool))
+		if err != nil {
+			return nil, err
+		}
+	case ".json":
+		templateJson, err = LoadJsonFromBytes([]byte(file.Content))
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, nil
+	}
+
+	resources, err := JsonResolveMap(templateJson, []string{"Resources"})
+	if err != nil {
+		return nil, nil
+	}
+
+	template := &CloudFormationTemplate{
+		Path:       file.Path,
+		Resources:  make(map[string]CloudFormationResource),
+		Parameters: make(map[string]interface{}),
+	}
+
+	for logicalId, resource := range resources {
+		resourceType, err := JsonResolveString(resource, []string{"Type"})
+		if err != nil || !cloudFormationResourceTypeRegexp.MatchString(resourceType) {
+			continue
+		}
+		properties, err := JsonResolveMap(resource, []string{"Properties"})
+		if err != nil {
+			properties = map[string]interface{}{}
+		}
+		template.Resources[logicalId] = CloudFormationResource{
+			LogicalId:  logicalId,
+			Type:       resourceType,
+			Properties: properties,
+			Line:       YamlKeyLine([]byte(file.Content), []string{"Resources", logicalId}),
+		}
+	}
+	if len(template.Resources) == 0 {
+		return nil, nil
+	}
+
+	if parameters, err := JsonResolveMap(templateJson, []string{"Parameters"}); err == nil {
+		for name, parameter := range parameters {
+			if defaultValue, err := JsonResolve(parameter, []string{"Default"}); err == nil {
+				template.Parameters[name] = defaultValue
+			}
+		}
+	}
+	template.Mappings, _ = JsonResolveMap(templateJson, []string{"Mappings"})
+	template.Globals, _ = JsonResolveMap(templateJson, []string{"Globals"})
+
+	return template, nil
+}
+
+func (template *CloudFormationTemplate) LogicalIds() []string {
+	logicalIds := maps.Keys(template.Resources)
+	slices.Sort(logicalIds)
+	return logicalIds
+}
+
+const cloudFormationMaxResolutionDepth = 16
+
+// NOTE: resolves parameters, mappings and substitutions as far as possible without deploying the template
+func (template *CloudFormationTemplate) ResolveValue(value interface{}) interface{} {
+	return template.resolveValue(value, make(map[string]bool), 0)
+}
+
+// NOTE: parameters referring to themselves, directly or through other parameters, and mappings nested deeper than
+// cloudFormationMaxResolutionDepth are left unresolved
+func (template *CloudFormationTemplate) resolveValue(value interface{}, resolvingParameters map[string]bool, depth int) interface{} {
+	if depth > cloudFormationMaxResolutionDepth {
+		return value
+	}
+	valueMap, ok := value.(map[string]interface{})
+	if !ok || len(valueMap) != 1 {
+		return value
+	}
+
+	for function, argument := range valueMap {
+		switch function {
+		case "Ref":
+			name, ok := argument.(string)
+			if !ok {
+				return value
+			}
+			if parameter, ok := template.Parameters[name]; ok && !resolvingParameters[name] {
+				resolvingParameters[name] = true
+				defer delete(resolvingParameters, name)
+				return template.resolveValue(parameter, resolvingParameters, depth+1)
+			}
+		case "Fn::FindInMap":
+			arguments, ok := argument.([]interface{})
+			if !ok || len(arguments) < 3 {
+				return value
+			}
+			keys := make([]string, 0, 3)
+			for _, key := range arguments[:3] {
+				keyString, ok := template.resolveValue(key, resolvingParameters, depth+1).(string)
+				if !ok {
+					return value
+				}
+				keys = append(keys, keyString)
+			}
+			if resolved, err := JsonResolve(template.Mappings, keys); err == nil {
+				return template.resolveValue(resolved, resolvingParameters, depth+1)
+			}
+		case "Fn::Sub":
+			text, ok := argument.(string)
+			if !ok {
+				return value
+			}
+			resolved := text
+			for name, parameter := range template.Parameters {
+				reference := fmt.Sprintf("${%s}", name)
+				if !strings.Contains(resolved, reference) || resolvingParameters[name] {
+					continue
+				}
+				resolvingParameters[name] = true
+				if parameterString, ok := template.resolveValue(parameter, resolvingParameters, depth+1).(string); ok {
+					resolved = strings.ReplaceAll(resolved, reference, parameterString)
+				}
+				delete(resolvingParameters, name)
+			}
+			if !strings.Contains(resolved, "${") {
+				return resolved
+			}
+		}
+	}
+	return value
+}
+
+var cloudFormationSubReferenceRegexp = regexp.MustCompile(`\$\{([A-Za-z0-9]+)(?:\.[A-Za-z0-9.]+)?\}`)
+
+// NOTE: follows `!Ref Function`, `!GetAtt Function.Arn` or `!Sub ${Function.Arn}:live` through versions and aliases
+func (template *CloudFormationTemplate) ReferencedResource(value interface{}) (CloudFormationResource, bool) {
+	return template.referencedResource(value, make(map[string]bool))
+}
+
+// NOTE: versions and aliases referring to each other don't reference a function
+func (template *CloudFormationTemplate) referencedResource(value interface{}, visitedLogicalIds map[string]bool) (CloudFormationResource, bool) {
+	logicalId := ""
+
+	switch value := value.(type) {
+	case string:
+		logicalId = value
+	case map[string]interface{}:
+		if ref, ok := value["Ref"].(string); ok {
+			logicalId = ref
+		} else if getAtt, ok := value["Fn::GetAtt"].([]interface{}); ok && len(getAtt) > 0 {
+			logicalId, _ = getAtt[0].(string)
+		} else if getAtt, ok := value["Fn::GetAtt"].(string); ok {
+			logicalId, _, _ = strings.Cut(getAtt, ".")
+		} else if sub, ok := value["Fn::Sub"].(string); ok {
+			for _, match := range cloudFormationSubReferenceRegexp.FindAllStringSubmatch(sub, -1) {
+				if _, ok := template.Resources[match[1]]; ok {
+					logicalId = match[1]
+					break
+				}
+			}
+		} else if joined, ok := value["Fn::Join"].([]interface{}); ok && len(joined) == 2 {
+			parts, _ := joined[1].([]interface{})
+			for _, part := range parts {
+				if resource, ok := template.referencedResource(part, visitedLogicalIds); ok {
+					return resource, true
+				}
+			}
+		}
+	}
+
+	// NOTE: SAM exposes the version and alias of a function with AutoPublishAlias as Function.Version and Function.Alias
+	logicalId = strings.TrimSuffix(strings.TrimSuffix(logicalId, ".Version"), ".Alias")
+
+	resource, ok := template.Resources[logicalId]
+	if !ok || visitedLogicalIds[logicalId] {
+		return CloudFormationResource{}, false
+	}
+	visitedLogicalIds[logicalId] = true
+
+	switch resource.Type {
+	case "AWS::Lambda::Version", "AWS::Lambda::Alias":
+		if functionName, ok := resource.Properties["FunctionName"]; ok && !slices.Contains([]string{"", logicalId}, fmt.Sprint(functionName)) {
+			return template.referencedResource(functionName, visitedLogicalIds)
+		}
+	}
+	return resource, true
+}
+
+// NOTE: SAM Globals apply to all functions of a template, properties of the function itself take precedence
+func (template *CloudFormationTemplate) FunctionProperties(resource CloudFormationResource) map[string]interface{} {
+	if resource.Type != "AWS::Serverless::Function" {
+		return resource.Properties
+	}
+	globals, err := JsonResolveMap(template.Globals, []string{"Function"})
+	if err != nil {
+		return resource.Properties
+	}
+	properties, ok := mergeJsons(globals, resource.Properties).(map[string]interface{})
+	if !ok {
+		return resource.Properties
+	}
+	return properties
+}
+
+// NOTE: local templates of nested stacks, remote ones (S3 URLs, SAR applications) are not part of the repository
+func (template *CloudFormationTemplate) NestedTemplatePaths() map[string]CloudFormationResource {
+	result := make(map[string]CloudFormationResource)
+	for _, logicalId := range template.LogicalIds() {
+		resource := template.Resources[logicalId]
+
+		var location interface{}
+		switch resource.Type {
+		case "AWS::CloudFormation::Stack":
+			location = resource.Properties["TemplateURL"]
+		case "AWS::Serverless::Application":
+			location = resource.Properties["Location"]
+		default:
+			continue
+		}
+
+		locationString, ok := template.ResolveValue(location).(string)
+		if !ok || strings.Contains(locationString, "://") || locationString == "" {
+			continue
+		}
+		result[path.Join(path.Dir(template.Path), locationString)] = resource
+	}
+	return result
+}
+
+var cloudFormationEventSourceInvocationTypes = map[string]FaaSInvocationType{
+	"AWS::SQS::Queue":                          FaaSInvocationTypeQueue,
+	"AWS::AmazonMQ::Broker":                    FaaSInvocationTypeQueue,
+	"AWS::Kinesis::Stream":                     FaaSInvocationTypeTopic,
+	"AWS::MSK::Cluster":                        FaaSInvocationTypeTopic,
+	"AWS::DynamoDB::Table":                     FaaSInvocationTypeOther,
+	"AWS::DocDB::DBCluster":                    FaaSInvocationTypeOther,
+	"AWS::SNS::Topic":                          FaaSInvocationTypeTopic,
+	"AWS::ApiGateway::RestApi":                 FaaSInvocationTypeHTTP,
+	"AWS::ApiGatewayV2::Api":                   FaaSInvocationTypeHTTP,
+	"AWS::S3::Bucket":                          FaaSInvocationTypeOther,
+	"AWS::Cognito::UserPool":                   FaaSInvocationTypeOther,
+	"AWS::IoT::TopicRule":                      FaaSInvocationTypeOther,
+	"AWS::Logs::LogGroup":                      FaaSInvocationTypeOther,
+	"AWS::ElasticLoadBalancingV2::TargetGroup": FaaSInvocationTypeHTTP,
+}
+
+var awsPrincipalInvocationTypes = map[string]FaaSInvocationType{
+	"apigateway.amazonaws.com":           FaaSInvocationTypeHTTP,
+	"elasticloadbalancing.amazonaws.com": FaaSInvocationTypeHTTP,
+	"sns.amazonaws.com":                  FaaSInvocationTypeTopic,
+	"sqs.amazonaws.com":                  FaaSInvocationTypeQueue,
+	"s3.amazonaws.com":                   FaaSInvocationTypeOther,
+	"logs.amazonaws.com":                 FaaSInvocationTypeOther,
+	"iot.amazonaws.com":                  FaaSInvocationTypeOther,
+	"cognito-idp.amazonaws.com":          FaaSInvocationTypeOther,
+	"alexa-appkit.amazon.com":            FaaSInvocationTypeOther,
+	"alexa-connectedhome.amazon.com":     FaaSInvocationTypeOther,
+	"appsync.amazonaws.com":              FaaSInvocationTypeGraphQL,
+	"scheduler.amazonaws.com":            FaaSInvocationTypeSchedule,
+}
+
+// NOTE: ARNs given as plain strings or substitutions, e.g. `arn:aws:sqs:...` or `!Sub arn:${AWS::Partition}:sqs:...`
+var awsArnServiceInvocationTypes = map[string]FaaSInvocationType{
+	"sqs":      FaaSInvocationTypeQueue,
+	"mq":       FaaSInvocationTypeQueue,
+	"kinesis":  FaaSInvocationTypeTopic,
+	"kafka":    FaaSInvocationTypeTopic,
+	"sns":      FaaSInvocationTypeTopic,
+	"dynamodb": FaaSInvocationTypeOther,
+}
+
+var awsArnServiceRegexp = regexp.MustCompile(`^arn:[^:]+:([a-z0-9-]+):`)
+
+func (template *CloudFormationTemplate) eventSourceInvocationType(value interface{}) FaaSInvocationType {
+	if resource, ok := template.ReferencedResource(value); ok {
+		if resource.Type == "AWS::Events::Rule" {
+			if _, ok := resource.Properties["ScheduleExpression"]; ok {
+				return FaaSInvocationTypeSchedule
+			}
+			return FaaSInvocationTypeOther
+		}
+		if invocationType, ok := cloudFormationEventSourceInvocationTypes[resource.Type]; ok {
+			return invocationType
+		}
+	}
+
+	arn := template.ResolveValue(value)
+	if sub, ok := arn.(map[string]interface{}); ok {
+		arn = sub["Fn::Sub"]
+	}
+	if arnString, ok := arn.(string); ok {
+		return awsArnInvocationType(strings.ReplaceAll(arnString, "${AWS::Partition}", "aws"))
+	}
+	return FaaSInvocationTypeUnknown
+}
+
+func awsArnInvocationType(arn string) FaaSInvocationType {
+	if match := awsArnServiceRegexp.FindStringSubmatch(arn); match != nil {
+		if invocationType, ok := awsArnServiceInvocationTypes[match[1]]; ok {
+			return invocationType
+		}
+	}
+	return FaaSInvocationTypeUnknown
+}
+
+// NOTE: triggers of plain Lambda functions are separate resources pointing to them, e.g. event source mappings
+func (template *CloudFormationTemplate) LambdaFunctionTriggers() map[string]FaaSInvocationType {
+	result := make(map[string]FaaSInvocationType)
+
+	setTrigger := func(functionReference interface{}, invocationType FaaSInvocationType) {
+		resource, ok := template.ReferencedResource(functionReference)
+		if !ok || invocationType == FaaSInvocationTypeUnknown {
+			return
+		}
+		// NOTE: a function behind an API is reported as such, even if it also has other triggers
+		if result[resource.LogicalId] == FaaSInvocationTypeHTTP {
+			return
+		}
+		result[resource.LogicalId] = invocationType
+	}
+
+	for _, logicalId := range template.LogicalIds() {
+		resource := template.Resources[logicalId]
+		properties := resource.Properties
+
+		switch resource.Type {
+		case "AWS::Lambda::EventSourceMapping":
+			setTrigger(properties["FunctionName"], template.eventSourceInvocationType(properties["EventSourceArn"]))
+			if _, ok := properties["SelfManagedEventSource"]; ok {
+				setTrigger(properties["FunctionName"], FaaSInvocationTypeTopic)
+			}
+		case "AWS::Lambda::Permission":
+			principal, _ := template.ResolveValue(properties["Principal"]).(string)
+			invocationType, ok := awsPrincipalInvocationTypes[principal]
+			if principal == "events.amazonaws.com" {
+				invocationType, ok = template.eventSourceInvocationType(properties["SourceArn"]), true
+				if invocationType == FaaSInvocationTypeUnknown {
+					invocationType = FaaSInvocationTypeOther
+				}
+			}
+			if ok {
+				setTrigger(properties["FunctionName"], invocationType)
+			}
+		case "AWS::Lambda::Url":
+			setTrigger(properties["TargetFunctionArn"], FaaSInvocationTypeHTTP)
+		case "AWS::SNS::Subscription":
+			if protocol, _ := template.ResolveValue(properties["Protocol"]).(string); protocol == "lambda" {
+				setTrigger(properties["Endpoint"], FaaSInvocationTypeTopic)
+			}
+		case "AWS::SNS::Topic":
+			subscriptions, _ := properties["Subscription"].([]interface{})
+			for _, subscription := range subscriptions {
+				if protocol, _ := JsonResolveString(subscription, []string{"Protocol"}); protocol == "lambda" {
+					endpoint, _ := JsonResolve(subscription, []string{"Endpoint"})
+					setTrigger(endpoint, FaaSInvocationTypeTopic)
+				}
+			}
+		case "AWS::Events::Rule":
+			invocationType := FaaSInvocationTypeOther
+			if _, ok := properties["ScheduleExpression"]; ok {
+				invocationType = FaaSInvocationTypeSchedule
+			}
+			targets, _ := properties["Targets"].([]interface{})
+			for _, target := range targets {
+				arn, _ := JsonResolve(target, []string{"Arn"})
+				setTrigger(arn, invocationType)
+			}
+		case "AWS::Scheduler::Schedule":
+			arn, _ := JsonResolve(properties, []string{"Target", "Arn"})
+			setTrigger(arn, FaaSInvocationTypeSchedule)
+		case "AWS::ApiGateway::Method":
+			uri, _ := JsonResolve(properties, []string{"Integration", "Uri"})
+			setTrigger(uri, FaaSInvocationTypeHTTP)
+		case "AWS::ApiGatewayV2::Integration":
+			setTrigger(properties["IntegrationUri"], FaaSInvocationTypeHTTP)
+		case "AWS::AppSync::DataSource":
+			arn, _ := JsonResolve(properties, []string{"LambdaConfig", "LambdaFunctionArn"})
+			setTrigger(arn, FaaSInvocationTypeGraphQL)
+		}
+	}
+
+	return result
+}
+
+// NOTE: Lambda@Edge functions are attached to CloudFront distributions by the ARN of one of their versions
+func (template *CloudFormationTemplate) EdgeLambdaFunctions() map[string]bool {
+	result := make(map[string]bool)
+
+	for _, logicalId := range template.LogicalIds() {
+		resource := template.Resources[logicalId]
+		if resource.Type != "AWS::CloudFront::Distribution" {
+			continue
+		}
+
+		for _, cacheBehavior := range cloudFrontCacheBehaviors(resource) {
+			lambdaFunctionAssociations, err := JsonResolveArray(cacheBehavior, []string{"LambdaFunctionAssociations"})
+			if err != nil {
+				continue
+			}
+			for _, lambdaFunctionAssociation := range lambdaFunctionAssociations {
+				arn, _ := JsonResolve(lambdaFunctionAssociation, []string{"LambdaFunctionARN"})
+				if function, ok := template.ReferencedResource(arn); ok {
+					result[function.LogicalId] = true
+				}
+			}
+		}
+	}
+
+	return result
+}
+
+// NOTE: provisioned concurrency is configured on versions or aliases of a function, e.g. an AWS::Lambda::Alias with
+// `FunctionName: !Ref Function` and `ProvisionedConcurrencyConfig: { ProvisionedConcurrentExecutions: 5 }`
+func (template *CloudFormationTemplate) FunctionProvisionedConcurrency(logicalId string) (int, bool) {
+	for _, otherLogicalId := range template.LogicalIds() {
+		resource := template.Resources[otherLogicalId]
+		if resource.Type != "AWS::Lambda::Alias" && resource.Type != "AWS::Lambda::Version" {
+			continue
+		}
+		if function, ok := template.ReferencedResource(resource.Properties["FunctionName"]); !ok || function.LogicalId != logicalId {
+			continue
+		}
+		provisionedConcurrency, _ := JsonResolve(resource.Properties, []string{"ProvisionedConcurrencyConfig", "ProvisionedConcurrentExecutions"})
+		if provisionedConcurrency, err := JsonResolveInt(template.ResolveValue(provisionedConcurrency), []string{}); err == nil {
+			return provisionedConcurrency, true
+		}
+	}
+	return 0, false
+}
+
+func cloudFrontCacheBehaviors(distribution CloudFormationResource) []interface{} {
+	allCacheBehaviors := make([]interface{}, 0)
+
+	distributionConfig, err := JsonResolveMap(distribution.Properties, []string{"DistributionConfig"})
+	if err != nil {
+		return allCacheBehaviors
+	}
+
+	defaultCacheBehavior, err := JsonResolveMap(distributionConfig, []string{"DefaultCacheBehavior"})
+	if err == nil {
+		allCacheBehaviors = append(allCacheBehaviors, defaultCacheBehavior)
+	}
+
+	cacheBehaviors, err := JsonResolveArray(distributionConfig, []string{"CacheBehaviors"})
+	if err == nil {
+		allCacheBehaviors = append(allCacheBehaviors, cacheBehaviors...)
+	}
+
+	return allCacheBehaviors
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"testing"
+)
+
+func loadTestCloudFormationTemplate(t *testing.T, content string) *CloudFormationTemplate {
+	t.Helper()
+
+	template, err := LoadCloudFormationTemplate(TextFile{Path: "template.yaml", Extension: ".yaml", Content: content})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if template == nil {
+		t.Fatalf("expected a CloudFormation template")
+	}
+	return template
+}
+
+func TestCloudFormationReferencedResourceStopsAtCycles(t *testing.T) {
+	template := loadTestCloudFormationTemplate(t, `
+Resources:
+  Live:
+    Type: AWS::Lambda::Alias
+    Properties:
+      FunctionName: !Ref Version
+  Version:
+    Type: AWS::Lambda::Version
+    Properties:
+      FunctionName: !GetAtt Live.Arn
+`)
+
+	for _, logicalId := range []string{"Live", "Version"} {
+		t.Run(logicalId, func(t *testing.T) {
+			if resource, ok := template.ReferencedResource(map[string]interface{}{"Ref": logicalId}); ok {
+				t.Errorf("expected no referenced resource, got %s", resource.LogicalId)
+			}
+		})
+	}
+}
+
+func TestCloudFormationResolveValueStopsAtCycles(t *testing.T) {
+	template := loadTestCloudFormationTemplate(t, `
+Parameters:
+  Self:
+    Type: String
+    Default: !Ref Self
+  First:
+    Type: String
+    Default: !Sub "${Second}-first"
+  Second:
+    Type: String
+    Default: !Sub "${First}-second"
+Resources:
+  Function:
+    Type: AWS::Lambda::Function
+    Properties:
+      FunctionName: !Ref Self
+`)
+
+	tests := []struct {
+		name  string
+		value interface{}
+	}{
+		{"parameter referring to itself", map[string]interface{}{"Ref": "Self"}},
+		{"parameters substituting each other", map[string]interface{}{"Ref": "First"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if resolved, ok := template.ResolveValue(test.value).(string); ok {
+				t.Errorf("expected the value to be left unresolved, got %q", resolved)
+			}
+		})
+	}
+}
+
+func TestLoadCloudFormationTemplateRejectsRecursiveAnchors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"anchor containing itself", "Resources: &a\n  X: *a\n"},
+		{"tagged anchor containing itself", "Resources:\n  X:\n    Type: AWS::Lambda::Function\n    Properties:\n      Code: !Sub &a [\"x\", *a]\n"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := LoadCloudFormationTemplate(TextFile{Path: "template.yaml", Extension: ".yaml", Content: test.content}); err == nil {
+				t.Errorf("expected an error")
+			}
+		})
+	}
+}
+
+func TestLoadCloudFormationTemplateExpandsAliases(t *testing.T) {
+	template := loadTestCloudFormationTemplate(t, `
+Resources:
+  First:
+    Type: AWS::Lambda::Function
+    Properties: &properties
+      MemorySize: 512
+  Second:
+    Type: AWS::Lambda::Function
+    Properties: *properties
+`)
+
+	for _, logicalId := range []string{"First", "Second"} {
+		if memorySize := template.Resources[logicalId].Properties["MemorySize"]; memorySize != 512 {
+			t.Errorf("expected MemorySize 512 of %s, got %v", logicalId, memorySize)
+		}
+	}
+}
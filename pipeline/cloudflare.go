@@ -0,0 +1,228 @@
+package main
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"golang.org/x/exp/maps"
+)
+
+type WranglerWorker struct {
+	Name string
+	// NOTE: empty for the top-level configuration, otherwise the name of the `[env.*]` section
+	Environment string
+	ConfigPath  string
+	// NOTE: the entrypoint relative to the repository, empty for Pages projects and static assets
+	Main              string
+	CompatibilityDate string
+	// NOTE: the CPU time an invocation may use, Workers have no limit on the wall-clock time, 0 if not configured
+	CPULimitMs     int
+	Routes         []string
+	Crons          []string
+	QueueConsumers []string
+	DurableObjects []string
+	Bindings       []string
+}
+
+// NOTE: bindings are recorded as `type:NAME`, e.g. `kv:CACHE` or `durable_object:COUNTER`, single bindings like the
+// AI binding are objects instead of arrays
+var wranglerBindingTypes = []struct {
+	path        []string
+	bindingType string
+	nameKey     string
+}{
+	{[]string{"kv_namespaces"}, "kv", "binding"},
+	{[]string{"d1_databases"}, "d1", "binding"},
+	{[]string{"r2_buckets"}, "r2", "binding"},
+	{[]string{"queues", "producers"}, "queue", "binding"},
+	{[]string{"durable_objects", "bindings"}, "durable_object", "name"},
+	{[]string{"services"}, "service", "binding"},
+	{[]string{"analytics_engine_datasets"}, "analytics_engine", "binding"},
+	{[]string{"hyperdrive"}, "hyperdrive", "binding"},
+	{[]string{"vectorize"}, "vectorize", "binding"},
+	{[]string{"workflows"}, "workflow", "binding"},
+	{[]string{"dispatch_namespaces"}, "dispatch_namespace", "binding"},
+	{[]string{"mtls_certificates"}, "mtls_certificate", "binding"},
+	{[]string{"send_email"}, "send_email", "name"},
+	{[]string{"ai"}, "ai", "binding"},
+	{[]string{"browser"}, "browser", "binding"},
+	{[]string{"images"}, "images", "binding"},
+}
+
+// NOTE: wrangler.toml, wrangler.json and wrangler.jsonc share the same keys
+func LoadWranglerConfig(file TextFile) (interface{}, error) {
+	if strings.HasSuffix(file.Path, ".toml") {
+		return LoadJsonFromTomlBytes([]byte(file.Content))
+	}
+	return LoadJsonFromJsoncBytes([]byte(file.Content))
+}
+
+// NOTE: returns the top-level Worker and one Worker per environment, environments inherit the entrypoint, routes
+// and triggers but not the bindings, and are deployed as `<name>-<environment>` unless they are named explicitly
+func LoadWranglerWorkers(file TextFile) ([]WranglerWorker, error) {
+	config, err := LoadWranglerConfig(file)
+	if err != nil {
+		return nil, err
+	}
+
+	topLevelWorker := wranglerWorker(file.Path, config, nil)
+	workers := []WranglerWorker{topLevelWorker}
+
+	environments, err := JsonResolveMap(config, []string{"env"})
+	if err != nil {
+		return workers, nil
+	}
+	environmentNames := maps.Keys(environments)
+	slices.Sort(environmentNames)
+	for _, environmentName := range environmentNames {
+		worker := wranglerWorker(file.Path, environments[environmentName], &topLevelWorker)
+		worker.Environment = environmentName
+		if _, err := JsonResolveString(environments[environmentName], []string{"name"}); err != nil {
+			worker.Name = fmt.Sprintf("%s-%s", topLevelWorker.Name, environmentName)
+		}
+		workers = append(workers, worker)
+	}
+	return workers, nil
+}
+
+func wranglerWorker(configPath string, config interface{}, inherited *WranglerWorker) WranglerWorker {
+	worker := WranglerWorker{ConfigPath: configPath}
+	if inherited != nil {
+		worker = *inherited
+		worker.QueueConsumers = nil
+		worker.DurableObjects = nil
+		worker.Bindings = nil
+	}
+
+	if name, err := JsonResolveString(config, []string{"name"}); err == nil {
+		worker.Name = name
+	}
+	if main, err := JsonResolveString(config, []string{"main"}); err == nil {
+		worker.Main = path.Join(path.Dir(configPath), main)
+	}
+	if compatibilityDate, err := JsonResolveString(config, []string{"compatibility_date"}); err == nil {
+		worker.CompatibilityDate = compatibilityDate
+	}
+	if cpuLimitMs, err := JsonResolveInt(config, []string{"limits", "cpu_ms"}); err == nil {
+		worker.CPULimitMs = cpuLimitMs
+	}
+
+	// NOTE: routes are patterns like `example.com/api/*` or objects with a pattern and a zone or custom domain
+	routes := make([]string, 0)
+	if route, err := JsonResolveString(config, []string{"route"}); err == nil {
+		routes = append(routes, route)
+	}
+	if routeArray, err := JsonResolveArray(config, []string{"routes"}); err == nil {
+		for _, route := range routeArray {
+			if pattern, err := JsonResolveString(route, []string{"pattern"}); err == nil {
+				routes = append(routes, pattern)
+			} else if pattern, ok := route.(string); ok {
+				routes = append(routes, pattern)
+			}
+		}
+	}
+	if len(routes) > 0 {
+		worker.Routes = routes
+	}
+
+	if cronArray, err := JsonResolveArray(config, []string{"triggers", "crons"}); err == nil {
+		worker.Crons = make([]string, 0, len(cronArray))
+		for _, cron := range cronArray {
+			if cron, ok := cron.(string); ok {
+				worker.Crons = append(worker.Crons, cron)
+			}
+		}
+	}
+
+	if consumerArray, err := JsonResolveArray(config, []string{"queues", "consumers"}); err == nil {
+		for _, consumer := range consumerArray {
+			if queue, err := JsonResolveString(consumer, []string{"queue"}); err == nil {
+				worker.QueueConsumers = append(worker.QueueConsumers, queue)
+			}
+		}
+	}
+
+	// NOTE: Durable Objects without a script name are implemented by the Worker itself
+	if durableObjectArray, err := JsonResolveArray(config, []string{"durable_objects", "bindings"}); err == nil {
+		for _, durableObject := range durableObjectArray {
+			className, err := JsonResolveString(durableObject, []string{"class_name"})
+			if err != nil {
+				continue
+			}
+			if _, err := JsonResolveString(durableObject, []string{"script_name"}); err != nil {
+				worker.DurableObjects = append(worker.DurableObjects, className)
+			}
+		}
+	}
+
+	for _, bindingType := range wranglerBindingTypes {
+		value, err := JsonResolve(config, bindingType.path)
+		if err != nil {
+			continue
+		}
+		bindings, ok := value.([]interface{})
+		if !ok {
+			bindings = []interface{}{value}
+		}
+		for _, binding := range bindings {
+			if name, err := JsonResolveString(binding, []string{bindingType.nameKey}); err == nil {
+				worker.Bindings = append(worker.Bindings, fmt.Sprintf("%s:%s", bindingType.bindingType, name))
+			}
+		}
+	}
+
+	return worker
+}
+
+// NOTE: the invocation type a Worker is configured for if its entrypoint is not part of the repository, e.g. when
+// `main` points to a build output
+func (worker WranglerWorker) ConfiguredInvocationType() FaaSInvocationType {
+	switch {
+	case len(worker.Routes) > 0:
+		return FaaSInvocationTypeHTTP
+	case len(worker.QueueConsumers) > 0:
+		return FaaSInvocationTypeQueue
+	case len(worker.Crons) > 0:
+		return FaaSInvocationTypeSchedule
+	default:
+		return FaaSInvocationTypeUnknown
+	}
+}
+
+func (worker WranglerWorker) Attributes() map[string]string {
+	attributes := make(map[string]string)
+	if worker.Environment != "" {
+		attributes["environment"] = worker.Environment
+	}
+	if worker.CompatibilityDate != "" {
+		attributes["compatibilityDate"] = worker.CompatibilityDate
+	}
+	if len(worker.Routes) > 0 {
+		attributes["routes"] = strings.Join(worker.Routes, ",")
+	}
+	if len(worker.Crons) > 0 {
+		attributes["crons"] = strings.Join(worker.Crons, ",")
+	}
+	if len(worker.QueueConsumers) > 0 {
+		attributes["queues"] = strings.Join(worker.QueueConsumers, ",")
+	}
+	if len(worker.DurableObjects) > 0 {
+		attributes["durableObjects"] = strings.Join(worker.DurableObjects, ",")
+	}
+	return attributes
+}
+
+// NOTE: the route of a Pages function is given by its path below the functions directory, e.g.
+// `functions/api/[id].ts` serves `/api/[id]`
+func cloudflarePagesRoute(functionsDirectory string, filePath string) string {
+	route := strings.TrimPrefix(filePath, functionsDirectory)
+	route = strings.TrimSuffix(route, path.Ext(route))
+	route = strings.TrimSuffix(route, "/index")
+	route = strings.TrimSuffix(route, "/_middleware")
+	if !strings.HasPrefix(route, "/") {
+		route = "/" + route
+	}
+	return route
+}
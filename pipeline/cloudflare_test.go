@@ -0,0 +1,104 @@
+package main
+
+import (
+	"maps"
+	"slices"
+	"testing"
+)
+
+func TestScanCloudflareParsesWranglerConfigurations(t *testing.T) {
+	findings := scanTestRepository(t, "cloudflare", map[string]string{
+		"workers/api/wrangler.toml": `name = "api"
+main = "src/index.ts"
+compatibility_date = "2024-01-01"
+routes = [{ pattern = "example.com/api/*", zone_name = "example.com" }]
+
+[triggers]
+crons = ["0 * * * *"]
+
+[[kv_namespaces]]
+binding = "CACHE"
+id = "cache"
+
+[[durable_objects.bindings]]
+name = "COUNTER"
+class_name = "Counter"
+
+[limits]
+cpu_ms = 1500
+
+[env.staging]
+routes = ["staging.example.com/*"]
+`,
+		"workers/api/src/index.ts": `export default {
+  async fetch(request: Request, env: Env): Promise<Response> {
+    return new Response("hello");
+  },
+  async scheduled(event: ScheduledEvent, env: Env) {},
+};
+
+export class Counter {}
+`,
+		"workers/api/src/unused.ts": `export default { fetch: () => new Response() };`,
+		"workers/consumer/wrangler.jsonc": `{
+  // built before deploying
+  "name": "consumer",
+  "main": "dist/index.js",
+  "queues": { "consumers": [{ "queue": "jobs" }] }
+}`,
+	})
+
+	tests := []struct {
+		name           string
+		invocationType FaaSInvocationType
+		sourceFilePath string
+		sourceFileLine int
+		timeoutSeconds int
+		attributes     map[string]string
+		bindings       []string
+	}{
+		{
+			"api", FaaSInvocationTypeHTTP, "workers/api/src/index.ts", 2, 2,
+			map[string]string{"compatibilityDate": "2024-01-01", "routes": "example.com/api/*", "crons": "0 * * * *", "durableObjects": "Counter", "handlers": "fetch,scheduled"},
+			[]string{"kv:CACHE", "durable_object:COUNTER"},
+		},
+		{
+			"api-staging", FaaSInvocationTypeHTTP, "workers/api/src/index.ts", 2, 2,
+			map[string]string{"environment": "staging", "compatibilityDate": "2024-01-01", "routes": "staging.example.com/*", "crons": "0 * * * *", "handlers": "fetch,scheduled"},
+			nil,
+		},
+		{
+			"consumer", FaaSInvocationTypeQueue, "workers/consumer/wrangler.jsonc", -1, -1,
+			map[string]string{"queues": "jobs"},
+			nil,
+		},
+	}
+
+	functions := testFunctionsByName(t, findings)
+	if len(functions) != len(tests) {
+		t.Errorf("expected %d functions, got %d", len(tests), len(functions))
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			function, ok := functions[test.name]
+			if !ok {
+				t.Fatalf("function %s was not found", test.name)
+			}
+			if function.InvocationType != test.invocationType {
+				t.Errorf("expected invocation type %s, got %s", test.invocationType, function.InvocationType)
+			}
+			if function.SourceFilePath != testRepositoryDirectory+"/"+test.sourceFilePath || function.SourceFileLine != test.sourceFileLine {
+				t.Errorf("expected %s:%d, got %s:%d", test.sourceFilePath, test.sourceFileLine, function.SourceFilePath, function.SourceFileLine)
+			}
+			if function.TimeoutSeconds != test.timeoutSeconds {
+				t.Errorf("expected a timeout of %d s, got %d s", test.timeoutSeconds, function.TimeoutSeconds)
+			}
+			if !maps.Equal(function.Attributes, test.attributes) {
+				t.Errorf("expected attributes %v, got %v", test.attributes, function.Attributes)
+			}
+			if !slices.Equal(function.Bindings, test.bindings) {
+				t.Errorf("expected bindings %v, got %v", test.bindings, function.Bindings)
+			}
+		})
+	}
+}
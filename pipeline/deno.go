@@ -0,0 +1,195 @@
+package main
+
+import (
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+
+	"golang.org/x/exp/maps"
+)
+
+type DenoDeployProject struct {
+	// NOTE: the name of the project on Deno Deploy, empty if it is only given by environment or interactively
+	Project    string
+	Entrypoint string
+	ConfigPath string
+	Line       int
+}
+
+// NOTE: deno.json can contain comments, so the line of the `deploy` key is searched in the text
+var denoDeployConfigRegexp = regexp.MustCompile(`"deploy"\s*:\s*\{`)
+
+// NOTE: the `deploy` key of deno.json, e.g. `"deploy": { "project": "hello", "entrypoint": "main.ts" }`
+func LoadDenoDeployConfig(file TextFile) (DenoDeployProject, bool) {
+	denoConfig, err := LoadJsonFromJsoncBytes([]byte(file.Content))
+	if err != nil {
+		return DenoDeployProject{}, false
+	}
+	if _, err := JsonResolveMap(denoConfig, []string{"deploy"}); err != nil {
+		return DenoDeployProject{}, false
+	}
+
+	project := DenoDeployProject{ConfigPath: file.Path, Line: -1}
+	if match := denoDeployConfigRegexp.FindStringIndex(file.Content); match != nil {
+		project.Line = strings.Count(file.Content[:match[0]], "\n") + 1
+	}
+	project.Project, _ = JsonResolveString(denoConfig, []string{"deploy", "project"})
+	if entrypoint, err := JsonResolveString(denoConfig, []string{"deploy", "entrypoint"}); err == nil {
+		project.Entrypoint = denoDeployEntrypoint(path.Dir(file.Path), entrypoint)
+	}
+	return project, true
+}
+
+var deployctlCommandRegexp = regexp.MustCompile(`\bdeployctl(?:@[\w.-]+)?\s+deploy\b([^\n;&|"'` + "`" + `]*)`)
+
+// NOTE: `deployctl deploy --project=hello main.ts` in shell scripts, Makefiles, package.json or deno.json tasks and CI
+// workflows, the entrypoint is the first positional argument or `--entrypoint`, relative to the directory of the file
+// or to the repository for workflows
+func ParseDeployctlCommands(file TextFile) []DenoDeployProject {
+	projects := make([]DenoDeployProject, 0)
+
+	directory := path.Dir(file.Path)
+	if repositoryDirectory, ok := gitHubWorkflowRepositoryDirectory(file.Path); ok {
+		directory = repositoryDirectory
+	}
+
+	for _, match := range deployctlCommandRegexp.FindAllStringSubmatchIndex(file.Content, -1) {
+		project := DenoDeployProject{
+			ConfigPath: file.Path,
+			Line:       strings.Count(file.Content[:match[0]], "\n") + 1,
+		}
+
+		arguments := strings.Fields(file.Content[match[2]:match[3]])
+		for index := 0; index < len(arguments); index++ {
+			argument := arguments[index]
+			flag, value, hasValue := strings.Cut(argument, "=")
+			switch {
+			case flag == "--project" || flag == "-p" || flag == "--entrypoint":
+				if !hasValue && index+1 < len(arguments) {
+					index++
+					value = arguments[index]
+				}
+				if flag == "--entrypoint" {
+					project.Entrypoint = denoDeployEntrypoint(directory, value)
+				} else {
+					project.Project = value
+				}
+			case strings.HasPrefix(argument, "-"):
+				continue
+			case project.Entrypoint == "":
+				project.Entrypoint = denoDeployEntrypoint(directory, argument)
+			}
+		}
+
+		projects = append(projects, project)
+	}
+
+	return projects
+}
+
+// NOTE: steps using the denoland/deployctl action, e.g. `with: { project: hello, entrypoint: main.ts, root: dist }`
+func ParseDenoDeployActions(file TextFile, documents []YamlDocument) []DenoDeployProject {
+	projects := make([]DenoDeployProject, 0)
+	repositoryDirectory, ok := gitHubWorkflowRepositoryDirectory(file.Path)
+	if !ok || len(documents) != 1 {
+		return projects
+	}
+
+	jobs, err := JsonResolveMap(documents[0].Json, []string{"jobs"})
+	if err != nil {
+		return projects
+	}
+	jobNames := maps.Keys(jobs)
+	slices.Sort(jobNames)
+
+	for _, jobName := range jobNames {
+		steps, err := JsonResolveArray(jobs[jobName], []string{"steps"})
+		if err != nil {
+			continue
+		}
+		for _, step := range steps {
+			uses, err := JsonResolveString(step, []string{"uses"})
+			if err != nil || !strings.HasPrefix(uses, "denoland/deployctl") {
+				continue
+			}
+
+			project := DenoDeployProject{ConfigPath: file.Path, Line: -1}
+			project.Project, _ = JsonResolveString(step, []string{"with", "project"})
+			root, _ := JsonResolveString(step, []string{"with", "root"})
+			if entrypoint, err := JsonResolveString(step, []string{"with", "entrypoint"}); err == nil {
+				project.Entrypoint = denoDeployEntrypoint(path.Join(repositoryDirectory, root), entrypoint)
+			}
+			if index := strings.Index(file.Content, uses); index != -1 {
+				project.Line = strings.Count(file.Content[:index], "\n") + 1
+			}
+			projects = append(projects, project)
+		}
+	}
+
+	return projects
+}
+
+// NOTE: workflows run in a checkout of the repository, which is the directory containing .github/, e.g.
+// data/repositories/<id> for data/repositories/<id>/.github/workflows/deploy.yml
+func gitHubWorkflowRepositoryDirectory(filePath string) (string, bool) {
+	if strings.HasPrefix(filePath, ".github/workflows/") {
+		return ".", true
+	}
+	if index := strings.LastIndex(filePath, "/.github/workflows/"); index != -1 {
+		return filePath[:index], true
+	}
+	return "", false
+}
+
+// NOTE: entrypoints can also be remote modules like `https://deno.land/std/http/file_server.ts`, which are kept as is
+func denoDeployEntrypoint(directory string, entrypoint string) string {
+	if strings.Contains(entrypoint, "://") {
+		return entrypoint
+	}
+	return path.Join(directory, entrypoint)
+}
+
+type DenoTrigger struct {
+	InvocationType FaaSInvocationType
+	Attributes     map[string]string
+	Line           int
+}
+
+// NOTE: the handlers a Deno module registers, `Deno.serve(...)` and `serve(...)` of the standard library answer HTTP
+// requests, `Deno.cron("name", "0 * * * *", fn)` runs on a schedule and `kv.listenQueue(fn)` consumes a queue,
+// sorted by their line
+func ParseDenoTriggers(module *JsModule) []DenoTrigger {
+	triggers := make([]DenoTrigger, 0)
+
+	for _, call := range module.Calls {
+		switch {
+		case call.CalleePath() == "Deno.serve" || call.CalleePath() == "serve" && importsDenoHttpServer(module):
+			triggers = append(triggers, DenoTrigger{InvocationType: FaaSInvocationTypeHTTP, Line: call.Line})
+		case call.CalleePath() == "addEventListener":
+			if event, ok := call.StringArgument(0); ok && event == "fetch" {
+				triggers = append(triggers, DenoTrigger{InvocationType: FaaSInvocationTypeHTTP, Line: call.Line})
+			}
+		case call.CalleePath() == "Deno.cron":
+			trigger := DenoTrigger{InvocationType: FaaSInvocationTypeSchedule, Attributes: make(map[string]string), Line: call.Line}
+			if schedule, ok := call.StringArgument(1); ok {
+				trigger.Attributes["schedule"] = schedule
+			}
+			triggers = append(triggers, trigger)
+		case call.CalleeName() == "listenQueue":
+			triggers = append(triggers, DenoTrigger{InvocationType: FaaSInvocationTypeQueue, Line: call.Line})
+		}
+	}
+
+	slices.SortStableFunc(triggers, func(a, b DenoTrigger) int {
+		return a.Line - b.Line
+	})
+	return triggers
+}
+
+// NOTE: e.g. `https://deno.land/std@0.177.0/http/server.ts` or `jsr:@std/http`
+func importsDenoHttpServer(module *JsModule) bool {
+	return slices.ContainsFunc(module.Imports, func(imported JsImport) bool {
+		return strings.Contains(imported.Source, "/http/server") || strings.Contains(imported.Source, "@std/http")
+	})
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/bmatcuk/doublestar/v4"
+)
+
+func TestDenoDeployWorkflowEntrypoints(t *testing.T) {
+	workflow := `
+jobs:
+  deploy:
+    steps:
+      - run: deployctl deploy --project=hello src/main.ts
+      - uses: denoland/deployctl@v1
+        with:
+          project: hello
+          root: dist
+          entrypoint: server.ts
+`
+	tests := []struct {
+		name                string
+		path                string
+		deployctlEntrypoint string
+		actionEntrypoint    string
+	}{
+		{"repository root", ".github/workflows/deploy.yml", "src/main.ts", "dist/server.ts"},
+		{"cloned repository", "data/repositories/1/.github/workflows/deploy.yml", "data/repositories/1/src/main.ts", "data/repositories/1/dist/server.ts"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			file := TextFile{Path: test.path, Extension: ".yml", Content: workflow}
+
+			matched := false
+			for _, pattern := range DefaultScannerRegistry().Lookup("deno_deploy").FilePatterns() {
+				if ok, _ := doublestar.Match(pattern, test.path); ok {
+					matched = true
+				}
+			}
+			if !matched {
+				t.Errorf("workflow is not matched by the file patterns of the scanner")
+			}
+
+			if projects := ParseDeployctlCommands(file); len(projects) != 1 || projects[0].Entrypoint != test.deployctlEntrypoint {
+				t.Errorf("expected deployctl entrypoint %q, got %+v", test.deployctlEntrypoint, projects)
+			}
+			if projects := ParseDenoDeployActions(file, LoadYamlDocuments([]byte(workflow))); len(projects) != 1 || projects[0].Entrypoint != test.actionEntrypoint {
+				t.Errorf("expected action entrypoint %q, got %+v", test.actionEntrypoint, projects)
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"path"
+	"slices"
+)
+
+// NOTE: the methods of the orchestration context `context.df` scheduling the steps of an orchestration
+var durableOrchestrationStepTypes = map[string]string{
+	"callActivity":                 "Activity",
+	"callActivityWithRetry":        "Activity",
+	"callSubOrchestrator":          "SubOrchestration",
+	"callSubOrchestratorWithRetry": "SubOrchestration",
+	"callEntity":                   "Entity",
+	"signalEntity":                 "Entity",
+	"callHttp":                     "Http",
+	"createTimer":                  "Timer",
+	"waitForExternalEvent":         "ExternalEvent",
+}
+
+// NOTE: orchestrators are registered with `df.app.orchestration("name", handler)` (v3) or exported as
+// `df.orchestrator(handler)` from the directory of their function.json (v2)
+func durableOrchestratorName(module *JsModule, call JsCall) (string, bool) {
+	switch {
+	case module.CallsImport(call, "durable-functions", "app", "orchestration"):
+		name, _ := call.StringArgument(0)
+		return name, true
+	case module.CallsImport(call, "durable-functions", "orchestrator"):
+		return path.Base(path.Dir(module.Path)), true
+	default:
+		return "", false
+	}
+}
+
+// NOTE: the line the handler of an orchestrator starts at, handlers declared before their registration like
+// `const handler = function* (context) { ... }` start at their declaration
+func durableOrchestratorLine(module *JsModule, call JsCall) int {
+	for _, argument := range call.Arguments {
+		if argument.Kind != JsValueIdentifier {
+			continue
+		}
+		for _, declaration := range module.Declarations {
+			if declaration.Name == argument.Text && declaration.Line < call.Line {
+				return declaration.Line
+			}
+		}
+	}
+	return call.Line
+}
+
+// NOTE: the steps of an orchestration are the calls of `context.df` in its handler, e.g.
+// `yield context.df.callActivity("hello", input)`, every call belongs to the closest orchestrator handler above it
+func ParseDurableOrchestrations(module *JsModule) []RepositoryWorkflowData {
+	workflows := make([]RepositoryWorkflowData, 0)
+	workflowLines := make([]int, 0)
+
+	for _, call := range module.Calls {
+		name, ok := durableOrchestratorName(module, call)
+		if !ok {
+			continue
+		}
+		workflows = append(workflows, RepositoryWorkflowData{
+			Name:           name,
+			Platform:       FaaSPlatformAzure,
+			Framework:      FaaSFrameworkAzureDurableFunctions,
+			Steps:          make([]RepositoryWorkflowStepData, 0),
+			SourceFilePath: module.Path,
+			SourceFileLine: call.Line,
+		})
+		workflowLines = append(workflowLines, durableOrchestratorLine(module, call))
+	}
+
+	if len(workflows) == 0 {
+		return workflows
+	}
+
+	for _, call := range module.Calls {
+		stepType, ok := durableOrchestrationStepTypes[call.CalleeName()]
+		if !ok || !slices.Contains(call.Callee, "df") {
+			continue
+		}
+
+		workflowIndex := -1
+		for index, line := range workflowLines {
+			if line <= call.Line && (workflowIndex == -1 || line > workflowLines[workflowIndex]) {
+				workflowIndex = index
+			}
+		}
+		if workflowIndex == -1 {
+			continue
+		}
+
+		step := RepositoryWorkflowStepData{Name: call.CalleeName(), Type: stepType}
+		if name, ok := call.StringArgument(0); ok {
+			step.Name = name
+			if stepType == "Activity" || stepType == "SubOrchestration" {
+				step.Function = name
+			}
+		}
+		workflows[workflowIndex].Steps = append(workflows[workflowIndex].Steps, step)
+	}
+
+	for index := range workflows {
+		workflows[index].Functions = workflowStepFunctions(workflows[index].Steps)
+	}
+
+	return workflows
+}
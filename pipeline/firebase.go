@@ -0,0 +1,214 @@
+package main
+
+import (
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// NOTE: triggers are matched by their namespace and method, which covers both 1st gen, e.g. functions.pubsub.topic().onPublish,
+// and 2nd gen, e.g. onMessagePublished from "firebase-functions/v2/pubsub", an empty method list matches all event handlers
+var firebaseFunctionTriggers = []struct {
+	namespace      string
+	methods        []string
+	invocationType FaaSInvocationType
+}{
+	{"https", []string{"onRequest", "onCall", "onCallGenkit"}, FaaSInvocationTypeHTTP},
+	{"scheduler", []string{"onSchedule"}, FaaSInvocationTypeSchedule},
+	{"pubsub", []string{"onRun"}, FaaSInvocationTypeSchedule},
+	{"pubsub", []string{"onPublish", "onMessagePublished"}, FaaSInvocationTypeTopic},
+	{"eventarc", []string{"onCustomEventPublished"}, FaaSInvocationTypeTopic},
+	{"tasks", []string{"onDispatch", "onTaskDispatched"}, FaaSInvocationTypeQueue},
+	{"firestore", nil, FaaSInvocationTypeOther},
+	{"database", nil, FaaSInvocationTypeOther},
+	{"storage", nil, FaaSInvocationTypeOther},
+	{"auth", nil, FaaSInvocationTypeOther},
+	{"identity", nil, FaaSInvocationTypeOther},
+	{"alerts", nil, FaaSInvocationTypeOther},
+	{"remoteConfig", nil, FaaSInvocationTypeOther},
+	{"analytics", nil, FaaSInvocationTypeOther},
+	{"testLab", nil, FaaSInvocationTypeOther},
+	{"dataconnect", nil, FaaSInvocationTypeOther},
+}
+
+const firebaseFunctionsModule = "firebase-functions"
+
+// NOTE: returns the invocation type of a call defining a Cloud Function for Firebase
+func firebaseFunctionTrigger(module *JsModule, call JsCall) (FaaSInvocationType, bool) {
+	memberPath, ok := module.ImportedMemberPath(call, firebaseFunctionsModule)
+	if !ok || len(memberPath) < 2 {
+		return FaaSInvocationTypeUnknown, false
+	}
+
+	// NOTE: the version subpaths and the builders region() and runWith() of 1st gen functions are not part of the trigger
+	namespace := ""
+	for _, member := range memberPath {
+		if !slices.Contains([]string{"v1", "v2", "region()", "runWith()"}, member) {
+			namespace = strings.TrimSuffix(member, "()")
+			break
+		}
+	}
+	method := memberPath[len(memberPath)-1]
+	if !strings.HasPrefix(method, "on") && !strings.HasPrefix(method, "before") {
+		return FaaSInvocationTypeUnknown, false
+	}
+
+	for _, trigger := range firebaseFunctionTriggers {
+		if trigger.namespace == namespace && (trigger.methods == nil || slices.Contains(trigger.methods, method)) {
+			return trigger.invocationType, true
+		}
+	}
+	return FaaSInvocationTypeUnknown, false
+}
+
+type firebaseFunctionOptions struct {
+	Regions        []string
+	MemoryMB       int
+	TimeoutSeconds int
+	MinInstances   int
+}
+
+// NOTE: options objects of 2nd gen functions, setGlobalOptions and runWith of 1st gen functions share their keys
+func (options *firebaseFunctionOptions) apply(module *JsModule, value JsValue) {
+	value = module.ResolveValue(value)
+	if value.Kind != JsValueObject {
+		return
+	}
+
+	if region := value.Property("region"); region != nil {
+		if regions := jsStrings(module.ResolveValue(*region)); len(regions) > 0 {
+			options.Regions = regions
+		}
+	}
+	if memory, ok := value.StringProperty("memory"); ok {
+		if memoryMB, ok := ParseMemorySizeMB(memory); ok {
+			options.MemoryMB = memoryMB
+		}
+	}
+	if timeoutSeconds := value.Property("timeoutSeconds"); timeoutSeconds != nil && timeoutSeconds.Kind == JsValueNumber {
+		if timeoutSecondsInt, err := strconv.Atoi(timeoutSeconds.Text); err == nil {
+			options.TimeoutSeconds = timeoutSecondsInt
+		}
+	}
+	if minInstances := value.Property("minInstances"); minInstances != nil && minInstances.Kind == JsValueNumber {
+		if minInstancesInt, err := strconv.Atoi(minInstances.Text); err == nil {
+			options.MinInstances = minInstancesInt
+		}
+	}
+}
+
+// NOTE: the codebases of firebase.json are deployed from their source directory with their runtime, e.g.
+// `"functions": [{ "source": "functions", "runtime": "nodejs20" }]`, the source defaults to "functions"
+func firebaseCodebaseRuntimes(configFile TextFile, config interface{}) map[string]string {
+	result := make(map[string]string)
+
+	codebases, err := JsonResolve(config, []string{"functions"})
+	if err != nil {
+		return result
+	}
+	codebaseArray, ok := codebases.([]interface{})
+	if !ok {
+		codebaseArray = []interface{}{codebases}
+	}
+	for _, codebase := range codebaseArray {
+		runtime, err := JsonResolveString(codebase, []string{"runtime"})
+		if err != nil {
+			continue
+		}
+		source, err := JsonResolveString(codebase, []string{"source"})
+		if err != nil {
+			source = "functions"
+		}
+		result[path.Join(path.Dir(configFile.Path), source)] = runtime
+	}
+	return result
+}
+
+// NOTE: the runtime of the codebase containing a source file, codebases may be nested in the directory of others
+func firebaseFunctionRuntime(codebaseRuntimes map[string]string, filePath string) string {
+	runtime := ""
+	runtimeDirectory := ""
+	for directory, codebaseRuntime := range codebaseRuntimes {
+		if (directory == "." || strings.HasPrefix(filePath, directory+"/")) && len(directory) > len(runtimeDirectory) {
+			runtime = codebaseRuntime
+			runtimeDirectory = directory
+		}
+	}
+	return runtime
+}
+
+func jsStrings(value JsValue) []string {
+	switch value.Kind {
+	case JsValueString:
+		return []string{value.Text}
+	case JsValueArray:
+		result := make([]string, 0, len(value.Elements))
+		for _, element := range value.Elements {
+			if element.Kind == JsValueString {
+				result = append(result, element.Text)
+			}
+		}
+		return result
+	default:
+		return nil
+	}
+}
+
+// NOTE: 1st gen functions are configured by builders, e.g. functions.region("a").runWith({ ... }).https.onRequest(fn),
+// 2nd gen functions take the options as first argument before the handler, e.g. onRequest({ region: "a" }, fn)
+func firebaseFunctionCallOptions(module *JsModule, call JsCall, globalOptions firebaseFunctionOptions) firebaseFunctionOptions {
+	options := firebaseFunctionOptions{MemoryMB: -1, TimeoutSeconds: -1}
+	if !isFirstGenFirebaseFunction(module, call) {
+		options = globalOptions
+	}
+
+	for _, chainCall := range module.ChainCalls(call) {
+		switch chainCall.CalleeName() {
+		case "region":
+			regions := make([]string, 0, len(chainCall.Arguments))
+			for _, argument := range chainCall.Arguments {
+				regions = append(regions, jsStrings(module.ResolveValue(argument))...)
+			}
+			if len(regions) > 0 {
+				options.Regions = regions
+			}
+		case "runWith":
+			if argument := chainCall.Argument(0); argument != nil {
+				options.apply(module, *argument)
+			}
+		}
+	}
+
+	if len(call.Arguments) >= 2 {
+		options.apply(module, call.Arguments[0])
+	}
+	return options
+}
+
+// NOTE: 1st gen functions are imported from the package itself or "firebase-functions/v1", as the package exports
+// 2nd gen functions only since version 6, older codebases are the majority
+func isFirstGenFirebaseFunction(module *JsModule, call JsCall) bool {
+	if len(call.Callee) == 0 {
+		return false
+	}
+	for _, imported := range module.Imports {
+		if imported.Source != firebaseFunctionsModule && imported.Source != firebaseFunctionsModule+"/v1" {
+			continue
+		}
+		local := call.Callee[0]
+		if imported.Names[local] != "" || local == imported.DefaultName || local == imported.NamespaceName {
+			return true
+		}
+	}
+	return false
+}
+
+// NOTE: setGlobalOptions applies to all 2nd gen functions of a codebase, usually it is called once in the entry point
+func (options *firebaseFunctionOptions) applyGlobalOptions(module *JsModule) {
+	for _, call := range module.CallsTo("setGlobalOptions") {
+		if argument := call.Argument(0); argument != nil && module.ImportsModule(firebaseFunctionsModule) {
+			options.apply(module, *argument)
+		}
+	}
+}
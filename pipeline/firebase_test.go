@@ -0,0 +1,83 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestScanFirebaseClassifiesTriggers(t *testing.T) {
+	findings := scanTestRepository(t, "firebase", map[string]string{
+		"firebase.json": `{ "functions": [{ "source": "functions", "runtime": "nodejs20" }] }`,
+		"functions/v1.js": `const functions = require("firebase-functions");
+
+exports.api = functions
+  .region("europe-west1")
+  .runWith({ memory: "1GB", timeoutSeconds: 60 })
+  .https.onRequest(app);
+exports.nightly = functions.pubsub.schedule("every 24 hours").onRun(() => null);
+exports.users = {
+  created: functions.auth.user().onCreate(() => null),
+};
+// exports.disabled = functions.https.onRequest(app);
+`,
+		"functions/v2.ts": `import { setGlobalOptions } from "firebase-functions/v2";
+import { onRequest } from "firebase-functions/v2/https";
+import { onMessagePublished } from "firebase-functions/v2/pubsub";
+import * as tasks from "firebase-functions/v2/tasks";
+
+setGlobalOptions({ region: "us-central1", memory: "512MiB" });
+
+export const hello = onRequest({ minInstances: 1, timeoutSeconds: 30 }, (request, response) => {
+  response.send("hello");
+});
+export const published = onMessagePublished("events", async (event) => {});
+export const dispatched = tasks.onTaskDispatched(async (request) => {});
+export const helper = (value: string) => value.trim();
+`,
+	}, "firebase-functions")
+
+	tests := []struct {
+		name           string
+		invocationType FaaSInvocationType
+		regions        []string
+		memoryMB       int
+		timeoutSeconds int
+		minInstances   int
+		line           int
+	}{
+		{"api", FaaSInvocationTypeHTTP, []string{"europe-west1"}, 1024, 60, 0, 3},
+		{"nightly", FaaSInvocationTypeSchedule, nil, -1, -1, 0, 7},
+		{"users-created", FaaSInvocationTypeOther, nil, -1, -1, 0, 9},
+		{"hello", FaaSInvocationTypeHTTP, []string{"us-central1"}, 512, 30, 1, 8},
+		{"published", FaaSInvocationTypeTopic, []string{"us-central1"}, 512, -1, 0, 11},
+		{"dispatched", FaaSInvocationTypeQueue, []string{"us-central1"}, 512, -1, 0, 12},
+	}
+
+	functions := testFunctionsByName(t, findings)
+	if len(functions) != len(tests) {
+		t.Errorf("expected %d functions, got %d", len(tests), len(functions))
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			function, ok := functions[test.name]
+			if !ok {
+				t.Fatalf("function %s was not found", test.name)
+			}
+			if function.InvocationType != test.invocationType {
+				t.Errorf("expected invocation type %s, got %s", test.invocationType, function.InvocationType)
+			}
+			if !slices.Equal(function.Regions, test.regions) {
+				t.Errorf("expected regions %v, got %v", test.regions, function.Regions)
+			}
+			if function.MemoryMB != test.memoryMB || function.TimeoutSeconds != test.timeoutSeconds || function.MinInstances != test.minInstances {
+				t.Errorf("expected %d MB, %d s and %d min instances, got %d MB, %d s and %d min instances", test.memoryMB, test.timeoutSeconds, test.minInstances, function.MemoryMB, function.TimeoutSeconds, function.MinInstances)
+			}
+			if function.Runtime != "nodejs20" {
+				t.Errorf("expected the runtime of the codebase, got %q", function.Runtime)
+			}
+			if function.SourceFileLine != test.line {
+				t.Errorf("expected line %d, got %d", test.line, function.SourceFileLine)
+			}
+		})
+	}
+}
@@ -0,0 +1,258 @@
+package main
+
+import (
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// NOTE: the source files a handler module is looked up as, in order, compiled JavaScript is preferred like by the
+// platforms themselves
+var functionHandlerExtensions = []string{".js", ".mjs", ".cjs", ".jsx", ".ts", ".mts", ".cts", ".tsx", ".py"}
+
+// NOTE: handlers configured as directory point to an entry file, e.g. the handler.js of OpenFaaS templates or the
+// index.js of Azure Functions
+var functionHandlerEntryFiles = []string{"index", "handler", "main"}
+
+var pythonFunctionDefinitionRegexp = regexp.MustCompile(`(?m)^[ \t]*(?:async\s+)?def\s+(\w+)\s*\(`)
+
+// NOTE: splits a handler as configured into the module and the export, e.g. "src/handler.main" into "src/handler"
+// and "main" and "dist/index.js:run" of an Azure function.json into "dist/index.js" and "run", source files like
+// "src/index.js" of Cloudflare Workers and directories like "./hello" of OpenFaaS have no export
+func splitFunctionHandler(handler string) (string, string) {
+	if module, export, ok := strings.Cut(handler, ":"); ok {
+		return module, export
+	}
+	extension := path.Ext(handler)
+	if extension == "" || slices.Contains(functionHandlerExtensions, extension) {
+		return handler, ""
+	}
+	return strings.TrimSuffix(handler, extension), extension[1:]
+}
+
+// NOTE: handlers are configured relative to the configuration file or to the root of the project, which may be any
+// parent directory, e.g. SST and CDK stacks live in a subdirectory of the project
+func functionHandlerModuleFile(module string, configFilePath string, files map[string]TextFile) (TextFile, bool) {
+	modulePaths := make([]string, 0)
+	for directory := path.Dir(configFilePath); ; directory = path.Dir(directory) {
+		modulePaths = append(modulePaths, path.Join(directory, module))
+		if directory == path.Dir(directory) {
+			break
+		}
+	}
+
+	for _, modulePath := range modulePaths {
+		if slices.Contains(functionHandlerExtensions, path.Ext(modulePath)) {
+			if file, ok := files[modulePath]; ok {
+				return file, true
+			}
+			continue
+		}
+		for _, extension := range functionHandlerExtensions {
+			if file, ok := files[modulePath+extension]; ok {
+				return file, true
+			}
+		}
+		for _, entryFile := range functionHandlerEntryFiles {
+			for _, extension := range functionHandlerExtensions {
+				if file, ok := files[path.Join(modulePath, entryFile+extension)]; ok {
+					return file, true
+				}
+			}
+		}
+	}
+	return TextFile{}, false
+}
+
+// NOTE: returns the export handling the invocations and its line, handlers without a configured export are the
+// default export or the export typed as handler
+func functionHandlerExport(file TextFile, export string, jsModules *JsModuleCache) (string, int, bool) {
+	if path.Ext(file.Path) == ".py" {
+		for _, match := range pythonFunctionDefinitionRegexp.FindAllStringSubmatchIndex(file.Content, -1) {
+			if name := file.Content[match[2]:match[3]]; name == export {
+				return name, strings.Count(file.Content[:match[2]], "\n") + 1, true
+			}
+		}
+		return "", -1, false
+	}
+
+	module := jsModules.Module(file)
+	if export != "" {
+		if jsExport := module.Export(export); jsExport != nil {
+			return jsExport.Name, jsExport.Line, true
+		}
+		return "", -1, false
+	}
+	if jsExport := module.Export("default"); jsExport != nil {
+		return jsExport.Name, jsExport.Line, true
+	}
+	if handlers := faasHandlerExports(module); len(handlers) > 0 {
+		return handlers[0].Name, handlers[0].Line, true
+	}
+	return "", -1, false
+}
+
+// NOTE: functions found in source files are handled there, e.g. `exports.api = onRequest(...)` of Firebase, the
+// export is the one at the line the function was found at or the one named like the handler
+func sourceFunctionHandlerExport(function RepositoryFaaSFunctionData, file TextFile, jsModules *JsModuleCache) (string, int, bool) {
+	if path.Ext(file.Path) == ".py" {
+		return functionHandlerExport(file, function.Handler, jsModules)
+	}
+
+	module := jsModules.Module(file)
+	for _, jsExport := range module.Exports {
+		if jsExport.Line == function.SourceFileLine {
+			return jsExport.Name, jsExport.Line, true
+		}
+	}
+	for _, name := range []string{function.Handler, function.Name} {
+		if jsExport := module.Export(name); name != "" && jsExport != nil {
+			return jsExport.Name, jsExport.Line, true
+		}
+	}
+	return "", -1, false
+}
+
+// NOTE: sets the source file, export and line of the handler of every function found, functions found in source
+// files are handled in these, functions found in configurations by the handler configured
+func resolveFunctionHandlers(functions []RepositoryFaaSFunctionData, files []TextFile, jsModules *JsModuleCache) {
+	filesByPath := make(map[string]TextFile, len(files))
+	for _, file := range files {
+		filesByPath[file.Path] = file
+	}
+
+	for index := range functions {
+		function := &functions[index]
+		function.HandlerFilePath = ""
+		function.HandlerExport = ""
+		function.HandlerFileLine = -1
+
+		// NOTE: handlers configured in code are mostly names, e.g. of Azure Functions, but paths for CDK and SST
+		configuredHandler := false
+		if strings.ContainsAny(function.Handler, "./") && function.SourceFilePath != "" {
+			module, export := splitFunctionHandler(function.Handler)
+			if file, ok := functionHandlerModuleFile(module, function.SourceFilePath, filesByPath); ok {
+				configuredHandler = true
+				function.HandlerFilePath = file.Path
+				function.HandlerExport = export
+				if export, line, ok := functionHandlerExport(file, export, jsModules); ok {
+					function.HandlerExport = export
+					function.HandlerFileLine = line
+				}
+			}
+		}
+
+		if file, ok := filesByPath[function.SourceFilePath]; ok && !configuredHandler && slices.Contains(functionHandlerExtensions, path.Ext(file.Path)) {
+			function.HandlerFilePath = file.Path
+			function.HandlerFileLine = function.SourceFileLine
+			if export, line, ok := sourceFunctionHandlerExport(*function, file, jsModules); ok {
+				function.HandlerExport = export
+				function.HandlerFileLine = line
+			}
+		}
+
+		// NOTE: the export names functions not named by their configuration, default exports are named by the file
+		if function.Name == "" && function.HandlerExport != "" && function.HandlerExport != "default" {
+			function.Name = function.HandlerExport
+		}
+	}
+}
+
+// NOTE: the same function is found by several scanners if it is configured with several tools, e.g. with a
+// serverless.yml and a Terraform configuration, or by the framework and the platform, e.g. a Hono app deployed as
+// Cloudflare Worker, these are identified by their handler, functions handled by a whole file by their name as well
+func isDuplicateFunction(function RepositoryFaaSFunctionData, other RepositoryFaaSFunctionData) bool {
+	switch {
+	case function.HandlerFilePath == "" || function.HandlerFilePath != other.HandlerFilePath:
+		return false
+	case function.Platform != other.Platform && function.Platform != FaaSPlatformUnknown && other.Platform != FaaSPlatformUnknown:
+		return false
+	case function.Framework == other.Framework || slices.Contains(function.DuplicateFrameworks, other.Framework):
+		return false
+	}
+
+	isFileHandler := func(export string) bool { return export == "" || export == "default" }
+	if isFileHandler(function.HandlerExport) || isFileHandler(other.HandlerExport) {
+		return function.Name == "" || other.Name == "" || function.Name == other.Name
+	}
+	return function.HandlerExport == other.HandlerExport
+}
+
+// NOTE: copies the configuration the first finding lacks from a duplicate of it
+func mergeDuplicateFunction(function *RepositoryFaaSFunctionData, duplicate RepositoryFaaSFunctionData) {
+	if function.Name == "" {
+		function.Name = duplicate.Name
+	}
+	if function.Platform == FaaSPlatformUnknown {
+		function.Platform = duplicate.Platform
+	}
+	if function.HandlerExport == "" {
+		function.HandlerExport = duplicate.HandlerExport
+		function.HandlerFileLine = duplicate.HandlerFileLine
+	}
+	if function.InvocationType == FaaSInvocationTypeUnknown {
+		function.InvocationType = duplicate.InvocationType
+	}
+	if function.Location == FaaSLocationUnknown {
+		function.Location = duplicate.Location
+	}
+	if len(function.Regions) == 0 {
+		function.Regions = duplicate.Regions
+	}
+	if function.Runtime == "" {
+		function.Runtime = duplicate.Runtime
+	}
+	if function.MemoryMB == -1 {
+		function.MemoryMB = duplicate.MemoryMB
+	}
+	if function.TimeoutSeconds == -1 {
+		function.TimeoutSeconds = duplicate.TimeoutSeconds
+	}
+	if function.Architecture == "" {
+		function.Architecture = duplicate.Architecture
+	}
+	function.ProvisionedConcurrency = max(function.ProvisionedConcurrency, duplicate.ProvisionedConcurrency)
+	function.ReservedConcurrency = max(function.ReservedConcurrency, duplicate.ReservedConcurrency)
+	function.MinInstances = max(function.MinInstances, duplicate.MinInstances)
+	if function.Handler == "" {
+		function.Handler = duplicate.Handler
+	}
+	for attribute, value := range duplicate.Attributes {
+		if function.Attributes == nil {
+			function.Attributes = make(map[string]string)
+		}
+		if _, ok := function.Attributes[attribute]; !ok {
+			function.Attributes[attribute] = value
+		}
+	}
+	if len(duplicate.Bindings) > 0 {
+		function.Bindings = UniqueSliceElements(slices.Concat(function.Bindings, duplicate.Bindings))
+	}
+	if len(duplicate.Resources) > 0 {
+		function.Resources = addFunctionResources(slices.Clone(function.Resources), duplicate.Resources)
+	}
+	function.DuplicateFrameworks = append(function.DuplicateFrameworks, duplicate.Framework)
+}
+
+// NOTE: merges functions found by scanners of different frameworks into the first finding, functions of the same
+// framework sharing a handler are deployed several times, e.g. with different triggers, and are kept
+func deduplicateFunctions(functions []RepositoryFaaSFunctionData) []RepositoryFaaSFunctionData {
+	result := make([]RepositoryFaaSFunctionData, 0, len(functions))
+	functionIndices := make(map[string][]int)
+
+	for _, function := range functions {
+		index := slices.IndexFunc(functionIndices[function.HandlerFilePath], func(index int) bool {
+			return isDuplicateFunction(result[index], function)
+		})
+		if index != -1 {
+			mergeDuplicateFunction(&result[functionIndices[function.HandlerFilePath][index]], function)
+			continue
+		}
+
+		functionIndices[function.HandlerFilePath] = append(functionIndices[function.HandlerFilePath], len(result))
+		result = append(result, function)
+	}
+
+	return result
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestResolveAndDeduplicateFunctionHandlers(t *testing.T) {
+	files := testRepositoryFiles(map[string]string{
+		"serverless.yml": `service: shop
+provider:
+  name: aws
+  runtime: nodejs20.x
+functions:
+  api:
+    handler: src/api.handler
+    events:
+      - httpApi: "*"
+  worker:
+    handler: src/worker.process
+    events:
+      - sqs: arn:aws:sqs:eu-west-1:123456789012:jobs
+  retry:
+    handler: src/worker.process
+    events:
+      - schedule: rate(1 hour)
+`,
+		"infra/main.tf": `resource "aws_lambda_function" "api" {
+  function_name = "api"
+  handler       = "src/api.handler"
+  runtime       = "nodejs20.x"
+}
+
+resource "aws_lambda_function" "cleanup" {
+  function_name = "cleanup"
+  handler       = "cleanup.run"
+  runtime       = "python3.12"
+}
+
+resource "aws_lambda_function" "missing" {
+  function_name = "missing"
+  handler       = "missing.handler"
+  runtime       = "nodejs20.x"
+}
+`,
+		"infra/cleanup.py": "import boto3\n\n\ndef run(event, context):\n    return None\n",
+		"src/api.ts":       "import type { Handler } from \"aws-lambda\";\n\nexport const handler: Handler = async (event) => {\n  return { statusCode: 200 };\n};\n",
+		"src/worker.js":    "// NOTE: compiled from worker.ts\nexports.process = async (event) => {};\n",
+		"src/worker.ts":    "export async function process(event) {}\n",
+	})
+
+	registry := NewScannerRegistry()
+	registry.Register(DefaultScannerRegistry().Lookup("serverless"))
+	registry.Register(DefaultScannerRegistry().Lookup("terraform"))
+	jsModules := NewJsModuleCache()
+	findings, reports := registry.Scan(files, nil, nil, jsModules, NewKubernetesManifestCache())
+	for _, report := range reports {
+		if report.Error != "" {
+			t.Fatalf("scanner %s failed: %s", report.Scanner, report.Error)
+		}
+	}
+
+	resolveFunctionHandlers(findings.Functions, files, jsModules)
+	findings.Functions = deduplicateFunctions(findings.Functions)
+
+	tests := []struct {
+		name                string
+		framework           FaaSFramework
+		handlerFilePath     string
+		handlerExport       string
+		handlerFileLine     int
+		duplicateFrameworks []FaaSFramework
+	}{
+		{"api", FaaSFrameworkServerless, "src/api.ts", "handler", 3, []FaaSFramework{FaaSFrameworkTerraform}},
+		{"worker", FaaSFrameworkServerless, "src/worker.js", "process", 2, nil},
+		{"retry", FaaSFrameworkServerless, "src/worker.js", "process", 2, nil},
+		{"cleanup", FaaSFrameworkTerraform, "infra/cleanup.py", "run", 4, nil},
+		{"missing", FaaSFrameworkTerraform, "", "", -1, nil},
+	}
+
+	functions := testFunctionsByName(t, findings)
+	if len(functions) != len(tests) {
+		t.Errorf("expected %d functions, got %d", len(tests), len(functions))
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			function, ok := functions[test.name]
+			if !ok {
+				t.Fatalf("function %s was not found", test.name)
+			}
+			if function.Framework != test.framework {
+				t.Errorf("expected the framework %s, got %s", test.framework, function.Framework)
+			}
+			handlerFilePath := ""
+			if test.handlerFilePath != "" {
+				handlerFilePath = testRepositoryDirectory + "/" + test.handlerFilePath
+			}
+			if function.HandlerFilePath != handlerFilePath || function.HandlerExport != test.handlerExport || function.HandlerFileLine != test.handlerFileLine {
+				t.Errorf("expected the handler %s:%s at line %d, got %s:%s at line %d", handlerFilePath, test.handlerExport, test.handlerFileLine, function.HandlerFilePath, function.HandlerExport, function.HandlerFileLine)
+			}
+			if !slices.Equal(function.DuplicateFrameworks, test.duplicateFrameworks) {
+				t.Errorf("expected the duplicate frameworks %v, got %v", test.duplicateFrameworks, function.DuplicateFrameworks)
+			}
+		})
+	}
+}
@@ -0,0 +1,291 @@
+package main
+
+import (
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// NOTE: CloudEvent types look like `google.cloud.pubsub.topic.v1.messagePublished`, legacy event types of 1st gen
+// functions like `providers/cloud.pubsub/eventTypes/topic.publish` or `google.storage.object.finalize`
+var gcpEventTypeInvocationTypes = []struct {
+	service        string
+	invocationType FaaSInvocationType
+}{
+	{"pubsub", FaaSInvocationTypeTopic},
+	{"scheduler", FaaSInvocationTypeSchedule},
+	{"storage", FaaSInvocationTypeOther},
+	{"firestore", FaaSInvocationTypeOther},
+	{"firebase", FaaSInvocationTypeOther},
+	{"audit", FaaSInvocationTypeOther},
+}
+
+func gcpEventTypeInvocationType(eventType string) FaaSInvocationType {
+	for _, eventTypeInvocationType := range gcpEventTypeInvocationTypes {
+		if strings.Contains(eventType, eventTypeInvocationType.service) {
+			return eventTypeInvocationType.invocationType
+		}
+	}
+	return FaaSInvocationTypeOther
+}
+
+var gcpEventTypeRegexp = regexp.MustCompile(`["'` + "`" + `]((?:google\.cloud|google\.(?:pubsub|storage|firebase)|providers/cloud\.\w+)[\w./-]*)["'` + "`" + `]`)
+
+// NOTE: typed handlers of @google/events and @google-cloud/functions-framework, e.g. `CloudEvent<MessagePublishedData>`
+var gcpCloudEventDataTypeRegexp = regexp.MustCompile(`CloudEvent<\s*(?:\w+\.)*(MessagePublishedData|StorageObjectData|DocumentEventData|LogEntryData|SchedulerJobData)\s*>`)
+
+var gcpCloudEventDataTypeInvocationTypes = map[string]FaaSInvocationType{
+	"MessagePublishedData": FaaSInvocationTypeTopic,
+	"StorageObjectData":    FaaSInvocationTypeOther,
+	"DocumentEventData":    FaaSInvocationTypeOther,
+	"LogEntryData":         FaaSInvocationTypeOther,
+	"SchedulerJobData":     FaaSInvocationTypeSchedule,
+}
+
+// NOTE: the event types a CloudEvent handler checks for or the data type it declares, unknown if they disagree
+func gcpCloudEventInvocationType(content string) FaaSInvocationType {
+	invocationTypes := make(map[FaaSInvocationType]bool)
+	for _, match := range gcpEventTypeRegexp.FindAllStringSubmatch(content, -1) {
+		invocationTypes[gcpEventTypeInvocationType(match[1])] = true
+	}
+	for _, match := range gcpCloudEventDataTypeRegexp.FindAllStringSubmatch(content, -1) {
+		invocationTypes[gcpCloudEventDataTypeInvocationTypes[match[1]]] = true
+	}
+
+	if len(invocationTypes) != 1 {
+		return FaaSInvocationTypeUnknown
+	}
+	for invocationType := range invocationTypes {
+		return invocationType
+	}
+	return FaaSInvocationTypeUnknown
+}
+
+// NOTE: timeouts are given as plain seconds or durations like `60s`, `5m` or `1h30m`
+func ParseDurationSeconds(duration string) (int, bool) {
+	duration = strings.TrimSpace(duration)
+	if seconds, err := strconv.Atoi(duration); err == nil {
+		return seconds, true
+	}
+	parsedDuration, err := time.ParseDuration(duration)
+	if err != nil {
+		return 0, false
+	}
+	return int(parsedDuration.Seconds()), true
+}
+
+type GCloudCommand struct {
+	Group  string // functions, run, run jobs or eventarc triggers
+	Action string // deploy or create
+	Name   string
+	Flags  map[string]string
+	Line   int
+}
+
+var gcloudCommandRegexp = regexp.MustCompile(`gcloud\s+(?:(?:alpha|beta)\s+)?(functions|run\s+jobs|run|eventarc\s+triggers)\s+(deploy|create)\b([^\n;&|]*)`)
+
+// NOTE: flags without a value, all other flags take the next argument unless it is given as `--flag=value`
+var gcloudBooleanFlags = []string{
+	"--gen2", "--no-gen2", "--trigger-http", "--allow-unauthenticated", "--no-allow-unauthenticated", "--quiet",
+	"--async", "--retry", "--source-only", "--execute-now", "--wait", "--no-traffic", "--use-http2", "--cpu-boost",
+	"--no-cpu-throttling", "--cpu-throttling", "--default-url", "--no-default-url",
+}
+
+var gcloudContinuationRegexp = regexp.MustCompile(`\\\r?\n\s*`)
+
+// NOTE: deploy commands in shell scripts, Makefiles, package.json scripts and CI workflows, lines continued with a
+// backslash are joined first while keeping the line of the command itself
+func ParseGCloudCommands(content string) []GCloudCommand {
+	commands := make([]GCloudCommand, 0)
+
+	// NOTE: continued lines are replaced by NUL characters to keep track of line numbers
+	joined := gcloudContinuationRegexp.ReplaceAllStringFunc(content, func(continuation string) string {
+		return " " + strings.Repeat("\x00", strings.Count(continuation, "\n"))
+	})
+
+	for _, match := range gcloudCommandRegexp.FindAllStringSubmatchIndex(joined, -1) {
+		command := GCloudCommand{
+			Group:  strings.Join(strings.Fields(joined[match[2]:match[3]]), " "),
+			Action: joined[match[4]:match[5]],
+			Flags:  make(map[string]string),
+			Line:   strings.Count(joined[:match[0]], "\n") + strings.Count(joined[:match[0]], "\x00") + 1,
+		}
+
+		arguments := splitShellArguments(strings.ReplaceAll(joined[match[6]:match[7]], "\x00", ""))
+		for index := 0; index < len(arguments); index++ {
+			argument := arguments[index]
+			if !strings.HasPrefix(argument, "--") {
+				if command.Name == "" {
+					command.Name = argument
+				}
+				continue
+			}
+
+			if flag, value, ok := strings.Cut(argument, "="); ok {
+				command.Flags[flag] = value
+			} else if !slices.Contains(gcloudBooleanFlags, argument) && index+1 < len(arguments) && !strings.HasPrefix(arguments[index+1], "--") {
+				command.Flags[argument] = arguments[index+1]
+				index++
+			} else {
+				command.Flags[argument] = ""
+			}
+		}
+
+		commands = append(commands, command)
+	}
+
+	return commands
+}
+
+func (command GCloudCommand) HasFlag(flag string) bool {
+	_, ok := command.Flags[flag]
+	return ok
+}
+
+// NOTE: `--trigger-event-filters=type=google.cloud.storage.object.v1.finalized,bucket=b` and `--event-filters` of
+// Eventarc triggers can be given multiple times, only the last one is kept which is fine for the type
+func (command GCloudCommand) EventType() (string, bool) {
+	for _, flag := range []string{"--trigger-event-filters", "--event-filters", "--trigger-event"} {
+		value, ok := command.Flags[flag]
+		if !ok {
+			continue
+		}
+		for _, filter := range strings.Split(value, ",") {
+			if key, eventType, ok := strings.Cut(filter, "="); ok && key == "type" {
+				return eventType, true
+			}
+		}
+		if flag == "--trigger-event" {
+			return value, true
+		}
+	}
+	return "", false
+}
+
+func (command GCloudCommand) InvocationType() FaaSInvocationType {
+	switch command.Group {
+	case "functions":
+		switch {
+		case command.HasFlag("--trigger-topic"):
+			return FaaSInvocationTypeTopic
+		case command.HasFlag("--trigger-bucket"):
+			return FaaSInvocationTypeOther
+		}
+		if eventType, ok := command.EventType(); ok {
+			return gcpEventTypeInvocationType(eventType)
+		}
+		return FaaSInvocationTypeHTTP
+	case "run":
+		return FaaSInvocationTypeHTTP
+	case "run jobs":
+		return FaaSInvocationTypeOther
+	default:
+		return FaaSInvocationTypeUnknown
+	}
+}
+
+func splitShellArguments(commandLine string) []string {
+	arguments := make([]string, 0)
+
+	var sb strings.Builder
+	quote := rune(0)
+	inArgument := false
+	for _, character := range commandLine {
+		switch {
+		case quote != 0 && character == quote:
+			quote = 0
+		case quote != 0:
+			sb.WriteRune(character)
+		case character == '"' || character == '\'':
+			quote = character
+			inArgument = true
+		case character == ' ' || character == '\t' || character == '\r':
+			if inArgument {
+				arguments = append(arguments, sb.String())
+				sb.Reset()
+				inArgument = false
+			}
+		default:
+			sb.WriteRune(character)
+			inArgument = true
+		}
+	}
+	if inArgument {
+		arguments = append(arguments, sb.String())
+	}
+
+	return arguments
+}
+
+// NOTE: Cloud Build steps run gcloud with separate arguments, e.g. `entrypoint: gcloud` and
+// `args: ['functions', 'deploy', 'name', '--gen2']`, which are joined to a command line
+func cloudBuildGCloudCommandLines(cloudBuildJson interface{}) []string {
+	commandLines := make([]string, 0)
+
+	steps, err := JsonResolveArray(cloudBuildJson, []string{"steps"})
+	if err != nil {
+		return commandLines
+	}
+	for _, step := range steps {
+		arguments, err := JsonResolveArray(step, []string{"args"})
+		if err != nil {
+			continue
+		}
+		name, _ := JsonResolveString(step, []string{"name"})
+		entrypoint, _ := JsonResolveString(step, []string{"entrypoint"})
+		if entrypoint != "gcloud" && !(entrypoint == "" && strings.Contains(name, "cloud-sdk")) {
+			continue
+		}
+
+		argumentStrings := []string{"gcloud"}
+		for _, argument := range arguments {
+			argumentString, ok := argument.(string)
+			if !ok {
+				continue
+			}
+			if strings.ContainsAny(argumentString, " \t\"'") {
+				argumentString = strconv.Quote(argumentString)
+			}
+			argumentStrings = append(argumentStrings, argumentString)
+		}
+		commandLines = append(commandLines, strings.Join(argumentStrings, " "))
+	}
+
+	return commandLines
+}
+
+// NOTE: Cloud Run manifests use the Knative serving API, they are told apart by the annotations and labels of Cloud
+// Run, e.g. `run.googleapis.com/ingress` or `cloud.googleapis.com/location`
+func isCloudRunManifest(manifestJson interface{}) bool {
+	apiVersion, _ := JsonResolveString(manifestJson, []string{"apiVersion"})
+	if strings.HasPrefix(apiVersion, "run.googleapis.com/") {
+		return true
+	}
+
+	var containsCloudRunKey func(value interface{}) bool
+	containsCloudRunKey = func(value interface{}) bool {
+		switch value := value.(type) {
+		case map[string]interface{}:
+			for key, child := range value {
+				if strings.HasPrefix(key, "run.googleapis.com/") || strings.HasPrefix(key, "cloud.googleapis.com/") {
+					return true
+				}
+				if containsCloudRunKey(child) {
+					return true
+				}
+			}
+		case []interface{}:
+			for _, child := range value {
+				if containsCloudRunKey(child) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	metadata, _ := JsonResolve(manifestJson, []string{"metadata"})
+	templateMetadata, _ := JsonResolve(manifestJson, []string{"spec", "template", "metadata"})
+	return containsCloudRunKey(metadata) || containsCloudRunKey(templateMetadata)
+}
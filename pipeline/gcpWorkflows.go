@@ -0,0 +1,175 @@
+package main
+
+import (
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+
+	"golang.org/x/exp/maps"
+)
+
+// NOTE: the keys of a step of the GCP Workflows syntax in the order they determine its type, e.g.
+// `- getUser: { call: http.get, args: { url: ... }, result: user }`
+var gcpWorkflowStepKeys = []string{"call", "switch", "for", "parallel", "try", "raise", "return", "assign", "steps", "next"}
+var gcpWorkflowStepOptionKeys = []string{"args", "result", "retry", "except", "branches", "shared", "concurrency_limit"}
+
+var gcpFunctionUrlRegexp = regexp.MustCompile(`\.cloudfunctions\.net/([\w-]+)`)
+var gcpFunctionResourceRegexp = regexp.MustCompile(`/functions/([\w-]+)$`)
+
+// NOTE: workflows are either a list of steps or a map of subworkflows with their `steps`, one of them called `main`,
+// steps are maps with a single key naming the step
+func isGCPWorkflowDefinition(definition interface{}) bool {
+	switch definition := definition.(type) {
+	case []interface{}:
+		return len(definition) > 0 && isGCPWorkflowSteps(definition)
+	case map[string]interface{}:
+		if _, ok := definition["main"]; !ok {
+			return false
+		}
+		for _, subworkflow := range definition {
+			steps, err := JsonResolveArray(subworkflow, []string{"steps"})
+			if err != nil || !isGCPWorkflowSteps(steps) {
+				return false
+			}
+		}
+		return true
+	default:
+		return false
+	}
+}
+
+func isGCPWorkflowSteps(steps []interface{}) bool {
+	for _, step := range steps {
+		stepMap, ok := step.(map[string]interface{})
+		if !ok || len(stepMap) != 1 {
+			return false
+		}
+		for _, body := range stepMap {
+			bodyMap, ok := body.(map[string]interface{})
+			if !ok || len(bodyMap) == 0 {
+				return false
+			}
+			for key := range bodyMap {
+				if !slices.Contains(gcpWorkflowStepKeys, key) && !slices.Contains(gcpWorkflowStepOptionKeys, key) {
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
+
+// NOTE: the steps of a workflow definition including the steps nested in `switch`, `for`, `parallel` and `try`,
+// subworkflows are sorted by their names
+func ParseGCPWorkflowSteps(definition interface{}) []RepositoryWorkflowStepData {
+	switch definition := definition.(type) {
+	case []interface{}:
+		return gcpWorkflowSteps(definition)
+	case map[string]interface{}:
+		steps := make([]RepositoryWorkflowStepData, 0)
+		names := maps.Keys(definition)
+		slices.Sort(names)
+		for _, name := range names {
+			subworkflowSteps, _ := JsonResolveArray(definition[name], []string{"steps"})
+			steps = append(steps, gcpWorkflowSteps(subworkflowSteps)...)
+		}
+		return steps
+	default:
+		return make([]RepositoryWorkflowStepData, 0)
+	}
+}
+
+func gcpWorkflowSteps(steps []interface{}) []RepositoryWorkflowStepData {
+	result := make([]RepositoryWorkflowStepData, 0)
+
+	for _, step := range steps {
+		stepMap, ok := step.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for name, body := range stepMap {
+			bodyMap, ok := body.(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			workflowStep := RepositoryWorkflowStepData{Name: name}
+			for _, key := range gcpWorkflowStepKeys {
+				if _, ok := bodyMap[key]; ok {
+					workflowStep.Type = key
+					break
+				}
+			}
+			if workflowStep.Type == "call" {
+				workflowStep.Function = gcpWorkflowCallFunction(bodyMap)
+			}
+			result = append(result, workflowStep)
+
+			for _, key := range []string{"switch", "for", "parallel", "try", "except", "steps"} {
+				if nested, ok := bodyMap[key]; ok {
+					result = append(result, gcpWorkflowNestedSteps(key, nested)...)
+				}
+			}
+		}
+	}
+
+	return result
+}
+
+// NOTE: e.g. the `steps` of `for`, every `branches` of `parallel` or the conditions of `switch`
+func gcpWorkflowNestedSteps(key string, value interface{}) []RepositoryWorkflowStepData {
+	switch value := value.(type) {
+	case []interface{}:
+		if key == "steps" || key == "branches" {
+			return gcpWorkflowSteps(value)
+		}
+		result := make([]RepositoryWorkflowStepData, 0)
+		for _, element := range value {
+			result = append(result, gcpWorkflowNestedSteps("", element)...)
+		}
+		return result
+	case map[string]interface{}:
+		result := make([]RepositoryWorkflowStepData, 0)
+		for _, nestedKey := range []string{"steps", "branches", "for", "call"} {
+			nested, ok := value[nestedKey]
+			if !ok {
+				continue
+			}
+			if nestedKey == "call" {
+				// NOTE: `try` can consist of a single call instead of steps
+				result = append(result, RepositoryWorkflowStepData{Name: key, Type: "call", Function: gcpWorkflowCallFunction(value)})
+				continue
+			}
+			result = append(result, gcpWorkflowNestedSteps(nestedKey, nested)...)
+		}
+		return result
+	default:
+		return make([]RepositoryWorkflowStepData, 0)
+	}
+}
+
+// NOTE: workflows invoke functions with an HTTP call of their URL, e.g.
+// `call: http.post, args: { url: https://us-central1-project.cloudfunctions.net/hello }`, or through the connector
+// `call: googleapis.cloudfunctions.v1.projects.locations.functions.call, args: { name: projects/.../functions/hello }`
+func gcpWorkflowCallFunction(step map[string]interface{}) string {
+	call, _ := step["call"].(string)
+	switch {
+	case strings.HasPrefix(call, "http."):
+		url, _ := JsonResolveString(step, []string{"args", "url"})
+		if match := gcpFunctionUrlRegexp.FindStringSubmatch(url); match != nil {
+			return match[1]
+		}
+	case strings.HasPrefix(call, "googleapis.cloudfunctions."):
+		name, _ := JsonResolveString(step, []string{"args", "name"})
+		if match := gcpFunctionResourceRegexp.FindStringSubmatch(name); match != nil {
+			return match[1]
+		}
+	}
+	return ""
+}
+
+// NOTE: workflows are named after their file, e.g. "order" for workflows/order.yaml
+func gcpWorkflowName(file TextFile) string {
+	return strings.TrimSuffix(path.Base(file.Path), path.Ext(file.Path))
+}
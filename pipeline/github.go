@@ -1,8 +1,7 @@
 package main
 
 import (
-	"archive/zip"
-	"bytes"
+	"cmp"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -12,7 +11,6 @@ import (
 	"path/filepath"
 	"slices"
 	"strconv"
-	"strings"
 	"sync"
 	"time"
 
@@ -180,72 +178,6 @@ func ScrapeGitHub(queries []string, numWorkers int, outputDirectory string) {
 	wgReceiver.Wait()
 }
 
-func Unzip(zippedBytes []byte, dest string) error {
-	zippedBytesReader := bytes.NewReader(zippedBytes)
-	r, err := zip.NewReader(zippedBytesReader, int64(len(zippedBytes)))
-	if err != nil {
-		return err
-	}
-
-	if err := os.MkdirAll(dest, 0755); err != nil {
-		return err
-	}
-
-	// Closure to address file descriptors issue with all the deferred .Close() methods
-	extractAndWriteFile := func(f *zip.File) error {
-		rc, err := f.Open()
-		if err != nil {
-			return err
-		}
-		defer func() {
-			if err := rc.Close(); err != nil {
-				panic(err)
-			}
-		}()
-
-		nameParts := strings.SplitN(f.Name, "/", 2)
-		if len(nameParts) != 2 {
-			return fmt.Errorf("invalid file name format: %s", f.Name)
-		}
-
-		outPath := filepath.Join(dest, nameParts[1])
-
-		if f.FileInfo().IsDir() {
-			if err := os.MkdirAll(outPath, f.Mode()); err != nil {
-				return err
-			}
-		} else {
-			if err := os.MkdirAll(filepath.Dir(outPath), f.Mode()); err != nil {
-				return err
-			}
-			f, err := os.OpenFile(outPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, f.Mode())
-			if err != nil {
-				return err
-			}
-			defer func() {
-				if err := f.Close(); err != nil {
-					panic(err)
-				}
-			}()
-
-			_, err = io.Copy(f, rc)
-			if err != nil {
-				return err
-			}
-		}
-		return nil
-	}
-
-	for _, f := range r.File {
-		err := extractAndWriteFile(f)
-		if err != nil {
-			return err
-		}
-	}
-
-	return nil
-}
-
 func DownloadRepositoryFile(httpClient *http.Client, fileURL, outPath string) error {
 	response, err := httpClient.Get(fileURL)
 	if err != nil {
@@ -383,10 +315,10 @@ func DownloadRepositoryFiles(
 	fmt.Printf("waited wgWorker\n")
 }
 
-func DownloadRepository(archiveURL string, path string) error {
-	response, err := http.Get(archiveURL)
+func DownloadRepositoryArchive(httpClient *http.Client, archiveURL string, maxSizeBytes int64) (string, int64, error) {
+	response, err := httpClient.Get(archiveURL)
 	if err != nil {
-		return err
+		return "", 0, err
 	}
 	defer func(Body io.ReadCloser) {
 		err := Body.Close()
@@ -395,37 +327,221 @@ func DownloadRepository(archiveURL string, path string) error {
 		}
 	}(response.Body)
 
-	responseBytes, err := io.ReadAll(response.Body)
+	if response.StatusCode == http.StatusTooManyRequests {
+		if retryAfter, err := ParseRetryAfterHeader(response.Header.Get("Retry-After")); err == nil {
+			return "", 0, &ThrottledError{retryAfter: retryAfter}
+		}
+		return "", 0, &ThrottledError{retryAfter: 60 * time.Second}
+	} else if response.StatusCode != http.StatusOK {
+		return "", 0, fmt.Errorf("unexpected status code: %d", response.StatusCode)
+	}
 
-	if err := Unzip(responseBytes, path); err != nil {
-		return err
+	// NOTE: codeload usually streams archives without a Content-Length, so the limit is enforced while copying as well
+	if maxSizeBytes > 0 && response.ContentLength > maxSizeBytes {
+		return "", 0, fmt.Errorf("archive size of %d bytes exceeds the limit of %d bytes", response.ContentLength, maxSizeBytes)
 	}
 
-	return nil
+	archiveFile, err := os.CreateTemp("", "repository-*.zip")
+	if err != nil {
+		return "", 0, err
+	}
+	archivePath := archiveFile.Name()
+
+	var body io.Reader = response.Body
+	if maxSizeBytes > 0 {
+		body = io.LimitReader(response.Body, maxSizeBytes+1)
+	}
+
+	archiveSize, err := io.Copy(archiveFile, body)
+	if closeErr := archiveFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil && maxSizeBytes > 0 && archiveSize > maxSizeBytes {
+		err = fmt.Errorf("archive size exceeds the limit of %d bytes", maxSizeBytes)
+	}
+	if err != nil {
+		_ = os.Remove(archivePath)
+		return "", 0, err
+	}
+
+	return archivePath, archiveSize, nil
 }
 
-func DownloadRepositories(repositoryIds []RepositoryId, repositoryInfosDirectory string, outputDirectory string) error {
-	for _, repositoryId := range repositoryIds {
-		repositoryInfoPath := path.Join(repositoryInfosDirectory, fmt.Sprintf("%d.json", repositoryId))
-		repositoryInfo, err := LoadRepositoryInfo(repositoryInfoPath)
-		if err != nil {
-			fmt.Printf("Error loading repository %d info: %s\n", repositoryId, err)
-			continue
-		}
+func DownloadRepository(
+	httpClient *http.Client,
+	archiveURL string,
+	path string,
+	limits ArchiveLimits,
+	filter *ArchiveEntryFilter,
+) (int64, ArchiveExtraction, error) {
+	type archive struct {
+		path string
+		size int64
+	}
 
-		repositoryPath := path.Join(outputDirectory, fmt.Sprintf("%d", repositoryId))
+	downloadedArchive, err := RetryWithResult(func() (archive, error) {
+		archivePath, archiveSize, err := DownloadRepositoryArchive(httpClient, archiveURL, limits.MaxArchiveSizeBytes)
+		return archive{path: archivePath, size: archiveSize}, err
+	}, DefaultErrorHandler)
+	if err != nil {
+		return 0, ArchiveExtraction{}, err
+	}
+	defer func() {
+		_ = os.Remove(downloadedArchive.path)
+	}()
 
-		if err := os.MkdirAll(repositoryPath, os.ModePerm); err != nil {
-			fmt.Printf("Error loading repository %d info: %s\n", repositoryId, err)
-			continue
-		}
+	// NOTE: extract next to the destination first, so that an interrupted extraction never looks like a complete clone
+	partialPath := path + ".partial"
+	if err := os.RemoveAll(partialPath); err != nil {
+		return 0, ArchiveExtraction{}, err
+	}
+	extraction, err := Unzip(downloadedArchive.path, partialPath, limits, filter)
+	if err != nil {
+		_ = os.RemoveAll(partialPath)
+		return 0, ArchiveExtraction{}, err
+	}
 
-		archiveURL := fmt.Sprintf("%s/archive/refs/heads/%s.zip", repositoryInfo.GetHTMLURL(), repositoryInfo.GetDefaultBranch())
-		if err := DownloadRepository(archiveURL, repositoryPath); err != nil {
-			fmt.Printf("Error cloning repository %d: %s\n", repositoryId, err)
-			continue
-		}
+	if err := os.RemoveAll(path); err != nil {
+		return 0, ArchiveExtraction{}, err
+	}
+	if err := os.Rename(partialPath, path); err != nil {
+		return 0, ArchiveExtraction{}, err
+	}
+
+	return downloadedArchive.size, extraction, nil
+}
+
+type RepositoryDownloadFailure struct {
+	RepositoryId RepositoryId
+	FullName     string
+	ArchiveURL   string
+	Error        string
+}
+
+type repositoryDownloadResult struct {
+	manifest RepositoryManifest
+	failure  *RepositoryDownloadFailure
+}
+
+func SaveRepositoryDownloadFailures(failures []RepositoryDownloadFailure, outPath string) error {
+	failuresBytes, err := json.Marshal(failures)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(outPath), os.ModePerm); err != nil {
+		return err
+	}
+	if err := os.WriteFile(outPath, failuresBytes, 0644); err != nil {
+		return err
 	}
 
 	return nil
 }
+
+func DownloadRepositories(
+	numWorkers int,
+	repositoryIds []RepositoryId,
+	repositoryInfosDirectory string,
+	outputDirectory string,
+	manifestsDirectory string,
+	limits ArchiveLimits,
+	filter *ArchiveEntryFilter,
+	failuresPath string,
+	resume bool,
+) error {
+	failures := []RepositoryDownloadFailure{}
+
+	ProcessInParallel(
+		repositoryIds,
+		func(repositoryId RepositoryId, httpClient *http.Client, githubClient GitHubClient) (repositoryDownloadResult, bool) {
+			manifestPath := path.Join(manifestsDirectory, fmt.Sprintf("%d.json", repositoryId))
+			repositoryPath := path.Join(outputDirectory, fmt.Sprintf("%d", repositoryId))
+
+			if resume {
+				// NOTE: the manifest is only written after a successful extraction
+				if _, err := os.Stat(manifestPath); err == nil {
+					if _, err := os.Stat(repositoryPath); err == nil {
+						return repositoryDownloadResult{}, false
+					}
+				}
+			}
+
+			fail := func(fullName string, archiveURL string, err error) (repositoryDownloadResult, bool) {
+				return repositoryDownloadResult{failure: &RepositoryDownloadFailure{
+					RepositoryId: repositoryId,
+					FullName:     fullName,
+					ArchiveURL:   archiveURL,
+					Error:        err.Error(),
+				}}, true
+			}
+
+			repositoryInfoPath := path.Join(repositoryInfosDirectory, fmt.Sprintf("%d.json", repositoryId))
+			repositoryInfo, err := LoadRepositoryInfo(repositoryInfoPath)
+			if err != nil {
+				return fail("", "", fmt.Errorf("loading repository info: %w", err))
+			}
+
+			name := repositoryInfo.GetName()
+			owner := repositoryInfo.GetOwner().GetLogin()
+
+			commitSHA, err := githubClient.GetCommitSHA(owner, name, repositoryInfo.GetDefaultBranch())
+			if err != nil {
+				return fail(repositoryInfo.GetFullName(), "", fmt.Errorf("resolving commit: %w", err))
+			}
+
+			// NOTE: archives of a specific commit are immutable, unlike the ones of the default branch
+			archiveURL := fmt.Sprintf("%s/archive/%s.zip", repositoryInfo.GetHTMLURL(), commitSHA)
+			archiveSize, extraction, err := DownloadRepository(
+				httpClient,
+				archiveURL,
+				repositoryPath,
+				limits,
+				filter,
+			)
+			if err != nil {
+				return fail(repositoryInfo.GetFullName(), archiveURL, fmt.Errorf("cloning repository: %w", err))
+			}
+
+			return repositoryDownloadResult{manifest: RepositoryManifest{
+				RepositoryId:       repositoryId,
+				FullName:           repositoryInfo.GetFullName(),
+				DefaultBranch:      repositoryInfo.GetDefaultBranch(),
+				CommitSHA:          commitSHA,
+				ArchiveURL:         archiveURL,
+				ArchiveSizeBytes:   archiveSize,
+				Sparse:             filter != nil,
+				NumExtractedFiles:  extraction.NumExtractedFiles,
+				NumFilteredEntries: extraction.NumFilteredEntries,
+				ExtractedSizeBytes: extraction.ExtractedSizeBytes,
+				SkippedEntries:     extraction.SkippedEntries,
+				ClonedAt:           time.Now().UTC(),
+			}}, true
+		},
+		func(result repositoryDownloadResult) {
+			if result.failure != nil {
+				fmt.Printf("Error downloading repository %d: %s\n", result.failure.RepositoryId, result.failure.Error)
+				failures = append(failures, *result.failure)
+				return
+			}
+
+			fmt.Printf("cloned repository %d\n", result.manifest.RepositoryId)
+			if err := SaveRepositoryManifest(
+				result.manifest,
+				path.Join(manifestsDirectory, fmt.Sprintf("%d.json", result.manifest.RepositoryId)),
+			); err != nil {
+				fmt.Printf("Error saving manifest of repository %d: %s\n", result.manifest.RepositoryId, err)
+			}
+		},
+		numWorkers,
+		1,
+		10_000,
+		10_000,
+	)
+
+	slices.SortFunc(failures, func(a, b RepositoryDownloadFailure) int {
+		return cmp.Compare(a.RepositoryId, b.RepositoryId)
+	})
+
+	return SaveRepositoryDownloadFailures(failures, failuresPath)
+}
@@ -320,3 +320,46 @@ func (ghc GitHubClient) GetIssues(owner, repo, state string) []*github.Issue {
 
 	return issues
 }
+
+// NOTE: client errors other than rate limits are permanent, e.g. 409 for empty or 451 for blocked repositories, while
+// server and network errors are retried a few times only, so that a single repository can't block a worker
+func (ghc GitHubClient) GetCommitSHA(owner, repo, ref string) (string, error) {
+	const maxRetries = 5
+	const backoff = 10 * time.Second
+
+	numRetries := 0
+	return RetryWithResult(func() (string, error) {
+		sha, _, err := ghc.client.Repositories.GetCommitSHA1(
+			context.Background(),
+			owner,
+			repo,
+			ref,
+			"",
+		)
+		return sha, err
+	}, func(err error) ErrorHandlerAction {
+		var githubRateLimitErr *github.RateLimitError
+		var githubAbuseRateLimitErr *github.AbuseRateLimitError
+		var githubErrorResponse *github.ErrorResponse
+
+		switch {
+		case err == nil:
+			return nil
+		case errors.As(err, &githubRateLimitErr):
+			// NOTE: add a buffer to avoid running into the rate limit again
+			return &ErrorHandlerActionRetryAfter{retryAfter: time.Until(githubRateLimitErr.Rate.Reset.Time) + backoff}
+		case errors.As(err, &githubAbuseRateLimitErr):
+			return &ErrorHandlerActionRetryAfter{retryAfter: githubAbuseRateLimitErr.GetRetryAfter() + backoff}
+		case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+			return &ErrorHandlerActionDoNotRetry{}
+		case errors.As(err, &githubErrorResponse) && githubErrorResponse.Response.StatusCode < http.StatusInternalServerError:
+			return &ErrorHandlerActionDoNotRetry{}
+		case numRetries >= maxRetries:
+			return &ErrorHandlerActionDoNotRetry{}
+		default:
+			numRetries++
+			fmt.Printf("Error: %v. Retry %d of %d\n", err, numRetries, maxRetries)
+			return &ErrorHandlerActionRetryAfter{retryAfter: backoff}
+		}
+	})
+}
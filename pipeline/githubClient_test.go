@@ -0,0 +1,35 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+
+	"github.com/google/go-github/github"
+)
+
+func TestGetCommitSHAFailsOnClientErrors(t *testing.T) {
+	for _, statusCode := range []int{http.StatusForbidden, http.StatusNotFound, http.StatusConflict, http.StatusUnprocessableEntity, http.StatusUnavailableForLegalReasons} {
+		t.Run(http.StatusText(statusCode), func(t *testing.T) {
+			var numRequests atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				numRequests.Add(1)
+				w.WriteHeader(statusCode)
+				_, _ = w.Write([]byte(`{"message": "error"}`))
+			}))
+			defer server.Close()
+
+			client := github.NewClient(server.Client())
+			client.BaseURL, _ = url.Parse(server.URL + "/")
+
+			if _, err := (GitHubClient{client: client}).GetCommitSHA("owner", "repo", "main"); err == nil {
+				t.Errorf("expected an error")
+			}
+			if numRequests.Load() != 1 {
+				t.Errorf("expected 1 request, got %d", numRequests.Load())
+			}
+		})
+	}
+}
@@ -0,0 +1,414 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"path"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"golang.org/x/exp/maps"
+	"gopkg.in/yaml.v3"
+)
+
+// NOTE: the release every chart is rendered as, names of resources usually derive from it, e.g. `release-chart`
+const helmReleaseName = "release"
+const helmMaxUndefinedFunctions = 16
+
+var helmUndefinedFunctionRegexp = regexp.MustCompile(`function "(\w+)" not defined`)
+
+// NOTE: `.Files.Get` of templates, files of the chart that aren't part of the scanned files read as empty
+type helmFiles map[string]string
+
+func (files helmFiles) Get(name string) string {
+	return files[name]
+}
+
+// NOTE: `.Capabilities.APIVersions.Has`, all API versions are assumed to be available
+type helmAPIVersions []string
+
+func (versions helmAPIVersions) Has(version string) bool {
+	return true
+}
+
+// NOTE: renders the templates of a chart with the default values of values.yaml, subcharts are rendered as charts
+// of their own, templates that fail to render are skipped
+func RenderHelmChart(chartDirectory string, files []TextFile) ([]KubernetesManifest, error) {
+	var chartFile *TextFile
+	var valuesFile *TextFile
+	templateFiles := make([]TextFile, 0)
+	chartFiles := make(helmFiles)
+	for index, file := range files {
+		switch {
+		case file.Path == path.Join(chartDirectory, "Chart.yaml"):
+			chartFile = &files[index]
+		case file.Path == path.Join(chartDirectory, "values.yaml"):
+			valuesFile = &files[index]
+		case strings.HasPrefix(file.Path, path.Join(chartDirectory, "templates")+"/"):
+			templateFiles = append(templateFiles, file)
+		}
+		if strings.HasPrefix(file.Path, chartDirectory+"/") {
+			chartFiles[strings.TrimPrefix(file.Path, chartDirectory+"/")] = file.Content
+		}
+	}
+	if chartFile == nil {
+		return nil, fmt.Errorf("missing Chart.yaml")
+	}
+
+	chart := make(map[string]interface{})
+	if err := yaml.Unmarshal([]byte(chartFile.Content), &chart); err != nil {
+		return nil, err
+	}
+	values := make(map[string]interface{})
+	if valuesFile != nil {
+		if err := yaml.Unmarshal([]byte(valuesFile.Content), &values); err != nil {
+			return nil, err
+		}
+	}
+
+	// NOTE: the keys of Chart.yaml are capitalized in templates, e.g. `.Chart.AppVersion`
+	chartValues := make(map[string]interface{})
+	for key, value := range chart {
+		chartValues[strings.ToUpper(key[:1])+key[1:]] = value
+	}
+
+	renderContext := map[string]interface{}{
+		"Values": values,
+		"Release": map[string]interface{}{
+			"Name":      helmReleaseName,
+			"Namespace": "default",
+			"Service":   "Helm",
+			"IsInstall": true,
+			"IsUpgrade": false,
+			"Revision":  1,
+		},
+		"Chart": chartValues,
+		"Capabilities": map[string]interface{}{
+			"KubeVersion": map[string]interface{}{"Version": "v1.30.0", "GitVersion": "v1.30.0", "Major": "1", "Minor": "30"},
+			"APIVersions": helmAPIVersions{},
+		},
+		"Files": chartFiles,
+	}
+
+	templates, err := parseHelmTemplates(templateFiles)
+	if err != nil {
+		return nil, err
+	}
+
+	manifests := make([]KubernetesManifest, 0)
+	for _, templateFile := range templateFiles {
+		// NOTE: partials like _helpers.tpl only define named templates
+		if strings.HasPrefix(path.Base(templateFile.Path), "_") || !slices.Contains(yamlExtensions, path.Ext(templateFile.Path)) {
+			continue
+		}
+
+		fileContext := maps.Clone(renderContext)
+		fileContext["Template"] = map[string]interface{}{
+			"Name":     templateFile.Path,
+			"BasePath": path.Join(chartDirectory, "templates"),
+		}
+
+		var output bytes.Buffer
+		if err := templates.ExecuteTemplate(&output, templateFile.Path, fileContext); err != nil {
+			continue
+		}
+		rendered := strings.ReplaceAll(output.String(), "<no value>", "")
+
+		for _, document := range LoadYamlDocuments([]byte(rendered)) {
+			for _, manifest := range kubernetesManifestsOfDocument(document, templateFile.Path) {
+				// NOTE: lines of the rendered output don't correspond to lines of the template
+				manifest.Line = -1
+				manifest.RenderedBy = chartDirectory
+				manifests = append(manifests, manifest)
+			}
+		}
+	}
+
+	return manifests, nil
+}
+
+var yamlExtensions = []string{".yaml", ".yml"}
+
+// NOTE: functions of Sprig and Helm which aren't implemented are replaced by functions returning an empty string,
+// templates which fail to parse otherwise are skipped
+func parseHelmTemplates(templateFiles []TextFile) (*template.Template, error) {
+	undefinedFunctions := make([]string, 0)
+	for {
+		templates := template.New("").Option("missingkey=zero")
+		templates.Funcs(helmFunctions(templates))
+
+		stubs := make(template.FuncMap)
+		for _, name := range undefinedFunctions {
+			stubs[name] = func(arguments ...interface{}) string { return "" }
+		}
+		templates.Funcs(stubs)
+
+		var err error
+		failedIndex := -1
+		for index, templateFile := range templateFiles {
+			if _, err = templates.New(templateFile.Path).Parse(templateFile.Content); err != nil {
+				failedIndex = index
+				break
+			}
+		}
+		if err == nil {
+			return templates, nil
+		}
+
+		if match := helmUndefinedFunctionRegexp.FindStringSubmatch(err.Error()); match != nil && len(undefinedFunctions) < helmMaxUndefinedFunctions {
+			undefinedFunctions = append(undefinedFunctions, match[1])
+		} else {
+			templateFiles = slices.Delete(slices.Clone(templateFiles), failedIndex, failedIndex+1)
+		}
+	}
+}
+
+func helmFunctions(templates *template.Template) template.FuncMap {
+	return template.FuncMap{
+		"include": func(name string, data interface{}) (string, error) {
+			var output bytes.Buffer
+			err := templates.ExecuteTemplate(&output, name, data)
+			return output.String(), err
+		},
+		"tpl": func(text string, data interface{}) (string, error) {
+			clone, err := templates.Clone()
+			if err != nil {
+				return "", err
+			}
+			parsed, err := clone.New("tpl").Parse(text)
+			if err != nil {
+				return "", err
+			}
+			var output bytes.Buffer
+			err = parsed.Execute(&output, data)
+			return output.String(), err
+		},
+		"required": func(message string, value interface{}) interface{} { return value },
+		"fail":     func(message string) (string, error) { return "", fmt.Errorf("%s", message) },
+		"lookup": func(arguments ...interface{}) map[string]interface{} {
+			return map[string]interface{}{}
+		},
+
+		"toYaml": func(value interface{}) string {
+			output, err := yaml.Marshal(value)
+			if err != nil {
+				return ""
+			}
+			return strings.TrimSuffix(string(output), "\n")
+		},
+		"fromYaml": func(text string) map[string]interface{} {
+			result := make(map[string]interface{})
+			_ = yaml.Unmarshal([]byte(text), &result)
+			return result
+		},
+		"toJson": func(value interface{}) string {
+			output, err := json.Marshal(value)
+			if err != nil {
+				return ""
+			}
+			return string(output)
+		},
+		"indent": func(spaces int, text string) string {
+			padding := strings.Repeat(" ", spaces)
+			return padding + strings.ReplaceAll(text, "\n", "\n"+padding)
+		},
+		"nindent": func(spaces int, text string) string {
+			padding := strings.Repeat(" ", spaces)
+			return "\n" + padding + strings.ReplaceAll(text, "\n", "\n"+padding)
+		},
+		"quote": func(values ...interface{}) string {
+			quoted := make([]string, 0, len(values))
+			for _, value := range values {
+				if value != nil {
+					quoted = append(quoted, strconv.Quote(helmString(value)))
+				}
+			}
+			return strings.Join(quoted, " ")
+		},
+		"squote": func(value interface{}) string { return "'" + helmString(value) + "'" },
+
+		"default": func(defaultValue interface{}, values ...interface{}) interface{} {
+			if len(values) == 0 || helmEmpty(values[0]) {
+				return defaultValue
+			}
+			return values[0]
+		},
+		"empty": helmEmpty,
+		"coalesce": func(values ...interface{}) interface{} {
+			for _, value := range values {
+				if !helmEmpty(value) {
+					return value
+				}
+			}
+			return nil
+		},
+		"ternary": func(trueValue interface{}, falseValue interface{}, condition bool) interface{} {
+			if condition {
+				return trueValue
+			}
+			return falseValue
+		},
+
+		"toString":   helmString,
+		"trim":       strings.TrimSpace,
+		"trimSuffix": func(suffix string, text string) string { return strings.TrimSuffix(text, suffix) },
+		"trimPrefix": func(prefix string, text string) string { return strings.TrimPrefix(text, prefix) },
+		"trunc": func(length int, text string) string {
+			if length >= 0 && len(text) > length {
+				return text[:length]
+			}
+			return text
+		},
+		"upper":     strings.ToUpper,
+		"lower":     strings.ToLower,
+		"title":     func(text string) string { return strings.Title(text) },
+		"replace":   func(old string, new string, text string) string { return strings.ReplaceAll(text, old, new) },
+		"contains":  func(substring string, text string) bool { return strings.Contains(text, substring) },
+		"hasPrefix": func(prefix string, text string) bool { return strings.HasPrefix(text, prefix) },
+		"hasSuffix": func(suffix string, text string) bool { return strings.HasSuffix(text, suffix) },
+		"splitList": func(separator string, text string) []string { return strings.Split(text, separator) },
+		"join": func(separator string, values interface{}) string {
+			texts := make([]string, 0)
+			for _, value := range helmList(values) {
+				texts = append(texts, helmString(value))
+			}
+			return strings.Join(texts, separator)
+		},
+		"regexMatch": func(pattern string, text string) bool {
+			matches, _ := regexp.MatchString(pattern, text)
+			return matches
+		},
+		"b64enc": func(text string) string { return base64.StdEncoding.EncodeToString([]byte(text)) },
+		"sha256sum": func(text string) string {
+			sum := sha256.Sum256([]byte(text))
+			return hex.EncodeToString(sum[:])
+		},
+
+		"list": func(values ...interface{}) []interface{} { return values },
+		"first": func(values interface{}) interface{} {
+			if list := helmList(values); len(list) > 0 {
+				return list[0]
+			}
+			return nil
+		},
+		"last": func(values interface{}) interface{} {
+			if list := helmList(values); len(list) > 0 {
+				return list[len(list)-1]
+			}
+			return nil
+		},
+		"dict": func(values ...interface{}) map[string]interface{} {
+			result := make(map[string]interface{})
+			for index := 0; index+1 < len(values); index += 2 {
+				result[helmString(values[index])] = values[index+1]
+			}
+			return result
+		},
+		"get": func(values map[string]interface{}, key string) interface{} { return values[key] },
+		"set": func(values map[string]interface{}, key string, value interface{}) map[string]interface{} {
+			values[key] = value
+			return values
+		},
+		"hasKey": func(values map[string]interface{}, key string) bool {
+			_, ok := values[key]
+			return ok
+		},
+		"keys": func(values map[string]interface{}) []string {
+			keys := maps.Keys(values)
+			slices.Sort(keys)
+			return keys
+		},
+		"merge": func(destination map[string]interface{}, sources ...map[string]interface{}) interface{} {
+			var result interface{} = destination
+			for _, source := range sources {
+				result = mergeJsons(source, result)
+			}
+			return result
+		},
+		"semverCompare": func(constraint string, version string) bool { return true },
+		"kindIs": func(kind string, value interface{}) bool {
+			return value != nil && reflect.TypeOf(value).Kind().String() == kind
+		},
+
+		"int":     helmInt,
+		"int64":   func(value interface{}) int64 { return int64(helmInt(value)) },
+		"float64": func(value interface{}) float64 { return float64(helmInt(value)) },
+		"add": func(values ...interface{}) int {
+			sum := 0
+			for _, value := range values {
+				sum += helmInt(value)
+			}
+			return sum
+		},
+		"sub": func(a interface{}, b interface{}) int { return helmInt(a) - helmInt(b) },
+		"mul": func(a interface{}, b interface{}) int { return helmInt(a) * helmInt(b) },
+		"div": func(a interface{}, b interface{}) int {
+			if helmInt(b) == 0 {
+				return 0
+			}
+			return helmInt(a) / helmInt(b)
+		},
+	}
+}
+
+func helmEmpty(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	reflected := reflect.ValueOf(value)
+	switch reflected.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+		return reflected.Len() == 0
+	case reflect.Bool:
+		return !reflected.Bool()
+	case reflect.Int, reflect.Int64, reflect.Int32:
+		return reflected.Int() == 0
+	case reflect.Float64, reflect.Float32:
+		return reflected.Float() == 0
+	default:
+		return false
+	}
+}
+
+func helmString(value interface{}) string {
+	if value == nil {
+		return ""
+	}
+	return fmt.Sprint(value)
+}
+
+func helmInt(value interface{}) int {
+	switch value := value.(type) {
+	case int:
+		return value
+	case int64:
+		return int(value)
+	case float64:
+		return int(value)
+	case string:
+		result, _ := strconv.Atoi(value)
+		return result
+	default:
+		return 0
+	}
+}
+
+func helmList(value interface{}) []interface{} {
+	reflected := reflect.ValueOf(value)
+	if reflected.Kind() != reflect.Slice && reflected.Kind() != reflect.Array {
+		return nil
+	}
+	result := make([]interface{}, 0, reflected.Len())
+	for index := 0; index < reflected.Len(); index++ {
+		result = append(result, reflected.Index(index).Interface())
+	}
+	return result
+}
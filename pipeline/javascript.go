@@ -0,0 +1,1466 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+// NOTE: this is not a complete JavaScript/TypeScript parser. It tokenizes the source properly (so comments, strings
+//       and template literals never produce false positives) and recognizes the handful of structures the scanners
+//       need: imports, exports, declarations, calls and literals. Everything else is skipped tolerantly.
+
+type JsTokenKind int
+
+const (
+	JsTokenIdentifier JsTokenKind = iota
+	JsTokenString
+	JsTokenTemplate
+	JsTokenNumber
+	JsTokenRegex
+	JsTokenPunctuator
+)
+
+type JsToken struct {
+	Kind  JsTokenKind
+	Value string
+	Line  int
+}
+
+type JsValueKind string
+
+const (
+	JsValueString     JsValueKind = "string"
+	JsValueNumber     JsValueKind = "number"
+	JsValueBoolean    JsValueKind = "boolean"
+	JsValueNull       JsValueKind = "null"
+	JsValueIdentifier JsValueKind = "identifier"
+	JsValueObject     JsValueKind = "object"
+	JsValueArray      JsValueKind = "array"
+	JsValueFunction   JsValueKind = "function"
+	JsValueClass      JsValueKind = "class"
+	JsValueCall       JsValueKind = "call"
+	JsValueOther      JsValueKind = "other"
+)
+
+type JsValue struct {
+	Kind JsValueKind
+	Line int
+
+	// NOTE: the unquoted string, the number or the dotted identifier path, depending on the kind
+	Text string
+
+	Properties []JsProperty
+	Elements   []JsValue
+	Call       *JsCall
+}
+
+type JsProperty struct {
+	Key   string
+	Value JsValue
+	Line  int
+}
+
+type JsCall struct {
+	// NOTE: the member chain of the callee, calls in between are marked with a "()" suffix,
+	//       e.g. functions.pubsub.topic("a").onPublish(fn) has the callee [functions pubsub topic() onPublish]
+	Callee    []string
+	IsNew     bool
+	Arguments []JsValue
+	Line      int
+}
+
+type JsImport struct {
+	Source string
+
+	DefaultName   string
+	NamespaceName string
+	// NOTE: local name -> imported name
+	Names map[string]string
+
+	IsRequire bool
+	Line      int
+}
+
+type JsExport struct {
+	// NOTE: "default" for default exports and `module.exports = ...`
+	Name           string
+	TypeAnnotation string
+	IsAsync        bool
+	Value          JsValue
+	Line           int
+}
+
+type JsDeclaration struct {
+	Name           string
+	TypeAnnotation string
+	Value          JsValue
+	Line           int
+}
+
+type JsModule struct {
+	Path string
+
+	Imports      []JsImport
+	Exports      []JsExport
+	Declarations []JsDeclaration
+	Calls        []JsCall
+
+	// NOTE: indices of the calls by line and callee, see ChainCalls
+	callIndicesOnce sync.Once
+	callIndices     map[string][]int
+}
+
+var jsPunctuators = []string{
+	">>>=", "...", "===", "!==", "**=", "<<=", ">>=", ">>>", "&&=", "||=", "??=",
+	"=>", "==", "!=", "<=", ">=", "&&", "||", "??", "?.", "++", "--", "+=", "-=", "*=", "/=", "%=", "&=", "|=", "^=",
+	"**", "<<", ">>",
+}
+
+var jsKeywordsBeforeRegex = []string{
+	"return", "typeof", "instanceof", "in", "of", "new", "delete", "void", "throw", "case", "do", "else", "yield", "await",
+}
+
+var jsKeywordsNotCallable = []string{
+	"if", "for", "while", "switch", "catch", "function", "return", "typeof", "with", "await", "async", "void", "delete",
+	"import", "super", "else", "yield", "throw", "in", "of", "instanceof", "case",
+}
+
+func isJsIdentifierStart(r rune) bool {
+	return r == '_' || r == '$' || r == '#' || unicode.IsLetter(r)
+}
+
+func isJsIdentifierPart(r rune) bool {
+	return r == '_' || r == '$' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+func TokenizeJs(content string) []JsToken {
+	tokens := make([]JsToken, 0, len(content)/4)
+	line := 1
+	i := 0
+
+	if strings.HasPrefix(content, "#!") {
+		for i < len(content) && content[i] != '\n' {
+			i++
+		}
+	}
+
+	regexAllowed := func() bool {
+		if len(tokens) == 0 {
+			return true
+		}
+		last := tokens[len(tokens)-1]
+		switch last.Kind {
+		case JsTokenIdentifier:
+			for _, keyword := range jsKeywordsBeforeRegex {
+				if last.Value == keyword {
+					return true
+				}
+			}
+			return false
+		case JsTokenPunctuator:
+			return last.Value != ")" && last.Value != "]" && last.Value != "}"
+		default:
+			return false
+		}
+	}
+
+	// NOTE: skips over a template literal starting at the backtick, including nested substitutions
+	var skipTemplate func(start int) (int, bool)
+	skipTemplate = func(start int) (int, bool) {
+		hasSubstitutions := false
+		j := start + 1
+		for j < len(content) {
+			switch content[j] {
+			case '\\':
+				j += 2
+				continue
+			case '\n':
+				line++
+			case '`':
+				return j + 1, hasSubstitutions
+			case '$':
+				if j+1 < len(content) && content[j+1] == '{' {
+					hasSubstitutions = true
+					depth := 1
+					j += 2
+					for j < len(content) && depth > 0 {
+						switch content[j] {
+						case '{':
+							depth++
+						case '}':
+							depth--
+						case '\n':
+							line++
+						case '`':
+							j, _ = skipTemplate(j)
+							continue
+						case '\'', '"':
+							quote := content[j]
+							j++
+							for j < len(content) && content[j] != quote && content[j] != '\n' {
+								if content[j] == '\\' {
+									j++
+								}
+								j++
+							}
+						}
+						j++
+					}
+					continue
+				}
+			}
+			j++
+		}
+		return len(content), hasSubstitutions
+	}
+
+	for i < len(content) {
+		c := content[i]
+
+		switch {
+		case c == '\n':
+			line++
+			i++
+			continue
+		case c == ' ' || c == '\t' || c == '\r' || c == '\f' || c == '\v':
+			i++
+			continue
+		case c == '/' && i+1 < len(content) && content[i+1] == '/':
+			for i < len(content) && content[i] != '\n' {
+				i++
+			}
+			continue
+		case c == '/' && i+1 < len(content) && content[i+1] == '*':
+			end := strings.Index(content[i+2:], "*/")
+			if end == -1 {
+				end = len(content) - i - 2
+			}
+			line += strings.Count(content[i:i+2+end], "\n")
+			i += end + 4
+			continue
+		}
+
+		startLine := line
+
+		switch {
+		case c == '\'' || c == '"':
+			var builder strings.Builder
+			j := i + 1
+			for j < len(content) && content[j] != c && content[j] != '\n' {
+				if content[j] == '\\' && j+1 < len(content) {
+					if content[j+1] == '\n' {
+						line++
+					}
+					builder.WriteByte(content[j+1])
+					j += 2
+					continue
+				}
+				builder.WriteByte(content[j])
+				j++
+			}
+			tokens = append(tokens, JsToken{Kind: JsTokenString, Value: builder.String(), Line: startLine})
+			i = j + 1
+		case c == '`':
+			end, hasSubstitutions := skipTemplate(i)
+			value := content[i:min(end, len(content))]
+			value = strings.TrimSuffix(strings.TrimPrefix(value, "`"), "`")
+			if hasSubstitutions {
+				// NOTE: substitutions make the value unknown, it is kept raw for diagnostics only
+				value = "`" + value + "`"
+			}
+			tokens = append(tokens, JsToken{Kind: JsTokenTemplate, Value: value, Line: startLine})
+			i = end
+		case c >= '0' && c <= '9' || c == '.' && i+1 < len(content) && content[i+1] >= '0' && content[i+1] <= '9':
+			j := i + 1
+			for j < len(content) {
+				d := content[j]
+				if d >= '0' && d <= '9' || d >= 'a' && d <= 'z' || d >= 'A' && d <= 'Z' || d == '_' || d == '.' {
+					j++
+				} else if (d == '+' || d == '-') && (content[j-1] == 'e' || content[j-1] == 'E') {
+					j++
+				} else {
+					break
+				}
+			}
+			tokens = append(tokens, JsToken{Kind: JsTokenNumber, Value: content[i:j], Line: startLine})
+			i = j
+		case c == '/' && regexAllowed():
+			j := i + 1
+			inClass := false
+			for j < len(content) && content[j] != '\n' {
+				if content[j] == '\\' {
+					j += 2
+					continue
+				}
+				if content[j] == '[' {
+					inClass = true
+				} else if content[j] == ']' {
+					inClass = false
+				} else if content[j] == '/' && !inClass {
+					break
+				}
+				j++
+			}
+			j++
+			for j < len(content) && isJsIdentifierPart(rune(content[j])) {
+				j++
+			}
+			j = min(j, len(content))
+			tokens = append(tokens, JsToken{Kind: JsTokenRegex, Value: content[i:j], Line: startLine})
+			i = j
+		default:
+			r, size := utf8.DecodeRuneInString(content[i:])
+			if isJsIdentifierStart(r) || c == '\\' {
+				j := i + size
+				for j < len(content) {
+					r, size := utf8.DecodeRuneInString(content[j:])
+					if !isJsIdentifierPart(r) {
+						break
+					}
+					j += size
+				}
+				tokens = append(tokens, JsToken{Kind: JsTokenIdentifier, Value: content[i:j], Line: startLine})
+				i = j
+				continue
+			}
+
+			punctuator := content[i : i+size]
+			for _, candidate := range jsPunctuators {
+				if strings.HasPrefix(content[i:], candidate) {
+					punctuator = candidate
+					break
+				}
+			}
+			// NOTE: `a?.5:1` is a conditional, not an optional chain
+			if punctuator == "?." && i+2 < len(content) && content[i+2] >= '0' && content[i+2] <= '9' {
+				punctuator = "?"
+			}
+			tokens = append(tokens, JsToken{Kind: JsTokenPunctuator, Value: punctuator, Line: startLine})
+			i += len(punctuator)
+		}
+	}
+
+	return tokens
+}
+
+type jsParser struct {
+	tokens []JsToken
+}
+
+func (p *jsParser) at(pos int) JsToken {
+	if pos < 0 || pos >= len(p.tokens) {
+		return JsToken{Kind: JsTokenPunctuator, Value: "", Line: -1}
+	}
+	return p.tokens[pos]
+}
+
+func (p *jsParser) is(pos int, values ...string) bool {
+	token := p.at(pos)
+	if token.Kind != JsTokenPunctuator && token.Kind != JsTokenIdentifier {
+		return false
+	}
+	for _, value := range values {
+		if token.Value == value {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *jsParser) isIdentifier(pos int) bool {
+	return p.at(pos).Kind == JsTokenIdentifier
+}
+
+// NOTE: returns the position after the bracket matching the one at pos
+func (p *jsParser) skipBalanced(pos int) int {
+	open := p.at(pos).Value
+	closing := map[string]string{"(": ")", "[": "]", "{": "}", "<": ">"}[open]
+	if closing == "" {
+		return pos + 1
+	}
+
+	depth := 0
+	for ; pos < len(p.tokens); pos++ {
+		token := p.tokens[pos]
+		if token.Kind != JsTokenPunctuator {
+			continue
+		}
+		switch token.Value {
+		case open:
+			depth++
+		case closing:
+			depth--
+			if depth == 0 {
+				return pos + 1
+			}
+		case ">>", ">>>":
+			if open == "<" {
+				depth -= len(token.Value)
+				if depth <= 0 {
+					return pos + 1
+				}
+			}
+		}
+	}
+	return pos
+}
+
+// NOTE: TypeScript type arguments are only accepted if they are followed by a call, e.g. `foo<Bar>(...)`
+func (p *jsParser) skipTypeArguments(pos int) (int, bool) {
+	if !p.is(pos, "<") {
+		return pos, false
+	}
+	depth := 0
+	for j := pos; j < len(p.tokens) && j < pos+64; j++ {
+		token := p.tokens[j]
+		if token.Kind == JsTokenPunctuator {
+			switch token.Value {
+			case "<":
+				depth++
+			case ">":
+				depth--
+			case ">>", ">>>":
+				depth -= len(token.Value)
+			case "[", "]", ",", ".", "|", "&", "?", ":", "=>", "(", ")", "{", "}", "=":
+			default:
+				return pos, false
+			}
+			if depth <= 0 {
+				if p.is(j+1, "(") {
+					return j + 1, true
+				}
+				return pos, false
+			}
+		}
+	}
+	return pos, false
+}
+
+// NOTE: skips a type annotation after a colon, returning the raw text of the type
+func (p *jsParser) skipTypeAnnotation(pos int) (int, string) {
+	start := pos
+	for pos < len(p.tokens) {
+		// NOTE: a brace after a complete type starts a body, e.g. `fetch(request): Promise<Response> {`
+		if p.is(pos, "{") && pos > start && !p.is(pos-1, "|", "&", "<", ",", "(", ":", "=>") {
+			break
+		}
+		if p.is(pos, "(", "[", "{", "<") {
+			pos = p.skipBalanced(pos)
+			continue
+		}
+		if p.is(pos, "=", ",", ";", ")", "]", "}", "=>") {
+			break
+		}
+		// NOTE: a new statement on the next line ends the type
+		if pos > start && p.at(pos).Line > p.at(pos-1).Line && !p.is(pos-1, "|", "&", ".") && !p.is(pos, "|", "&", ".") {
+			break
+		}
+		pos++
+	}
+
+	parts := make([]string, 0, pos-start)
+	for _, token := range p.tokens[start:pos] {
+		parts = append(parts, token.Value)
+	}
+	return pos, strings.Join(parts, "")
+}
+
+// NOTE: skips an expression up to the next delimiter at depth 0
+func (p *jsParser) skipExpression(pos int) int {
+	start := pos
+	for pos < len(p.tokens) {
+		if p.is(pos, "(", "[", "{") {
+			pos = p.skipBalanced(pos)
+			continue
+		}
+		if p.is(pos, ",", ";", ")", "]", "}") {
+			break
+		}
+		if pos > start && p.startsStatement(pos) {
+			break
+		}
+		pos++
+	}
+	return pos
+}
+
+// NOTE: statements without semicolons, an identifier on a new line starts one if the previous token can't continue the expression
+func (p *jsParser) startsStatement(pos int) bool {
+	if pos == 0 || !p.isIdentifier(pos) || p.at(pos).Line == p.at(pos-1).Line {
+		return false
+	}
+	if p.is(pos, "as", "satisfies", "instanceof", "in", "of") {
+		return false
+	}
+	previous := p.at(pos - 1)
+	return previous.Kind == JsTokenIdentifier || previous.Kind == JsTokenString || previous.Kind == JsTokenTemplate ||
+		previous.Kind == JsTokenNumber || previous.Kind == JsTokenRegex || p.is(pos-1, ")", "]", "}")
+}
+
+func (p *jsParser) skipFunctionBody(pos int) int {
+	for pos < len(p.tokens) && !p.is(pos, "{") {
+		if p.is(pos, "(", "[", "<") {
+			pos = p.skipBalanced(pos)
+			continue
+		}
+		if p.is(pos, ";", ",", ")", "]", "}") {
+			return pos
+		}
+		pos++
+	}
+	return p.skipBalanced(pos)
+}
+
+func (p *jsParser) skipArrowBody(pos int) int {
+	if p.is(pos, "{") {
+		return p.skipBalanced(pos)
+	}
+	return p.skipExpression(pos)
+}
+
+// NOTE: parses a member chain like `a.b?.c`, returning the parts and the position after it
+func (p *jsParser) parseMemberChain(pos int) ([]string, int) {
+	parts := []string{p.at(pos).Value}
+	pos++
+	for p.is(pos, ".", "?.") && p.isIdentifier(pos+1) {
+		parts = append(parts, p.at(pos+1).Value)
+		pos += 2
+	}
+	return parts, pos
+}
+
+func (p *jsParser) parseArguments(pos int) ([]JsValue, int) {
+	arguments := make([]JsValue, 0)
+	end := p.skipBalanced(pos)
+	pos++
+	for pos < end-1 {
+		if p.is(pos, ",") {
+			pos++
+			continue
+		}
+		if p.is(pos, "...") {
+			pos++
+		}
+		value, next := p.parseValue(pos)
+		arguments = append(arguments, value)
+		if next <= pos {
+			next = pos + 1
+		}
+		pos = next
+	}
+	return arguments, end
+}
+
+// NOTE: parses a callee chain with its calls starting at pos, e.g. `new a.b(1).c(2)`
+func (p *jsParser) parseCallChain(pos int) (*JsCall, int, bool) {
+	isNew := false
+	if p.is(pos, "new") && p.isIdentifier(pos+1) {
+		isNew = true
+		pos++
+	}
+	if !p.isIdentifier(pos) {
+		return nil, pos, false
+	}
+
+	callee, next := p.parseMemberChain(pos)
+	line := p.at(pos).Line
+
+	var call *JsCall
+	for {
+		if typeArgumentsEnd, ok := p.skipTypeArguments(next); ok {
+			next = typeArgumentsEnd
+		}
+		if !p.is(next, "(") {
+			break
+		}
+		arguments, end := p.parseArguments(next)
+		call = &JsCall{
+			Callee:    callee,
+			IsNew:     isNew && call == nil,
+			Arguments: arguments,
+			Line:      line,
+		}
+		next = end
+
+		if !p.is(next, ".", "?.") || !p.isIdentifier(next+1) {
+			break
+		}
+		chained := append([]string{}, callee...)
+		chained[len(chained)-1] += "()"
+		rest, after := p.parseMemberChain(next + 1)
+		callee = append(chained, rest...)
+		next = after
+	}
+
+	if call == nil {
+		return &JsCall{Callee: callee, IsNew: isNew, Line: line}, next, false
+	}
+	return call, next, true
+}
+
+func (p *jsParser) parseObject(pos int) (JsValue, int) {
+	value := JsValue{Kind: JsValueObject, Line: p.at(pos).Line, Properties: make([]JsProperty, 0)}
+	end := p.skipBalanced(pos)
+	pos++
+
+	for pos < end-1 {
+		if p.is(pos, ",") {
+			pos++
+			continue
+		}
+		if p.is(pos, "...") {
+			pos = p.skipExpression(pos + 1)
+			continue
+		}
+
+		// NOTE: modifiers of methods, e.g. `async fetch(request) {}`
+		for p.is(pos, "async", "get", "set", "static", "*") && !p.is(pos+1, ":", "(", ",", "}") {
+			pos++
+		}
+
+		key := ""
+		keyLine := p.at(pos).Line
+		switch token := p.at(pos); {
+		case token.Kind == JsTokenIdentifier, token.Kind == JsTokenString, token.Kind == JsTokenNumber:
+			key = token.Value
+			pos++
+		case p.is(pos, "["):
+			pos = p.skipBalanced(pos)
+		default:
+			pos = max(p.skipExpression(pos), pos+1)
+			continue
+		}
+
+		switch {
+		case p.is(pos, ":"):
+			propertyValue, next := p.parseValue(pos + 1)
+			value.Properties = append(value.Properties, JsProperty{Key: key, Value: propertyValue, Line: keyLine})
+			pos = next
+		case p.is(pos, "(", "<"):
+			value.Properties = append(value.Properties, JsProperty{
+				Key:   key,
+				Value: JsValue{Kind: JsValueFunction, Line: keyLine},
+				Line:  keyLine,
+			})
+			pos = p.skipFunctionBody(pos)
+		default:
+			// NOTE: shorthand properties and default values in patterns
+			value.Properties = append(value.Properties, JsProperty{
+				Key:   key,
+				Value: JsValue{Kind: JsValueIdentifier, Text: key, Line: keyLine},
+				Line:  keyLine,
+			})
+			if p.is(pos, "=") {
+				pos = p.skipExpression(pos + 1)
+			}
+		}
+	}
+
+	return value, end
+}
+
+func (p *jsParser) parseArray(pos int) (JsValue, int) {
+	value := JsValue{Kind: JsValueArray, Line: p.at(pos).Line, Elements: make([]JsValue, 0)}
+	end := p.skipBalanced(pos)
+	pos++
+
+	for pos < end-1 {
+		if p.is(pos, ",") {
+			pos++
+			continue
+		}
+		if p.is(pos, "...") {
+			pos++
+		}
+		element, next := p.parseValue(pos)
+		value.Elements = append(value.Elements, element)
+		if next <= pos {
+			next = pos + 1
+		}
+		pos = next
+	}
+
+	return value, end
+}
+
+func (p *jsParser) isArrowFunction(pos int) bool {
+	if p.is(pos, "async") && !p.is(pos+1, "=>") {
+		pos++
+	}
+	if p.is(pos, "<") {
+		pos = p.skipBalanced(pos)
+	}
+	switch {
+	case p.is(pos, "("):
+		pos = p.skipBalanced(pos)
+		if p.is(pos, ":") {
+			pos, _ = p.skipTypeAnnotation(pos + 1)
+		}
+		return p.is(pos, "=>")
+	case p.isIdentifier(pos):
+		return p.is(pos+1, "=>")
+	}
+	return false
+}
+
+func (p *jsParser) parseValue(pos int) (JsValue, int) {
+	token := p.at(pos)
+	value := JsValue{Kind: JsValueOther, Line: token.Line}
+	next := pos + 1
+
+	switch {
+	case token.Kind == JsTokenString:
+		value.Kind = JsValueString
+		value.Text = token.Value
+	case token.Kind == JsTokenTemplate && !strings.HasPrefix(token.Value, "`"):
+		value.Kind = JsValueString
+		value.Text = token.Value
+	case token.Kind == JsTokenNumber:
+		value.Kind = JsValueNumber
+		value.Text = token.Value
+	case p.is(pos, "true", "false"):
+		value.Kind = JsValueBoolean
+		value.Text = token.Value
+	case p.is(pos, "null", "undefined"):
+		value.Kind = JsValueNull
+	case p.is(pos, "{"):
+		value, next = p.parseObject(pos)
+	case p.is(pos, "["):
+		value, next = p.parseArray(pos)
+	case p.is(pos, "function"), p.is(pos, "async") && p.is(pos+1, "function"):
+		value.Kind = JsValueFunction
+		next = p.skipFunctionBody(pos)
+	case p.is(pos, "class"):
+		value.Kind = JsValueClass
+		next = p.skipFunctionBody(pos)
+	case p.isArrowFunction(pos):
+		value.Kind = JsValueFunction
+		for next = pos; !p.is(next, "=>") && next < len(p.tokens); next++ {
+			if p.is(next, "(", "<") {
+				next = p.skipBalanced(next) - 1
+			}
+		}
+		next = p.skipArrowBody(next + 1)
+	case p.isIdentifier(pos):
+		call, after, isCall := p.parseCallChain(pos)
+		if isCall {
+			value.Kind = JsValueCall
+			value.Call = call
+		} else {
+			value.Kind = JsValueIdentifier
+			value.Text = strings.Join(call.Callee, ".")
+		}
+		next = after
+	case p.is(pos, "("):
+		// NOTE: parenthesized expressions, e.g. `(async () => {})` or `(handler as Handler)`
+		inner, _ := p.parseValue(pos + 1)
+		value = inner
+		next = p.skipBalanced(pos)
+	default:
+		return value, p.skipExpression(pos)
+	}
+
+	// NOTE: TypeScript assertions don't change the value
+	for {
+		switch {
+		case p.is(next, "as", "satisfies"):
+			next, _ = p.skipTypeAnnotation(next + 1)
+			continue
+		case p.is(next, "!") && !p.is(next+1, "="):
+			next++
+			continue
+		}
+		break
+	}
+
+	if p.startsStatement(next) {
+		return value, next
+	}
+	end := p.skipExpression(next)
+	if end != next && value.Kind != JsValueFunction {
+		// NOTE: the value is only the start of a larger expression, e.g. a binary operation
+		value = JsValue{Kind: JsValueOther, Line: token.Line}
+	}
+	return value, end
+}
+
+func (p *jsParser) parseStringAfterFrom(pos int) (string, int) {
+	for pos < len(p.tokens) && !p.is(pos, "from", ";") {
+		if p.at(pos).Kind == JsTokenString {
+			return p.at(pos).Value, pos + 1
+		}
+		pos++
+	}
+	if p.is(pos, "from") && p.at(pos+1).Kind == JsTokenString {
+		return p.at(pos + 1).Value, pos + 2
+	}
+	return "", pos
+}
+
+func (p *jsParser) parseImport(pos int) (JsImport, int) {
+	result := JsImport{Names: make(map[string]string), Line: p.at(pos).Line}
+	pos++
+
+	if p.is(pos, "type") && !p.is(pos+1, "from", ",") {
+		pos++
+	}
+
+	if p.at(pos).Kind == JsTokenString {
+		result.Source = p.at(pos).Value
+		return result, pos + 1
+	}
+
+	if p.isIdentifier(pos) && !p.is(pos, "from") && p.is(pos+1, "=") {
+		// NOTE: TypeScript `import x = require("m")`
+		result.DefaultName = p.at(pos).Value
+		if p.is(pos+2, "require") && p.is(pos+3, "(") && p.at(pos+4).Kind == JsTokenString {
+			result.Source = p.at(pos + 4).Value
+			result.IsRequire = true
+		}
+		return result, p.skipExpression(pos + 2)
+	}
+
+	for pos < len(p.tokens) && !p.is(pos, "from", ";") {
+		switch {
+		case p.is(pos, "*") && p.is(pos+1, "as") && p.isIdentifier(pos+2):
+			result.NamespaceName = p.at(pos + 2).Value
+			pos += 3
+		case p.is(pos, "{"):
+			end := p.skipBalanced(pos)
+			for j := pos + 1; j < end-1; j++ {
+				if p.is(j, "type") && p.isIdentifier(j+1) && !p.is(j+1, "as") {
+					continue
+				}
+				if !p.isIdentifier(j) && p.at(j).Kind != JsTokenString {
+					continue
+				}
+				imported := p.at(j).Value
+				local := imported
+				if p.is(j+1, "as") && p.isIdentifier(j+2) {
+					local = p.at(j + 2).Value
+					j += 2
+				}
+				result.Names[local] = imported
+			}
+			pos = end
+		case p.isIdentifier(pos):
+			result.DefaultName = p.at(pos).Value
+			pos++
+		default:
+			pos++
+		}
+	}
+
+	result.Source, pos = p.parseStringAfterFrom(pos)
+	return result, pos
+}
+
+// NOTE: parses `const|let|var a: T = v, { b } = require("m")`
+func (p *jsParser) parseVariableDeclaration(pos int, module *JsModule, exported bool) int {
+	pos++
+	for pos < len(p.tokens) {
+		line := p.at(pos).Line
+
+		switch {
+		case p.isIdentifier(pos):
+			name := p.at(pos).Value
+			pos++
+			if p.is(pos, "!") {
+				pos++
+			}
+
+			typeAnnotation := ""
+			if p.is(pos, ":") {
+				pos, typeAnnotation = p.skipTypeAnnotation(pos + 1)
+			}
+
+			value := JsValue{Kind: JsValueNull, Line: line}
+			isAsync := false
+			if p.is(pos, "=") {
+				isAsync = p.is(pos+1, "async")
+				value, pos = p.parseValue(pos + 1)
+			}
+
+			declaration := JsDeclaration{Name: name, TypeAnnotation: typeAnnotation, Value: value, Line: line}
+			module.Declarations = append(module.Declarations, declaration)
+			if exported {
+				module.Exports = append(module.Exports, JsExport{
+					Name:           name,
+					TypeAnnotation: typeAnnotation,
+					IsAsync:        isAsync,
+					Value:          value,
+					Line:           line,
+				})
+			}
+			if value.Kind == JsValueCall && len(value.Call.Callee) == 1 && value.Call.Callee[0] == "require" {
+				if source, ok := requireSource(value.Call); ok {
+					module.Imports = append(module.Imports, JsImport{
+						Source:      source,
+						DefaultName: name,
+						Names:       make(map[string]string),
+						IsRequire:   true,
+						Line:        line,
+					})
+				}
+			}
+		case p.is(pos, "{", "["):
+			var pattern JsValue
+			if p.is(pos, "{") {
+				pattern, pos = p.parseObject(pos)
+			} else {
+				pattern, pos = p.parseArray(pos)
+			}
+			if p.is(pos, ":") {
+				pos, _ = p.skipTypeAnnotation(pos + 1)
+			}
+			if !p.is(pos, "=") {
+				break
+			}
+			value, next := p.parseValue(pos + 1)
+			pos = next
+			if value.Kind == JsValueCall && len(value.Call.Callee) == 1 && value.Call.Callee[0] == "require" {
+				if source, ok := requireSource(value.Call); ok {
+					names := make(map[string]string)
+					for _, property := range pattern.Properties {
+						local := property.Key
+						if property.Value.Kind == JsValueIdentifier {
+							local = property.Value.Text
+						}
+						names[local] = property.Key
+					}
+					module.Imports = append(module.Imports, JsImport{
+						Source:    source,
+						Names:     names,
+						IsRequire: true,
+						Line:      line,
+					})
+				}
+			}
+			for _, property := range pattern.Properties {
+				if exported {
+					module.Exports = append(module.Exports, JsExport{Name: property.Value.Text, Value: value, Line: property.Line})
+				}
+			}
+		default:
+			return pos
+		}
+
+		if !p.is(pos, ",") {
+			return pos
+		}
+		pos++
+	}
+	return pos
+}
+
+func requireSource(call *JsCall) (string, bool) {
+	if len(call.Arguments) != 1 || call.Arguments[0].Kind != JsValueString {
+		return "", false
+	}
+	return call.Arguments[0].Text, true
+}
+
+func (p *jsParser) parseExport(pos int, module *JsModule) int {
+	line := p.at(pos).Line
+	pos++
+
+	switch {
+	case p.is(pos, "default"):
+		pos++
+		isAsync := p.is(pos, "async")
+		value, next := p.parseValue(pos)
+		// NOTE: `export default function handler() {}` also declares handler
+		if (p.is(pos, "function") || p.is(pos, "class") || isAsync && p.is(pos+1, "function")) && value.Kind != JsValueOther {
+			namePos := pos + 1
+			if isAsync {
+				namePos++
+			}
+			if p.is(namePos, "*") {
+				namePos++
+			}
+			if p.isIdentifier(namePos) {
+				module.Declarations = append(module.Declarations, JsDeclaration{Name: p.at(namePos).Value, Value: value, Line: line})
+			}
+		}
+		module.Exports = append(module.Exports, JsExport{Name: "default", IsAsync: isAsync, Value: value, Line: line})
+		return next
+	case p.is(pos, "const", "let", "var"):
+		return p.parseVariableDeclaration(pos, module, true)
+	case p.is(pos, "async") && p.is(pos+1, "function"), p.is(pos, "function"), p.is(pos, "class"), p.is(pos, "abstract") && p.is(pos+1, "class"):
+		isAsync := p.is(pos, "async")
+		if isAsync || p.is(pos, "abstract") {
+			pos++
+		}
+		kind := JsValueFunction
+		if p.is(pos, "class") {
+			kind = JsValueClass
+		}
+		namePos := pos + 1
+		if p.is(namePos, "*") {
+			namePos++
+		}
+		name := p.at(namePos).Value
+		value := JsValue{Kind: kind, Line: line}
+		module.Declarations = append(module.Declarations, JsDeclaration{Name: name, Value: value, Line: line})
+		module.Exports = append(module.Exports, JsExport{Name: name, IsAsync: isAsync, Value: value, Line: line})
+		return p.skipFunctionBody(pos)
+	case p.is(pos, "{"), p.is(pos, "type") && p.is(pos+1, "{"):
+		if p.is(pos, "type") {
+			pos++
+		}
+		end := p.skipBalanced(pos)
+		for j := pos + 1; j < end-1; j++ {
+			if !p.isIdentifier(j) || p.is(j, "type") && p.isIdentifier(j+1) && !p.is(j+1, "as") {
+				continue
+			}
+			local := p.at(j).Value
+			exported := local
+			if p.is(j+1, "as") && (p.isIdentifier(j+2) || p.at(j+2).Kind == JsTokenString) {
+				exported = p.at(j + 2).Value
+				j += 2
+			}
+			module.Exports = append(module.Exports, JsExport{
+				Name:  exported,
+				Value: JsValue{Kind: JsValueIdentifier, Text: local, Line: line},
+				Line:  line,
+			})
+		}
+		pos = end
+		if p.is(pos, "from") {
+			source := p.at(pos + 1).Value
+			module.Imports = append(module.Imports, JsImport{Source: source, Names: make(map[string]string), Line: line})
+			pos += 2
+		}
+		return pos
+	case p.is(pos, "*"):
+		_, next := p.parseStringAfterFrom(pos)
+		return next
+	case p.is(pos, "="):
+		// NOTE: TypeScript `export = handler`
+		value, next := p.parseValue(pos + 1)
+		module.Exports = append(module.Exports, JsExport{Name: "default", Value: value, Line: line})
+		return next
+	}
+
+	// NOTE: type-only exports like `export type` and `export interface`
+	return pos
+}
+
+// NOTE: handles `module.exports = ...`, `module.exports.a = ...` and `exports.a = ...`
+func (p *jsParser) parseCommonJsExport(pos int, module *JsModule) (int, bool) {
+	chain, next := p.parseMemberChain(pos)
+	if !p.is(next, "=") {
+		return pos, false
+	}
+
+	name := ""
+	switch {
+	case len(chain) == 2 && chain[0] == "module" && chain[1] == "exports":
+		name = "default"
+	case len(chain) == 3 && chain[0] == "module" && chain[1] == "exports":
+		name = chain[2]
+	case len(chain) == 2 && chain[0] == "exports":
+		name = chain[1]
+	default:
+		return pos, false
+	}
+
+	line := p.at(pos).Line
+	value, end := p.parseValue(next + 1)
+	module.Exports = append(module.Exports, JsExport{Name: name, Value: value, Line: line})
+
+	// NOTE: `module.exports = { handler }` exports handler as well
+	if name == "default" && value.Kind == JsValueObject {
+		for _, property := range value.Properties {
+			module.Exports = append(module.Exports, JsExport{Name: property.Key, Value: property.Value, Line: property.Line})
+		}
+	}
+	return end, true
+}
+
+func (p *jsParser) isMethodDefinition(call *JsCall, end int) bool {
+	if call.IsNew || len(call.Callee) != 1 {
+		return false
+	}
+	if p.is(end, ":") {
+		end, _ = p.skipTypeAnnotation(end + 1)
+	}
+	return p.is(end, "{")
+}
+
+func ParseJsModule(filePath string, content string) *JsModule {
+	p := &jsParser{tokens: TokenizeJs(content)}
+	module := &JsModule{
+		Path:         filePath,
+		Imports:      make([]JsImport, 0),
+		Exports:      make([]JsExport, 0),
+		Declarations: make([]JsDeclaration, 0),
+		Calls:        make([]JsCall, 0),
+	}
+
+	// Statements at the top level
+	depth := 0
+	for pos := 0; pos < len(p.tokens); {
+		token := p.tokens[pos]
+		statementStart := pos == 0 || p.is(pos-1, ";", "{", "}") || p.at(pos-1).Line < token.Line
+
+		switch {
+		case p.is(pos, "{", "(", "["):
+			depth++
+			pos++
+		case p.is(pos, "}", ")", "]"):
+			depth = max(depth-1, 0)
+			pos++
+		case depth == 0 && statementStart && p.is(pos, "import") && !p.is(pos+1, "(", "."):
+			imported, next := p.parseImport(pos)
+			module.Imports = append(module.Imports, imported)
+			pos = max(next, pos+1)
+		case depth == 0 && statementStart && p.is(pos, "export"):
+			pos = max(p.parseExport(pos, module), pos+1)
+		case depth == 0 && statementStart && p.is(pos, "const", "let", "var"):
+			pos = max(p.parseVariableDeclaration(pos, module, false), pos+1)
+		case depth == 0 && statementStart && (p.is(pos, "function") || p.is(pos, "async") && p.is(pos+1, "function")):
+			namePos := pos + 1
+			if p.is(pos, "async") {
+				namePos++
+			}
+			if p.is(namePos, "*") {
+				namePos++
+			}
+			if p.isIdentifier(namePos) {
+				module.Declarations = append(module.Declarations, JsDeclaration{
+					Name:  p.at(namePos).Value,
+					Value: JsValue{Kind: JsValueFunction, Line: token.Line},
+					Line:  token.Line,
+				})
+			}
+			pos = max(p.skipFunctionBody(pos), pos+1)
+		case depth == 0 && statementStart && p.is(pos, "module", "exports"):
+			if next, ok := p.parseCommonJsExport(pos, module); ok {
+				pos = next
+			} else {
+				pos++
+			}
+		default:
+			pos++
+		}
+	}
+
+	// Calls anywhere in the module
+	for pos := 0; pos < len(p.tokens); pos++ {
+		if !p.isIdentifier(pos) || p.is(pos-1, ".", "?.", "function") {
+			continue
+		}
+		if p.is(pos-1, "new") {
+			continue
+		}
+		if p.is(pos, "new") && !p.isIdentifier(pos+1) {
+			continue
+		}
+		if !p.is(pos, "new") {
+			isKeyword := false
+			for _, keyword := range jsKeywordsNotCallable {
+				if p.at(pos).Value == keyword {
+					isKeyword = true
+					break
+				}
+			}
+			if isKeyword {
+				continue
+			}
+		}
+
+		// NOTE: record every call of the chain, e.g. both `a.b()` and `a.b().c()`
+		var previous *JsCall
+		for next := pos; next < len(p.tokens); {
+			partial, end, ok := p.parseCallChainLink(previous, next)
+			if !ok {
+				break
+			}
+			if p.isMethodDefinition(partial, end) {
+				break
+			}
+			module.Calls = append(module.Calls, *partial)
+			if !p.is(end, ".", "?.") {
+				break
+			}
+			previous = partial
+			next = end
+		}
+	}
+
+	return module
+}
+
+// NOTE: parses the calls of a chain one by one, so that intermediate calls are reported with their own arguments,
+// the callee of a call continues the one of the previous call, which from is positioned after at the "."
+func (p *jsParser) parseCallChainLink(previous *JsCall, from int) (*JsCall, int, bool) {
+	var callee []string
+	var next int
+	isNew := false
+	line := 0
+	if previous == nil {
+		pos := from
+		if p.is(pos, "new") {
+			isNew = true
+			pos++
+		}
+		callee, next = p.parseMemberChain(pos)
+		line = p.at(pos).Line
+	} else {
+		callee = slices.Clone(previous.Callee)
+		callee[len(callee)-1] += "()"
+		var rest []string
+		rest, next = p.parseMemberChain(from + 1)
+		callee = append(callee, rest...)
+		line = previous.Line
+	}
+
+	if typeArgumentsEnd, ok := p.skipTypeArguments(next); ok {
+		next = typeArgumentsEnd
+	}
+	if !p.is(next, "(") {
+		return nil, next, false
+	}
+	arguments, end := p.parseArguments(next)
+	return &JsCall{Callee: callee, IsNew: isNew, Arguments: arguments, Line: line}, end, true
+}
+
+func (call JsCall) CalleeName() string {
+	if len(call.Callee) == 0 {
+		return ""
+	}
+	return call.Callee[len(call.Callee)-1]
+}
+
+func (call JsCall) CalleePath() string {
+	return strings.Join(call.Callee, ".")
+}
+
+// NOTE: the receiver of a method call without the method, e.g. "app" for app.http(...)
+func (call JsCall) Receiver() string {
+	if len(call.Callee) < 2 {
+		return ""
+	}
+	return strings.Join(call.Callee[:len(call.Callee)-1], ".")
+}
+
+// NOTE: checks whether the callee ends with the given member chain, e.g. ("lambda", "Function") for aws.lambda.Function
+func (call JsCall) CalleeEndsWith(parts ...string) bool {
+	if len(parts) > len(call.Callee) {
+		return false
+	}
+	offset := len(call.Callee) - len(parts)
+	for index, part := range parts {
+		if call.Callee[offset+index] != part {
+			return false
+		}
+	}
+	return true
+}
+
+func (call JsCall) Argument(index int) *JsValue {
+	if index < 0 || index >= len(call.Arguments) {
+		return nil
+	}
+	return &call.Arguments[index]
+}
+
+func (call JsCall) StringArgument(index int) (string, bool) {
+	argument := call.Argument(index)
+	if argument == nil || argument.Kind != JsValueString {
+		return "", false
+	}
+	return argument.Text, true
+}
+
+func (value JsValue) Property(key string) *JsValue {
+	for index := range value.Properties {
+		if value.Properties[index].Key == key {
+			return &value.Properties[index].Value
+		}
+	}
+	return nil
+}
+
+func (value JsValue) StringProperty(key string) (string, bool) {
+	property := value.Property(key)
+	if property == nil || property.Kind != JsValueString {
+		return "", false
+	}
+	return property.Text, true
+}
+
+func (value JsValue) HasProperty(key string) bool {
+	return value.Property(key) != nil
+}
+
+func (module *JsModule) ImportsModule(sources ...string) bool {
+	return module.Import(sources...) != nil
+}
+
+// NOTE: matches the module itself and its subpaths, e.g. "firebase-functions" matches "firebase-functions/v2/https"
+func (module *JsModule) Import(sources ...string) *JsImport {
+	for index := range module.Imports {
+		for _, source := range sources {
+			imported := module.Imports[index].Source
+			if imported == source || strings.HasPrefix(imported, source+"/") {
+				return &module.Imports[index]
+			}
+		}
+	}
+	return nil
+}
+
+func (module *JsModule) Export(name string) *JsExport {
+	for index := range module.Exports {
+		if module.Exports[index].Name == name {
+			return &module.Exports[index]
+		}
+	}
+	return nil
+}
+
+func (module *JsModule) Declaration(name string) *JsDeclaration {
+	for index := range module.Declarations {
+		if module.Declarations[index].Name == name {
+			return &module.Declarations[index]
+		}
+	}
+	return nil
+}
+
+// NOTE: follows identifiers to the value of their top-level declaration, e.g. for `export default app`
+func (module *JsModule) ResolveValue(value JsValue) JsValue {
+	for depth := 0; depth < 8 && value.Kind == JsValueIdentifier; depth++ {
+		declaration := module.Declaration(value.Text)
+		if declaration == nil || declaration.Value.Kind == JsValueNull {
+			break
+		}
+		value = declaration.Value
+	}
+	return value
+}
+
+func (module *JsModule) CallsTo(names ...string) []JsCall {
+	result := make([]JsCall, 0)
+	for _, call := range module.Calls {
+		for _, name := range names {
+			if call.CalleeName() == name {
+				result = append(result, call)
+				break
+			}
+		}
+	}
+	return result
+}
+
+type JsModuleCache struct {
+	mutex   sync.Mutex
+	modules map[string]*JsModule
+}
+
+func NewJsModuleCache() *JsModuleCache {
+	return &JsModuleCache{modules: make(map[string]*JsModule)}
+}
+
+func (cache *JsModuleCache) Module(file TextFile) *JsModule {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	if module, ok := cache.modules[file.Path]; ok {
+		return module
+	}
+	module := ParseJsModule(file.Path, file.Content)
+	cache.modules[file.Path] = module
+	return module
+}
+
+// NOTE: checks whether the call targets a member of an imported module, so both ff.http(...) and http(...) match ("@google-cloud/functions-framework", "http")
+func (module *JsModule) CallsImport(call JsCall, source string, member ...string) bool {
+	if len(member) == 0 || len(call.Callee) == 0 {
+		return false
+	}
+
+	for _, imported := range module.Imports {
+		if imported.Source != source && !strings.HasPrefix(imported.Source, source+"/") {
+			continue
+		}
+
+		local := call.Callee[0]
+		switch {
+		case imported.Names[local] == member[0]:
+			if slices.Equal(call.Callee[1:], member[1:]) {
+				return true
+			}
+		case local == imported.DefaultName || local == imported.NamespaceName:
+			if slices.Equal(call.Callee[1:], member) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// NOTE: the member chain of a call relative to the imported module including its subpath, e.g. [https onRequest]
+// for both functions.https.onRequest(...) and onRequest(...) imported from "firebase-functions/v2/https"
+func (module *JsModule) ImportedMemberPath(call JsCall, source string) ([]string, bool) {
+	if len(call.Callee) == 0 {
+		return nil, false
+	}
+
+	for _, imported := range module.Imports {
+		if imported.Source != source && !strings.HasPrefix(imported.Source, source+"/") {
+			continue
+		}
+
+		subpath := strings.Split(strings.TrimPrefix(imported.Source, source), "/")[1:]
+		local := call.Callee[0]
+		switch {
+		case imported.Names[local] != "":
+			return slices.Concat(subpath, []string{imported.Names[local]}, call.Callee[1:]), true
+		case local == imported.DefaultName || local == imported.NamespaceName:
+			return slices.Concat(subpath, call.Callee[1:]), true
+		}
+	}
+	return nil, false
+}
+
+// NOTE: the calls a chained call is built from, e.g. functions.region("a") and functions.region().runWith({ ... })
+// for functions.region("a").runWith({ ... }).https.onRequest(fn)
+func (module *JsModule) ChainCalls(call JsCall) []JsCall {
+	module.callIndicesOnce.Do(func() {
+		module.callIndices = make(map[string][]int, len(module.Calls))
+		for index, other := range module.Calls {
+			key := jsCallKey(other.Line, other.Callee)
+			module.callIndices[key] = append(module.callIndices[key], index)
+		}
+	})
+
+	// NOTE: the calls of the chain have the callee up to a call of it, e.g. [functions region] for the "region()" of
+	// [functions region() runWith() https onRequest]
+	indices := make([]int, 0)
+	for last := 0; last < len(call.Callee)-1; last++ {
+		name, ok := strings.CutSuffix(call.Callee[last], "()")
+		if !ok {
+			continue
+		}
+		callee := append(slices.Clone(call.Callee[:last]), name)
+		indices = append(indices, module.callIndices[jsCallKey(call.Line, callee)]...)
+	}
+	slices.Sort(indices)
+
+	result := make([]JsCall, 0, len(indices))
+	for _, index := range indices {
+		result = append(result, module.Calls[index])
+	}
+	return result
+}
+
+func jsCallKey(line int, callee []string) string {
+	return fmt.Sprintf("%d:%s", line, strings.Join(callee, "."))
+}
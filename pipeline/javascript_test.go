@@ -0,0 +1,235 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+type testJsToken struct {
+	Kind  JsTokenKind
+	Value string
+}
+
+func testJsTokens(tokens []JsToken) []testJsToken {
+	result := make([]testJsToken, 0, len(tokens))
+	for _, token := range tokens {
+		result = append(result, testJsToken{Kind: token.Kind, Value: token.Value})
+	}
+	return result
+}
+
+func TestTokenizeJs(t *testing.T) {
+	identifier := func(value string) testJsToken { return testJsToken{JsTokenIdentifier, value} }
+	punctuator := func(value string) testJsToken { return testJsToken{JsTokenPunctuator, value} }
+
+	tests := []struct {
+		name    string
+		content string
+		tokens  []testJsToken
+	}{
+		{"line comment", "a // b(\"c\")\nd", []testJsToken{identifier("a"), identifier("d")}},
+		{"block comment", "a /* b(\"c\") */ d", []testJsToken{identifier("a"), identifier("d")}},
+		{"comment markers in strings", `"// a" '/* b */'`, []testJsToken{{JsTokenString, "// a"}, {JsTokenString, "/* b */"}}},
+		{"single quoted string", `'it\'s'`, []testJsToken{{JsTokenString, "it's"}}},
+		{"double quoted string", `"say \"hi\""`, []testJsToken{{JsTokenString, `say "hi"`}}},
+		{"template literal", "`plain`", []testJsToken{{JsTokenTemplate, "plain"}}},
+		{"template literal with substitution", "`a ${b} c` d", []testJsToken{{JsTokenTemplate, "`a ${b} c`"}, identifier("d")}},
+		{"nested template literals", "`a ${ `b ${c}` } d` e", []testJsToken{{JsTokenTemplate, "`a ${ `b ${c}` } d`"}, identifier("e")}},
+		{"braces in substituted strings", "`${ '}' + \"{\" }` a", []testJsToken{{JsTokenTemplate, "`${ '}' + \"{\" }`"}, identifier("a")}},
+		{"division", "a / b / c", []testJsToken{identifier("a"), punctuator("/"), identifier("b"), punctuator("/"), identifier("c")}},
+		{"division after parenthesis", "(a) / 2", []testJsToken{punctuator("("), identifier("a"), punctuator(")"), punctuator("/"), {JsTokenNumber, "2"}}},
+		{"regex after assignment", "a = /b+c/gi", []testJsToken{identifier("a"), punctuator("="), {JsTokenRegex, "/b+c/gi"}}},
+		{"regex after keyword", "return /[/]/.source", []testJsToken{identifier("return"), {JsTokenRegex, "/[/]/"}, punctuator("."), identifier("source")}},
+		{"regex with escaped slash", `f(/a\/b/)`, []testJsToken{identifier("f"), punctuator("("), {JsTokenRegex, `/a\/b/`}, punctuator(")")}},
+		{"optional chain", "a?.b", []testJsToken{identifier("a"), punctuator("?."), identifier("b")}},
+		{"conditional with decimal", "a?.5:1", []testJsToken{identifier("a"), punctuator("?"), {JsTokenNumber, ".5"}, punctuator(":"), {JsTokenNumber, "1"}}},
+		{"shebang", "#!/usr/bin/env node\na", []testJsToken{identifier("a")}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if tokens := testJsTokens(TokenizeJs(test.content)); !slices.Equal(tokens, test.tokens) {
+				t.Errorf("expected %v, got %v", test.tokens, tokens)
+			}
+		})
+	}
+}
+
+func TestTokenizeJsLines(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		line    int
+	}{
+		{"line comment", "// a\nb", 2},
+		{"block comment", "/* a\n\nb */ c", 3},
+		{"template literal", "`a\n${\n`b\n`}`\nc", 5},
+		{"string continuation", "'a\\\nb'\nc", 3},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tokens := TokenizeJs(test.content)
+			if len(tokens) == 0 {
+				t.Fatalf("expected tokens")
+			}
+			if last := tokens[len(tokens)-1]; last.Line != test.line {
+				t.Errorf("expected %s at line %d, got %d", last.Value, test.line, last.Line)
+			}
+		})
+	}
+}
+
+func TestParseJsModuleExports(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		exports []string
+	}{
+		{"default function", "export default function handler() {}", []string{"default"}},
+		{"default arrow function", "export default async (request) => new Response()", []string{"default"}},
+		{"variables", "export const a = 1, b = 2", []string{"a", "b"}},
+		{"function and class", "export async function f() {}\nexport class C {}", []string{"f", "C"}},
+		{"named", "const a = 1, b = 2\nexport { a, b as c }", []string{"a", "c"}},
+		{"re-export", `export { handler } from "./handler"`, []string{"handler"}},
+		{"re-export all", `export * from "./handler"`, []string{}},
+		{"types", "export type A = string\nexport interface B {}", []string{}},
+		{"TypeScript export assignment", "export = handler", []string{"default"}},
+		{"module.exports", "module.exports = { handler, other: fn }", []string{"default", "handler", "other"}},
+		{"module.exports member", "module.exports.handler = fn", []string{"handler"}},
+		{"exports member", "exports.api = functions.https.onRequest(app)", []string{"api"}},
+		{"commented out", "// export const a = 1\n/* exports.b = 2 */", []string{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			module := ParseJsModule("index.ts", test.content)
+			names := make([]string, 0, len(module.Exports))
+			for _, export := range module.Exports {
+				names = append(names, export.Name)
+			}
+			if !slices.Equal(names, test.exports) {
+				t.Errorf("expected exports %v, got %v", test.exports, names)
+			}
+		})
+	}
+}
+
+func TestParseJsModuleExportValues(t *testing.T) {
+	module := ParseJsModule("index.js", `
+export default function handler() {}
+export { handler as run } from "./run"
+exports.api = functions.https.onRequest(app)
+`)
+
+	if module.Declaration("handler") == nil {
+		t.Errorf("expected the default export to declare handler")
+	}
+	if run := module.Export("run"); run == nil || run.Value.Text != "handler" {
+		t.Errorf("expected run to export handler, got %+v", run)
+	}
+	if module.Import("./run") == nil {
+		t.Errorf("expected the re-export to import ./run")
+	}
+	api := module.Export("api")
+	if api == nil || api.Value.Kind != JsValueCall || api.Value.Call.CalleePath() != "functions.https.onRequest" {
+		t.Fatalf("expected api to export a call of functions.https.onRequest, got %+v", api)
+	}
+}
+
+func TestParseJsModuleTypeScript(t *testing.T) {
+	module := ParseJsModule("handler.ts", `import type { Handler } from "aws-lambda";
+
+export const handler: Handler<APIGatewayProxyEvent, Result> = async (event) => {
+  const body = JSON.parse(event.body as string) as Body;
+  const items = new Map<string, Item[]>();
+  return client.send<Output>(new PutCommand({ TableName: "table" }));
+};
+
+function isLess(a: number, b: number): boolean {
+  return a < b && b > 0;
+}
+`)
+
+	handler := module.Export("handler")
+	if handler == nil {
+		t.Fatalf("expected handler to be exported")
+	}
+	if handler.TypeAnnotation != "Handler<APIGatewayProxyEvent,Result>" {
+		t.Errorf("expected the type annotation of handler, got %q", handler.TypeAnnotation)
+	}
+	if !handler.IsAsync || handler.Value.Kind != JsValueFunction {
+		t.Errorf("expected handler to be an async function, got %+v", handler)
+	}
+	if handler.Line != 3 {
+		t.Errorf("expected handler at line 3, got %d", handler.Line)
+	}
+
+	calls := make(map[string]JsCall)
+	for _, call := range module.Calls {
+		calls[call.CalleePath()] = call
+	}
+	for _, callee := range []string{"JSON.parse", "Map", "client.send", "PutCommand"} {
+		if _, ok := calls[callee]; !ok {
+			t.Errorf("expected a call of %s, got %v", callee, module.Calls)
+		}
+	}
+	if _, ok := calls["isLess"]; ok {
+		t.Errorf("expected the function definition not to be a call")
+	}
+
+	putCommand := calls["PutCommand"]
+	if !putCommand.IsNew || putCommand.Line != 6 {
+		t.Errorf("expected new PutCommand at line 6, got %+v", putCommand)
+	}
+	if table, ok := putCommand.Argument(0).StringProperty("TableName"); !ok || table != "table" {
+		t.Errorf("expected the table name argument, got %q", table)
+	}
+	if send := calls["client.send"]; len(send.Arguments) != 1 || send.Arguments[0].Kind != JsValueCall {
+		t.Errorf("expected client.send to be called with a call, got %+v", send.Arguments)
+	}
+}
+
+func TestParseJsModuleLines(t *testing.T) {
+	module := ParseJsModule("index.js", "/*\n * a(\n */\nconst template = `\n${b()}\n`;\n\nexports.handler = async () => {\n  await c(\n    1,\n  );\n};\n")
+
+	lines := make(map[string]int)
+	for _, call := range module.Calls {
+		lines[call.CalleePath()] = call.Line
+	}
+	if _, ok := lines["a"]; ok {
+		t.Errorf("expected no call in the comment")
+	}
+	if lines["c"] != 9 {
+		t.Errorf("expected c at line 9, got %d", lines["c"])
+	}
+	if handler := module.Export("handler"); handler == nil || handler.Line != 8 {
+		t.Errorf("expected handler at line 8, got %+v", handler)
+	}
+}
+
+func TestJsModuleChainCalls(t *testing.T) {
+	module := ParseJsModule("index.js", `exports.api = functions.region("europe-west1").runWith({ memory: "1GB" }).https.onRequest(app);
+exports.other = functions.https.onRequest(other);
+`)
+
+	callees := make([]string, 0, len(module.Calls))
+	for _, call := range module.Calls {
+		callees = append(callees, call.CalleePath())
+	}
+	expected := []string{"functions.region", "functions.region().runWith", "functions.region().runWith().https.onRequest", "functions.https.onRequest"}
+	if !slices.Equal(callees, expected) {
+		t.Fatalf("expected calls %v, got %v", expected, callees)
+	}
+
+	chain := module.ChainCalls(module.Calls[2])
+	if len(chain) != 2 || chain[0].CalleeName() != "region" || chain[1].CalleeName() != "runWith" {
+		t.Fatalf("expected the region and runWith calls, got %+v", chain)
+	}
+	if region, ok := chain[0].StringArgument(0); !ok || region != "europe-west1" {
+		t.Errorf("expected the region argument, got %q", region)
+	}
+	if len(module.ChainCalls(module.Calls[3])) != 0 {
+		t.Errorf("expected no chain of the unchained call")
+	}
+}
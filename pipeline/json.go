@@ -1,6 +1,7 @@
 package main
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"github.com/pelletier/go-toml/v2"
@@ -18,6 +19,50 @@ func LoadJsonFromBytes(jsonBytes []byte) (interface{}, error) {
 	return result, nil
 }
 
+// NOTE: JSON with comments and trailing commas, e.g. wrangler.jsonc or tsconfig.json
+func LoadJsonFromJsoncBytes(jsoncBytes []byte) (interface{}, error) {
+	var sb bytes.Buffer
+	inString := false
+	for index := 0; index < len(jsoncBytes); index++ {
+		character := jsoncBytes[index]
+		switch {
+		case inString:
+			sb.WriteByte(character)
+			if character == '\\' && index+1 < len(jsoncBytes) {
+				index++
+				sb.WriteByte(jsoncBytes[index])
+			} else if character == '"' {
+				inString = false
+			}
+		case character == '"':
+			inString = true
+			sb.WriteByte(character)
+		case character == '/' && index+1 < len(jsoncBytes) && jsoncBytes[index+1] == '/':
+			for index < len(jsoncBytes) && jsoncBytes[index] != '\n' {
+				index++
+			}
+			sb.WriteByte('\n')
+		case character == '/' && index+1 < len(jsoncBytes) && jsoncBytes[index+1] == '*':
+			end := bytes.Index(jsoncBytes[index+2:], []byte("*/"))
+			if end < 0 {
+				index = len(jsoncBytes)
+			} else {
+				index += end + 3
+			}
+		case character == '}' || character == ']':
+			// NOTE: trailing commas are removed from the output written so far
+			trimmed := bytes.TrimRight(sb.Bytes(), " \t\r\n")
+			if len(trimmed) > 0 && trimmed[len(trimmed)-1] == ',' {
+				sb.Truncate(len(trimmed) - 1)
+			}
+			sb.WriteByte(character)
+		default:
+			sb.WriteByte(character)
+		}
+	}
+	return LoadJsonFromBytes(sb.Bytes())
+}
+
 func LoadJsonFromTomlBytes(tomlBytes []byte) (interface{}, error) {
 	var result interface{}
 	if err := toml.Unmarshal(tomlBytes, &result); err != nil {
@@ -131,7 +176,7 @@ func JsonResolve(obj interface{}, path []string) (interface{}, error) {
 
 		value := obj[index]
 
-		return JsonResolve(value, path)
+		return JsonResolve(value, path[1:])
 	default:
 		return nil, fmt.Errorf("cannot resolve path %v in object %v", path, obj)
 	}
@@ -201,3 +246,125 @@ func JsonResolveMap(obj interface{}, path []string) (map[string]interface{}, err
 
 	return valueMap, nil
 }
+
+// NOTE: YAML numbers are decoded as int instead of float64 and configuration values are often quoted, both are accepted
+func JsonResolveInt(obj interface{}, path []string) (int, error) {
+	value, err := JsonResolve(obj, path)
+	if err != nil {
+		return 0, err
+	}
+
+	switch value := value.(type) {
+	case int:
+		return value, nil
+	case int64:
+		return int(value), nil
+	case uint64:
+		return int(value), nil
+	case float64:
+		return int(value), nil
+	case string:
+		valueInt, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil {
+			return 0, fmt.Errorf("value at path %v is not an integer", path)
+		}
+		return valueInt, nil
+	default:
+		return 0, fmt.Errorf("value at path %v is not an integer", path)
+	}
+}
+
+// NOTE: returns the line of the key at the given path in the first document containing it, or -1 if there is none
+func YamlKeyLine(yamlBytes []byte, path []string) int {
+	decoder := yaml.NewDecoder(bytes.NewReader(yamlBytes))
+	for {
+		var document yaml.Node
+		if err := decoder.Decode(&document); err != nil {
+			return -1
+		}
+
+		node := &document
+		if node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+			node = node.Content[0]
+		}
+
+		line := -1
+		for _, key := range path {
+			// NOTE: sequences of maps are searched element by element, e.g. `functions: [{ a: ... }, { b: ... }]`
+			mappings := []*yaml.Node{node}
+			if node.Kind == yaml.SequenceNode {
+				mappings = node.Content
+			}
+
+			found := false
+			for _, mapping := range mappings {
+				if mapping.Kind != yaml.MappingNode {
+					continue
+				}
+				for index := 0; index+1 < len(mapping.Content); index += 2 {
+					if mapping.Content[index].Value == key {
+						line = mapping.Content[index].Line
+						node = mapping.Content[index+1]
+						found = true
+						break
+					}
+				}
+				if found {
+					break
+				}
+			}
+			if !found {
+				line = -1
+				break
+			}
+		}
+
+		if line != -1 {
+			return line
+		}
+	}
+}
+
+// NOTE: returns the lines of the items of the sequence at the given path in the first document containing it, or nil
+// if there is none, items of sequences on the path are selected by their index
+func YamlSequenceItemLines(yamlBytes []byte, path []string) []int {
+	decoder := yaml.NewDecoder(bytes.NewReader(yamlBytes))
+	for {
+		var document yaml.Node
+		if err := decoder.Decode(&document); err != nil {
+			return nil
+		}
+
+		node := &document
+		if node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+			node = node.Content[0]
+		}
+
+		for _, key := range path {
+			value := (*yaml.Node)(nil)
+			switch {
+			case node == nil:
+			case node.Kind == yaml.MappingNode:
+				for index := 0; index+1 < len(node.Content); index += 2 {
+					if node.Content[index].Value == key {
+						value = node.Content[index+1]
+						break
+					}
+				}
+			case node.Kind == yaml.SequenceNode:
+				if index, err := strconv.Atoi(key); err == nil && index >= 0 && index < len(node.Content) {
+					value = node.Content[index]
+				}
+			}
+			node = value
+		}
+
+		if node != nil && node.Kind == yaml.SequenceNode {
+			lines := make([]int, 0, len(node.Content))
+			for _, item := range node.Content {
+				lines = append(lines, item.Line)
+			}
+			return lines
+		}
+	}
+}
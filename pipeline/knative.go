@@ -0,0 +1,98 @@
+package main
+
+import (
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// NOTE: sources of other kinds like ApiServerSource, GitHubSource or ContainerSource emit events of other systems,
+// CronJobSource is the name of PingSource before Knative 0.13
+var knativeSourceInvocationTypes = map[string]FaaSInvocationType{
+	"PingSource":        FaaSInvocationTypeSchedule,
+	"CronJobSource":     FaaSInvocationTypeSchedule,
+	"KafkaSource":       FaaSInvocationTypeTopic,
+	"RabbitmqSource":    FaaSInvocationTypeQueue,
+	"AwsSqsSource":      FaaSInvocationTypeQueue,
+	"RedisStreamSource": FaaSInvocationTypeQueue,
+}
+
+// NOTE: whether the reference addresses the manifest, either by `ref` or by an URI like
+// `http://name.namespace.svc.cluster.local`, references without API version match resources of any group
+func (reference KubernetesReference) References(manifest KubernetesManifest) bool {
+	if reference.Name != "" {
+		if reference.Kind != manifest.Kind || reference.Name != manifest.Name {
+			return false
+		}
+		return reference.ApiVersion == "" || strings.Split(reference.ApiVersion, "/")[0] == manifest.Group()
+	}
+
+	uri, err := url.Parse(reference.Uri)
+	if err != nil || uri.Hostname() == "" {
+		return false
+	}
+	labels := strings.Split(uri.Hostname(), ".")
+	if labels[0] != manifest.Name {
+		return false
+	}
+	return len(labels) == 1 || manifest.Namespace == "" || labels[1] == manifest.Namespace
+}
+
+type KnativeServiceTrigger struct {
+	// NOTE: the trigger or source, e.g. `Trigger/orders` or `PingSource/nightly`
+	Resource       string
+	InvocationType FaaSInvocationType
+	Attributes     map[string]string
+}
+
+// NOTE: a service is invoked by the sources sinking directly to it and by the triggers subscribing it to a broker,
+// a trigger takes the invocation type of the sources sinking to its broker if they all agree, e.g. a PingSource
+// sending to the broker, otherwise it is an event delivered through a topic
+func KnativeServiceTriggers(service KnativeService, triggers []KnativeTrigger, sources []KnativeSource) []KnativeServiceTrigger {
+	result := make([]KnativeServiceTrigger, 0)
+
+	for _, source := range sources {
+		if !source.Sink.References(service.KubernetesManifest) {
+			continue
+		}
+		result = append(result, KnativeServiceTrigger{
+			Resource:       source.Kind + "/" + source.Name,
+			InvocationType: source.InvocationType,
+			Attributes:     source.Attributes,
+		})
+	}
+
+	for _, trigger := range triggers {
+		if !trigger.Subscriber.References(service.KubernetesManifest) {
+			continue
+		}
+		serviceTrigger := KnativeServiceTrigger{
+			Resource:       trigger.Kind + "/" + trigger.Name,
+			InvocationType: FaaSInvocationTypeTopic,
+			Attributes:     map[string]string{"broker": trigger.Broker},
+		}
+		if eventType, ok := trigger.Filters["type"]; ok {
+			serviceTrigger.Attributes["eventTypes"] = eventType
+		}
+
+		invocationTypes := make([]FaaSInvocationType, 0)
+		for _, source := range sources {
+			if source.Sink.Kind != "Broker" || source.Sink.Name != trigger.Broker {
+				continue
+			}
+			if !slices.Contains(invocationTypes, source.InvocationType) {
+				invocationTypes = append(invocationTypes, source.InvocationType)
+			}
+			for key, value := range source.Attributes {
+				serviceTrigger.Attributes[key] = value
+			}
+		}
+		if len(invocationTypes) == 1 {
+			serviceTrigger.InvocationType = invocationTypes[0]
+		}
+
+		result = append(result, serviceTrigger)
+	}
+
+	return result
+}
@@ -0,0 +1,535 @@
+package main
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+type YamlDocument struct {
+	Json interface{}
+	// NOTE: the line of the first line of the document in its file
+	Line int
+}
+
+// NOTE: like LoadJsonsFromYamlBytes but keeps track of the line each document starts at, documents that fail to
+// parse are skipped
+func LoadYamlDocuments(yamlBytes []byte) []YamlDocument {
+	documents := make([]YamlDocument, 0)
+
+	var sb strings.Builder
+	startLine := 1
+	flush := func() {
+		content := sb.String()
+		sb.Reset()
+		if strings.TrimSpace(content) == "" {
+			return
+		}
+		var result interface{}
+		if err := yaml.Unmarshal([]byte(content), &result); err != nil || result == nil {
+			return
+		}
+		// NOTE: leading empty lines and comments don't belong to the document
+		line := startLine
+		for _, contentLine := range strings.Split(content, "\n") {
+			if trimmed := strings.TrimSpace(contentLine); trimmed != "" && !strings.HasPrefix(trimmed, "#") {
+				break
+			}
+			line++
+		}
+		documents = append(documents, YamlDocument{Json: result, Line: line})
+	}
+
+	for index, line := range strings.Split(string(yamlBytes), "\n") {
+		if strings.TrimSpace(line) == "---" {
+			flush()
+			startLine = index + 2
+		} else {
+			sb.WriteString(fmt.Sprintf("%s\n", line))
+		}
+	}
+	flush()
+
+	return documents
+}
+
+type KubernetesManifest struct {
+	ApiVersion string
+	Kind       string
+	Name       string
+	Namespace  string
+	Json       interface{}
+
+	Path string
+	Line int
+	// NOTE: the directory of the Helm chart or kustomization the manifest was rendered by, empty for plain manifests
+	RenderedBy string
+}
+
+// NOTE: returns the manifests of a document, lists like `kind: List` are flattened
+func kubernetesManifestsOfDocument(document YamlDocument, filePath string) []KubernetesManifest {
+	apiVersion, err := JsonResolveString(document.Json, []string{"apiVersion"})
+	if err != nil {
+		return nil
+	}
+	kind, err := JsonResolveString(document.Json, []string{"kind"})
+	if err != nil {
+		return nil
+	}
+
+	if strings.HasSuffix(kind, "List") {
+		manifests := make([]KubernetesManifest, 0)
+		if items, err := JsonResolveArray(document.Json, []string{"items"}); err == nil {
+			for _, item := range items {
+				manifests = append(manifests, kubernetesManifestsOfDocument(YamlDocument{item, document.Line}, filePath)...)
+			}
+		}
+		return manifests
+	}
+
+	manifest := KubernetesManifest{
+		ApiVersion: apiVersion,
+		Kind:       kind,
+		Json:       document.Json,
+		Path:       filePath,
+		Line:       document.Line,
+	}
+	manifest.Name, _ = JsonResolveString(document.Json, []string{"metadata", "name"})
+	manifest.Namespace, _ = JsonResolveString(document.Json, []string{"metadata", "namespace"})
+	return []KubernetesManifest{manifest}
+}
+
+// NOTE: the API group of the manifest, e.g. `serving.knative.dev` for `serving.knative.dev/v1`
+func (manifest KubernetesManifest) Group() string {
+	group, _, ok := strings.Cut(manifest.ApiVersion, "/")
+	if !ok {
+		return ""
+	}
+	return group
+}
+
+func (manifest KubernetesManifest) Is(group string, kinds ...string) bool {
+	return manifest.Group() == group && slices.Contains(kinds, manifest.Kind)
+}
+
+func (manifest KubernetesManifest) String(path ...string) string {
+	value, _ := JsonResolveString(manifest.Json, path)
+	return value
+}
+
+func (manifest KubernetesManifest) Int(path ...string) (int, bool) {
+	value, err := JsonResolveInt(manifest.Json, path)
+	if err != nil {
+		return 0, false
+	}
+	return value, true
+}
+
+func (manifest KubernetesManifest) StringMap(path ...string) map[string]string {
+	result := make(map[string]string)
+	values, err := JsonResolveMap(manifest.Json, path)
+	if err != nil {
+		return result
+	}
+	for key, value := range values {
+		result[key] = fmt.Sprint(value)
+	}
+	return result
+}
+
+func (manifest KubernetesManifest) Labels() map[string]string {
+	return manifest.StringMap("metadata", "labels")
+}
+
+func (manifest KubernetesManifest) Annotations() map[string]string {
+	return manifest.StringMap("metadata", "annotations")
+}
+
+// NOTE: parses every YAML file and renders Helm charts and kustomizations only once per repository, as all
+// Kubernetes based scanners read the same manifests
+type KubernetesManifestCache struct {
+	mutex     sync.Mutex
+	documents map[string][]YamlDocument
+	manifests map[string][]KubernetesManifest
+}
+
+func NewKubernetesManifestCache() *KubernetesManifestCache {
+	return &KubernetesManifestCache{
+		documents: make(map[string][]YamlDocument),
+		manifests: make(map[string][]KubernetesManifest),
+	}
+}
+
+func (cache *KubernetesManifestCache) Documents(file TextFile) []YamlDocument {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	return cache.documentsLocked(file)
+}
+
+func (cache *KubernetesManifestCache) documentsLocked(file TextFile) []YamlDocument {
+	if documents, ok := cache.documents[file.Path]; ok {
+		return documents
+	}
+	documents := LoadYamlDocuments([]byte(file.Content))
+	cache.documents[file.Path] = documents
+	return documents
+}
+
+// NOTE: the manifests of plain files, Helm charts rendered with their default values and kustomizations, files used
+// by a kustomization are only part of the output of the outermost kustomizations using them, e.g. the overlays
+func (cache *KubernetesManifestCache) Manifests(files []TextFile) []KubernetesManifest {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	filePaths := make([]string, 0, len(files))
+	for _, file := range files {
+		filePaths = append(filePaths, file.Path)
+	}
+	key := strings.Join(filePaths, "\n")
+	if manifests, ok := cache.manifests[key]; ok {
+		return manifests
+	}
+
+	manifests := make([]KubernetesManifest, 0)
+
+	chartDirectories := make([]string, 0)
+	for _, file := range files {
+		if path.Base(file.Path) == "Chart.yaml" {
+			chartDirectories = append(chartDirectories, path.Dir(file.Path))
+		}
+	}
+	isChartFile := func(filePath string) bool {
+		for _, chartDirectory := range chartDirectories {
+			if strings.HasPrefix(filePath, path.Join(chartDirectory, "templates")+"/") {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, chartDirectory := range chartDirectories {
+		chartManifests, err := RenderHelmChart(chartDirectory, files)
+		if err != nil {
+			fmt.Printf("Failed to render Helm chart %s: %v\n", chartDirectory, err)
+		}
+		manifests = append(manifests, chartManifests...)
+	}
+
+	kustomizationManifests, kustomizedFilePaths := BuildKustomizations(files, cache.documentsLocked)
+	manifests = append(manifests, kustomizationManifests...)
+
+	for _, file := range files {
+		if isChartFile(file.Path) || slices.Contains(kustomizedFilePaths, file.Path) || isKustomizationFile(file.Path) {
+			continue
+		}
+		for _, document := range cache.documentsLocked(file) {
+			manifests = append(manifests, kubernetesManifestsOfDocument(document, file.Path)...)
+		}
+	}
+
+	cache.manifests[key] = manifests
+	return manifests
+}
+
+type KubernetesReference struct {
+	ApiVersion string
+	Kind       string
+	Name       string
+	Uri        string
+}
+
+// NOTE: references to addressables like `sink: { ref: { kind: Service, name: a } }` or `sink: { uri: http://a }`
+func kubernetesReference(manifest KubernetesManifest, path ...string) KubernetesReference {
+	return KubernetesReference{
+		ApiVersion: manifest.String(append(slices.Clone(path), "ref", "apiVersion")...),
+		Kind:       manifest.String(append(slices.Clone(path), "ref", "kind")...),
+		Name:       manifest.String(append(slices.Clone(path), "ref", "name")...),
+		Uri:        manifest.String(append(slices.Clone(path), "uri")...),
+	}
+}
+
+type KnativeService struct {
+	KubernetesManifest
+	TimeoutSeconds int
+	// NOTE: the `autoscaling.knative.dev/minScale` annotation of the revision template, 0 if not configured
+	MinInstances int
+}
+
+type KnativeTrigger struct {
+	KubernetesManifest
+	Broker     string
+	Filters    map[string]string
+	Subscriber KubernetesReference
+}
+
+type KnativeSource struct {
+	KubernetesManifest
+	Sink           KubernetesReference
+	InvocationType FaaSInvocationType
+	Attributes     map[string]string
+}
+
+type OpenFaaSFunction struct {
+	KubernetesManifest
+	FunctionName string
+	Image        string
+	// NOTE: annotations of the function itself, e.g. `topic` for connectors
+	FunctionAnnotations map[string]string
+}
+
+type FissionFunction struct {
+	KubernetesManifest
+	Environment string
+	Entrypoint  string
+}
+
+type FissionTrigger struct {
+	KubernetesManifest
+	Function       string
+	InvocationType FaaSInvocationType
+	Attributes     map[string]string
+}
+
+type KubelessFunction struct {
+	KubernetesManifest
+	Runtime string
+	Handler string
+}
+
+type KubelessTrigger struct {
+	KubernetesManifest
+	Function       string
+	FunctionLabels map[string]string
+	InvocationType FaaSInvocationType
+	Attributes     map[string]string
+}
+
+type KubernetesManifests []KubernetesManifest
+
+func (manifests KubernetesManifests) KnativeServices() []KnativeService {
+	result := make([]KnativeService, 0)
+	for _, manifest := range manifests {
+		if !manifest.Is("serving.knative.dev", "Service") {
+			continue
+		}
+		service := KnativeService{KubernetesManifest: manifest, TimeoutSeconds: -1}
+		if timeoutSeconds, ok := manifest.Int("spec", "template", "spec", "timeoutSeconds"); ok {
+			service.TimeoutSeconds = timeoutSeconds
+		}
+		if minInstances, err := strconv.Atoi(manifest.String("spec", "template", "metadata", "annotations", "autoscaling.knative.dev/minScale")); err == nil {
+			service.MinInstances = minInstances
+		}
+		result = append(result, service)
+	}
+	return result
+}
+
+func (manifests KubernetesManifests) KnativeTriggers() []KnativeTrigger {
+	result := make([]KnativeTrigger, 0)
+	for _, manifest := range manifests {
+		if !manifest.Is("eventing.knative.dev", "Trigger") {
+			continue
+		}
+		result = append(result, KnativeTrigger{
+			KubernetesManifest: manifest,
+			Broker:             manifest.String("spec", "broker"),
+			Filters:            manifest.StringMap("spec", "filter", "attributes"),
+			Subscriber:         kubernetesReference(manifest, "spec", "subscriber"),
+		})
+	}
+	return result
+}
+
+// NOTE: sources of all API groups, e.g. PingSource of `sources.knative.dev` or KafkaSource of
+// `sources.knative.dev` and older `sources.eventing.knative.dev`
+func (manifests KubernetesManifests) KnativeSources() []KnativeSource {
+	result := make([]KnativeSource, 0)
+	for _, manifest := range manifests {
+		if !strings.HasPrefix(manifest.Group(), "sources.") || !strings.HasSuffix(manifest.Group(), "knative.dev") {
+			continue
+		}
+		// NOTE: a sink binding injects its sink into another workload, which sends the events itself
+		if manifest.Kind == "SinkBinding" {
+			continue
+		}
+		source := KnativeSource{
+			KubernetesManifest: manifest,
+			Sink:               kubernetesReference(manifest, "spec", "sink"),
+			InvocationType:     FaaSInvocationTypeOther,
+			Attributes:         make(map[string]string),
+		}
+		if invocationType, ok := knativeSourceInvocationTypes[manifest.Kind]; ok {
+			source.InvocationType = invocationType
+		}
+		switch manifest.Kind {
+		case "PingSource", "CronJobSource":
+			source.Attributes["schedule"] = manifest.String("spec", "schedule")
+		case "KafkaSource":
+			source.Attributes["topics"] = strings.Join(jsonStrings(manifest.Json, []string{"spec", "topics"}), ",")
+		case "ApiServerSource":
+			kinds := make([]string, 0)
+			resources, _ := JsonResolveArray(manifest.Json, []string{"spec", "resources"})
+			for _, resource := range resources {
+				if kind, err := JsonResolveString(resource, []string{"kind"}); err == nil {
+					kinds = append(kinds, kind)
+				}
+			}
+			source.Attributes["resources"] = strings.Join(kinds, ",")
+		}
+		for key, value := range source.Attributes {
+			if value == "" {
+				delete(source.Attributes, key)
+			}
+		}
+		result = append(result, source)
+	}
+	return result
+}
+
+func (manifests KubernetesManifests) OpenFaaSFunctions() []OpenFaaSFunction {
+	result := make([]OpenFaaSFunction, 0)
+	for _, manifest := range manifests {
+		if !manifest.Is("openfaas.com", "Function") {
+			continue
+		}
+		function := OpenFaaSFunction{
+			KubernetesManifest:  manifest,
+			FunctionName:        manifest.String("spec", "name"),
+			Image:               manifest.String("spec", "image"),
+			FunctionAnnotations: manifest.StringMap("spec", "annotations"),
+		}
+		if function.FunctionName == "" {
+			function.FunctionName = manifest.Name
+		}
+		result = append(result, function)
+	}
+	return result
+}
+
+func (manifests KubernetesManifests) FissionFunctions() []FissionFunction {
+	result := make([]FissionFunction, 0)
+	for _, manifest := range manifests {
+		if !manifest.Is("fission.io", "Function") {
+			continue
+		}
+		result = append(result, FissionFunction{
+			KubernetesManifest: manifest,
+			Environment:        manifest.String("spec", "environment", "name"),
+			Entrypoint:         manifest.String("spec", "package", "functionName"),
+		})
+	}
+	return result
+}
+
+func (manifests KubernetesManifests) FissionTriggers() []FissionTrigger {
+	result := make([]FissionTrigger, 0)
+	for _, manifest := range manifests {
+		if manifest.Group() != "fission.io" {
+			continue
+		}
+		trigger := FissionTrigger{
+			KubernetesManifest: manifest,
+			Function:           manifest.String("spec", "functionref", "name"),
+			Attributes:         make(map[string]string),
+		}
+		switch manifest.Kind {
+		case "HTTPTrigger":
+			trigger.InvocationType = FaaSInvocationTypeHTTP
+			trigger.Attributes["route"] = manifest.String("spec", "relativeurl")
+			if prefix := manifest.String("spec", "prefix"); prefix != "" {
+				trigger.Attributes["route"] = prefix
+			}
+			trigger.Attributes["methods"] = strings.Join(jsonStrings(manifest.Json, []string{"spec", "methods"}), ",")
+			if method := manifest.String("spec", "method"); method != "" {
+				trigger.Attributes["methods"] = method
+			}
+		case "TimeTrigger":
+			trigger.InvocationType = FaaSInvocationTypeSchedule
+			trigger.Attributes["schedule"] = manifest.String("spec", "cron")
+		case "MessageQueueTrigger":
+			trigger.InvocationType = FaaSInvocationTypeTopic
+			trigger.Attributes["topic"] = manifest.String("spec", "topic")
+			trigger.Attributes["messageQueueType"] = manifest.String("spec", "messageQueueType")
+		case "KubernetesWatchTrigger":
+			trigger.InvocationType = FaaSInvocationTypeOther
+		default:
+			continue
+		}
+		for key, value := range trigger.Attributes {
+			if value == "" {
+				delete(trigger.Attributes, key)
+			}
+		}
+		result = append(result, trigger)
+	}
+	return result
+}
+
+func (manifests KubernetesManifests) KubelessFunctions() []KubelessFunction {
+	result := make([]KubelessFunction, 0)
+	for _, manifest := range manifests {
+		if !manifest.Is("kubeless.io", "Function") {
+			continue
+		}
+		result = append(result, KubelessFunction{
+			KubernetesManifest: manifest,
+			Runtime:            manifest.String("spec", "runtime"),
+			Handler:            manifest.String("spec", "handler"),
+		})
+	}
+	return result
+}
+
+// NOTE: HTTP and cron job triggers name their function, Kafka and NATS triggers select functions by their labels
+func (manifests KubernetesManifests) KubelessTriggers() []KubelessTrigger {
+	result := make([]KubelessTrigger, 0)
+	for _, manifest := range manifests {
+		if manifest.Group() != "kubeless.io" {
+			continue
+		}
+		trigger := KubelessTrigger{
+			KubernetesManifest: manifest,
+			Function:           manifest.String("spec", "function-name"),
+			FunctionLabels:     manifest.StringMap("spec", "functionSelector", "matchLabels"),
+			Attributes:         make(map[string]string),
+		}
+		switch manifest.Kind {
+		case "HTTPTrigger":
+			trigger.InvocationType = FaaSInvocationTypeHTTP
+			if route := manifest.String("spec", "path"); route != "" {
+				trigger.Attributes["route"] = route
+			}
+		case "CronJobTrigger":
+			trigger.InvocationType = FaaSInvocationTypeSchedule
+			trigger.Attributes["schedule"] = manifest.String("spec", "schedule")
+		case "KafkaTrigger", "NATSTrigger", "KinesisTrigger":
+			trigger.InvocationType = FaaSInvocationTypeTopic
+			if topic := manifest.String("spec", "topic"); topic != "" {
+				trigger.Attributes["topic"] = topic
+			}
+		default:
+			continue
+		}
+		result = append(result, trigger)
+	}
+	return result
+}
+
+// NOTE: whether the labels contain all labels of the selector, an empty selector selects nothing
+func matchesKubernetesLabels(labels map[string]string, selector map[string]string) bool {
+	if len(selector) == 0 {
+		return false
+	}
+	for key, value := range selector {
+		if labels[key] != value {
+			return false
+		}
+	}
+	return true
+}
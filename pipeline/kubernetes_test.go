@@ -0,0 +1,135 @@
+package main
+
+import (
+	"maps"
+	"testing"
+)
+
+func TestScanKnativeReadsManifestsChartsAndKustomizations(t *testing.T) {
+	findings := scanTestRepository(t, "knative", map[string]string{
+		"k8s/hello.yaml": `apiVersion: serving.knative.dev/v1
+kind: Service
+metadata:
+  name: hello
+spec:
+  template:
+    metadata:
+      annotations:
+        autoscaling.knative.dev/minScale: "1"
+    spec:
+      timeoutSeconds: 120
+---
+apiVersion: sources.knative.dev/v1
+kind: PingSource
+metadata:
+  name: ping
+spec:
+  schedule: "*/5 * * * *"
+  sink:
+    ref:
+      apiVersion: serving.knative.dev/v1
+      kind: Service
+      name: hello
+`,
+		"charts/api/Chart.yaml":  "apiVersion: v2\nname: api\nversion: 1.0.0\n",
+		"charts/api/values.yaml": "timeoutSeconds: 60\nbroker: default\n",
+		"charts/api/templates/_helpers.tpl": `{{- define "api.fullname" -}}
+{{ .Release.Name }}-{{ .Chart.Name }}
+{{- end }}
+`,
+		"charts/api/templates/service.yaml": `apiVersion: serving.knative.dev/v1
+kind: Service
+metadata:
+  name: {{ include "api.fullname" . }}
+spec:
+  template:
+    spec:
+      timeoutSeconds: {{ .Values.timeoutSeconds }}
+`,
+		"charts/api/templates/trigger.yaml": `apiVersion: eventing.knative.dev/v1
+kind: Trigger
+metadata:
+  name: {{ include "api.fullname" . }}-orders
+spec:
+  broker: {{ .Values.broker }}
+  filter:
+    attributes:
+      type: order.created
+  subscriber:
+    ref:
+      apiVersion: serving.knative.dev/v1
+      kind: Service
+      name: {{ include "api.fullname" . }}
+`,
+		"kustomize/base/kustomization.yaml": "resources:\n  - service.yaml\n",
+		"kustomize/base/service.yaml": `apiVersion: serving.knative.dev/v1
+kind: Service
+metadata:
+  name: worker
+spec:
+  template:
+    spec:
+      timeoutSeconds: 30
+`,
+		"kustomize/overlays/prod/kustomization.yaml": `resources:
+  - ../../base
+namePrefix: prod-
+patches:
+  - path: timeout.yaml
+`,
+		"kustomize/overlays/prod/timeout.yaml": `apiVersion: serving.knative.dev/v1
+kind: Service
+metadata:
+  name: worker
+spec:
+  template:
+    spec:
+      timeoutSeconds: 300
+`,
+	})
+
+	tests := []struct {
+		name           string
+		invocationType FaaSInvocationType
+		sourceFilePath string
+		sourceFileLine int
+		timeoutSeconds int
+		minInstances   int
+		attributes     map[string]string
+	}{
+		{
+			"hello", FaaSInvocationTypeSchedule, "k8s/hello.yaml", 1, 120, 1,
+			map[string]string{"triggers": "PingSource/ping", "schedule": "*/5 * * * *"},
+		},
+		{
+			"release-api", FaaSInvocationTypeTopic, "charts/api/templates/service.yaml", -1, 60, 0,
+			map[string]string{"triggers": "Trigger/release-api-orders", "broker": "default", "eventTypes": "order.created"},
+		},
+		{"prod-worker", FaaSInvocationTypeHTTP, "kustomize/base/service.yaml", 1, 300, 0, nil},
+	}
+
+	functions := testFunctionsByName(t, findings)
+	if len(functions) != len(tests) {
+		t.Errorf("expected %d functions, got %d", len(tests), len(functions))
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			function, ok := functions[test.name]
+			if !ok {
+				t.Fatalf("function %s was not found", test.name)
+			}
+			if function.InvocationType != test.invocationType {
+				t.Errorf("expected invocation type %s, got %s", test.invocationType, function.InvocationType)
+			}
+			if function.SourceFilePath != testRepositoryDirectory+"/"+test.sourceFilePath || function.SourceFileLine != test.sourceFileLine {
+				t.Errorf("expected %s:%d, got %s:%d", test.sourceFilePath, test.sourceFileLine, function.SourceFilePath, function.SourceFileLine)
+			}
+			if function.TimeoutSeconds != test.timeoutSeconds || function.MinInstances != test.minInstances {
+				t.Errorf("expected a timeout of %d s and %d min instances, got %d s and %d", test.timeoutSeconds, test.minInstances, function.TimeoutSeconds, function.MinInstances)
+			}
+			if !maps.Equal(function.Attributes, test.attributes) {
+				t.Errorf("expected attributes %v, got %v", test.attributes, function.Attributes)
+			}
+		})
+	}
+}
@@ -0,0 +1,356 @@
+package main
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const kustomizeMaxDepth = 8
+
+var kustomizationFileNames = []string{"kustomization.yaml", "kustomization.yml", "Kustomization"}
+
+func isKustomizationFile(filePath string) bool {
+	return slices.Contains(kustomizationFileNames, path.Base(filePath))
+}
+
+type kustomizeBuilder struct {
+	files     map[string]TextFile
+	documents func(file TextFile) []YamlDocument
+	// NOTE: all files and kustomizations used by a kustomization
+	usedFilePaths []string
+}
+
+// NOTE: builds the outermost kustomizations, i.e. the ones no other kustomization uses, like overlays of a base, and
+// returns their manifests and the files they used
+func BuildKustomizations(files []TextFile, documents func(file TextFile) []YamlDocument) ([]KubernetesManifest, []string) {
+	builder := kustomizeBuilder{
+		files:         make(map[string]TextFile),
+		documents:     documents,
+		usedFilePaths: make([]string, 0),
+	}
+	kustomizationDirectories := make([]string, 0)
+	for _, file := range files {
+		builder.files[file.Path] = file
+		if isKustomizationFile(file.Path) {
+			kustomizationDirectories = append(kustomizationDirectories, path.Dir(file.Path))
+		}
+	}
+
+	builds := make(map[string][]kustomizedManifest)
+	for _, kustomizationDirectory := range kustomizationDirectories {
+		builds[kustomizationDirectory] = builder.build(kustomizationDirectory, 0)
+	}
+
+	manifests := make([]KubernetesManifest, 0)
+	for _, kustomizationDirectory := range kustomizationDirectories {
+		if builder.isUsedKustomization(kustomizationDirectory) {
+			continue
+		}
+		for _, manifest := range builds[kustomizationDirectory] {
+			manifests = append(manifests, manifest.KubernetesManifest)
+		}
+	}
+	return manifests, builder.usedFilePaths
+}
+
+func (builder *kustomizeBuilder) kustomization(directory string) (TextFile, bool) {
+	for _, fileName := range kustomizationFileNames {
+		if file, ok := builder.files[path.Join(directory, fileName)]; ok {
+			return file, true
+		}
+	}
+	return TextFile{}, false
+}
+
+func (builder *kustomizeBuilder) isUsedKustomization(directory string) bool {
+	file, ok := builder.kustomization(directory)
+	return ok && slices.Contains(builder.usedFilePaths, file.Path)
+}
+
+func (builder *kustomizeBuilder) use(filePath string) {
+	if !slices.Contains(builder.usedFilePaths, filePath) {
+		builder.usedFilePaths = append(builder.usedFilePaths, filePath)
+	}
+}
+
+type kustomizedManifest struct {
+	KubernetesManifest
+	// NOTE: patches refer to resources by their names before prefixes and suffixes were added
+	originalName string
+}
+
+func (builder *kustomizeBuilder) build(directory string, depth int) []kustomizedManifest {
+	kustomizationFile, ok := builder.kustomization(directory)
+	if !ok || depth > kustomizeMaxDepth {
+		return nil
+	}
+	kustomizationDocuments := builder.documents(kustomizationFile)
+	if len(kustomizationDocuments) != 1 {
+		return nil
+	}
+	kustomization := kustomizationDocuments[0].Json
+
+	manifests := make([]kustomizedManifest, 0)
+	resources := slices.Concat(
+		jsonStrings(kustomization, []string{"resources"}),
+		jsonStrings(kustomization, []string{"bases"}),
+		jsonStrings(kustomization, []string{"components"}),
+	)
+	for _, resource := range resources {
+		// NOTE: remote resources like `github.com/org/repo//path?ref=v1` can't be resolved
+		if strings.Contains(resource, "://") || strings.HasPrefix(resource, "github.com/") {
+			continue
+		}
+		resourcePath := path.Join(directory, resource)
+
+		if file, ok := builder.files[resourcePath]; ok {
+			builder.use(file.Path)
+			for _, document := range builder.documents(file) {
+				for _, manifest := range kubernetesManifestsOfDocument(document, file.Path) {
+					manifests = append(manifests, kustomizedManifest{manifest, manifest.Name})
+				}
+			}
+			continue
+		}
+
+		if resourceKustomizationFile, ok := builder.kustomization(resourcePath); ok {
+			builder.use(resourceKustomizationFile.Path)
+			manifests = append(manifests, builder.build(resourcePath, depth+1)...)
+		}
+	}
+
+	builder.applyPatches(directory, kustomization, manifests)
+
+	namePrefix, _ := JsonResolveString(kustomization, []string{"namePrefix"})
+	nameSuffix, _ := JsonResolveString(kustomization, []string{"nameSuffix"})
+	namespace, _ := JsonResolveString(kustomization, []string{"namespace"})
+
+	result := make([]kustomizedManifest, 0, len(manifests))
+	for _, manifest := range manifests {
+		if namePrefix != "" || nameSuffix != "" {
+			manifest.Name = namePrefix + manifest.Name + nameSuffix
+			manifest.Json = mergeJsons(manifest.Json, map[string]interface{}{
+				"metadata": map[string]interface{}{"name": manifest.Name},
+			})
+		}
+		if namespace != "" {
+			manifest.Namespace = namespace
+			manifest.Json = mergeJsons(manifest.Json, map[string]interface{}{
+				"metadata": map[string]interface{}{"namespace": namespace},
+			})
+		}
+		manifest.RenderedBy = directory
+		result = append(result, manifest)
+	}
+	return result
+}
+
+type kustomizePatch struct {
+	// NOTE: either a strategic merge patch or the operations of a JSON patch
+	patch      interface{}
+	targetKind string
+	targetName string
+}
+
+func (builder *kustomizeBuilder) applyPatches(directory string, kustomization interface{}, manifests []kustomizedManifest) {
+	patches := make([]kustomizePatch, 0)
+
+	loadPatches := func(patchPath string, inlinePatch string, targetKind string, targetName string) {
+		content := inlinePatch
+		if patchPath != "" {
+			file, ok := builder.files[path.Join(directory, patchPath)]
+			if !ok {
+				return
+			}
+			builder.use(file.Path)
+			content = file.Content
+		}
+		for _, document := range LoadYamlDocuments([]byte(content)) {
+			patches = append(patches, kustomizePatch{document.Json, targetKind, targetName})
+		}
+	}
+
+	for _, patchPath := range jsonStrings(kustomization, []string{"patchesStrategicMerge"}) {
+		loadPatches(patchPath, "", "", "")
+	}
+	for _, key := range []string{"patches", "patchesJson6902"} {
+		patchConfigs, err := JsonResolveArray(kustomization, []string{key})
+		if err != nil {
+			continue
+		}
+		for _, patchConfig := range patchConfigs {
+			if patchPath, ok := patchConfig.(string); ok {
+				loadPatches(patchPath, "", "", "")
+				continue
+			}
+			patchPath, _ := JsonResolveString(patchConfig, []string{"path"})
+			inlinePatch, _ := JsonResolveString(patchConfig, []string{"patch"})
+			targetKind, _ := JsonResolveString(patchConfig, []string{"target", "kind"})
+			targetName, _ := JsonResolveString(patchConfig, []string{"target", "name"})
+			loadPatches(patchPath, inlinePatch, targetKind, targetName)
+		}
+	}
+
+	for _, patch := range patches {
+		operations, isJsonPatch := patch.patch.([]interface{})
+		targetKind, targetName := patch.targetKind, patch.targetName
+		if !isJsonPatch && targetKind == "" && targetName == "" {
+			targetKind, _ = JsonResolveString(patch.patch, []string{"kind"})
+			targetName, _ = JsonResolveString(patch.patch, []string{"metadata", "name"})
+		}
+
+		for index, manifest := range manifests {
+			if targetKind != "" && manifest.Kind != targetKind {
+				continue
+			}
+			if targetName != "" && manifest.originalName != targetName && manifest.Name != targetName {
+				continue
+			}
+			if isJsonPatch {
+				manifests[index].Json = applyJsonPatch(manifest.Json, operations)
+			} else {
+				manifests[index].Json = strategicMergeJsons(manifest.Json, patch.patch)
+			}
+			// NOTE: patches name their target by its original name, which must not replace the current one
+			manifests[index].Json = mergeJsons(manifests[index].Json, map[string]interface{}{
+				"metadata": map[string]interface{}{"name": manifest.Name},
+			})
+		}
+	}
+}
+
+// NOTE: like mergeJsons, but lists of objects with a name, e.g. containers, are merged by name
+func strategicMergeJsons(base interface{}, patch interface{}) interface{} {
+	switch patch := patch.(type) {
+	case map[string]interface{}:
+		baseMap, ok := base.(map[string]interface{})
+		if !ok {
+			return patch
+		}
+		result := make(map[string]interface{}, len(baseMap)+len(patch))
+		for key, value := range baseMap {
+			result[key] = value
+		}
+		for key, value := range patch {
+			if baseValue, ok := result[key]; ok {
+				result[key] = strategicMergeJsons(baseValue, value)
+			} else {
+				result[key] = value
+			}
+		}
+		return result
+	case []interface{}:
+		baseList, ok := base.([]interface{})
+		if !ok {
+			return patch
+		}
+		result := slices.Clone(baseList)
+		for _, patchItem := range patch {
+			patchName, err := JsonResolveString(patchItem, []string{"name"})
+			if err != nil {
+				return patch
+			}
+			merged := false
+			for index, baseItem := range result {
+				if baseName, err := JsonResolveString(baseItem, []string{"name"}); err == nil && baseName == patchName {
+					result[index] = strategicMergeJsons(baseItem, patchItem)
+					merged = true
+				}
+			}
+			if !merged {
+				result = append(result, patchItem)
+			}
+		}
+		return result
+	default:
+		return patch
+	}
+}
+
+// NOTE: supports the add, replace and remove operations of RFC 6902, other operations are ignored
+func applyJsonPatch(document interface{}, operations []interface{}) interface{} {
+	for _, operation := range operations {
+		op, _ := JsonResolveString(operation, []string{"op"})
+		pointer, _ := JsonResolveString(operation, []string{"path"})
+		value, _ := JsonResolve(operation, []string{"value"})
+		if !slices.Contains([]string{"add", "replace", "remove"}, op) || !strings.HasPrefix(pointer, "/") {
+			continue
+		}
+
+		tokens := strings.Split(pointer[1:], "/")
+		for index, token := range tokens {
+			tokens[index] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		}
+		if patched, err := patchJsonPointer(document, tokens, op, value); err == nil {
+			document = patched
+		}
+	}
+	return document
+}
+
+func patchJsonPointer(document interface{}, tokens []string, op string, value interface{}) (interface{}, error) {
+	token := tokens[0]
+	isLast := len(tokens) == 1
+
+	switch document := document.(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(document))
+		for key, child := range document {
+			result[key] = child
+		}
+		if isLast {
+			if op == "remove" {
+				delete(result, token)
+			} else {
+				result[token] = value
+			}
+			return result, nil
+		}
+		child, ok := document[token]
+		if !ok {
+			return nil, fmt.Errorf("missing key %s", token)
+		}
+		patched, err := patchJsonPointer(child, tokens[1:], op, value)
+		if err != nil {
+			return nil, err
+		}
+		result[token] = patched
+		return result, nil
+	case []interface{}:
+		result := slices.Clone(document)
+		index, err := strconv.Atoi(token)
+		if token == "-" {
+			index, err = len(result), nil
+		}
+		if err != nil || index < 0 || index > len(result) {
+			return nil, fmt.Errorf("invalid index %s", token)
+		}
+		if isLast {
+			switch {
+			case op == "add":
+				return slices.Insert(result, index, value), nil
+			case index == len(result):
+				return nil, fmt.Errorf("invalid index %s", token)
+			case op == "remove":
+				return slices.Delete(result, index, index+1), nil
+			default:
+				result[index] = value
+				return result, nil
+			}
+		}
+		if index == len(result) {
+			return nil, fmt.Errorf("invalid index %s", token)
+		}
+		patched, err := patchJsonPointer(result[index], tokens[1:], op, value)
+		if err != nil {
+			return nil, err
+		}
+		result[index] = patched
+		return result, nil
+	default:
+		return nil, fmt.Errorf("can't patch %v", document)
+	}
+}
@@ -1,9 +1,12 @@
 // create a entrypoint for the application
 package main
 
+import "slices"
+
 const (
 	REPOSITORY_INFOS_DIRECTORY                             = "data/repositoryInfos"
 	REPOSITORIES_DIRECTORY                                 = "data/repositories"
+	REPOSITORY_MANIFESTS_DIRECTORY                         = "data/repositoryManifests"
 	RAW_REPOSITORY_EVENTS_DIRECTORY                        = "data/rawRepositoryEvents"
 	REPOSITORY_EVENTS_DIRECTORY                            = "data/repositoryEvents"
 	REPOSITORIES_ISSUES_COMMITS_AND_CONTRIBUTORS_DIRECTORY = "data/repositoryIssuesCommitsAndContributorsDirectory"
@@ -14,9 +17,37 @@ const (
 	REPOSITORY_IDS_PATH                 = "data/repositoryIds.json"
 	RELEVANT_REPOSITORY_IDS_PATH        = "data/relevantRepositoryIds.json"
 	HIGHLY_RELEVANT_REPOSITORY_IDS_PATH = "data/highlyRelevantRepositoryIds.json"
+	REPOSITORY_DOWNLOAD_FAILURES_PATH   = "data/repositoryDownloadFailures.json"
+
+	HIGHLY_RELEVANT_REPOSITORIES_SAMPLE_PATH            = "data/highlyRelevantRepositoriesSample.csv"
+	HIGHLY_RELEVANT_REPOSITORIES_SAMPLE_EVALUATION_PATH = "data/highlyRelevantRepositoriesSampleEvaluation.json"
 )
 
 var (
+	REPOSITORY_ARCHIVE_LIMITS = ArchiveLimits{
+		MaxArchiveSizeBytes:           512 * 1024 * 1024,
+		MaxFiles:                      100_000,
+		MaxExtractedSizeBytes:         2 * 1024 * 1024 * 1024,
+		MaxCompressionRatio:           200,
+		MinCompressionRatioCheckBytes: 1024 * 1024,
+	}
+	// NOTE: sparse clones lack the files the complexity metrics count, so repositories are cloned completely unless
+	// enabled, see REPOSITORY_SPARSE_FILTER
+	REPOSITORY_SPARSE_CLONES = false
+	REPOSITORY_SPARSE_FILTER = &ArchiveEntryFilter{
+		// NOTE: disabled scanners are ignored here, so that enabling them later doesn't require cloning again
+		IncludePatterns: AnalysisFilePatterns(DefaultScannerRegistry()),
+		ExcludeDirectories: append(slices.Clone(EXCLUDE_DIRECTORIES),
+			"/dist/",
+			"/build/",
+			"/vendor/",
+			"/.next/",
+			"/.git/",
+			"/coverage/",
+		),
+		MaxFileSizeBytes: 2 * 1024 * 1024,
+		SkipBinaryFiles:  true,
+	}
 	DOCUMENTATION_FILES = []string{
 		"readme.md",
 		"README.md",
@@ -69,6 +100,8 @@ var (
 		"library",
 		"plugin",
 	}
+	// NOTE: names as registered in DefaultScannerRegistry
+	DISABLED_SCANNERS   = []string{}
 	EXCLUDE_DIRECTORIES = []string{
 		"node_modules",
 		"test",
@@ -168,7 +201,22 @@ func cloneRelevantRepositories() {
 		panic(err)
 	}
 
-	if err := DownloadRepositories(repositoryIds, REPOSITORY_INFOS_DIRECTORY, REPOSITORIES_DIRECTORY); err != nil {
+	var filter *ArchiveEntryFilter
+	if REPOSITORY_SPARSE_CLONES {
+		filter = REPOSITORY_SPARSE_FILTER
+	}
+
+	if err := DownloadRepositories(
+		20,
+		repositoryIds,
+		REPOSITORY_INFOS_DIRECTORY,
+		REPOSITORIES_DIRECTORY,
+		REPOSITORY_MANIFESTS_DIRECTORY,
+		REPOSITORY_ARCHIVE_LIMITS,
+		filter,
+		REPOSITORY_DOWNLOAD_FAILURES_PATH,
+		true,
+	); err != nil {
 		panic(err)
 	}
 }
@@ -194,13 +242,20 @@ func aggregateRelevantRepositoryData() {
 		panic(err)
 	}
 
+	scannerRegistry, err := DefaultScannerRegistry().Without(DISABLED_SCANNERS...)
+	if err != nil {
+		panic(err)
+	}
+
 	AggregateRepositoriesData(
 		20,
 		repositoryIds,
 		REPOSITORIES_DIRECTORY,
 		REPOSITORY_INFOS_DIRECTORY,
 		REPOSITORIES_ISSUES_COMMITS_AND_CONTRIBUTORS_DIRECTORY,
+		REPOSITORY_MANIFESTS_DIRECTORY,
 		EXCLUDE_DIRECTORIES,
+		scannerRegistry,
 		REPOSITORIES_DATA_DIRECTORY,
 	)
 }
@@ -270,6 +325,39 @@ func exportRepositories() {
 	}
 }
 
+func sampleHighlyRelevantRepositories() {
+	highlyRelevantRepositoryIds, err := LoadRepositoryIds(HIGHLY_RELEVANT_REPOSITORY_IDS_PATH)
+	if err != nil {
+		panic(err)
+	}
+
+	if err := SampleRepositories(
+		highlyRelevantRepositoryIds,
+		RepositorySampleStratificationPlatform,
+		10,
+		42,
+		KEYWORDS,
+		DOCUMENTATION_FILES,
+		REPOSITORY_INFOS_DIRECTORY,
+		REPOSITORY_EVENTS_DIRECTORY,
+		REPOSITORIES_DIRECTORY,
+		REPOSITORIES_DATA_DIRECTORY,
+		HIGHLY_RELEVANT_REPOSITORIES_SAMPLE_PATH,
+	); err != nil {
+		panic(err)
+	}
+}
+
+func evaluateHighlyRelevantRepositoriesSample() {
+	if err := EvaluateRepositorySampleSheet(
+		HIGHLY_RELEVANT_REPOSITORIES_SAMPLE_PATH,
+		1.96,
+		HIGHLY_RELEVANT_REPOSITORIES_SAMPLE_EVALUATION_PATH,
+	); err != nil {
+		panic(err)
+	}
+}
+
 // main function
 func main() {
 	// prepareRepositoryQueries()
@@ -284,6 +372,8 @@ func main() {
 	aggregateRelevantRepositoryData()
 	filterHighlyRelevantRepositories()
 	exportRepositories()
+	// sampleHighlyRelevantRepositories()
+	// evaluateHighlyRelevantRepositoriesSample()
 	// 2768 -> 2651 -> 2383
 	// 764 -> 647 -> 572 -> 354
 
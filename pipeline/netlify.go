@@ -0,0 +1,251 @@
+package main
+
+import (
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+	"golang.org/x/exp/maps"
+)
+
+// NOTE: background functions run asynchronously for up to 15 minutes, their name ends with `-background`
+const netlifyBackgroundFunctionSuffix = "-background"
+const netlifyBackgroundFunctionTimeoutSeconds = 15 * 60
+
+type NetlifyFunctionConfig struct {
+	Pattern       string
+	Schedule      string
+	NodeBundler   string
+	IncludedFiles []string
+}
+
+type NetlifyEdgeFunctionDeclaration struct {
+	Function string
+	Path     string
+}
+
+type NetlifyConfig struct {
+	Path string
+	// NOTE: the base directory of the build relative to the repository
+	Base                   string
+	FunctionsDirectory     string
+	EdgeFunctionsDirectory string
+	// NOTE: configuration of all functions, i.e. `[functions]`, comes first and is overridden by the configuration of
+	// single functions, i.e. `[functions."name"]`, in the order of the file
+	Functions     []NetlifyFunctionConfig
+	EdgeFunctions []NetlifyEdgeFunctionDeclaration
+	Plugins       []string
+}
+
+func LoadNetlifyConfig(file TextFile) (NetlifyConfig, error) {
+	config := NetlifyConfig{Path: file.Path}
+
+	netlifyConfig, err := LoadJsonFromTomlBytes([]byte(file.Content))
+	if err != nil {
+		return config, err
+	}
+
+	buildBase, err := JsonResolveString(netlifyConfig, []string{"build", "base"})
+	if err != nil {
+		buildBase = ""
+	}
+	config.Base = path.Join(path.Dir(file.Path), buildBase)
+
+	functionsBase := ""
+	if v1, err := JsonResolveString(netlifyConfig, []string{"functions", "directory"}); err == nil {
+		functionsBase = v1
+	} else if v2, err := JsonResolveString(netlifyConfig, []string{"build", "functions"}); err == nil {
+		functionsBase = v2
+	} else {
+		functionsBase = "netlify/functions"
+	}
+	config.FunctionsDirectory = path.Join(config.Base, functionsBase)
+
+	edgeFunctionsBase := ""
+	if v, err := JsonResolveString(netlifyConfig, []string{"build", "edge_functions"}); err == nil {
+		edgeFunctionsBase = v
+	} else {
+		edgeFunctionsBase = "netlify/edge-functions"
+	}
+	config.EdgeFunctionsDirectory = path.Join(config.Base, edgeFunctionsBase)
+
+	if functions, err := JsonResolveMap(netlifyConfig, []string{"functions"}); err == nil {
+		config.Functions = append(config.Functions, netlifyFunctionConfig("*", functions))
+
+		patterns := maps.Keys(functions)
+		slices.Sort(patterns)
+		for _, pattern := range patterns {
+			if functionConfig, ok := functions[pattern].(map[string]interface{}); ok {
+				config.Functions = append(config.Functions, netlifyFunctionConfig(pattern, functionConfig))
+			}
+		}
+	}
+
+	if edgeFunctions, err := JsonResolveArray(netlifyConfig, []string{"edge_functions"}); err == nil {
+		for _, edgeFunction := range edgeFunctions {
+			function, err := JsonResolveString(edgeFunction, []string{"function"})
+			if err != nil {
+				continue
+			}
+			edgeFunctionPath, _ := JsonResolveString(edgeFunction, []string{"path"})
+			config.EdgeFunctions = append(config.EdgeFunctions, NetlifyEdgeFunctionDeclaration{function, edgeFunctionPath})
+		}
+	}
+
+	if plugins, err := JsonResolveArray(netlifyConfig, []string{"plugins"}); err == nil {
+		for _, plugin := range plugins {
+			if pluginPackage, err := JsonResolveString(plugin, []string{"package"}); err == nil {
+				config.Plugins = append(config.Plugins, pluginPackage)
+			}
+		}
+	}
+
+	return config, nil
+}
+
+func netlifyFunctionConfig(pattern string, functionConfig map[string]interface{}) NetlifyFunctionConfig {
+	result := NetlifyFunctionConfig{Pattern: pattern}
+	result.Schedule, _ = JsonResolveString(functionConfig, []string{"schedule"})
+	result.NodeBundler, _ = JsonResolveString(functionConfig, []string{"node_bundler"})
+	result.IncludedFiles = jsonStrings(functionConfig, []string{"included_files"})
+	return result
+}
+
+// NOTE: the configuration of a function merged from all matching patterns, e.g. `*` and `"api-*"`
+func (config NetlifyConfig) FunctionConfig(name string) NetlifyFunctionConfig {
+	result := NetlifyFunctionConfig{Pattern: name}
+	for _, functionConfig := range config.Functions {
+		if matches, err := doublestar.Match(functionConfig.Pattern, name); err != nil || !matches {
+			continue
+		}
+		if functionConfig.Schedule != "" {
+			result.Schedule = functionConfig.Schedule
+		}
+		if functionConfig.NodeBundler != "" {
+			result.NodeBundler = functionConfig.NodeBundler
+		}
+		if len(functionConfig.IncludedFiles) > 0 {
+			result.IncludedFiles = functionConfig.IncludedFiles
+		}
+	}
+	return result
+}
+
+func (config NetlifyConfig) EdgeFunctionPaths(name string) []string {
+	paths := make([]string, 0)
+	for _, edgeFunction := range config.EdgeFunctions {
+		if edgeFunction.Function == name && edgeFunction.Path != "" {
+			paths = append(paths, edgeFunction.Path)
+		}
+	}
+	return paths
+}
+
+// NOTE: functions are files directly in the functions directory or directories with an entry file of the same name
+// or named index, e.g. `functions/hello.ts`, `functions/hello/hello.ts` or `functions/hello/index.ts`, all other
+// files are modules imported by the functions
+func netlifyFunctionName(functionsDirectory string, filePath string) (string, bool) {
+	relativePath := strings.TrimPrefix(filePath, functionsDirectory+"/")
+	if functionsDirectory == "." {
+		relativePath = filePath
+	}
+	name := strings.TrimSuffix(path.Base(relativePath), path.Ext(relativePath))
+
+	switch strings.Count(relativePath, "/") {
+	case 0:
+		return name, true
+	case 1:
+		directoryName := path.Dir(relativePath)
+		if name == directoryName || name == "index" {
+			return directoryName, true
+		}
+	}
+	return "", false
+}
+
+// NOTE: framework plugins generate functions during the build, e.g. the Next.js runtime renders all pages and API
+// routes in a single server handler and runs the middleware as an edge function
+var netlifyFrameworkPlugins = []struct {
+	plugin        string
+	functions     []string
+	edgeFunctions []string
+}{
+	{"@netlify/plugin-nextjs", []string{"___netlify-server-handler"}, []string{"___netlify-edge-handler-middleware"}},
+	{"@netlify/plugin-gatsby", []string{"__api", "__ssr", "__dsg"}, nil},
+	{"@netlify/angular-runtime", []string{"server"}, nil},
+}
+
+var netlifyLambdaBuildRegexp = regexp.MustCompile(`netlify-lambda\s+build\s+([^\s;&|]+)`)
+
+// NOTE: netlify-lambda bundles the sources of the functions into the functions directory during the build, e.g.
+// `netlify-lambda build src/lambda`, so the sources are the functions
+func netlifyLambdaSourceDirectories(packageJsonFile TextFile) []string {
+	directories := make([]string, 0)
+
+	packageJson, err := LoadJsonFromBytes([]byte(packageJsonFile.Content))
+	if err != nil {
+		return directories
+	}
+	scripts, err := JsonResolveMap(packageJson, []string{"scripts"})
+	if err != nil {
+		return directories
+	}
+	scriptNames := maps.Keys(scripts)
+	slices.Sort(scriptNames)
+	for _, scriptName := range scriptNames {
+		script, ok := scripts[scriptName].(string)
+		if !ok {
+			continue
+		}
+		for _, match := range netlifyLambdaBuildRegexp.FindAllStringSubmatch(script, -1) {
+			directory := path.Join(path.Dir(packageJsonFile.Path), match[1])
+			if !slices.Contains(directories, directory) {
+				directories = append(directories, directory)
+			}
+		}
+	}
+	return directories
+}
+
+type netlifyInlineConfig struct {
+	Schedule string
+	Paths    []string
+}
+
+// NOTE: functions configure themselves with `export const config = { schedule: "@daily", path: "/api" }` or
+// are wrapped by `schedule("@daily", handler)` of @netlify/functions
+func netlifyModuleInlineConfig(module *JsModule) netlifyInlineConfig {
+	inlineConfig := netlifyInlineConfig{}
+
+	if export := module.Export("config"); export != nil {
+		config := module.ResolveValue(export.Value)
+		inlineConfig.Schedule, _ = config.StringProperty("schedule")
+		if configPath := config.Property("path"); configPath != nil {
+			inlineConfig.Paths = jsStrings(module.ResolveValue(*configPath))
+		}
+	}
+
+	for _, call := range module.CallsTo("schedule") {
+		if !module.CallsImport(call, "@netlify/functions", "schedule") {
+			continue
+		}
+		if schedule, ok := call.StringArgument(0); ok {
+			inlineConfig.Schedule = schedule
+		}
+	}
+
+	return inlineConfig
+}
+
+// NOTE: v1 functions export a handler, v2 functions and edge functions export a default function
+func netlifyHandlerLine(module *JsModule) (int, bool) {
+	if handlers := faasHandlerExports(module); len(handlers) > 0 {
+		return handlers[0].Line, true
+	}
+	if export := module.Export("default"); export != nil && isHandlerValue(module, export.Value) {
+		return export.Line, true
+	}
+	return -1, false
+}
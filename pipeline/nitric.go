@@ -0,0 +1,227 @@
+package main
+
+import (
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// NOTE: stack files like nitric.dev.yaml select the provider, e.g. `provider: nitric/aws@1.1.0`, custom providers
+// can deploy anywhere
+var nitricProviderPlatforms = map[string]FaaSPlatform{
+	"nitric/aws":   FaaSPlatformAWS,
+	"nitric/awstf": FaaSPlatformAWS,
+	"nitric/gcp":   FaaSPlatformGCP,
+	"nitric/gcptf": FaaSPlatformGCP,
+	"nitric/azure": FaaSPlatformAzure,
+}
+
+var nitricStackFileRegexp = regexp.MustCompile(`^nitric\.[\w-]+\.ya?ml$`)
+
+// NOTE: returns the platforms of all stacks of a project, sorted and without duplicates
+func nitricStackPlatforms(projectDirectory string, files []TextFile) []FaaSPlatform {
+	platforms := make([]FaaSPlatform, 0)
+	for _, file := range files {
+		if path.Dir(file.Path) != projectDirectory || !nitricStackFileRegexp.MatchString(path.Base(file.Path)) {
+			continue
+		}
+		stackJsons := LoadJsonsFromYamlBytes([]byte(file.Content))
+		if len(stackJsons) != 1 {
+			continue
+		}
+		provider, err := JsonResolveString(stackJsons[0], []string{"provider"})
+		if err != nil {
+			continue
+		}
+		provider, _, _ = strings.Cut(provider, "@")
+
+		platform, ok := nitricProviderPlatforms[provider]
+		if !ok {
+			platform = FaaSPlatformUnknown
+		}
+		if !slices.Contains(platforms, platform) {
+			platforms = append(platforms, platform)
+		}
+	}
+	slices.Sort(platforms)
+	return platforms
+}
+
+// NOTE: resources are declared the same way in all SDKs, e.g. `api("main")` in JavaScript and Python,
+// `nitric.NewApi("main")` in Go or `Nitric.api("main")` in Dart, topics and buckets only trigger a service if it
+// subscribes to them, otherwise the service only publishes or reads and writes files
+var nitricResourceRegexp = regexp.MustCompile(`(?:\b(\w+)\s*:?=\s*(?:await\s+)?)?\b(?:\w+\.)*(?:[Nn]ew)?([Aa]pi|[Ss]chedule|[Tt]opic|[Bb]ucket|[Ww]ebsocket|[Ww]eb[Ss]ocket)\s*\(\s*["']([^"']+)["']`)
+var nitricHttpRegexp = regexp.MustCompile(`\b(?:\w+\.)*(?:[Nn]ew)?[Hh]ttp\s*\(`)
+
+var nitricResourceInvocationTypes = map[string]FaaSInvocationType{
+	"api":       FaaSInvocationTypeHTTP,
+	"schedule":  FaaSInvocationTypeSchedule,
+	"topic":     FaaSInvocationTypeTopic,
+	"bucket":    FaaSInvocationTypeOther,
+	"websocket": FaaSInvocationTypeWebsocket,
+}
+
+// NOTE: the methods that register a handler for a resource, e.g. `topic("a").subscribe(fn)` or `b.On("write", ...)`
+var nitricResourceTriggerMethods = map[string][]string{
+	"topic":  {"subscribe"},
+	"bucket": {"on"},
+}
+
+type NitricTrigger struct {
+	Resource       string
+	Name           string
+	InvocationType FaaSInvocationType
+	Line           int
+}
+
+// NOTE: matches a method call on a variable, e.g. `t.subscribe(` or `b.On(`, and a method chained to a call, e.g.
+// `).subscribe(`, the variable and the method are compared afterward
+var nitricMethodCallRegexp = regexp.MustCompile(`\b(\w+)\s*\.\s*(\w+)\s*\(`)
+var nitricChainedMethodCallRegexp = regexp.MustCompile(`^\s*\.\s*(\w+)\s*\(`)
+
+const nitricJsSdk = "@nitric/sdk"
+
+// NOTE: JavaScript and TypeScript services are parsed, so resources in comments and strings are ignored, the
+// services of the other SDKs are matched by regular expressions
+func ParseNitricTriggers(file TextFile, modules *JsModuleCache) []NitricTrigger {
+	switch sourceLanguageOfFile(file.Path) {
+	case SourceLanguageJavaScript, SourceLanguageTypeScript:
+		return parseNitricJsTriggers(modules.Module(file))
+	default:
+		return parseNitricTriggers(file.Content)
+	}
+}
+
+func parseNitricJsTriggers(module *JsModule) []NitricTrigger {
+	triggers := make([]NitricTrigger, 0)
+	if !module.ImportsModule(nitricJsSdk) {
+		return triggers
+	}
+
+	for _, call := range module.Calls {
+		if module.CallsImport(call, nitricJsSdk, "http") {
+			triggers = append(triggers, NitricTrigger{
+				Resource:       "http",
+				InvocationType: FaaSInvocationTypeHTTP,
+				Line:           call.Line,
+			})
+			continue
+		}
+
+		for resource, invocationType := range nitricResourceInvocationTypes {
+			if !module.CallsImport(call, nitricJsSdk, resource) {
+				continue
+			}
+			name, ok := call.StringArgument(0)
+			if !ok {
+				continue
+			}
+			if methods, ok := nitricResourceTriggerMethods[resource]; ok && !nitricJsCallsMethod(module, call, methods) {
+				continue
+			}
+
+			triggers = append(triggers, NitricTrigger{
+				Resource:       resource,
+				Name:           name,
+				InvocationType: invocationType,
+				Line:           call.Line,
+			})
+		}
+	}
+
+	slices.SortStableFunc(triggers, func(a, b NitricTrigger) int {
+		return a.Line - b.Line
+	})
+	return triggers
+}
+
+// NOTE: the method is either chained to the resource, e.g. `topic("a").subscribe(fn)`, or called on the variable the
+// resource is assigned to, also if it is awaited
+func nitricJsCallsMethod(module *JsModule, resource JsCall, methods []string) bool {
+	isResource := func(call JsCall) bool {
+		return call.Line == resource.Line && slices.Equal(call.Callee, resource.Callee)
+	}
+
+	variables := make([]string, 0)
+	for _, declaration := range module.Declarations {
+		value := declaration.Value
+		if value.Kind == JsValueCall && isResource(*value.Call) || value.Kind == JsValueOther && value.Line == resource.Line {
+			variables = append(variables, declaration.Name)
+		}
+	}
+
+	for _, call := range module.CallsTo(methods...) {
+		if len(call.Callee) == 2 && slices.Contains(variables, call.Callee[0]) {
+			return true
+		}
+		if slices.ContainsFunc(module.ChainCalls(call), isResource) {
+			return true
+		}
+	}
+	return false
+}
+
+func parseNitricTriggers(content string) []NitricTrigger {
+	triggers := make([]NitricTrigger, 0)
+	if !strings.Contains(strings.ToLower(content), "nitric") {
+		return triggers
+	}
+
+	for _, match := range nitricResourceRegexp.FindAllStringSubmatchIndex(content, -1) {
+		variable := ""
+		if match[2] != -1 {
+			variable = content[match[2]:match[3]]
+		}
+		resource := strings.ToLower(content[match[4]:match[5]])
+		name := content[match[6]:match[7]]
+
+		if methods, ok := nitricResourceTriggerMethods[resource]; ok {
+			call := balancedParentheses(content[match[5]+strings.Index(content[match[5]:], "("):])
+			chained := content[match[5]+strings.Index(content[match[5]:], "(")+len(call):]
+			if !nitricCallsMethod(chained, "", methods) && (variable == "" || !nitricCallsMethod(content, variable, methods)) {
+				continue
+			}
+		}
+
+		triggers = append(triggers, NitricTrigger{
+			Resource:       resource,
+			Name:           name,
+			InvocationType: nitricResourceInvocationTypes[resource],
+			Line:           strings.Count(content[:match[0]], "\n") + 1,
+		})
+	}
+
+	for _, match := range nitricHttpRegexp.FindAllStringIndex(content, -1) {
+		triggers = append(triggers, NitricTrigger{
+			Resource:       "http",
+			InvocationType: FaaSInvocationTypeHTTP,
+			Line:           strings.Count(content[:match[0]], "\n") + 1,
+		})
+	}
+
+	slices.SortStableFunc(triggers, func(a, b NitricTrigger) int {
+		return a.Line - b.Line
+	})
+	return triggers
+}
+
+// NOTE: without a variable, only a method chained directly to the text is considered, e.g. `).subscribe(`
+func nitricCallsMethod(text string, variable string, methods []string) bool {
+	isMethod := func(name string) bool {
+		return slices.ContainsFunc(methods, func(method string) bool {
+			return strings.EqualFold(method, name)
+		})
+	}
+
+	if variable == "" {
+		match := nitricChainedMethodCallRegexp.FindStringSubmatch(text)
+		return match != nil && isMethod(match[1])
+	}
+	for _, match := range nitricMethodCallRegexp.FindAllStringSubmatch(text, -1) {
+		if match[1] == variable && isMethod(match[2]) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"maps"
+	"testing"
+)
+
+func TestScanNitricDetectsServiceTriggers(t *testing.T) {
+	findings := scanTestRepository(t, "nitric", map[string]string{
+		"nitric.yaml": `name: shop
+services:
+  - match: services/*.ts
+  - match: services/*.py
+`,
+		"nitric.aws.yaml": "provider: nitric/aws@1.1.0\nregion: eu-west-1\n",
+		"services/orders.ts": `import { api, topic, bucket } from "@nitric/sdk";
+
+// const legacy = api("legacy");
+/* schedule("cleanup").every("1 day", cleanup); */
+const description = 'api("docs")';
+
+const orders = api("orders");
+orders.get("/orders", async (ctx) => ctx);
+
+const created = topic("created");
+created.subscribe(async (ctx) => ctx);
+
+topic("unused").publish();
+const images = bucket("images");
+`,
+		"services/reports.ts": `import * as nitric from "@nitric/sdk";
+
+const updates = await nitric.topic("updates");
+
+nitric.bucket("reports")
+  .on("write", "*", async (ctx) => ctx);
+updates.subscribe(async (ctx) => ctx);
+`,
+		"services/worker.py": `from nitric.resources import topic, schedule
+from nitric.application import Nitric
+
+jobs = topic("jobs")
+
+@jobs.subscribe()
+async def process(ctx):
+    return ctx
+
+@schedule("nightly").every("1 days")
+async def nightly(ctx):
+    return ctx
+
+Nitric.run()
+`,
+	})
+
+	tests := []struct {
+		name           string
+		invocationType FaaSInvocationType
+		sourceFileLine int
+		attributes     map[string]string
+	}{
+		{"orders", FaaSInvocationTypeHTTP, 7, map[string]string{"apis": "orders", "topics": "created"}},
+		{"reports", FaaSInvocationTypeTopic, 3, map[string]string{"topics": "updates", "buckets": "reports"}},
+		{"worker", FaaSInvocationTypeTopic, 4, map[string]string{"topics": "jobs", "schedules": "nightly"}},
+	}
+
+	functions := testFunctionsByName(t, findings)
+	if len(functions) != len(tests) {
+		t.Errorf("expected %d functions, got %d", len(tests), len(functions))
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			function, ok := functions[test.name]
+			if !ok {
+				t.Fatalf("function %s was not found", test.name)
+			}
+			if function.Platform != FaaSPlatformAWS {
+				t.Errorf("expected the platform %s, got %s", FaaSPlatformAWS, function.Platform)
+			}
+			if function.InvocationType != test.invocationType || function.SourceFileLine != test.sourceFileLine {
+				t.Errorf("expected %s at line %d, got %s at line %d", test.invocationType, test.sourceFileLine, function.InvocationType, function.SourceFileLine)
+			}
+			if !maps.Equal(function.Attributes, test.attributes) {
+				t.Errorf("expected attributes %v, got %v", test.attributes, function.Attributes)
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"slices"
+	"strings"
+)
+
+// NOTE: `faas` is the provider name of faas-cli versions before 0.8
+var openFaaSStackProviders = []string{"openfaas", "faas"}
+
+type OpenFaaSStackFunction struct {
+	Name           string
+	Language       string
+	Handler        string
+	Image          string
+	Annotations    map[string]string
+	MemoryMB       int
+	TimeoutSeconds int
+	Line           int
+}
+
+// NOTE: stack files of faas-cli, usually stack.yml but any file passed with `-f`, e.g.
+// `provider: { name: openfaas }` and `functions: { hello: { lang: node18, handler: ./hello } }`
+func LoadOpenFaaSStack(file TextFile, documents []YamlDocument) ([]OpenFaaSStackFunction, bool) {
+	if len(documents) != 1 {
+		return nil, false
+	}
+	stackJson := documents[0].Json
+
+	provider, err := JsonResolveString(stackJson, []string{"provider", "name"})
+	if err != nil || !slices.Contains(openFaaSStackProviders, provider) {
+		return nil, false
+	}
+	stackFunctions, err := JsonResolveMap(stackJson, []string{"functions"})
+	if err != nil {
+		return nil, false
+	}
+
+	functions := make([]OpenFaaSStackFunction, 0, len(stackFunctions))
+	for name, stackFunction := range stackFunctions {
+		function := OpenFaaSStackFunction{
+			Name:           name,
+			Annotations:    make(map[string]string),
+			MemoryMB:       -1,
+			TimeoutSeconds: -1,
+			Line:           YamlKeyLine([]byte(file.Content), []string{"functions", name}),
+		}
+		function.Language, _ = JsonResolveString(stackFunction, []string{"lang"})
+		function.Handler, _ = JsonResolveString(stackFunction, []string{"handler"})
+		function.Image, _ = JsonResolveString(stackFunction, []string{"image"})
+		if annotations, err := JsonResolveMap(stackFunction, []string{"annotations"}); err == nil {
+			for key, value := range annotations {
+				if value, ok := value.(string); ok {
+					function.Annotations[key] = value
+				}
+			}
+		}
+
+		for _, memoryPath := range [][]string{{"limits", "memory"}, {"requests", "memory"}} {
+			if memory, err := JsonResolveString(stackFunction, memoryPath); err == nil {
+				if memoryMB, ok := ParseMemorySizeMB(memory); ok {
+					function.MemoryMB = memoryMB
+					break
+				}
+			}
+		}
+
+		// NOTE: the watchdogs stop functions after exec_timeout, the gateway after write_timeout
+		for _, timeoutPath := range [][]string{{"environment", "exec_timeout"}, {"environment", "write_timeout"}} {
+			if timeout, err := JsonResolveString(stackFunction, timeoutPath); err == nil {
+				if timeoutSeconds, ok := ParseDurationSeconds(timeout); ok {
+					function.TimeoutSeconds = timeoutSeconds
+					break
+				}
+			}
+		}
+
+		functions = append(functions, function)
+	}
+
+	slices.SortFunc(functions, func(a, b OpenFaaSStackFunction) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return functions, true
+}
+
+// NOTE: functions are invoked through the gateway unless a connector subscribes them to the topics of their `topic`
+// annotation, the cron-connector uses the topic `cron-function` together with a `schedule` annotation
+func openFaaSAnnotationsTrigger(annotations map[string]string) (FaaSInvocationType, map[string]string) {
+	attributes := make(map[string]string)
+	schedule := annotations["schedule"]
+	topic := annotations["topic"]
+
+	switch {
+	case schedule != "" || topic == "cron-function":
+		if schedule != "" {
+			attributes["schedule"] = schedule
+		}
+		return FaaSInvocationTypeSchedule, attributes
+	case topic != "":
+		attributes["topics"] = topic
+		return FaaSInvocationTypeTopic, attributes
+	default:
+		return FaaSInvocationTypeHTTP, attributes
+	}
+}
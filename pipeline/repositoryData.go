@@ -12,14 +12,16 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"regexp"
 	"slices"
+	"strconv"
 	"strings"
 	"time"
 )
 
 // Supported managed t1 platforms:          AWS, Azure, GCP, IBM, Oracle, Digital Ocean, Alibaba, Tencent, Cloudflare, Fastly
-// Supported managed t2 platforms:          Vercel, Netlify, Firebase
-// Supported self-hosted non-k8s platforms: Fn Project, Nuclio
+// Supported managed t2 platforms:          Vercel, Netlify, Firebase, Supabase, Deno Deploy, Appwrite
+// Supported self-hosted non-k8s platforms: Fn Project, Nuclio, Appwrite
 // Supported self-hosted k8s platforms:     OpenWhisk, Fission, Kubeless, OpenFaaS, Nuclio, Knative
 
 // Tools that require looking at multiple indicators to identify FaaS applications:
@@ -43,6 +45,10 @@ import (
 // - https://arc.codes ; platforms: AWS
 // - https://begin.com/ ; platform: AWS ; framework: architect
 // - https://fnproject.io ; platforms: Fn Project
+// - https://supabase.com/edge-functions ; platforms: Supabase
+// - https://deno.com/deploy ; platforms: Deno Deploy
+// - https://docs.amplify.aws ; platforms: AWS
+// - https://appwrite.io/docs/products/functions ; platforms: Appwrite
 
 // # Relevant
 // [✓] firebase
@@ -51,6 +57,10 @@ import (
 // [✓] tencent
 // [✓] openfaas
 // [✓] digital ocean functions
+// [✓] supabase
+// [✓] deno deploy
+// [✓] amplify
+// [✓] appwrite
 
 // # Irrelevant
 // [x] begin (uses architect)
@@ -184,6 +194,8 @@ var ExtensionToLanguage = map[string]string{
 	".mjs":         "JavaScript",
 	".cjs":         "JavaScript",
 	".ts":          "TypeScript",
+	".mts":         "TypeScript",
+	".cts":         "TypeScript",
 	".jl":          "Julia",
 	".janet":       "Janet",
 	".json":        "JSON",
@@ -433,6 +445,8 @@ var ExtensionToCategory = map[string]string{
 	".mjs":                     "Source Code",
 	".cjs":                     "Source Code",
 	".ts":                      "Source Code",
+	".mts":                     "Source Code",
+	".cts":                     "Source Code",
 	".jl":                      "Source Code",
 	".janet":                   "Source Code",
 	".json":                    "Data",
@@ -961,8 +975,11 @@ const (
 	FaaSPlatformTencent      FaaSPlatform = "tencent"
 	FaaSPlatformDigitalOcean FaaSPlatform = "digitalocean"
 
-	FaaSPlatformVercel  FaaSPlatform = "vercel"
-	FaaSPlatformNetlify FaaSPlatform = "netlify"
+	FaaSPlatformVercel     FaaSPlatform = "vercel"
+	FaaSPlatformNetlify    FaaSPlatform = "netlify"
+	FaaSPlatformSupabase   FaaSPlatform = "supabase"
+	FaaSPlatformDenoDeploy FaaSPlatform = "denodeploy"
+	FaaSPlatformAppwrite   FaaSPlatform = "appwrite"
 
 	FaaSPlatformFnProject FaaSPlatform = "fnproject"
 	FaaSPlatformNuclio    FaaSPlatform = "nuclio"
@@ -989,12 +1006,19 @@ const (
 	FaaSFrameworkArchitect                FaaSFramework = "architect"
 	FaaSFrameworkAWSCDKAndSST             FaaSFramework = "aws_cdk_and_sst"
 	FaaSFrameworkGCPFunctions             FaaSFramework = "gcp_functions"
+	FaaSFrameworkGCPCloudRun              FaaSFramework = "gcp_cloud_run"
+	FaaSFrameworkGCloudCLI                FaaSFramework = "gcloud_cli"
+	FaaSFrameworkGCPWorkflows             FaaSFramework = "gcp_workflows"
 	FaaSFrameworkAzureFunctions           FaaSFramework = "azure_functions"
 	FaaSFrameworkAzureDurableFunctions    FaaSFramework = "azure_durable_functions"
 	FaaSFrameworkServerlessCloudFramework FaaSFramework = "serverless_cloud_framework"
 	FaaSFrameworkDigitalOcean             FaaSFramework = "digitalocean"
 	FaaSFrameworkAlexaSkillsKit           FaaSFramework = "alexa_skills_kit"
 	FaaSFrameworkHono                     FaaSFramework = "hono"
+	FaaSFrameworkSupabase                 FaaSFramework = "supabase"
+	FaaSFrameworkDenoDeploy               FaaSFramework = "denodeploy"
+	FaaSFrameworkAmplify                  FaaSFramework = "amplify"
+	FaaSFrameworkAppwrite                 FaaSFramework = "appwrite"
 
 	FaaSFrameworkTerraform                           FaaSFramework = "terraform"
 	FaaSFrameworkPulumi                              FaaSFramework = "pulumi"
@@ -1033,6 +1057,80 @@ const (
 	FaaSLocationRegion  FaaSLocation = "region"
 )
 
+type FaaSResourceType string
+
+const (
+	FaaSResourceTypeUnknown FaaSResourceType = "unknown"
+
+	FaaSResourceTypeDynamoDB       FaaSResourceType = "dynamodb"
+	FaaSResourceTypeS3             FaaSResourceType = "s3"
+	FaaSResourceTypeSQS            FaaSResourceType = "sqs"
+	FaaSResourceTypeSNS            FaaSResourceType = "sns"
+	FaaSResourceTypeKinesis        FaaSResourceType = "kinesis"
+	FaaSResourceTypeEventBridge    FaaSResourceType = "eventbridge"
+	FaaSResourceTypeSecretsManager FaaSResourceType = "secretsmanager"
+	FaaSResourceTypeSSMParameter   FaaSResourceType = "ssm_parameter"
+	FaaSResourceTypeStepFunctions  FaaSResourceType = "stepfunctions"
+	FaaSResourceTypeLambda         FaaSResourceType = "lambda"
+	FaaSResourceTypeRDS            FaaSResourceType = "rds"
+
+	FaaSResourceTypeFirestore        FaaSResourceType = "firestore"
+	FaaSResourceTypeFirebaseDatabase FaaSResourceType = "firebase_database"
+	FaaSResourceTypeCloudStorage     FaaSResourceType = "cloud_storage"
+	FaaSResourceTypePubSub           FaaSResourceType = "pubsub"
+	FaaSResourceTypeSecretManager    FaaSResourceType = "secret_manager"
+
+	FaaSResourceTypeCosmosDB     FaaSResourceType = "cosmosdb"
+	FaaSResourceTypeBlobStorage  FaaSResourceType = "blob_storage"
+	FaaSResourceTypeQueueStorage FaaSResourceType = "queue_storage"
+	FaaSResourceTypeTableStorage FaaSResourceType = "table_storage"
+	FaaSResourceTypeServiceBus   FaaSResourceType = "service_bus"
+	FaaSResourceTypeEventHub     FaaSResourceType = "event_hub"
+	FaaSResourceTypeEventGrid    FaaSResourceType = "event_grid"
+	FaaSResourceTypeAzureSQL     FaaSResourceType = "azure_sql"
+	FaaSResourceTypeKeyVault     FaaSResourceType = "key_vault"
+
+	FaaSResourceTypeKV            FaaSResourceType = "kv"
+	FaaSResourceTypeD1            FaaSResourceType = "d1"
+	FaaSResourceTypeR2            FaaSResourceType = "r2"
+	FaaSResourceTypeWorkersQueue  FaaSResourceType = "workers_queue"
+	FaaSResourceTypeDurableObject FaaSResourceType = "durable_object"
+	FaaSResourceTypeService       FaaSResourceType = "service"
+	FaaSResourceTypeHyperdrive    FaaSResourceType = "hyperdrive"
+	FaaSResourceTypeVectorize     FaaSResourceType = "vectorize"
+)
+
+type FaaSResourceAccess string
+
+const (
+	FaaSResourceAccessUnknown   FaaSResourceAccess = "unknown"
+	FaaSResourceAccessRead      FaaSResourceAccess = "read"
+	FaaSResourceAccessWrite     FaaSResourceAccess = "write"
+	FaaSResourceAccessReadWrite FaaSResourceAccess = "read_write"
+	// NOTE: the resource invokes the function, e.g. the queue of an event source mapping
+	FaaSResourceAccessTrigger FaaSResourceAccess = "trigger"
+)
+
+// NOTE: where a binding was found, `iac` for event sources, bindings and connectors of the deployment configuration,
+// `iam` for permissions, `environment` for resources passed as environment variables and `sdk` for SDK client usage
+type FaaSResourceOrigin string
+
+const (
+	FaaSResourceOriginIaC         FaaSResourceOrigin = "iac"
+	FaaSResourceOriginIAM         FaaSResourceOrigin = "iam"
+	FaaSResourceOriginEnvironment FaaSResourceOrigin = "environment"
+	FaaSResourceOriginSDK         FaaSResourceOrigin = "sdk"
+)
+
+type SourceLanguage string
+
+const (
+	SourceLanguageUnknown    SourceLanguage = "unknown"
+	SourceLanguageJavaScript SourceLanguage = "javascript"
+	SourceLanguageTypeScript SourceLanguage = "typescript"
+	SourceLanguageMixed      SourceLanguage = "mixed"
+)
+
 type RepositoryPackageData struct {
 	RootPath string
 
@@ -1060,8 +1158,69 @@ type RepositoryFaaSFunctionData struct {
 	Framework      FaaSFramework
 	InvocationType FaaSInvocationType
 	Location       FaaSLocation
+	// NOTE: the regions a function is deployed to if configured explicitly, e.g. for Firebase
+	Regions []string
 
+	Runtime        string
+	MemoryMB       int
 	TimeoutSeconds int
+	// NOTE: the instruction set architecture as configured, e.g. "arm64" or "x86_64" for AWS Lambda
+	Architecture string
+	// NOTE: instances kept initialized to avoid cold starts, e.g. the provisioned concurrency of AWS Lambda or
+	//       `minInstances` of Firebase and Cloud Run, and the concurrency reserved for a function, 0 if not configured
+	ProvisionedConcurrency int
+	ReservedConcurrency    int
+	MinInstances           int
+	// NOTE: the handler as configured, e.g. "src/handler.main" for the Serverless Framework, prefixed with the directory
+	//       of the code if configured separately, e.g. the `CodeUri` of SAM
+	Handler string
+	// NOTE: trigger options as configured, e.g. the route of an HTTP trigger or the schedule of a timer trigger
+	Attributes map[string]string
+	// NOTE: the resources bound to a function as `type:NAME`, e.g. "kv:CACHE" for Cloudflare Workers
+	Bindings []string
+	// NOTE: the resources a function reads, writes or is triggered by, the edges of the resource graph
+	Resources []RepositoryFunctionResourceData
+
+	// NOTE: where a function was found, the configuration file for functions found in configurations
+	SourceFilePath string
+	SourceFileLine int
+	// NOTE: the source file, export and line of the handler code if it can be resolved, "" respectively -1 otherwise,
+	//       the export is "" for handlers of a whole file like Cloudflare Workers, see resolveFunctionHandlers
+	HandlerFilePath string
+	HandlerExport   string
+	HandlerFileLine int
+	// NOTE: the frameworks of other scanners that found the same function, see deduplicateFunctions
+	DuplicateFrameworks []FaaSFramework
+}
+
+type RepositoryFunctionResourceData struct {
+	Type FaaSResourceType
+	// NOTE: the name as configured, e.g. the logical ID of a CloudFormation resource, the name of a bucket or the
+	//       environment variable holding it as `${TABLE_NAME}`, "*" for wildcards of IAM policies
+	Name   string
+	Access FaaSResourceAccess
+	Origin FaaSResourceOrigin
+}
+
+type RepositoryWorkflowStepData struct {
+	Name string
+	// NOTE: the type of the step as named by the orchestrator, e.g. "Task" or "Choice" for AWS Step Functions
+	Type string
+	// NOTE: the function invoked by the step if it can be resolved, empty otherwise
+	Function string
+}
+
+// NOTE: orchestrations composing functions, e.g. AWS Step Functions state machines, Azure Durable Functions
+// orchestrators and GCP Workflows, the platform determines the orchestrator
+type RepositoryWorkflowData struct {
+	Name string
+
+	Platform  FaaSPlatform
+	Framework FaaSFramework
+
+	Steps []RepositoryWorkflowStepData
+	// NOTE: the distinct functions invoked by the steps, named like the functions of the same framework if possible
+	Functions []string
 
 	SourceFilePath string
 	SourceFileLine int
@@ -1085,6 +1244,10 @@ type RepositoryData struct {
 	RepositoryId RepositoryId
 	Url          string
 
+	CommitSHA    string
+	ManifestPath string
+	Sparse       bool
+
 	Name        string
 	Description string
 	License     string
@@ -1121,6 +1284,15 @@ type RepositoryData struct {
 	Functions    []RepositoryFaaSFunctionData
 	NumFunctions int
 
+	Workflows    []RepositoryWorkflowData
+	NumWorkflows int
+
+	SourceLanguage     SourceLanguage
+	NumJavaScriptFiles int
+	NumTypeScriptFiles int
+
+	ScannerReports []ScannerReport
+
 	Packages                   []RepositoryPackageData
 	NumPackages                int
 	NumPublishedToNPM          int
@@ -1214,6 +1386,123 @@ func FilterTextFiles(
 	return filteredTextFiles, nil
 }
 
+var JS_EXTENSIONS = []string{".js", ".jsx", ".mjs"}
+var TS_EXTENSIONS = []string{".ts", ".tsx", ".mts", ".cts"}
+
+func JsAndTsFilePatterns(baseDirectory string) []string {
+	patterns := make([]string, 0, len(JS_EXTENSIONS)+len(TS_EXTENSIONS))
+	for _, extension := range slices.Concat(JS_EXTENSIONS, TS_EXTENSIONS) {
+		patterns = append(patterns, path.Join(baseDirectory, fmt.Sprintf("**/*%s", extension)))
+	}
+	return patterns
+}
+
+func isTypeScriptDeclarationFile(filePath string) bool {
+	return strings.HasSuffix(filePath, ".d.ts") ||
+		strings.HasSuffix(filePath, ".d.mts") ||
+		strings.HasSuffix(filePath, ".d.cts")
+}
+
+func sourceLanguageOfFile(filePath string) SourceLanguage {
+	extension := path.Ext(filePath)
+	switch {
+	case slices.Contains(JS_EXTENSIONS, extension):
+		return SourceLanguageJavaScript
+	case slices.Contains(TS_EXTENSIONS, extension) && !isTypeScriptDeclarationFile(filePath):
+		return SourceLanguageTypeScript
+	default:
+		return SourceLanguageUnknown
+	}
+}
+
+// NOTE: declaration files only contain types, so they can't contain handlers and would only lead to false positives
+func FilterJsAndTsFiles(files []TextFile, baseDirectories ...string) ([]TextFile, error) {
+	if len(baseDirectories) == 0 {
+		baseDirectories = []string{""}
+	}
+
+	patterns := make([]string, 0)
+	for _, baseDirectory := range baseDirectories {
+		patterns = append(patterns, JsAndTsFilePatterns(baseDirectory)...)
+	}
+
+	matchingFiles, err := FilterTextFiles(files, patterns...)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]TextFile, 0, len(matchingFiles))
+	for _, file := range matchingFiles {
+		if !isTypeScriptDeclarationFile(file.Path) {
+			result = append(result, file)
+		}
+	}
+	return result, nil
+}
+
+var faasHandlerTypeRegexp = regexp.MustCompile(`^(?:\w+\.)?\w*Handler(?:V2)?(?:<.*>)?$`)
+
+// NOTE: also matches typed TypeScript handlers like `export const main: APIGatewayProxyHandler = ...`
+func faasHandlerExports(module *JsModule) []JsExport {
+	result := make([]JsExport, 0)
+	for _, export := range module.Exports {
+		if export.Name == "handler" || faasHandlerTypeRegexp.MatchString(export.TypeAnnotation) {
+			result = append(result, export)
+		}
+	}
+	return result
+}
+
+var httpMethodHandlerNames = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS"}
+
+// NOTE: default exports in API directories are handlers unless they are obviously data, e.g. `export default { ... }`
+func isHandlerValue(module *JsModule, value JsValue) bool {
+	switch module.ResolveValue(value).Kind {
+	case JsValueFunction, JsValueCall, JsValueIdentifier:
+		return true
+	default:
+		return false
+	}
+}
+
+func countSourceFilesByLanguage(files []TextFile) (int, int) {
+	numJavaScriptFiles := 0
+	numTypeScriptFiles := 0
+	for _, file := range files {
+		switch sourceLanguageOfFile(file.Path) {
+		case SourceLanguageJavaScript:
+			numJavaScriptFiles += 1
+		case SourceLanguageTypeScript:
+			numTypeScriptFiles += 1
+		}
+	}
+	return numJavaScriptFiles, numTypeScriptFiles
+}
+
+func sourceLanguageOfRepository(numJavaScriptFiles int, numTypeScriptFiles int) SourceLanguage {
+	switch {
+	case numJavaScriptFiles > 0 && numTypeScriptFiles > 0:
+		return SourceLanguageMixed
+	case numJavaScriptFiles > 0:
+		return SourceLanguageJavaScript
+	case numTypeScriptFiles > 0:
+		return SourceLanguageTypeScript
+	default:
+		return SourceLanguageUnknown
+	}
+}
+
+// NOTE: the files the scanners are interested in, package.json files and all source files with a known extension
+func AnalysisFilePatterns(scannerRegistry *ScannerRegistry) []string {
+	patterns := append([]string{"**/package.json"}, scannerRegistry.FilePatterns()...)
+	extensions := maps.Keys(ExtensionToLanguage)
+	slices.Sort(extensions)
+	for _, extension := range extensions {
+		patterns = append(patterns, fmt.Sprintf("**/*%s", extension))
+	}
+	return patterns
+}
+
 func usedPlatformsToString(platforms map[FaaSPlatform]bool) string {
 	results := make([]string, 0)
 	for platform, isUsed := range platforms {
@@ -1492,13 +1781,12 @@ func scanFaaSRuntimeDependencies(data *RepositoryPackageData) {
 	data.NumFaaSRuntimeDependencies = len(data.FaaSRuntimeDependencies)
 }
 
-func scanFaaSHandlers(data *RepositoryPackageData, files []TextFile) {
+func scanFaaSHandlers(data *RepositoryPackageData, files []TextFile, jsModules *JsModuleCache) {
 	for _, file := range files {
-		if !(file.Extension == ".js" || file.Extension == ".mjs") {
+		if sourceLanguageOfFile(file.Path) == SourceLanguageUnknown || isTypeScriptDeclarationFile(file.Path) {
 			continue
 		}
-		data.NumFaaSHandlers += strings.Count(file.Content, "exports.handler")
-		data.NumFaaSHandlers += strings.Count(file.Content, "export const handler")
+		data.NumFaaSHandlers += len(faasHandlerExports(jsModules.Module(file)))
 	}
 }
 
@@ -1510,37 +1798,41 @@ func scanPackageJsons(data *RepositoryPackageData, files []TextFile) {
 	}
 }
 
-func scanVercel(data *RepositoryData, files []TextFile) {
+func scanVercel(data *ScannerData, files []TextFile) error {
 	vercelConfigFiles, err := FilterTextFiles(files, "**/vercel.json")
 	if err != nil {
-		return
+		return err
 	}
 
+	vercelConfigs := make([]VercelConfig, 0, len(vercelConfigFiles))
 	for _, vercelConfigFile := range vercelConfigFiles {
-		vercelConfig, err := LoadJsonFromBytes([]byte(vercelConfigFile.Content))
+		vercelConfig, err := LoadVercelConfig(vercelConfigFile)
 		if err != nil {
 			continue
 		}
+		vercelConfigs = append(vercelConfigs, vercelConfig)
+	}
 
+	for _, vercelConfig := range vercelConfigs {
 		data.UsedFrameworks[FaaSFrameworkVercel] = true
 		data.UsedPlatforms[FaaSPlatformVercel] = true
 
-		functionBaseDirectory := path.Dir(vercelConfigFile.Path)
+		functionBaseDirectory := vercelConfig.Directory
 
-		functionDirectories := []string{
-			path.Join(functionBaseDirectory, "api/**/*.js"),
-			path.Join(functionBaseDirectory, "api/**/*.mjs"),
-			path.Join(functionBaseDirectory, "pages/api/**/*.js"),
-			path.Join(functionBaseDirectory, "pages/api/**/*.mjs"),
-			path.Join(functionBaseDirectory, "src/pages/api/**/*.js"),
-			path.Join(functionBaseDirectory, "src/pages/api/**/*.mjs"),
+		routeDirectories := make([]string, 0)
+		for _, apiDirectory := range vercelApiDirectories {
+			routeDirectories = append(routeDirectories, path.Join(functionBaseDirectory, apiDirectory))
 		}
-
-		functions, err := JsonResolveMap(vercelConfig, []string{"functions"})
-		if err == nil {
-			for functionDirectory, _ := range functions {
-				functionDirectories = append(functionDirectories, path.Join(functionBaseDirectory, functionDirectory))
-			}
+		functionDirectories := make([]string, 0)
+		for _, routeDirectory := range routeDirectories {
+			functionDirectories = append(functionDirectories, JsAndTsFilePatterns(routeDirectory)...)
+		}
+		for _, appDirectory := range vercelAppDirectories {
+			functionDirectories = append(functionDirectories, JsAndTsFilePatterns(path.Join(functionBaseDirectory, appDirectory))...)
+		}
+		functionDirectories = append(functionDirectories, JsAndTsFilePatterns(functionBaseDirectory)...)
+		for _, functionConfig := range vercelConfig.Functions {
+			functionDirectories = append(functionDirectories, path.Join(functionBaseDirectory, functionConfig.Pattern))
 		}
 
 		jsFiles, err := FilterTextFiles(
@@ -1553,62 +1845,143 @@ func scanVercel(data *RepositoryData, files []TextFile) {
 		}
 
 		for _, jsFile := range jsFiles {
-			hasHandler1 := strings.Contains(jsFile.Content, "export default") && strings.Contains(jsFile.Content, "handler")
-			hasHandler2 := strings.Contains(jsFile.Content, "module.exports") && (strings.Contains(jsFile.Content, "req") ||
-				strings.Contains(jsFile.Content, "res"))
-			hasHandler3 := strings.Contains(jsFile.Content, "export function GET(") ||
-				strings.Contains(jsFile.Content, "export function POST(") ||
-				strings.Contains(jsFile.Content, "export function PUT(") ||
-				strings.Contains(jsFile.Content, "export function DELETE(") ||
-				strings.Contains(jsFile.Content, "export function HEAD(") ||
-				strings.Contains(jsFile.Content, "export function OPTIONS(")
-
-			if !(hasHandler1 || hasHandler2 || hasHandler3) {
+			if isTypeScriptDeclarationFile(jsFile.Path) {
+				continue
+			}
+
+			relativePath := strings.TrimPrefix(jsFile.Path, functionBaseDirectory+"/")
+
+			// NOTE: App Router routes are `route` files, middleware runs in front of all routes on the edge
+			routeDirectory := ""
+			isMiddleware := vercelMiddlewareFileRegexp.MatchString(relativePath)
+			for _, directory := range routeDirectories {
+				if strings.HasPrefix(jsFile.Path, directory+"/") {
+					routeDirectory = directory
+				}
+			}
+			for _, appDirectory := range vercelAppDirectories {
+				directory := path.Join(functionBaseDirectory, appDirectory)
+				if strings.HasPrefix(jsFile.Path, directory+"/") && vercelRouteFileRegexp.MatchString(path.Base(jsFile.Path)) {
+					routeDirectory = directory
+				}
+			}
+			functionConfig, hasFunctionConfig := vercelConfig.FunctionConfig(jsFile.Path)
+			if routeDirectory == "" && !isMiddleware && !hasFunctionConfig {
+				continue
+			}
+
+			module := data.JsModules.Module(jsFile)
+
+			// NOTE: covers both `export default` and `module.exports =`
+			var handler *JsExport
+			if export := module.Export("default"); export != nil && isHandlerValue(module, export.Value) {
+				handler = export
+			}
+			for _, method := range httpMethodHandlerNames {
+				if export := module.Export(method); handler == nil && export != nil {
+					handler = export
+				}
+			}
+			if export := module.Export("middleware"); isMiddleware && handler == nil && export != nil {
+				handler = export
+			}
+			if handler == nil {
 				continue
 			}
 
 			function := RepositoryFaaSFunctionData{
-				Name:           "",
+				Name:           "", // set below
 				Platform:       FaaSPlatformVercel,
 				Framework:      FaaSFrameworkVercel,
 				InvocationType: FaaSInvocationTypeHTTP,
 				Location:       FaaSLocationUnknown,
 				TimeoutSeconds: -1,
+				MemoryMB:       -1,
 				SourceFilePath: jsFile.Path,
-				SourceFileLine: -1,
+				SourceFileLine: handler.Line,
 			}
 
-			isEdge1 := strings.Contains(jsFile.Content, "export const config") &&
-				strings.Contains(jsFile.Content, "runtime") &&
-				(strings.Contains(jsFile.Content, "'edge'") || strings.Contains(jsFile.Content, "\"edge\""))
+			switch {
+			case isMiddleware:
+				function.Name = "middleware"
+			case routeDirectory != "":
+				function.Name = vercelRoute(routeDirectory, jsFile.Path)
+			default:
+				function.Name = "/" + strings.TrimSuffix(relativePath, path.Ext(relativePath))
+			}
 
-			isEdge2 := strings.Contains(jsFile.Content, "export const runtime") &&
-				(strings.Contains(jsFile.Content, "'edge'") || strings.Contains(jsFile.Content, "\"edge\""))
+			segmentConfig := vercelModuleSegmentConfig(module)
+			isEdge := segmentConfig.IsEdge
+			if isMiddleware {
+				isEdge = module.Export("runtime") == nil || segmentConfig.IsEdge
+			}
+			if hasFunctionConfig {
+				function.MemoryMB = functionConfig.MemoryMB
+				function.TimeoutSeconds = functionConfig.TimeoutSeconds
+				function.Runtime = functionConfig.Runtime
+				isEdge = isEdge || functionConfig.Runtime == "edge"
+				if len(functionConfig.Regions) > 0 {
+					function.Regions = functionConfig.Regions
+				}
+			}
+			if segmentConfig.Runtime != "" {
+				function.Runtime = segmentConfig.Runtime
+			}
+			if segmentConfig.TimeoutSeconds != -1 {
+				function.TimeoutSeconds = segmentConfig.TimeoutSeconds
+			}
+			if len(segmentConfig.Regions) > 0 {
+				function.Regions = segmentConfig.Regions
+			}
 
-			if isEdge1 || isEdge2 {
+			// NOTE: the regions of vercel.json only apply to serverless functions
+			if isEdge {
 				function.Location = FaaSLocationEdge
 			} else {
 				function.Location = FaaSLocationRegion
+				if len(function.Regions) == 0 {
+					function.Regions = vercelConfig.Regions
+				}
 			}
 
-			// TODO: peak into vercel.json to get possible cron schedules and additional runtime configuration
+			// NOTE: cron jobs invoke the route of a function with a GET request on schedule
+			if schedules := vercelConfig.CronSchedules(function.Name); len(schedules) > 0 {
+				function.InvocationType = FaaSInvocationTypeSchedule
+				function.Attributes = map[string]string{"schedule": strings.Join(schedules, ",")}
+			}
 
 			data.Functions = append(data.Functions, function)
 		}
 	}
+
+	return nil
 }
 
-func scanNetlify(data *RepositoryData, files []TextFile) {
+func scanNetlify(data *ScannerData, files []TextFile) error {
 	netlifyConfigFiles, err := FilterTextFiles(
 		files,
 		"**/netlify.toml",
 	)
 	if err != nil {
-		return
+		return err
 	}
 
+	defaultFunction := RepositoryFaaSFunctionData{
+		Name:           "", // set below
+		Platform:       FaaSPlatformNetlify,
+		Framework:      FaaSFrameworkNetlify,
+		InvocationType: FaaSInvocationTypeHTTP,
+		Location:       FaaSLocationRegion, // set below
+		TimeoutSeconds: -1,
+		MemoryMB:       -1,
+		SourceFilePath: "", // set below
+		SourceFileLine: -1, // set below
+	}
+
+	allDependencies := slices.Concat(data.Dependencies, data.DevDependencies)
+
 	for _, netlifyConfigFile := range netlifyConfigFiles {
-		netlifyConfig, err := LoadJsonFromTomlBytes([]byte(netlifyConfigFile.Content))
+		netlifyConfig, err := LoadNetlifyConfig(netlifyConfigFile)
 		if err != nil {
 			continue
 		}
@@ -1616,143 +1989,232 @@ func scanNetlify(data *RepositoryData, files []TextFile) {
 		data.UsedFrameworks[FaaSFrameworkNetlify] = true
 		data.UsedPlatforms[FaaSPlatformNetlify] = true
 
-		buildBase, err := JsonResolveString(netlifyConfig, []string{"build", "base"})
-		if err != nil {
-			buildBase = ""
-		}
-
-		functionsBase := ""
-		if v1, err := JsonResolveString(netlifyConfig, []string{"functions", "directory"}); err == nil {
-			functionsBase = v1
-		} else if v2, err := JsonResolveString(netlifyConfig, []string{"build", "functions"}); err == nil {
-			functionsBase = v2
-		} else {
-			functionsBase = "netlify/functions"
+		// NOTE: functions bundled by netlify-lambda only end up in the functions directory during the build
+		functionsDirectories := []string{netlifyConfig.FunctionsDirectory}
+		if packageJsonFiles, err := FilterTextFiles(files, path.Join(netlifyConfig.Base, "package.json")); err == nil {
+			for _, packageJsonFile := range packageJsonFiles {
+				functionsDirectories = append(functionsDirectories, netlifyLambdaSourceDirectories(packageJsonFile)...)
+			}
 		}
 
-		functionsPath := path.Join(
-			path.Dir(netlifyConfigFile.Path),
-			buildBase,
-			functionsBase,
-		)
+		for _, functionsDirectory := range functionsDirectories {
+			functionsJsFiles, err := FilterJsAndTsFiles(files, functionsDirectory)
+			if err != nil {
+				continue
+			}
 
-		functionsJsFiles, err := FilterTextFiles(
-			files,
-			path.Join(functionsPath, "**/*.js"),
-			path.Join(functionsPath, "**/*.mjs"),
-		)
-		if err == nil {
 			for _, jsFile := range functionsJsFiles {
-				if !(strings.Contains(jsFile.Content, "exports.handler") ||
-					strings.Contains(jsFile.Content, "export const handler") ||
-					strings.Contains(jsFile.Content, "export const function handler") ||
-					strings.Contains(jsFile.Content, "export const async function handler")) {
+				name, ok := netlifyFunctionName(functionsDirectory, jsFile.Path)
+				if !ok {
+					continue
+				}
+				module := data.JsModules.Module(jsFile)
+				line, ok := netlifyHandlerLine(module)
+				if !ok {
 					continue
 				}
 
-				data.Functions = append(data.Functions, RepositoryFaaSFunctionData{
-					Name:           "",
-					Platform:       FaaSPlatformNetlify,
-					Framework:      FaaSFrameworkNetlify,
-					InvocationType: FaaSInvocationTypeHTTP,
-					Location:       FaaSLocationRegion,
-					TimeoutSeconds: -1,
-					SourceFilePath: jsFile.Path,
-					SourceFileLine: -1,
-				})
-			}
+				function := defaultFunction
+				function.Name = name
+				function.SourceFilePath = jsFile.Path
+				function.SourceFileLine = line
+				function.Attributes = make(map[string]string)
 
-			// TODO: Functions could also be defined elsewhere and only be put in the functions directory
-			//       during the build process. We would need to somehow find the real location, maybe by
-			//       scanning for build tools that target the functions directory location.
-		}
+				functionConfig := netlifyConfig.FunctionConfig(name)
+				inlineConfig := netlifyModuleInlineConfig(module)
+				if functionConfig.NodeBundler != "" {
+					function.Attributes["nodeBundler"] = functionConfig.NodeBundler
+				}
+				if len(functionConfig.IncludedFiles) > 0 {
+					function.Attributes["includedFiles"] = strings.Join(functionConfig.IncludedFiles, ",")
+				}
+				if len(inlineConfig.Paths) > 0 {
+					function.Attributes["path"] = strings.Join(inlineConfig.Paths, ",")
+				}
 
-		edgeFunctionsBase := ""
-		if v, err := JsonResolveString(netlifyConfig, []string{"build", "edge_functions"}); err == nil {
-			edgeFunctionsBase = v
-		} else {
-			edgeFunctionsBase = "netlify/edge_functions"
-		}
+				schedule := functionConfig.Schedule
+				if inlineConfig.Schedule != "" {
+					schedule = inlineConfig.Schedule
+				}
+				switch {
+				case schedule != "":
+					function.InvocationType = FaaSInvocationTypeSchedule
+					function.Attributes["schedule"] = schedule
+				case strings.HasSuffix(name, netlifyBackgroundFunctionSuffix):
+					function.TimeoutSeconds = netlifyBackgroundFunctionTimeoutSeconds
+					function.Attributes["background"] = "true"
+				}
 
-		edgeFunctionsPath := path.Join(
-			path.Dir(netlifyConfigFile.Path),
-			buildBase,
-			edgeFunctionsBase,
-		)
+				data.Functions = append(data.Functions, function)
+			}
+		}
 
-		edgeFunctionsJsFiles, err := FilterTextFiles(
-			files,
-			path.Join(edgeFunctionsPath, "**/*.js"),
-			path.Join(edgeFunctionsPath, "**/*.mjs"),
-		)
+		edgeFunctionsJsFiles, err := FilterJsAndTsFiles(files, netlifyConfig.EdgeFunctionsDirectory)
 		if err == nil {
 			for _, jsFile := range edgeFunctionsJsFiles {
-				if !(strings.Contains(jsFile.Content, "exports.handler") ||
-					strings.Contains(jsFile.Content, "export const handler") ||
-					strings.Contains(jsFile.Content, "export const function handler") ||
-					strings.Contains(jsFile.Content, "export const async function handler")) {
+				name, ok := netlifyFunctionName(netlifyConfig.EdgeFunctionsDirectory, jsFile.Path)
+				if !ok {
+					continue
+				}
+				module := data.JsModules.Module(jsFile)
+				line, ok := netlifyHandlerLine(module)
+				if !ok {
 					continue
 				}
 
-				data.Functions = append(data.Functions, RepositoryFaaSFunctionData{
-					Name:           "",
-					Platform:       FaaSPlatformNetlify,
-					Framework:      FaaSFrameworkNetlify,
-					InvocationType: FaaSInvocationTypeHTTP,
-					Location:       FaaSLocationEdge,
-					TimeoutSeconds: -1,
-					SourceFilePath: jsFile.Path,
-					SourceFileLine: -1,
-				})
+				// NOTE: edge functions are routed by `[[edge_functions]]` in netlify.toml or by their inline config
+				function := defaultFunction
+				function.Name = name
+				function.Location = FaaSLocationEdge
+				function.SourceFilePath = jsFile.Path
+				function.SourceFileLine = line
+				function.Attributes = make(map[string]string)
+				paths := slices.Concat(netlifyConfig.EdgeFunctionPaths(name), netlifyModuleInlineConfig(module).Paths)
+				if len(paths) > 0 {
+					function.Attributes["path"] = strings.Join(paths, ",")
+				}
+
+				data.Functions = append(data.Functions, function)
+			}
+		}
+
+		// Framework plugins
+		for _, frameworkPlugin := range netlifyFrameworkPlugins {
+			if !slices.Contains(netlifyConfig.Plugins, frameworkPlugin.plugin) && !slices.Contains(allDependencies, frameworkPlugin.plugin) {
+				continue
 			}
 
-			// TODO: Edge functions could also be defined elsewhere and only be put in the functions directory
-			//       during the build process. We would need to somehow find the real location, maybe by
-			//       scanning for build tools that target the functions directory location.
+			for _, name := range frameworkPlugin.functions {
+				function := defaultFunction
+				function.Name = name
+				function.SourceFilePath = netlifyConfigFile.Path
+				data.Functions = append(data.Functions, function)
+			}
+
+			// NOTE: edge functions of plugins are only generated for middleware of the framework
+			middlewareFiles, err := FilterJsAndTsFiles(files, netlifyConfig.Base)
+			if err != nil {
+				continue
+			}
+			for _, middlewareFile := range middlewareFiles {
+				relativePath := strings.TrimPrefix(middlewareFile.Path, netlifyConfig.Base+"/")
+				if netlifyConfig.Base == "." {
+					relativePath = middlewareFile.Path
+				}
+				if !vercelMiddlewareFileRegexp.MatchString(relativePath) {
+					continue
+				}
+				for _, name := range frameworkPlugin.edgeFunctions {
+					function := defaultFunction
+					function.Name = name
+					function.Location = FaaSLocationEdge
+					function.SourceFilePath = middlewareFile.Path
+					data.Functions = append(data.Functions, function)
+				}
+			}
 		}
 	}
+
+	return nil
 }
 
-func scanServerless(data *RepositoryData, files []TextFile) {
+func scanServerless(data *ScannerData, files []TextFile) error {
 	serverlessConfigs, err := FilterTextFiles(
 		files,
 		"**/serverless.yml", "**/serverless.yaml",
 	)
 	if err != nil || len(serverlessConfigs) == 0 {
-		return
+		return nil
 	}
 
-	data.UsedPlatforms[FaaSPlatformAWS] = true
-	data.UsedFrameworks[FaaSFrameworkServerless] = true
-
 	for _, serverlessConfig := range serverlessConfigs {
-		// TODO: resolve file references (`${file(...)`}
+		serverlessConfigJson, err := ResolveServerlessConfig(serverlessConfig, files, data.JsModules)
+		if err != nil {
+			continue
+		}
 
-		serverlessConfigJsons := LoadJsonsFromYamlBytes([]byte(serverlessConfig.Content))
-		if len(serverlessConfigJsons) != 1 {
+		// NOTE: Serverless Components use the same file name, see scanTencent
+		if _, ok := serverlessConfigJson["component"]; ok {
 			continue
 		}
 
-		serverlessConfigJson := serverlessConfigJsons[0]
+		platform := FaaSPlatformAWS
+		if providerName, err := JsonResolveString(serverlessConfigJson, []string{"provider", "name"}); err == nil {
+			if providerPlatform, ok := serverlessProviderPlatforms[providerName]; ok {
+				platform = providerPlatform
+			} else {
+				platform = FaaSPlatformUnknown
+			}
+		}
+
+		data.UsedPlatforms[platform] = true
+		data.UsedFrameworks[FaaSFrameworkServerless] = true
 
-		// TODO: look at the resources to identify serverless resources
-		// TODO: handle common (popular) serverless plugins
+		providerRuntime, _ := JsonResolveString(serverlessConfigJson, []string{"provider", "runtime"})
+		// NOTE: the defaults are the ones of the AWS provider, functions of other providers are left unconfigured
+		providerMemorySizeMB, err := JsonResolveInt(serverlessConfigJson, []string{"provider", "memorySize"})
+		if err != nil {
+			providerMemorySizeMB = -1
+			if platform == FaaSPlatformAWS {
+				providerMemorySizeMB = serverlessDefaultMemorySizeMB
+			}
+		}
+		providerTimeoutSeconds, err := JsonResolveInt(serverlessConfigJson, []string{"provider", "timeout"})
+		if err != nil {
+			providerTimeoutSeconds = -1
+			if platform == FaaSPlatformAWS {
+				providerTimeoutSeconds = serverlessDefaultTimeoutSeconds
+			}
+		}
+		providerArchitecture, _ := JsonResolveString(serverlessConfigJson, []string{"provider", "architecture"})
 
 		serverlessFunctions, err := JsonResolveMap(serverlessConfigJson, []string{"functions"})
 		if err != nil {
-			continue
+			serverlessFunctions = map[string]interface{}{}
 		}
 
-		for _, serverlessFunction := range serverlessFunctions {
+		functionNames := maps.Keys(serverlessFunctions)
+		slices.Sort(functionNames)
+
+		resourcesTemplate := serverlessResourcesTemplate(serverlessConfig.Path, serverlessConfigJson)
+
+		for _, functionName := range functionNames {
+			serverlessFunction := serverlessFunctions[functionName]
+
 			function := RepositoryFaaSFunctionData{
-				Name:           "",
-				Platform:       FaaSPlatformAWS,
+				Name:           functionName,
+				Platform:       platform,
 				Framework:      FaaSFrameworkServerless,
 				InvocationType: FaaSInvocationTypeUnknown,
-				Location:       FaaSLocationUnknown, // TODO: implement
-				TimeoutSeconds: -1,
+				Location:       FaaSLocationUnknown,
+				Runtime:        providerRuntime,
+				MemoryMB:       providerMemorySizeMB,
+				TimeoutSeconds: providerTimeoutSeconds,
+				Architecture:   providerArchitecture,
 				SourceFilePath: serverlessConfig.Path,
-				SourceFileLine: -1,
+				SourceFileLine: YamlKeyLine([]byte(serverlessConfig.Content), []string{"functions", functionName}),
+			}
+
+			if runtime, err := JsonResolveString(serverlessFunction, []string{"runtime"}); err == nil {
+				function.Runtime = runtime
+			}
+			if memorySizeMB, err := JsonResolveInt(serverlessFunction, []string{"memorySize"}); err == nil {
+				function.MemoryMB = memorySizeMB
+			}
+			if timeoutSeconds, err := JsonResolveInt(serverlessFunction, []string{"timeout"}); err == nil {
+				function.TimeoutSeconds = timeoutSeconds
+			}
+			if architecture, err := JsonResolveString(serverlessFunction, []string{"architecture"}); err == nil {
+				function.Architecture = architecture
+			}
+			if provisionedConcurrency, err := JsonResolveInt(serverlessFunction, []string{"provisionedConcurrency"}); err == nil {
+				function.ProvisionedConcurrency = provisionedConcurrency
+			}
+			if reservedConcurrency, err := JsonResolveInt(serverlessFunction, []string{"reservedConcurrency"}); err == nil {
+				function.ReservedConcurrency = reservedConcurrency
+			}
+			if handler, err := JsonResolveString(serverlessFunction, []string{"handler"}); err == nil {
+				function.Handler = handler
 			}
 
 			events, err := JsonResolveArray(serverlessFunction, []string{"events"})
@@ -1763,59 +2225,140 @@ func scanServerless(data *RepositoryData, files []TextFile) {
 						continue
 					}
 
-					eventNames := maps.Keys(eventMap)
-					for _, eventName := range eventNames {
-						switch eventName {
-						case "httpApi", "http":
-							function.InvocationType = FaaSInvocationTypeHTTP
-							function.Location = FaaSLocationRegion
-						case "websocket":
-							function.InvocationType = FaaSInvocationTypeWebsocket
-							function.Location = FaaSLocationRegion
-						case "schedule":
-							function.InvocationType = FaaSInvocationTypeSchedule
-							function.Location = FaaSLocationRegion
-						case "sns", "stream", "msk", "kafka":
-							function.InvocationType = FaaSInvocationTypeTopic
-							function.Location = FaaSLocationRegion
-						case "sqs", "activemq", "rabbitmq":
-							function.InvocationType = FaaSInvocationTypeQueue
-							function.Location = FaaSLocationRegion
-						case "s3", "alexa", "iot",
-							"cloudwatchEvent", "cognitoUserPool",
-							"alb", "eventBridge":
-							function.InvocationType = FaaSInvocationTypeOther
-							function.Location = FaaSLocationRegion
-						case "cloudFront":
-							function.InvocationType = FaaSInvocationTypeOther
-							function.Location = FaaSLocationEdge
-						default:
-							fmt.Printf("unkown serverless event: %s\n", eventName)
-							function.InvocationType = FaaSInvocationTypeUnknown
-							function.Location = FaaSLocationUnknown
+					for _, eventName := range maps.Keys(eventMap) {
+						invocationType, location, ok := serverlessEventInvocationType(eventName)
+						if !ok {
+							data.Warnf("unknown serverless event %s of function %s in %s", eventName, functionName, serverlessConfig.Path)
 						}
+						function.InvocationType, function.Location = invocationType, location
 					}
 				}
 			}
 
+			if platform == FaaSPlatformAWS {
+				function.Resources = serverlessFunctionResources(resourcesTemplate, serverlessConfigJson, serverlessFunction)
+			}
+
 			data.Functions = append(data.Functions, function)
 		}
-	}
-}
 
-func scanNitric(data *RepositoryData, files []TextFile) {
-	nitricConfigs, err := FilterTextFiles(
-		files,
-		"**/nitric.yaml",
-		"**/nitric.yml",
-	)
-	if err != nil || len(nitricConfigs) == 0 {
-		return
-	}
+		// NOTE: state machines of the serverless-step-functions plugin
+		stateMachines, err := JsonResolveMap(serverlessConfigJson, []string{"stepFunctions", "stateMachines"})
+		if err != nil {
+			stateMachines = map[string]interface{}{}
+		}
+		stateMachineNames := maps.Keys(stateMachines)
+		slices.Sort(stateMachineNames)
+
+		for _, stateMachineName := range stateMachineNames {
+			workflow := RepositoryWorkflowData{
+				Name:           stateMachineName,
+				Platform:       platform,
+				Framework:      FaaSFrameworkServerless,
+				SourceFilePath: serverlessConfig.Path,
+				SourceFileLine: YamlKeyLine([]byte(serverlessConfig.Content), []string{"stepFunctions", "stateMachines", stateMachineName}),
+			}
+			definition, _ := JsonResolve(stateMachines[stateMachineName], []string{"definition"})
+			workflow.Steps = ParseAmazonStatesLanguage(definition, func(value interface{}) string {
+				return serverlessStateMachineFunction(value, functionNames)
+			})
+			workflow.Functions = workflowStepFunctions(workflow.Steps)
+			data.Workflows = append(data.Workflows, workflow)
+		}
+
+		resourceFunctions := serverlessResourceFunctions(serverlessConfigJson)
+		logicalIds := maps.Keys(resourceFunctions)
+		slices.Sort(logicalIds)
+
+		for _, logicalId := range logicalIds {
+			properties := resourceFunctions[logicalId]
+
+			function := RepositoryFaaSFunctionData{
+				Name:           logicalId,
+				Platform:       FaaSPlatformAWS,
+				Framework:      FaaSFrameworkServerless,
+				InvocationType: FaaSInvocationTypeUnknown,
+				Location:       FaaSLocationRegion,
+				MemoryMB:       -1,
+				TimeoutSeconds: -1,
+				SourceFilePath: serverlessConfig.Path,
+				SourceFileLine: YamlKeyLine([]byte(serverlessConfig.Content), []string{"resources", "Resources", logicalId}),
+			}
+			function.Runtime, _ = JsonResolveString(properties, []string{"Runtime"})
+			function.Handler, _ = JsonResolveString(properties, []string{"Handler"})
+			if memorySizeMB, err := JsonResolveInt(properties, []string{"MemorySize"}); err == nil {
+				function.MemoryMB = memorySizeMB
+			}
+			if timeoutSeconds, err := JsonResolveInt(properties, []string{"Timeout"}); err == nil {
+				function.TimeoutSeconds = timeoutSeconds
+			}
+			function.Architecture, _ = JsonResolveString(properties, []string{"Architectures", "0"})
+			function.ReservedConcurrency, _ = JsonResolveInt(properties, []string{"ReservedConcurrentExecutions"})
+			if resource, ok := resourcesTemplate.Resources[logicalId]; ok {
+				function.Resources = resourcesTemplate.FunctionResources(resource)
+			}
+
+			data.Functions = append(data.Functions, function)
+		}
+
+		// NOTE: queue workers of serverless-lift are deployed as functions as well
+		if slices.Contains(serverlessPlugins(serverlessConfigJson), "serverless-lift") {
+			constructs, err := JsonResolveMap(serverlessConfigJson, []string{"constructs"})
+			if err != nil {
+				constructs = map[string]interface{}{}
+			}
+
+			constructNames := maps.Keys(constructs)
+			slices.Sort(constructNames)
+
+			for _, constructName := range constructNames {
+				construct := constructs[constructName]
+				if constructType, err := JsonResolveString(construct, []string{"type"}); err != nil || constructType != "queue" {
+					continue
+				}
+				worker, err := JsonResolveMap(construct, []string{"worker"})
+				if err != nil {
+					continue
+				}
+
+				function := RepositoryFaaSFunctionData{
+					Name:           fmt.Sprintf("%sWorker", constructName),
+					Platform:       FaaSPlatformAWS,
+					Framework:      FaaSFrameworkServerless,
+					InvocationType: FaaSInvocationTypeQueue,
+					Location:       FaaSLocationRegion,
+					Runtime:        providerRuntime,
+					MemoryMB:       providerMemorySizeMB,
+					TimeoutSeconds: providerTimeoutSeconds,
+					SourceFilePath: serverlessConfig.Path,
+					SourceFileLine: YamlKeyLine([]byte(serverlessConfig.Content), []string{"constructs", constructName}),
+				}
+				function.Handler, _ = JsonResolveString(worker, []string{"handler"})
+				if memorySizeMB, err := JsonResolveInt(worker, []string{"memorySize"}); err == nil {
+					function.MemoryMB = memorySizeMB
+				}
+				if timeoutSeconds, err := JsonResolveInt(worker, []string{"timeout"}); err == nil {
+					function.TimeoutSeconds = timeoutSeconds
+				}
+
+				data.Functions = append(data.Functions, function)
+			}
+		}
+	}
+
+	return nil
+}
+
+func scanNitric(data *ScannerData, files []TextFile) error {
+	nitricConfigs, err := FilterTextFiles(
+		files,
+		"**/nitric.yaml",
+		"**/nitric.yml",
+	)
+	if err != nil || len(nitricConfigs) == 0 {
+		return nil
+	}
 
-	data.UsedPlatforms[FaaSPlatformAWS] = true
-	data.UsedPlatforms[FaaSPlatformGCP] = true
-	data.UsedPlatforms[FaaSPlatformAzure] = true
 	data.UsedFrameworks[FaaSFrameworkNitric] = true
 
 	for _, nitricConfig := range nitricConfigs {
@@ -1825,17 +2368,35 @@ func scanNitric(data *RepositoryData, files []TextFile) {
 		}
 		nitricConfigJson := nitricConfigJsons[0]
 
-		nitricServices, err := JsonResolveArray(nitricConfigJson, []string{"services"})
-		if err != nil {
-			continue
+		// NOTE: a project can be deployed to several platforms by its stacks, functions are only attributed to a
+		// platform if all stacks agree
+		platform := FaaSPlatformUnknown
+		platforms := nitricStackPlatforms(path.Dir(nitricConfig.Path), files)
+		for _, stackPlatform := range platforms {
+			data.UsedPlatforms[stackPlatform] = true
+		}
+		if len(platforms) == 1 {
+			platform = platforms[0]
 		}
 
-		for _, nitricService := range nitricServices {
-			nitricServiceRelMatchPattern, err := JsonResolveString(nitricService, []string{"match"})
-			if err != nil {
-				continue
+		// NOTE: older projects list handlers instead of services, e.g. `handlers: [functions/*.ts]`
+		nitricServiceMatchPatterns := make([]string, 0)
+		if nitricServices, err := JsonResolveArray(nitricConfigJson, []string{"services"}); err == nil {
+			for _, nitricService := range nitricServices {
+				if nitricServiceRelMatchPattern, err := JsonResolveString(nitricService, []string{"match"}); err == nil {
+					nitricServiceMatchPatterns = append(nitricServiceMatchPatterns, nitricServiceRelMatchPattern)
+				}
+			}
+		}
+		if nitricHandlers, err := JsonResolveArray(nitricConfigJson, []string{"handlers"}); err == nil {
+			for _, nitricHandler := range nitricHandlers {
+				if nitricHandlerMatchPattern, ok := nitricHandler.(string); ok {
+					nitricServiceMatchPatterns = append(nitricServiceMatchPatterns, nitricHandlerMatchPattern)
+				}
 			}
+		}
 
+		for _, nitricServiceRelMatchPattern := range nitricServiceMatchPatterns {
 			nitricServiceFunctions, err := FilterTextFiles(
 				files,
 				path.Join(path.Dir(nitricConfig.Path), nitricServiceRelMatchPattern),
@@ -1844,24 +2405,82 @@ func scanNitric(data *RepositoryData, files []TextFile) {
 				continue
 			}
 
-			for _, _ = range nitricServiceFunctions {
-				// TODO: inspect function to figure out invocation type
-				data.Functions = append(data.Functions, RepositoryFaaSFunctionData{
-					Name:           "",
-					Platform:       FaaSPlatformAWS,
-					Framework:      FaaSFrameworkServerless,
+			// NOTE: each service is deployed as one function handling all of its triggers, the first one is reported
+			for _, nitricServiceFunction := range nitricServiceFunctions {
+				function := RepositoryFaaSFunctionData{
+					Name:           strings.TrimSuffix(path.Base(nitricServiceFunction.Path), path.Ext(nitricServiceFunction.Path)),
+					Platform:       platform,
+					Framework:      FaaSFrameworkNitric,
 					InvocationType: FaaSInvocationTypeUnknown,
 					Location:       FaaSLocationRegion,
 					TimeoutSeconds: -1,
-					SourceFilePath: nitricConfig.Path,
+					MemoryMB:       -1,
+					SourceFilePath: nitricServiceFunction.Path,
 					SourceFileLine: -1,
-				})
+				}
+
+				triggers := ParseNitricTriggers(nitricServiceFunction, data.JsModules)
+				if len(triggers) > 0 {
+					function.InvocationType = triggers[0].InvocationType
+					function.SourceFileLine = triggers[0].Line
+
+					function.Attributes = make(map[string]string)
+					for _, trigger := range triggers {
+						if trigger.Name == "" {
+							continue
+						}
+						attribute := trigger.Resource + "s"
+						if function.Attributes[attribute] != "" {
+							function.Attributes[attribute] += ","
+						}
+						function.Attributes[attribute] += trigger.Name
+					}
+				}
+
+				data.Functions = append(data.Functions, function)
 			}
 		}
 	}
+
+	return nil
+}
+
+var architectInvocationTypes = map[string]FaaSInvocationType{
+	"ws":             FaaSInvocationTypeWebsocket,
+	"http":           FaaSInvocationTypeHTTP,
+	"queues":         FaaSInvocationTypeQueue,
+	"events":         FaaSInvocationTypeTopic,
+	"scheduled":      FaaSInvocationTypeSchedule,
+	"tables-streams": FaaSInvocationTypeOther,
+}
+
+var architectHttpPathReplacer = strings.NewReplacer("/", "-", ":", "000", "*", "catchall", ".", "_")
+
+// NOTE: the handlers of Architect live in directories named after their entries by convention, e.g.
+// src/http/get-notes-000id for `get /notes/:id` and src/queues/resize for the queue `resize`
+func architectFunction(section string, entry []string) (string, string) {
+	if len(entry) == 0 || entry[0] == "" {
+		return "", ""
+	}
+
+	name := entry[0]
+	directory := entry[0]
+	if section == "http" {
+		method, route := "any", entry[0]
+		if len(entry) > 1 {
+			method, route = strings.ToLower(entry[0]), entry[1]
+		}
+		name = method + " " + route
+		directory = method + architectHttpPathReplacer.Replace(route)
+		if route == "/" {
+			directory = method + "-index"
+		}
+	}
+
+	return name, path.Join("src", section, directory, "index.handler")
 }
 
-func scanArchitect(data *RepositoryData, files []TextFile) {
+func scanArchitect(data *ScannerData, files []TextFile) error {
 	architectConfigs, err := FilterTextFiles(
 		files,
 		"**/.arc", "**/app.arc",
@@ -1869,33 +2488,34 @@ func scanArchitect(data *RepositoryData, files []TextFile) {
 		"**/arc.json",
 	)
 	if err != nil {
-		return
+		return err
 	}
 
 	if len(architectConfigs) == 0 {
-		return
+		return nil
 	}
 
 	data.UsedPlatforms[FaaSPlatformAWS] = true
 	data.UsedFrameworks[FaaSFrameworkArchitect] = true
 
 	defaultFunction := RepositoryFaaSFunctionData{
-		Name:           "",
+		Name:           "", // set below
 		Platform:       FaaSPlatformAWS,
 		Framework:      FaaSFrameworkArchitect,
 		InvocationType: FaaSInvocationTypeUnknown, // set below
 		Location:       FaaSLocationRegion,
 		TimeoutSeconds: -1,
+		MemoryMB:       -1,
 		SourceFilePath: "", // set below
-		SourceFileLine: -1,
+		SourceFileLine: -1, // set below
 	}
 	for _, architectConfig := range architectConfigs {
 		if architectConfig.Extension == ".arc" {
 			section := ""
-			for _, line := range strings.Split(architectConfig.Content, "\n") {
+			for lineIndex, line := range strings.Split(architectConfig.Content, "\n") {
+				// NOTE: indented lines configure the entry above them, e.g. `src` or `method` of an HTTP route
+				indented := strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")
 				line = strings.TrimSpace(line)
-				function := defaultFunction
-				function.SourceFilePath = architectConfig.Path
 				switch {
 				case len(line) == 0:
 					continue
@@ -1904,21 +2524,20 @@ func scanArchitect(data *RepositoryData, files []TextFile) {
 				case strings.HasPrefix(line, "@"):
 					section = line[1:]
 					continue
-				case section == "ws":
-					function.InvocationType = FaaSInvocationTypeWebsocket
-				case section == "http":
-					function.InvocationType = FaaSInvocationTypeHTTP
-				case section == "queues":
-					function.InvocationType = FaaSInvocationTypeQueue
-				case section == "events":
-					function.InvocationType = FaaSInvocationTypeTopic
-				case section == "scheduled":
-					function.InvocationType = FaaSInvocationTypeSchedule
-				case section == "tables-streams":
-					function.InvocationType = FaaSInvocationTypeOther
-				default:
+				case indented:
 					continue
 				}
+
+				invocationType, ok := architectInvocationTypes[section]
+				if !ok {
+					continue
+				}
+
+				function := defaultFunction
+				function.InvocationType = invocationType
+				function.Name, function.Handler = architectFunction(section, strings.Fields(line))
+				function.SourceFilePath = architectConfig.Path
+				function.SourceFileLine = lineIndex + 1
 				data.Functions = append(data.Functions, function)
 			}
 		} else {
@@ -1939,265 +2558,256 @@ func scanArchitect(data *RepositoryData, files []TextFile) {
 				continue
 			}
 
-			architectWsFunctions, err := JsonResolveArray(architectConfigJson, []string{"ws"})
-			if err == nil {
-				for _, _ = range architectWsFunctions {
-					function := defaultFunction
-					function.SourceFilePath = architectConfig.Path
-					function.InvocationType = FaaSInvocationTypeWebsocket
-					data.Functions = append(data.Functions, function)
-				}
-			}
-
-			architectHttpFunctions, err := JsonResolveArray(architectConfigJson, []string{"http"})
-			if err == nil {
-				for _, _ = range architectHttpFunctions {
-					function := defaultFunction
-					function.SourceFilePath = architectConfig.Path
-					function.InvocationType = FaaSInvocationTypeHTTP
-					data.Functions = append(data.Functions, function)
-				}
-			}
-
-			architectQueueFunctions, err := JsonResolveArray(architectConfigJson, []string{"queues"})
-			if err == nil {
-				for _, _ = range architectQueueFunctions {
-					function := defaultFunction
-					function.SourceFilePath = architectConfig.Path
-					function.InvocationType = FaaSInvocationTypeQueue
-					data.Functions = append(data.Functions, function)
-				}
-			}
-
-			architectPubSubFunctions, err := JsonResolveArray(architectConfigJson, []string{"events"})
-			if err == nil {
-				for _, _ = range architectPubSubFunctions {
-					function := defaultFunction
-					function.SourceFilePath = architectConfig.Path
-					function.InvocationType = FaaSInvocationTypeTopic
-					data.Functions = append(data.Functions, function)
-				}
-			}
-
-			architectScheduledFunctions, err := JsonResolveArray(architectConfigJson, []string{"scheduled"})
-			if err == nil {
-				for _, _ = range architectScheduledFunctions {
-					function := defaultFunction
-					function.SourceFilePath = architectConfig.Path
-					function.InvocationType = FaaSInvocationTypeSchedule
-					data.Functions = append(data.Functions, function)
+			for _, section := range []string{"ws", "http", "queues", "events", "scheduled", "tables-streams"} {
+				architectFunctions, err := JsonResolveArray(architectConfigJson, []string{section})
+				if err != nil {
+					continue
 				}
-			}
+				itemLines := YamlSequenceItemLines([]byte(architectConfig.Content), []string{section})
+				for index, architectFunctionJson := range architectFunctions {
+					// NOTE: entries are either names or lists like `["get", "/notes"]`
+					entry := make([]string, 0)
+					switch architectFunctionJson := architectFunctionJson.(type) {
+					case string:
+						entry = strings.Fields(architectFunctionJson)
+					case []interface{}:
+						for _, value := range architectFunctionJson {
+							entry = append(entry, fmt.Sprint(value))
+						}
+					}
 
-			architectOtherFunctions, err := JsonResolveArray(architectConfigJson, []string{"tables-streams"})
-			if err == nil {
-				for _, _ = range architectOtherFunctions {
 					function := defaultFunction
+					function.InvocationType = architectInvocationTypes[section]
+					function.Name, function.Handler = architectFunction(section, entry)
 					function.SourceFilePath = architectConfig.Path
-					function.InvocationType = FaaSInvocationTypeOther
+					if index < len(itemLines) {
+						function.SourceFileLine = itemLines[index]
+					}
 					data.Functions = append(data.Functions, function)
 				}
 			}
 		}
 	}
+
+	return nil
 }
 
-func scanAWSCDKAndSST(data *RepositoryData, files []TextFile) {
+var awsCDKAndSSTConstructs = map[string]struct {
+	invocationType FaaSInvocationType
+	location       FaaSLocation
+}{
+	"Function":       {FaaSInvocationTypeUnknown, FaaSLocationUnknown},
+	"NodejsFunction": {FaaSInvocationTypeUnknown, FaaSLocationUnknown},
+	"Api":            {FaaSInvocationTypeHTTP, FaaSLocationRegion},
+	"WebsocketApi":   {FaaSInvocationTypeWebsocket, FaaSLocationRegion},
+	"Cron":           {FaaSInvocationTypeSchedule, FaaSLocationRegion},
+	"Job":            {FaaSInvocationTypeQueue, FaaSLocationRegion},
+	"Queue":          {FaaSInvocationTypeQueue, FaaSLocationRegion},
+	"Topic":          {FaaSInvocationTypeTopic, FaaSLocationRegion},
+	"EventBus":       {FaaSInvocationTypeTopic, FaaSLocationRegion},
+	"KinesisStream":  {FaaSInvocationTypeTopic, FaaSLocationRegion},
+	"AppSyncApi":     {FaaSInvocationTypeOther, FaaSLocationRegion},
+}
+
+func scanAWSCDKAndSST(data *ScannerData, files []TextFile) error {
 	if !(slices.Contains(data.Dependencies, "sst") ||
 		slices.Contains(data.DevDependencies, "sst") ||
 		slices.Contains(data.Dependencies, "aws-cdk-lib") ||
 		slices.Contains(data.DevDependencies, "aws-cdk-lib")) {
-		return
+		return nil
 	}
 
-	jsFiles, err := FilterTextFiles(files, "**/*.js", "**/*.jsx", "**/*.mjs")
+	jsFiles, err := FilterJsAndTsFiles(files)
 	if err != nil {
-		return
+		return err
 	}
 
 	if len(jsFiles) == 0 {
-		return
+		return nil
 	}
 
 	defaultFunction := RepositoryFaaSFunctionData{
-		Name:           "",
+		Name:           "", // set below
 		Platform:       FaaSPlatformAWS,
 		Framework:      FaaSFrameworkAWSCDKAndSST,
 		InvocationType: FaaSInvocationTypeUnknown, // set below
 		Location:       FaaSLocationUnknown,       // TODO: implement
 		TimeoutSeconds: -1,
+		MemoryMB:       -1,
 		SourceFilePath: "", // set below
 		SourceFileLine: -1,
 	}
 
 	for _, jsFile := range jsFiles {
-		if !(strings.Contains(jsFile.Content, "sst/constructs") ||
-			strings.Contains(jsFile.Content, "@serverless-stack/resources") ||
-			strings.Contains(jsFile.Content, "aws-cdk-lib")) {
+		module := data.JsModules.Module(jsFile)
+		if !module.ImportsModule("sst", "@serverless-stack/resources", "aws-cdk-lib") {
 			continue
 		}
 
-		numLambdas := strings.Count(jsFile.Content, "new Function(") +
-			strings.Count(jsFile.Content, ".Function(")
-		numNodejsLambdas := strings.Count(jsFile.Content, "new NodejsFunction(") +
-			strings.Count(jsFile.Content, ".NodejsFunction(")
-		for index := 0; index < numLambdas+numNodejsLambdas; index++ {
-			function := defaultFunction
-			function.SourceFilePath = jsFile.Path
-			function.InvocationType = FaaSInvocationTypeUnknown
-			data.Functions = append(data.Functions, function)
-		}
-
-		numApis := strings.Count(jsFile.Content, "new Api(") +
-			strings.Count(jsFile.Content, ".Api(")
-		for index := 0; index < numApis; index++ {
-			function := defaultFunction
-			function.SourceFilePath = jsFile.Path
-			function.InvocationType = FaaSInvocationTypeHTTP
-			function.Location = FaaSLocationRegion
-			data.Functions = append(data.Functions, function)
-		}
-
-		numWebsocketApis := strings.Count(jsFile.Content, "new WebsocketApi(") +
-			strings.Count(jsFile.Content, ".WebsocketApi(")
-		for index := 0; index < numWebsocketApis; index++ {
-			function := defaultFunction
-			function.SourceFilePath = jsFile.Path
-			function.InvocationType = FaaSInvocationTypeWebsocket
-			function.Location = FaaSLocationRegion
-			data.Functions = append(data.Functions, function)
-		}
-
-		numSchedules := strings.Count(jsFile.Content, "new Cron(") +
-			strings.Count(jsFile.Content, ".Cron(")
-		for index := 0; index < numSchedules; index++ {
-			function := defaultFunction
-			function.SourceFilePath = jsFile.Path
-			function.InvocationType = FaaSInvocationTypeSchedule
-			function.Location = FaaSLocationRegion
-			data.Functions = append(data.Functions, function)
-		}
-
-		numJobs := strings.Count(jsFile.Content, "new Job(") +
-			strings.Count(jsFile.Content, ".Job(")
-		numQueues := strings.Count(jsFile.Content, "new Queue(") +
-			strings.Count(jsFile.Content, ".Queue(")
-		for index := 0; index < numJobs+numQueues; index++ {
-			function := defaultFunction
-			function.SourceFilePath = jsFile.Path
-			function.InvocationType = FaaSInvocationTypeQueue
-			function.Location = FaaSLocationRegion
-			data.Functions = append(data.Functions, function)
-		}
-
-		numTopics := strings.Count(jsFile.Content, "new Topic(") +
-			strings.Count(jsFile.Content, ".Topic(")
-		numEventBus := strings.Count(jsFile.Content, "new EventBus(") +
-			strings.Count(jsFile.Content, ".EventBus(")
-		numKinesisStreams := strings.Count(jsFile.Content, "new KinesisStream(") +
-			strings.Count(jsFile.Content, ".KinesisStream(")
-		for index := 0; index < numTopics+numEventBus+numKinesisStreams; index++ {
-			function := defaultFunction
-			function.SourceFilePath = jsFile.Path
-			function.InvocationType = FaaSInvocationTypeTopic
-			function.Location = FaaSLocationRegion
-			data.Functions = append(data.Functions, function)
-		}
+		for _, call := range module.Calls {
+			construct, ok := awsCDKAndSSTConstructs[call.CalleeName()]
+			// NOTE: a bare identifier has to be instantiated, otherwise any call of a function named e.g. Api would count
+			if !ok || !(call.IsNew || len(call.Callee) > 1) {
+				continue
+			}
 
-		numAppSyncApis := strings.Count(jsFile.Content, "new AppSyncApi(") +
-			strings.Count(jsFile.Content, ".AppSyncApi(")
-		for index := 0; index < numAppSyncApis; index++ {
 			function := defaultFunction
+			function.Name = awsCDKConstructId(call)
+			if function.Name == "" {
+				// NOTE: SST v3 constructs take the name as first argument, e.g. `new sst.aws.Function("Api", { ... })`
+				function.Name, _ = call.StringArgument(0)
+			}
 			function.SourceFilePath = jsFile.Path
-			function.InvocationType = FaaSInvocationTypeOther
-			function.Location = FaaSLocationRegion
+			function.SourceFileLine = call.Line
+			function.InvocationType = construct.invocationType
+			function.Location = construct.location
+			if call.CalleeName() == "Function" || call.CalleeName() == "NodejsFunction" {
+				if props, ok := awsCDKConstructProps(module, call); ok {
+					applyAWSCDKFunctionProps(module, props, &function)
+				}
+			}
 			data.Functions = append(data.Functions, function)
-		}
 
-		if numLambdas+numNodejsLambdas+numApis+numAppSyncApis+numWebsocketApis+
-			numJobs+numSchedules+numTopics+numQueues+numEventBus+numKinesisStreams > 0 {
 			data.UsedPlatforms[FaaSPlatformAWS] = true
 			data.UsedFrameworks[FaaSFrameworkAWSCDKAndSST] = true
 		}
+
+		data.Workflows = append(data.Workflows, ParseAWSCDKStateMachines(module)...)
 	}
+
+	return nil
 }
 
-func scanTerraform(data *RepositoryData, files []TextFile) {
+func scanTerraform(data *ScannerData, files []TextFile) error {
 	terraformFiles, err := FilterTextFiles(
 		files,
 		"**/*.tf",
 	)
 	if err != nil {
-		return
+		return err
 	}
 
 	if len(terraformFiles) == 0 {
-		return
+		return nil
 	}
 
-	platformChecks := map[string]FaaSPlatform{
-		"resource \"aws_lambda_function\"":            FaaSPlatformAWS,
-		"resource \"google_cloudfunctions_function\"": FaaSPlatformGCP,
-		"resource \"azurerm_function_app\"":           FaaSPlatformAzure,
-		"resource \"azurerm_linux_function_app\"":     FaaSPlatformAzure,
-		"resource \"azurerm_windows_function_app\"":   FaaSPlatformAzure,
-		"resource \"ibm_function_action\"":            FaaSPlatformIBM,
-		"resource \"oci_functions_function\"":         FaaSPlatformOracle,
-		"resource \"alicloud_fc_function\"":           FaaSPlatformAlibaba,
-	}
+	configurations := LoadTerraformConfigurations(terraformFiles)
 
-	knownPlatformLocations := map[FaaSPlatform]FaaSLocation{
-		FaaSPlatformGCP:     FaaSLocationRegion,
-		FaaSPlatformAzure:   FaaSLocationRegion,
-		FaaSPlatformIBM:     FaaSLocationRegion,
-		FaaSPlatformOracle:  FaaSLocationRegion,
-		FaaSPlatformAlibaba: FaaSLocationRegion,
-	}
+	directories := maps.Keys(configurations)
+	slices.Sort(directories)
 
-	for _, terraformFile := range terraformFiles {
-		for check, platform := range platformChecks {
-			count := strings.Count(terraformFile.Content, check)
-			for i := 0; i < count; i++ {
-				data.UsedFrameworks[FaaSFrameworkTerraform] = true
-				data.UsedPlatforms[platform] = true
+	for _, directory := range directories {
+		configuration := configurations[directory]
+		functionTriggers := configuration.FunctionTriggers()
+		edgeFunctions := configuration.EdgeFunctions()
 
-				location, ok := knownPlatformLocations[platform]
-				if !ok {
-					location = FaaSLocationUnknown
+		for _, address := range slices.Concat(configuration.ResourceAddresses(), configuration.ModuleCallAddresses()) {
+			block, _ := configuration.Block(address)
+			functionType, ok := configuration.FunctionType(block)
+			if !ok {
+				continue
+			}
+
+			data.UsedFrameworks[FaaSFrameworkTerraform] = true
+			data.UsedPlatforms[functionType.platform] = true
+
+			function := RepositoryFaaSFunctionData{
+				Name:           block.Name,
+				Platform:       functionType.platform,
+				Framework:      FaaSFrameworkTerraform,
+				InvocationType: functionType.invocationType,
+				Location:       functionType.location,
+				TimeoutSeconds: -1,
+				MemoryMB:       -1,
+				SourceFilePath: block.Path,
+				SourceFileLine: block.Line,
+			}
+			if name, ok := configuration.String(block.Body, functionType.name...); ok {
+				function.Name = name
+			}
+			function.Runtime, _ = configuration.String(block.Body, functionType.runtime...)
+			function.Handler, _ = configuration.String(block.Body, functionType.handler...)
+			if codeDirectory, ok := configuration.FunctionCodeDirectory(block.Body); ok && function.Handler != "" {
+				function.Handler = path.Join(codeDirectory, function.Handler)
+			}
+			if memorySize, ok := configuration.String(block.Body, functionType.memoryMB...); ok {
+				if memorySizeMB, ok := ParseMemorySizeMB(memorySize); ok {
+					function.MemoryMB = memorySizeMB
 				}
+			}
+			if timeout, ok := configuration.String(block.Body, functionType.timeoutSeconds...); ok {
+				if timeoutSeconds, ok := ParseDurationSeconds(timeout); ok {
+					function.TimeoutSeconds = timeoutSeconds
+				}
+			}
 
-				data.Functions = append(data.Functions, RepositoryFaaSFunctionData{
-					Name:           "",
-					Platform:       platform,
-					Framework:      FaaSFrameworkTerraform,
-					InvocationType: FaaSInvocationTypeUnknown,
-					Location:       location,
-					TimeoutSeconds: -1,
-					SourceFilePath: terraformFile.Path,
-					SourceFileLine: -1,
-				})
+			function.Architecture, _ = configuration.FirstString(block.Body, functionType.architecture...)
+			function.ProvisionedConcurrency, _ = configuration.Int(block.Body, functionType.provisionedConcurrency...)
+			if provisionedConcurrency, ok := configuration.FunctionProvisionedConcurrency(address); ok {
+				function.ProvisionedConcurrency = provisionedConcurrency
+			}
+			function.ReservedConcurrency, _ = configuration.Int(block.Body, functionType.reservedConcurrency...)
+			function.MinInstances, _ = configuration.Int(block.Body, functionType.minInstances...)
+
+			if invocationType, ok := functionTriggers[address]; ok {
+				function.InvocationType = invocationType
+			}
+			if edgeFunctions[address] {
+				function.InvocationType = FaaSInvocationTypeHTTP
+				function.Location = FaaSLocationEdge
+			}
+			function.Resources = configuration.FunctionResources(address)
+
+			for i := 0; i < configuration.Multiplicity*configuration.BlockMultiplicity(block); i++ {
+				data.Functions = append(data.Functions, function)
 			}
 		}
 	}
+
+	return nil
+}
+
+// NOTE: the first provider imported by a file wins
+var pulumiFunctionProviders = []struct {
+	platform   FaaSPlatform
+	location   FaaSLocation
+	modules    []string
+	constructs [][]string
+}{
+	{FaaSPlatformAWS, FaaSLocationUnknown, []string{"@pulumi/aws", "@pulumi/aws-native", "@pulumi/awsx"}, [][]string{
+		{"lambda", "Function"},
+	}},
+	{FaaSPlatformAzure, FaaSLocationRegion, []string{"@pulumi/azure-native", "@pulumi/azure", "@pulumi/azapi"}, [][]string{
+		{"appservice", "FunctionApp"}, {"appservice", "LinuxFunctionApp"}, {"appservice", "WindowsFunctionApp"},
+	}},
+	{FaaSPlatformGCP, FaaSLocationRegion, []string{"@pulumi/gcp", "@pulumi/google-native"}, [][]string{
+		{"cloudfunctions", "Function"}, {"cloudfunctionsv2", "Function"},
+	}},
+	{FaaSPlatformOracle, FaaSLocationRegion, []string{"@pulumi/oci"}, [][]string{
+		{"functions", "Function"},
+	}},
+	{FaaSPlatformAlibaba, FaaSLocationRegion, []string{"@pulumi/alicloud"}, [][]string{
+		{"fc", "Function"},
+	}},
 }
 
-func scanPulumi(data *RepositoryData, files []TextFile) {
+func scanPulumi(data *ScannerData, files []TextFile) error {
 	pulumiConfigFiles, err := FilterTextFiles(
 		files,
 		"**/Pulumi.yaml", "**/Pulumi.yml",
 	)
 	if err != nil {
-		return
+		return err
 	}
 
 	if len(pulumiConfigFiles) == 0 {
-		return
+		return nil
 	}
 
 	allDependencies := append(data.Dependencies, data.DevDependencies...)
 
 	if !(slices.Contains(allDependencies, "@pulumi/pulumi")) {
-		return
+		return nil
 	}
 
 	data.UsedFrameworks[FaaSFrameworkPulumi] = true
@@ -2220,160 +2830,161 @@ func scanPulumi(data *RepositoryData, files []TextFile) {
 	default:
 	}
 
-	jsFiles, err := FilterTextFiles(files, "**/*.js", "**/*.jsx", "**/*.mjs")
+	jsFiles, err := FilterJsAndTsFiles(files)
 	if err != nil {
-		return
+		return err
 	}
 
 	if len(jsFiles) == 0 {
-		return
+		return nil
 	}
 
 	defaultFunction := RepositoryFaaSFunctionData{
-		Name:           "",
+		Name:           "",                  // set below
 		Platform:       FaaSPlatformUnknown, // set below
 		Framework:      FaaSFrameworkPulumi,
 		InvocationType: FaaSInvocationTypeUnknown, // set below
 		Location:       FaaSLocationUnknown,       // set below
 		TimeoutSeconds: -1,
+		MemoryMB:       -1,
 		SourceFilePath: "", // set below
 		SourceFileLine: -1,
 	}
 
 	for _, jsFile := range jsFiles {
-		switch {
-		case strings.Contains(jsFile.Content, "@pulumi/aws"),
-			strings.Contains(jsFile.Content, "@pulumi/aws-native"),
-			strings.Contains(jsFile.Content, "@pulumi/awsx"):
-			numFunctions := strings.Count(jsFile.Content, "lambda.Function(")
-			for index := 0; index < numFunctions; index++ {
-				function := defaultFunction
-				function.Platform = FaaSPlatformAWS
-				function.InvocationType = FaaSInvocationTypeUnknown
-				function.Location = FaaSLocationUnknown
-				function.SourceFilePath = jsFile.Path
-				data.Functions = append(data.Functions, function)
-			}
-		case strings.Contains(jsFile.Content, "@pulumi/azure-native"),
-			strings.Contains(jsFile.Content, "@pulumi/azure"),
-			strings.Contains(jsFile.Content, "@pulumi/azapi"):
-			numFunctions := strings.Count(jsFile.Content, "appservice.FunctionApp(") +
-				strings.Count(jsFile.Content, "appservice.LinuxFunctionApp(") +
-				strings.Count(jsFile.Content, "appservice.WindowsFunctionApp(")
-			for index := 0; index < numFunctions; index++ {
-				function := defaultFunction
-				function.Platform = FaaSPlatformAzure
-				function.InvocationType = FaaSInvocationTypeUnknown
-				function.Location = FaaSLocationRegion
-				function.SourceFilePath = jsFile.Path
-				data.Functions = append(data.Functions, function)
-			}
-		case strings.Contains(jsFile.Content, "@pulumi/gcp"),
-			strings.Contains(jsFile.Content, "@pulumi/google-native"):
-			numFunctions := strings.Count(jsFile.Content, "cloudfunctions.Function(") +
-				strings.Count(jsFile.Content, "cloudfunctionsv2.Function(")
-			for index := 0; index < numFunctions; index++ {
-				function := defaultFunction
-				function.Platform = FaaSPlatformGCP
-				function.InvocationType = FaaSInvocationTypeUnknown
-				function.Location = FaaSLocationRegion
-				function.SourceFilePath = jsFile.Path
-				data.Functions = append(data.Functions, function)
-			}
-		case strings.Contains(jsFile.Content, "@pulumi/oci"):
-			numFunctions := strings.Count(jsFile.Content, "functions.Function(")
-			for index := 0; index < numFunctions; index++ {
-				function := defaultFunction
-				function.Platform = FaaSPlatformOracle
-				function.InvocationType = FaaSInvocationTypeUnknown
-				function.Location = FaaSLocationRegion
-				function.SourceFilePath = jsFile.Path
-				data.Functions = append(data.Functions, function)
+		module := data.JsModules.Module(jsFile)
+
+		for _, provider := range pulumiFunctionProviders {
+			if !module.ImportsModule(provider.modules...) {
+				continue
 			}
-		case strings.Contains(jsFile.Content, "@pulumi/alicloud"):
-			numFunctions := strings.Count(jsFile.Content, "fc.Function(")
-			for index := 0; index < numFunctions; index++ {
-				function := defaultFunction
-				function.Platform = FaaSPlatformAlibaba
-				function.InvocationType = FaaSInvocationTypeUnknown
-				function.Location = FaaSLocationRegion
-				function.SourceFilePath = jsFile.Path
-				data.Functions = append(data.Functions, function)
+
+			for _, call := range module.Calls {
+				for _, construct := range provider.constructs {
+					if !call.CalleeEndsWith(construct...) {
+						continue
+					}
+
+					function := defaultFunction
+					// NOTE: resources are named by their first argument, e.g. `new aws.lambda.Function("api", { ... })`
+					function.Name, _ = call.StringArgument(0)
+					function.Platform = provider.platform
+					function.InvocationType = FaaSInvocationTypeUnknown
+					function.Location = provider.location
+					function.SourceFilePath = jsFile.Path
+					function.SourceFileLine = call.Line
+					data.Functions = append(data.Functions, function)
+				}
 			}
-		default:
+			break
 		}
 	}
+
+	return nil
 }
 
-func scanAWSCloudFormationAndSAM(data *RepositoryData, files []TextFile) {
+func scanAWSCloudFormationAndSAM(data *ScannerData, files []TextFile) error {
 	configs, err := FilterTextFiles(
 		files,
 		"**/*.yaml", "**/*.yml",
 		"**/*.json",
 	)
 	if err != nil {
-		return
+		return err
 	}
 
 	defaultFunction := RepositoryFaaSFunctionData{
-		Name:           "",
+		Name:           "", // set below
 		Platform:       FaaSPlatformAWS,
 		Framework:      FaaSFrameworkAWSCloudFormationAndSAM,
 		InvocationType: FaaSInvocationTypeUnknown, // set below
-		Location:       FaaSLocationUnknown,       // set below
-		TimeoutSeconds: -1,
-		SourceFilePath: "", // set below
-		SourceFileLine: -1,
+		Location:       FaaSLocationRegion,        // corrected below
+		TimeoutSeconds: -1,                        // set below
+		MemoryMB:       -1,                        // set below
+		SourceFilePath: "",                        // set below
+		SourceFileLine: -1,                        // set below
 	}
 
-	numEdgeLambdaFunctions := 0
-	prelimFunctions := make([]RepositoryFaaSFunctionData, 0)
-
+	templates := make(map[string]*CloudFormationTemplate)
+	templatePaths := make([]string, 0)
 	for _, config := range configs {
-		var configJson interface{}
-		switch config.Extension {
-		case ".yaml", ".yml":
-			configJsons := LoadJsonsFromYamlBytes([]byte(config.Content))
-			if len(configJsons) != 1 {
+		template, err := LoadCloudFormationTemplate(config)
+		if err != nil || template == nil {
+			continue
+		}
+		templates[config.Path] = template
+		templatePaths = append(templatePaths, config.Path)
+	}
+
+	// NOTE: parameters passed to nested stacks override the defaults of the nested template
+	for _, templatePath := range templatePaths {
+		template := templates[templatePath]
+		for nestedTemplatePath, stack := range template.NestedTemplatePaths() {
+			nestedTemplate, ok := templates[nestedTemplatePath]
+			if !ok {
 				continue
 			}
-			configJson = configJsons[0]
-		case ".json":
-			configJson, err = LoadJsonFromBytes([]byte(config.Content))
+			parameters, err := JsonResolveMap(stack.Properties, []string{"Parameters"})
 			if err != nil {
 				continue
 			}
-		default:
-			continue
+			for name, value := range parameters {
+				nestedTemplate.Parameters[name] = template.ResolveValue(value)
+			}
 		}
+	}
 
-		resources, err := JsonResolveMap(configJson, []string{"Resources"})
-		if err != nil {
-			continue
-		}
+	for _, templatePath := range templatePaths {
+		template := templates[templatePath]
 
-		for _, resource := range resources {
-			resourceType, err := JsonResolveString(resource, []string{"Type"})
-			if err != nil {
-				continue
-			}
+		data.UsedPlatforms[FaaSPlatformAWS] = true
+		data.UsedFrameworks[FaaSFrameworkAWSCloudFormationAndSAM] = true
 
-			data.UsedPlatforms[FaaSPlatformAWS] = true
-			data.UsedFrameworks[FaaSFrameworkAWSCloudFormationAndSAM] = true
+		lambdaFunctionTriggers := template.LambdaFunctionTriggers()
+		edgeLambdaFunctions := template.EdgeLambdaFunctions()
 
-			switch resourceType {
-			case "AWS::Lambda::Function":
-				function := defaultFunction
-				function.InvocationType = FaaSInvocationTypeUnknown
-				function.Location = FaaSLocationRegion // corrected below
-				function.SourceFilePath = config.Path
-				prelimFunctions = append(prelimFunctions, function)
-			case "AWS::Serverless::Function":
-				function := defaultFunction
-				function.SourceFilePath = config.Path
-				function.Location = FaaSLocationRegion
+		for _, logicalId := range template.LogicalIds() {
+			resource := template.Resources[logicalId]
+
+			function := defaultFunction
+			function.Name = logicalId
+			function.SourceFilePath = template.Path
+			function.SourceFileLine = resource.Line
+
+			switch resource.Type {
+			case "AWS::Lambda::Function", "AWS::Serverless::Function":
+				properties := template.FunctionProperties(resource)
+
+				function.Runtime, _ = template.ResolveValue(properties["Runtime"]).(string)
+				function.Handler, _ = template.ResolveValue(properties["Handler"]).(string)
+				// NOTE: the handler is relative to the code of the function, which is uploaded to S3 if not local
+				if codeUri, ok := template.ResolveValue(properties["CodeUri"]).(string); ok && function.Handler != "" && !strings.Contains(codeUri, "://") {
+					function.Handler = path.Join(codeUri, function.Handler)
+				}
+				if memorySizeMB, err := JsonResolveInt(template.ResolveValue(properties["MemorySize"]), []string{}); err == nil {
+					function.MemoryMB = memorySizeMB
+				}
+				if timeoutSeconds, err := JsonResolveInt(template.ResolveValue(properties["Timeout"]), []string{}); err == nil {
+					function.TimeoutSeconds = timeoutSeconds
+				}
+				if architectures, ok := template.ResolveValue(properties["Architectures"]).([]interface{}); ok && len(architectures) > 0 {
+					function.Architecture, _ = template.ResolveValue(architectures[0]).(string)
+				}
+				provisionedConcurrency, _ := JsonResolve(properties, []string{"ProvisionedConcurrencyConfig", "ProvisionedConcurrentExecutions"})
+				if provisionedConcurrency, err := JsonResolveInt(template.ResolveValue(provisionedConcurrency), []string{}); err == nil {
+					function.ProvisionedConcurrency = provisionedConcurrency
+				} else if provisionedConcurrency, ok := template.FunctionProvisionedConcurrency(logicalId); ok {
+					function.ProvisionedConcurrency = provisionedConcurrency
+				}
+				if reservedConcurrency, err := JsonResolveInt(template.ResolveValue(properties["ReservedConcurrentExecutions"]), []string{}); err == nil {
+					function.ReservedConcurrency = reservedConcurrency
+				}
+
+				if invocationType, ok := lambdaFunctionTriggers[logicalId]; ok {
+					function.InvocationType = invocationType
+				}
 
-				events, err := JsonResolveMap(resource, []string{"Properties", "Events"})
+				events, err := JsonResolveMap(properties, []string{"Events"})
 				if err == nil {
 					for _, event := range events {
 						eventType, err := JsonResolveString(event, []string{"Type"})
@@ -2386,13 +2997,13 @@ func scanAWSCloudFormationAndSAM(data *RepositoryData, files []TextFile) {
 							function.InvocationType = FaaSInvocationTypeHTTP
 						case "Schedule", "ScheduleV2":
 							function.InvocationType = FaaSInvocationTypeSchedule
-						case "Kinesis", "SNS", "MQMSK", "SelfManagedKafka":
+						case "Kinesis", "SNS", "MQ", "MSK", "SelfManagedKafka":
 							function.InvocationType = FaaSInvocationTypeTopic
 						case "SQS":
 							function.InvocationType = FaaSInvocationTypeQueue
 						case "S3", "DynamoDB", "AlexaSkill", "Cognito",
 							"CloudWatchLogs", "CloudWatchEvent",
-							"CognitoDocumentDB", "EventBridgeRule", "IoTRule":
+							"DocumentDB", "EventBridgeRule", "IoTRule":
 							function.InvocationType = FaaSInvocationTypeOther
 						default:
 							fmt.Printf("eventType: %s\n", eventType)
@@ -2401,75 +3012,71 @@ func scanAWSCloudFormationAndSAM(data *RepositoryData, files []TextFile) {
 					}
 				}
 
+				function.Resources = template.FunctionResources(resource)
+
+				if edgeLambdaFunctions[logicalId] {
+					function.InvocationType = FaaSInvocationTypeHTTP
+					function.Location = FaaSLocationEdge
+				}
+
 				data.Functions = append(data.Functions, function)
 			case "AWS::Serverless::GraphQLApi":
-				function := defaultFunction
 				function.InvocationType = FaaSInvocationTypeGraphQL
-				function.Location = FaaSLocationRegion
-				function.SourceFilePath = config.Path
 				data.Functions = append(data.Functions, function)
 			case "AWS::CloudFront::Distribution":
-				properties, err := JsonResolveMap(resource, []string{"Properties"})
-				if err != nil {
-					continue
-				}
-
-				distributionConfig, err := JsonResolveMap(properties, []string{"DistributionConfig"})
-				if err != nil {
-					continue
-				}
-
-				allCacheBehaviors := make([]interface{}, 0)
-
-				defaultCacheBehavior, err := JsonResolveMap(distributionConfig, []string{"DefaultCacheBehavior"})
-				if err == nil {
-					allCacheBehaviors = append(allCacheBehaviors, defaultCacheBehavior)
-				}
-
-				cacheBehaviors, err := JsonResolveArray(distributionConfig, []string{"CacheBehaviors"})
-				if err == nil {
-					allCacheBehaviors = append(allCacheBehaviors, cacheBehaviors...)
-				}
-
-				for _, cacheBehavior := range allCacheBehaviors {
-					lambdaFunctionAssociations, err := JsonResolveArray(cacheBehavior, []string{"LambdaFunctionAssociations"})
-					if err == nil {
-						numEdgeLambdaFunctions += len(lambdaFunctionAssociations)
-					}
-
+				for _, cacheBehavior := range cloudFrontCacheBehaviors(resource) {
 					functionAssociations, err := JsonResolveArray(cacheBehavior, []string{"FunctionAssociations"})
-					if err == nil {
-						for _, _ = range functionAssociations {
-							function := defaultFunction
-							function.InvocationType = FaaSInvocationTypeHTTP
-							function.Location = FaaSLocationEdge
-							function.SourceFilePath = config.Path
-							data.Functions = append(data.Functions, function)
+					if err != nil {
+						continue
+					}
+					for _, functionAssociation := range functionAssociations {
+						edgeFunction := function
+						edgeFunction.InvocationType = FaaSInvocationTypeHTTP
+						edgeFunction.Location = FaaSLocationEdge
+
+						arn, _ := JsonResolve(functionAssociation, []string{"FunctionARN"})
+						if cloudFrontFunction, ok := template.ReferencedResource(arn); ok {
+							edgeFunction.Name = cloudFrontFunction.LogicalId
+							edgeFunction.SourceFileLine = cloudFrontFunction.Line
+							edgeFunction.Runtime, _ = JsonResolveString(cloudFrontFunction.Properties, []string{"FunctionConfig", "Runtime"})
 						}
+						data.Functions = append(data.Functions, edgeFunction)
 					}
-
 				}
+			case "AWS::Serverless::StateMachine", "AWS::StepFunctions::StateMachine":
+				workflow := RepositoryWorkflowData{
+					Name:           logicalId,
+					Platform:       FaaSPlatformAWS,
+					Framework:      FaaSFrameworkAWSCloudFormationAndSAM,
+					Steps:          make([]RepositoryWorkflowStepData, 0),
+					Functions:      make([]string, 0),
+					SourceFilePath: template.Path,
+					SourceFileLine: resource.Line,
+				}
+				if definition, substitutions, ok := template.StateMachineDefinition(resource, files); ok {
+					workflow.Steps = ParseAmazonStatesLanguage(definition, func(value interface{}) string {
+						return template.StateMachineFunction(value, substitutions)
+					})
+					workflow.Functions = workflowStepFunctions(workflow.Steps)
+				}
+				data.Workflows = append(data.Workflows, workflow)
 			default:
 			}
 		}
 	}
 
-	// TODO: figure out what to do if numEdgeLambdaFunctions > len(prelimFunctions)
-	if numEdgeLambdaFunctions <= len(prelimFunctions) {
-		for index := 0; index < numEdgeLambdaFunctions; index++ {
-			prelimFunctions[index].Location = FaaSLocationEdge
-		}
-		data.Functions = append(data.Functions, prelimFunctions...)
-	}
+	return nil
 }
 
-func scanAzureResourceManager(data *RepositoryData, files []TextFile) {
+var bicepResourceRegexp = regexp.MustCompile(`resource\s+(\w+)\s+'$`)
+
+func scanAzureResourceManager(data *ScannerData, files []TextFile) error {
 	armJsonFiles, err := FilterTextFiles(
 		files,
 		"**/*.json",
 	)
 	if err != nil {
-		return
+		return err
 	}
 
 	armBicepFiles, err := FilterTextFiles(
@@ -2477,22 +3084,23 @@ func scanAzureResourceManager(data *RepositoryData, files []TextFile) {
 		"**/*.bicep",
 	)
 	if err != nil {
-		return
+		return err
 	}
 
 	if len(armBicepFiles)+len(armJsonFiles) == 0 {
-		return
+		return nil
 	}
 
 	defaultFunction := RepositoryFaaSFunctionData{
-		Name:           "",
+		Name:           "", // set below
 		Platform:       FaaSPlatformAzure,
 		Framework:      FaaSFrameworkAzureResourceManager,
 		InvocationType: FaaSInvocationTypeUnknown, // set below
 		Location:       FaaSLocationRegion,
 		TimeoutSeconds: -1,
+		MemoryMB:       -1,
 		SourceFilePath: "", // set below
-		SourceFileLine: -1,
+		SourceFileLine: -1, // set below
 	}
 
 	for _, armJsonFile := range armJsonFiles {
@@ -2517,19 +3125,34 @@ func scanAzureResourceManager(data *RepositoryData, files []TextFile) {
 			continue
 		}
 
+		// NOTE: resources are listed or, since language version 2.0, keyed by their symbolic name
 		resources := make([]interface{}, 0)
+		resourceNames := make([]string, 0)
+		resourceLines := make([]int, 0)
 
 		resourcesMap, err1 := JsonResolveMap(armJson, []string{"resources"})
 		if err1 == nil {
-			for _, resource := range resourcesMap {
-				resources = append(resources, resource)
+			symbolicNames := maps.Keys(resourcesMap)
+			slices.Sort(symbolicNames)
+			for _, symbolicName := range symbolicNames {
+				resources = append(resources, resourcesMap[symbolicName])
+				resourceNames = append(resourceNames, symbolicName)
+				resourceLines = append(resourceLines, YamlKeyLine([]byte(armJsonFile.Content), []string{"resources", symbolicName}))
 			}
 		}
 
 		resourcesArray, err2 := JsonResolveArray(armJson, []string{"resources"})
 		if err2 == nil {
-			for _, resource := range resourcesArray {
+			itemLines := YamlSequenceItemLines([]byte(armJsonFile.Content), []string{"resources"})
+			for index, resource := range resourcesArray {
+				name, _ := JsonResolveString(resource, []string{"name"})
 				resources = append(resources, resource)
+				resourceNames = append(resourceNames, name)
+				if index < len(itemLines) {
+					resourceLines = append(resourceLines, itemLines[index])
+				} else {
+					resourceLines = append(resourceLines, -1)
+				}
 			}
 		}
 
@@ -2540,7 +3163,7 @@ func scanAzureResourceManager(data *RepositoryData, files []TextFile) {
 		data.UsedPlatforms[FaaSPlatformAzure] = true
 		data.UsedFrameworks[FaaSFrameworkAzureResourceManager] = true
 
-		for _, resource := range resources {
+		for index, resource := range resources {
 			resourceType, err := JsonResolveString(resource, []string{"type"})
 			if err != nil {
 				continue
@@ -2556,7 +3179,9 @@ func scanAzureResourceManager(data *RepositoryData, files []TextFile) {
 			}
 
 			function := defaultFunction
+			function.Name = resourceNames[index]
 			function.SourceFilePath = armJsonFile.Path
+			function.SourceFileLine = resourceLines[index]
 
 			// https://github.com/Azure/app-service-linux-docs/blob/master/Things_You_Should_Know/kind_property.md
 			switch resourceKind {
@@ -2572,21 +3197,52 @@ func scanAzureResourceManager(data *RepositoryData, files []TextFile) {
 	}
 
 	for _, armBicepFile := range armBicepFiles {
-		numFunctions := strings.Count(armBicepFile.Content, "Microsoft.Web/sites@20")
-
 		data.UsedPlatforms[FaaSPlatformAzure] = true
 		data.UsedFrameworks[FaaSFrameworkAzureResourceManager] = true
 
-		for index := 0; index < numFunctions; index++ {
+		for offset := 0; ; {
+			index := strings.Index(armBicepFile.Content[offset:], "Microsoft.Web/sites@20")
+			if index == -1 {
+				break
+			}
+			offset += index
+
+			lineStart := strings.LastIndex(armBicepFile.Content[:offset], "\n") + 1
 			function := defaultFunction
+			// NOTE: resources are declared like `resource functionApp 'Microsoft.Web/sites@2022-03-01' = {`
+			if match := bicepResourceRegexp.FindStringSubmatch(armBicepFile.Content[lineStart:offset]); match != nil {
+				function.Name = match[1]
+			}
 			function.SourceFilePath = armBicepFile.Path
+			function.SourceFileLine = strings.Count(armBicepFile.Content[:offset], "\n") + 1
 			function.InvocationType = FaaSInvocationTypeUnknown
 			data.Functions = append(data.Functions, function)
+
+			offset += len("Microsoft.Web/sites@20")
 		}
 	}
+
+	return nil
 }
 
-func scanGCPCloudDeploymentManager(data *RepositoryData, files []TextFile) {
+var deploymentManagerResourceNameRegexp = regexp.MustCompile(`^\s*(?:-\s*)?name:\s*['"]?([^'"\s]+)`)
+
+// NOTE: resources are listed like `- name: function \n  type: gcp-types/...`, the name is searched for in the lines of
+// the list item up to the type, templates written in Python are not supported
+func deploymentManagerResourceName(content string, typeOffset int) string {
+	lines := strings.Split(content[:typeOffset], "\n")
+	for index := len(lines) - 1; index >= 0; index-- {
+		if match := deploymentManagerResourceNameRegexp.FindStringSubmatch(lines[index]); match != nil {
+			return match[1]
+		}
+		if strings.HasPrefix(strings.TrimSpace(lines[index]), "-") {
+			break
+		}
+	}
+	return ""
+}
+
+func scanGCPCloudDeploymentManager(data *ScannerData, files []TextFile) error {
 	ValidResourceTypes := []string{
 		"spanner.v1.instance",
 		"compute.beta.image",
@@ -2751,7 +3407,7 @@ func scanGCPCloudDeploymentManager(data *RepositoryData, files []TextFile) {
 		"**/*.yaml", "**/*.yml",
 	)
 	if err != nil {
-		return
+		return err
 	}
 
 	for _, dmConfigFile := range dmConfigFiles {
@@ -2781,7 +3437,7 @@ func scanGCPCloudDeploymentManager(data *RepositoryData, files []TextFile) {
 	}
 
 	if !usesGCPCloudDeploymentManager {
-		return
+		return nil
 	}
 
 	data.UsedFrameworks[FaaSFrameworkGCPCloudDeploymentManager] = true
@@ -2793,40 +3449,53 @@ func scanGCPCloudDeploymentManager(data *RepositoryData, files []TextFile) {
 		"**/*.jinja", "**/*.py",
 	)
 	if err != nil {
-		return
+		return err
 	}
 
 	for _, dmFile := range dmFiles {
-		numFunctions := strings.Count(dmFile.Content, "gcp-types/cloudfunctions-v1:projects.locations.functions") +
-			strings.Count(dmFile.Content, "gcp-types/cloudfunctions-v2beta:projects.locations.functions")
+		for _, functionType := range []string{
+			"gcp-types/cloudfunctions-v1:projects.locations.functions",
+			"gcp-types/cloudfunctions-v2beta:projects.locations.functions",
+		} {
+			for offset := 0; ; {
+				index := strings.Index(dmFile.Content[offset:], functionType)
+				if index == -1 {
+					break
+				}
+				offset += index
 
-		for index := 0; index < numFunctions; index++ {
-			data.Functions = append(data.Functions, RepositoryFaaSFunctionData{
-				Name:           "",
-				Platform:       FaaSPlatformGCP,
-				Framework:      FaaSFrameworkGCPCloudDeploymentManager,
-				InvocationType: FaaSInvocationTypeUnknown,
-				Location:       FaaSLocationRegion,
-				TimeoutSeconds: -1,
-				SourceFilePath: dmFile.Path,
-				SourceFileLine: -1,
-			})
+				data.Functions = append(data.Functions, RepositoryFaaSFunctionData{
+					Name:           deploymentManagerResourceName(dmFile.Content, offset),
+					Platform:       FaaSPlatformGCP,
+					Framework:      FaaSFrameworkGCPCloudDeploymentManager,
+					InvocationType: FaaSInvocationTypeUnknown,
+					Location:       FaaSLocationRegion,
+					TimeoutSeconds: -1,
+					MemoryMB:       -1,
+					SourceFilePath: dmFile.Path,
+					SourceFileLine: strings.Count(dmFile.Content[:offset], "\n") + 1,
+				})
+
+				offset += len(functionType)
+			}
 		}
 	}
+
+	return nil
 }
 
-func scanAlibabaResourceOrchestrationService(data *RepositoryData, files []TextFile) {
+func scanAlibabaResourceOrchestrationService(data *ScannerData, files []TextFile) error {
 	configs, err := FilterTextFiles(
 		files,
 		"**/*.yaml", "**/*.yml",
 		"**/*.json",
 	)
 	if err != nil {
-		return
+		return err
 	}
 
 	if len(configs) == 0 {
-		return
+		return nil
 	}
 
 	for _, config := range configs {
@@ -2852,7 +3521,11 @@ func scanAlibabaResourceOrchestrationService(data *RepositoryData, files []TextF
 			continue
 		}
 
-		for _, resource := range resources {
+		logicalIds := maps.Keys(resources)
+		slices.Sort(logicalIds)
+
+		for _, logicalId := range logicalIds {
+			resource := resources[logicalId]
 			resourceType, err := JsonResolveString(resource, []string{"Type"})
 			if err != nil {
 				continue
@@ -2862,14 +3535,15 @@ func scanAlibabaResourceOrchestrationService(data *RepositoryData, files []TextF
 			data.UsedFrameworks[FaaSFrameworkAWSCloudFormationAndSAM] = true
 
 			function := RepositoryFaaSFunctionData{
-				Name:           "",
+				Name:           logicalId,
 				Platform:       FaaSPlatformAlibaba,
 				Framework:      FaaSFrameworkAlibabaResourceOrchestrationService,
 				InvocationType: FaaSInvocationTypeUnknown, // set below
 				Location:       FaaSLocationRegion,
 				TimeoutSeconds: -1,
+				MemoryMB:       -1,
 				SourceFilePath: config.Path,
-				SourceFileLine: -1,
+				SourceFileLine: YamlKeyLine([]byte(config.Content), []string{"Resources", logicalId}),
 			}
 
 			switch resourceType {
@@ -2880,57 +3554,60 @@ func scanAlibabaResourceOrchestrationService(data *RepositoryData, files []TextF
 			}
 		}
 	}
+
+	return nil
 }
 
-func scanFnProject(data *RepositoryData, files []TextFile) {
+func scanFnProject(data *ScannerData, files []TextFile) error {
 	allDependencies := make([]string, 0)
 	allDependencies = append(allDependencies, data.Dependencies...)
 	allDependencies = append(allDependencies, data.DevDependencies...)
 
 	if !slices.Contains(allDependencies, "@fnproject/fdk") {
-		return
+		return nil
 	}
 
 	data.UsedFrameworks[FaaSFrameworkFnProject] = true
 	data.UsedPlatforms[FaaSPlatformFnProject] = true
 
-	jsFiles, err := FilterTextFiles(
-		files,
-		"**/*.js", "**/*.jsx", "**/*.mjs",
-	)
+	jsFiles, err := FilterJsAndTsFiles(files)
 	if err != nil {
-		return
+		return err
 	}
 
 	for _, jsFile := range jsFiles {
-		if !strings.Contains(jsFile.Content, "@fnproject/fdk") {
-			continue
-		}
+		module := data.JsModules.Module(jsFile)
+
+		for _, call := range module.Calls {
+			if !module.CallsImport(call, "@fnproject/fdk", "handle") {
+				continue
+			}
 
-		if !strings.Contains(jsFile.Content, ".handle(") {
-			continue
+			// NOTE: functions are named after their directory unless renamed in their func.yaml
+			data.Functions = append(data.Functions, RepositoryFaaSFunctionData{
+				Name:           path.Base(path.Dir(jsFile.Path)),
+				Platform:       FaaSPlatformFnProject,
+				Framework:      FaaSFrameworkFnProject,
+				InvocationType: FaaSInvocationTypeHTTP,
+				Location:       FaaSLocationRegion,
+				TimeoutSeconds: -1,
+				MemoryMB:       -1,
+				SourceFilePath: jsFile.Path,
+				SourceFileLine: call.Line,
+			})
 		}
-
-		data.Functions = append(data.Functions, RepositoryFaaSFunctionData{
-			Name:           "",
-			Platform:       FaaSPlatformFnProject,
-			Framework:      FaaSFrameworkFnProject,
-			InvocationType: FaaSInvocationTypeHTTP,
-			Location:       FaaSLocationRegion,
-			TimeoutSeconds: -1,
-			SourceFilePath: jsFile.Path,
-			SourceFileLine: -1,
-		})
 	}
+
+	return nil
 }
 
-func scanNuclio(data *RepositoryData, files []TextFile) {
+func scanNuclio(data *ScannerData, files []TextFile) error {
 	nuclioConfigFiles, err := FilterTextFiles(
 		files,
 		"**/*.yaml", "**/*.yml",
 	)
 	if err != nil {
-		return
+		return err
 	}
 
 	for _, nuclioConfigFile := range nuclioConfigFiles {
@@ -2956,43 +3633,59 @@ func scanNuclio(data *RepositoryData, files []TextFile) {
 
 			data.UsedPlatforms[FaaSPlatformNuclio] = true
 			data.UsedFrameworks[FaaSFrameworkNuclio] = true
+
+			name, _ := JsonResolveString(nuclioConfigJson, []string{"metadata", "name"})
+			// NOTE: handlers are configured as `module:function`, e.g. "main:handler"
+			handler, _ := JsonResolveString(nuclioConfigJson, []string{"spec", "handler"})
+			sourceFileLine := -1
+			if len(nuclioConfigJsons) == 1 {
+				sourceFileLine = YamlKeyLine([]byte(nuclioConfigFile.Content), []string{"kind"})
+			}
+
 			data.Functions = append(data.Functions, RepositoryFaaSFunctionData{
-				Name:           "",
+				Name:           name,
 				Platform:       FaaSPlatformNuclio,
 				Framework:      FaaSFrameworkNuclio,
 				InvocationType: FaaSInvocationTypeUnknown,
 				Location:       FaaSLocationRegion,
 				TimeoutSeconds: -1,
+				MemoryMB:       -1,
+				Handler:        handler,
 				SourceFilePath: nuclioConfigFile.Path,
-				SourceFileLine: -1,
+				SourceFileLine: sourceFileLine,
 			})
 		}
 	}
+
+	return nil
 }
 
-func scanOpenWhisk(data *RepositoryData, files []TextFile) {
+func scanOpenWhisk(data *ScannerData, files []TextFile) error {
 	openWhiskConfigFiles, err := FilterTextFiles(
 		files,
 		"**/*.yaml", "**/*.yml",
 	)
 	if err != nil {
-		return
+		return err
 	}
 
 	for _, openWhiskConfigFile := range openWhiskConfigFiles {
-		openWhiskConfigJsons := LoadJsonsFromYamlBytes([]byte(openWhiskConfigFile.Content))
-		if len(openWhiskConfigJsons) != 1 {
+		openWhiskConfigDocuments := data.KubernetesManifests.Documents(openWhiskConfigFile)
+		if len(openWhiskConfigDocuments) != 1 {
 			continue
 		}
-		openWhiskConfigJson := openWhiskConfigJsons[0]
+		openWhiskConfigJson := openWhiskConfigDocuments[0].Json
 
 		openWhiskPackages, err := JsonResolveMap(openWhiskConfigJson, []string{"packages"})
 		if err != nil {
 			continue
 		}
 
-		for _, openWhiskPackage := range openWhiskPackages {
-			openWhiskPackageActions, err := JsonResolveMap(openWhiskPackage, []string{"actions"})
+		packageNames := maps.Keys(openWhiskPackages)
+		slices.Sort(packageNames)
+
+		for _, packageName := range packageNames {
+			openWhiskPackageActions, err := JsonResolveMap(openWhiskPackages[packageName], []string{"actions"})
 			if err != nil {
 				continue
 			}
@@ -3000,195 +3693,213 @@ func scanOpenWhisk(data *RepositoryData, files []TextFile) {
 			data.UsedPlatforms[FaaSPlatformOpenWhisk] = true
 			data.UsedFrameworks[FaaSFrameworkOpenWhisk] = true
 
-			numFunctions := len(openWhiskPackageActions)
-			for index := 0; index < numFunctions; index++ {
+			actionNames := maps.Keys(openWhiskPackageActions)
+			slices.Sort(actionNames)
+
+			for _, actionName := range actionNames {
+				// NOTE: actions point to their code with `function`, the export invoked is `main` unless configured
+				handler := ""
+				if actionFunction, err := JsonResolveString(openWhiskPackageActions[actionName], []string{"function"}); err == nil {
+					main, err := JsonResolveString(openWhiskPackageActions[actionName], []string{"main"})
+					if err != nil {
+						main = "main"
+					}
+					handler = fmt.Sprintf("%s:%s", actionFunction, main)
+				}
+
 				data.Functions = append(data.Functions, RepositoryFaaSFunctionData{
-					Name:           "",
+					Name:           actionName,
 					Platform:       FaaSPlatformOpenWhisk,
 					Framework:      FaaSFrameworkOpenWhisk,
 					InvocationType: FaaSInvocationTypeUnknown,
 					Location:       FaaSLocationRegion,
 					TimeoutSeconds: -1,
+					MemoryMB:       -1,
+					Handler:        handler,
 					SourceFilePath: openWhiskConfigFile.Path,
-					SourceFileLine: -1,
+					SourceFileLine: YamlKeyLine([]byte(openWhiskConfigFile.Content), []string{"packages", packageName, "actions", actionName}),
 				})
 			}
 		}
 	}
+
+	return nil
 }
 
-func scanFission(data *RepositoryData, files []TextFile) {
-	fissionConfigFiles, err := FilterTextFiles(
-		files,
-		"**/*.yaml", "**/*.yml",
-	)
-	if err != nil {
-		return
+func scanFission(data *ScannerData, files []TextFile) error {
+	defaultFunction := RepositoryFaaSFunctionData{
+		Name:           "", // set below
+		Platform:       FaaSPlatformFission,
+		Framework:      FaaSFrameworkFission,
+		InvocationType: FaaSInvocationTypeUnknown, // set below
+		Location:       FaaSLocationRegion,
+		TimeoutSeconds: -1,
+		MemoryMB:       -1,
+		SourceFilePath: "", // set below
+		SourceFileLine: -1, // set below
 	}
 
-	for _, fissionConfigFile := range fissionConfigFiles {
-		fissionConfigJsons := LoadJsonsFromYamlBytes([]byte(fissionConfigFile.Content))
-		for _, fissionConfigJson := range fissionConfigJsons {
-			apiVersion, err := JsonResolveString(fissionConfigJson, []string{"apiVersion"})
-			if err != nil {
-				continue
-			}
+	manifests := KubernetesManifests(data.KubernetesManifests.Manifests(files))
+	fissionTriggers := manifests.FissionTriggers()
 
-			if !strings.HasPrefix(apiVersion, "fission.io/") {
-				continue
-			}
+	for _, fissionFunction := range manifests.FissionFunctions() {
+		data.UsedPlatforms[FaaSPlatformFission] = true
+		data.UsedFrameworks[FaaSFrameworkFission] = true
 
-			kind, err := JsonResolveString(fissionConfigJson, []string{"kind"})
-			if err != nil {
+		function := defaultFunction
+		function.Name = fissionFunction.Name
+		function.Handler = fissionFunction.Entrypoint
+		function.SourceFilePath = fissionFunction.Path
+		function.SourceFileLine = fissionFunction.Line
+
+		// NOTE: a function without triggers can only be invoked through the router's internal URL or the CLI
+		for _, fissionTrigger := range fissionTriggers {
+			if fissionTrigger.Function != fissionFunction.Name || fissionTrigger.Namespace != fissionFunction.Namespace {
 				continue
 			}
-
-			if kind != "Function" {
-				continue
+			function.InvocationType = fissionTrigger.InvocationType
+			if len(fissionTrigger.Attributes) > 0 {
+				function.Attributes = fissionTrigger.Attributes
 			}
-
-			data.UsedPlatforms[FaaSPlatformFission] = true
-			data.UsedFrameworks[FaaSFrameworkFission] = true
-			data.Functions = append(data.Functions, RepositoryFaaSFunctionData{
-				Name:           "",
-				Platform:       FaaSPlatformFission,
-				Framework:      FaaSFrameworkFission,
-				InvocationType: FaaSInvocationTypeUnknown,
-				Location:       FaaSLocationRegion,
-				TimeoutSeconds: -1,
-				SourceFilePath: fissionConfigFile.Path,
-				SourceFileLine: -1,
-			})
+			break
 		}
+
+		data.Functions = append(data.Functions, function)
 	}
+
+	return nil
 }
 
-func scanKubeless(data *RepositoryData, files []TextFile) {
-	kubelessConfigFiles, err := FilterTextFiles(
-		files,
-		"**/*.yaml", "**/*.yml",
-	)
-	if err != nil {
-		return
+func scanKubeless(data *ScannerData, files []TextFile) error {
+	defaultFunction := RepositoryFaaSFunctionData{
+		Name:           "", // set below
+		Platform:       FaaSPlatformKubeless,
+		Framework:      FaaSFrameworkKubeless,
+		InvocationType: FaaSInvocationTypeUnknown, // set below
+		Location:       FaaSLocationRegion,
+		TimeoutSeconds: -1,
+		MemoryMB:       -1,
+		SourceFilePath: "", // set below
+		SourceFileLine: -1, // set below
 	}
 
-	for _, kubelessConfigFile := range kubelessConfigFiles {
-		kubelessConfigJsons := LoadJsonsFromYamlBytes([]byte(kubelessConfigFile.Content))
-		for _, kubelessConfigJson := range kubelessConfigJsons {
-			apiVersion, err := JsonResolveString(kubelessConfigJson, []string{"apiVersion"})
-			if err != nil {
-				continue
-			}
+	manifests := KubernetesManifests(data.KubernetesManifests.Manifests(files))
+	kubelessTriggers := manifests.KubelessTriggers()
 
-			if !strings.HasPrefix(apiVersion, "kubeless.io/") {
-				continue
-			}
+	for _, kubelessFunction := range manifests.KubelessFunctions() {
+		data.UsedPlatforms[FaaSPlatformKubeless] = true
+		data.UsedFrameworks[FaaSFrameworkKubeless] = true
 
-			kind, err := JsonResolveString(kubelessConfigJson, []string{"kind"})
-			if err != nil {
+		function := defaultFunction
+		function.Name = kubelessFunction.Name
+		function.Runtime = kubelessFunction.Runtime
+		function.Handler = kubelessFunction.Handler
+		function.SourceFilePath = kubelessFunction.Path
+		function.SourceFileLine = kubelessFunction.Line
+
+		for _, kubelessTrigger := range kubelessTriggers {
+			if kubelessTrigger.Function != kubelessFunction.Name && !matchesKubernetesLabels(kubelessFunction.Labels(), kubelessTrigger.FunctionLabels) {
 				continue
 			}
-
-			if kind != "Function" {
-				continue
+			function.InvocationType = kubelessTrigger.InvocationType
+			if len(kubelessTrigger.Attributes) > 0 {
+				function.Attributes = kubelessTrigger.Attributes
 			}
-
-			data.UsedPlatforms[FaaSPlatformKubeless] = true
-			data.UsedFrameworks[FaaSFrameworkKubeless] = true
-			data.Functions = append(data.Functions, RepositoryFaaSFunctionData{
-				Name:           "",
-				Platform:       FaaSPlatformKubeless,
-				Framework:      FaaSFrameworkKubeless,
-				InvocationType: FaaSInvocationTypeUnknown,
-				Location:       FaaSLocationRegion,
-				TimeoutSeconds: -1,
-				SourceFilePath: kubelessConfigFile.Path,
-				SourceFileLine: -1,
-			})
+			break
 		}
+
+		data.Functions = append(data.Functions, function)
 	}
+
+	return nil
 }
 
-func scanKnative(data *RepositoryData, files []TextFile) {
-	knativeConfigFiles, err := FilterTextFiles(
-		files,
-		"**/*.yaml", "**/*.yml",
-	)
-	if err != nil {
-		return
+func scanKnative(data *ScannerData, files []TextFile) error {
+	defaultFunction := RepositoryFaaSFunctionData{
+		Name:           "", // set below
+		Platform:       FaaSPlatformKnative,
+		Framework:      FaaSFrameworkKnative,
+		InvocationType: FaaSInvocationTypeHTTP, // set below
+		Location:       FaaSLocationRegion,
+		TimeoutSeconds: -1, // set below
+		MemoryMB:       -1,
+		SourceFilePath: "", // set below
+		SourceFileLine: -1, // set below
 	}
 
-	for _, knativeConfigFile := range knativeConfigFiles {
-		knativeConfigJsons := LoadJsonsFromYamlBytes([]byte(knativeConfigFile.Content))
-		for _, knativeConfigJson := range knativeConfigJsons {
-			apiVersion, err := JsonResolveString(knativeConfigJson, []string{"apiVersion"})
-			if err != nil {
-				continue
-			}
+	manifests := KubernetesManifests(data.KubernetesManifests.Manifests(files))
+	knativeTriggers := manifests.KnativeTriggers()
+	knativeSources := manifests.KnativeSources()
 
-			if !strings.HasPrefix(apiVersion, "serving.knative.dev/") {
-				continue
-			}
+	for _, knativeService := range manifests.KnativeServices() {
+		if isCloudRunManifest(knativeService.Json) {
+			continue
+		}
 
-			kind, err := JsonResolveString(knativeConfigJson, []string{"kind"})
-			if err != nil {
-				continue
-			}
+		data.UsedPlatforms[FaaSPlatformKnative] = true
+		data.UsedFrameworks[FaaSFrameworkKnative] = true
 
-			if kind != "Service" {
-				continue
+		function := defaultFunction
+		function.Name = knativeService.Name
+		function.TimeoutSeconds = knativeService.TimeoutSeconds
+		function.MinInstances = knativeService.MinInstances
+		function.SourceFilePath = knativeService.Path
+		function.SourceFileLine = knativeService.Line
+
+		// NOTE: services are always reachable through their route, events are delivered as HTTP requests as well,
+		// the first trigger is reported like for other functions handling several triggers
+		serviceTriggers := KnativeServiceTriggers(knativeService, knativeTriggers, knativeSources)
+		if len(serviceTriggers) > 0 {
+			function.InvocationType = serviceTriggers[0].InvocationType
+
+			function.Attributes = make(map[string]string)
+			for _, serviceTrigger := range serviceTriggers {
+				attributes := maps.Clone(serviceTrigger.Attributes)
+				attributes["triggers"] = serviceTrigger.Resource
+				for attribute, value := range attributes {
+					if function.Attributes[attribute] != "" {
+						function.Attributes[attribute] += ","
+					}
+					function.Attributes[attribute] += value
+				}
 			}
-
-			data.UsedPlatforms[FaaSPlatformKnative] = true
-			data.UsedFrameworks[FaaSFrameworkKnative] = true
-			data.Functions = append(data.Functions, RepositoryFaaSFunctionData{
-				Name:           "",
-				Platform:       FaaSPlatformKnative,
-				Framework:      FaaSFrameworkKnative,
-				InvocationType: FaaSInvocationTypeUnknown,
-				Location:       FaaSLocationRegion,
-				TimeoutSeconds: -1,
-				SourceFilePath: knativeConfigFile.Path,
-				SourceFileLine: -1,
-			})
 		}
+
+		data.Functions = append(data.Functions, function)
 	}
+
+	return nil
 }
 
-func scanFirebase(data *RepositoryData, files []TextFile) {
+func scanFirebase(data *ScannerData, files []TextFile) error {
 	defaultFunction := RepositoryFaaSFunctionData{
-		Name:           "",
+		Name:           "", // set below
 		Platform:       FaaSPlatformFirebase,
 		Framework:      FaaSFrameworkFirebase,
 		InvocationType: FaaSInvocationTypeUnknown, // set below
 		Location:       FaaSLocationRegion,
-		TimeoutSeconds: -1,
+		TimeoutSeconds: -1, // set below
+		MemoryMB:       -1, // set below
 		SourceFilePath: "", // set below
-		SourceFileLine: -1,
+		SourceFileLine: -1, // set below
 	}
 
+	// NOTE: the functions of firebase.json are codebases, the functions themselves are only known from the sources
+	codebaseRuntimes := make(map[string]string)
 	firebaseConfigFiles, err := FilterTextFiles(files, "**/firebase.json")
-	if err == nil && len(firebaseConfigFiles) > 0 {
-		data.UsedPlatforms[FaaSPlatformFirebase] = true
-		data.UsedFrameworks[FaaSFrameworkFirebase] = true
-
+	if err == nil {
 		for _, firebaseConfigFile := range firebaseConfigFiles {
 			firebaseConfigJson, err := LoadJsonFromBytes([]byte(firebaseConfigFile.Content))
 			if err != nil {
 				continue
 			}
-
-			functions, err := JsonResolveArray(firebaseConfigJson, []string{"functions"})
-			if err != nil {
+			if _, err := JsonResolve(firebaseConfigJson, []string{"functions"}); err != nil {
 				continue
 			}
+			maps.Copy(codebaseRuntimes, firebaseCodebaseRuntimes(firebaseConfigFile, firebaseConfigJson))
 
-			for _, _ = range functions {
-				function := defaultFunction
-				function.SourceFilePath = firebaseConfigFile.Path
-				function.InvocationType = FaaSInvocationTypeUnknown
-				data.Functions = append(data.Functions, function)
-			}
+			data.UsedPlatforms[FaaSPlatformFirebase] = true
+			data.UsedFrameworks[FaaSFrameworkFirebase] = true
 		}
 	}
 
@@ -3197,124 +3908,81 @@ func scanFirebase(data *RepositoryData, files []TextFile) {
 	allDependencies = append(allDependencies, data.DevDependencies...)
 
 	if !slices.Contains(allDependencies, "firebase-functions") {
-		return
+		return nil
 	}
 
 	data.UsedPlatforms[FaaSPlatformFirebase] = true
 	data.UsedFrameworks[FaaSFrameworkFirebase] = true
 
-	jsFiles, err := FilterTextFiles(files, "**/*.js", "**/*.jsx", "**/*.mjs")
+	jsFiles, err := FilterJsAndTsFiles(files)
 	if err != nil {
-		return
+		return err
 	}
 
+	modules := make([]*JsModule, 0, len(jsFiles))
+	globalOptions := firebaseFunctionOptions{MemoryMB: -1, TimeoutSeconds: -1}
 	for _, jsFile := range jsFiles {
 		if !strings.Contains(jsFile.Content, "firebase-functions") {
 			continue
 		}
+		module := data.JsModules.Module(jsFile)
+		if !module.ImportsModule("firebase-functions") {
+			continue
+		}
+		modules = append(modules, module)
+		globalOptions.applyGlobalOptions(module)
+	}
 
-		function := defaultFunction
-		function.SourceFilePath = jsFile.Path
+	for _, module := range modules {
+		for _, export := range module.Exports {
+			if export.Name == "default" {
+				continue
+			}
 
-		// 2nd gen
-		switch {
-		case strings.Contains(jsFile.Content, "https") && (strings.Contains(jsFile.Content, "onRequest(") ||
-			strings.Contains(jsFile.Content, "onCall(")):
-			function.InvocationType = FaaSInvocationTypeHTTP
-			data.Functions = append(data.Functions, function)
-		case strings.Contains(jsFile.Content, "pubsub") && strings.Contains(jsFile.Content, "onMessagePublished("):
-			function.InvocationType = FaaSInvocationTypeTopic
-			data.Functions = append(data.Functions, function)
-		case strings.Contains(jsFile.Content, "eventarc") && strings.Contains(jsFile.Content, "onCustomEventPublished("):
-			function.InvocationType = FaaSInvocationTypeTopic
-			data.Functions = append(data.Functions, function)
-		case strings.Contains(jsFile.Content, "scheduler") && strings.Contains(jsFile.Content, "onSchedule("):
-			function.InvocationType = FaaSInvocationTypeSchedule
-			data.Functions = append(data.Functions, function)
-		case strings.Contains(jsFile.Content, "database") && (strings.Contains(jsFile.Content, "onValueCreated(") ||
-			strings.Contains(jsFile.Content, "onValueDeleted(") ||
-			strings.Contains(jsFile.Content, "onValueUpdated(") ||
-			strings.Contains(jsFile.Content, "onValueWritten(")):
-			function.InvocationType = FaaSInvocationTypeOther
-			data.Functions = append(data.Functions, function)
-		case strings.Contains(jsFile.Content, "firestore") && (strings.Contains(jsFile.Content, "onDocumentCreated(") ||
-			strings.Contains(jsFile.Content, "onDocumentDeleted(") ||
-			strings.Contains(jsFile.Content, "onDocumentUpdated(") ||
-			strings.Contains(jsFile.Content, "onDocumentWritten(")):
-			function.InvocationType = FaaSInvocationTypeOther
-			data.Functions = append(data.Functions, function)
-		case strings.Contains(jsFile.Content, "storage") && (strings.Contains(jsFile.Content, "onObjectArchived(") ||
-			strings.Contains(jsFile.Content, "onObjectDeleted(") ||
-			strings.Contains(jsFile.Content, "onObjectFinalized(") ||
-			strings.Contains(jsFile.Content, "onObjectMetadataUpdated(")):
-			function.InvocationType = FaaSInvocationTypeOther
-			data.Functions = append(data.Functions, function)
-		case strings.Contains(jsFile.Content, "tasks") && strings.Contains(jsFile.Content, "onTaskDispatched("):
-			function.InvocationType = FaaSInvocationTypeOther
-			data.Functions = append(data.Functions, function)
-		case strings.Contains(jsFile.Content, "alerts") && strings.Contains(jsFile.Content, "onAlertPublished("):
-			function.InvocationType = FaaSInvocationTypeOther
-			data.Functions = append(data.Functions, function)
-		case strings.Contains(jsFile.Content, "remoteConfig") && strings.Contains(jsFile.Content, "onConfigUpdated("):
-			function.InvocationType = FaaSInvocationTypeOther
-			data.Functions = append(data.Functions, function)
-		case strings.Contains(jsFile.Content, "identity") && (strings.Contains(jsFile.Content, "beforeOperation(") ||
-			strings.Contains(jsFile.Content, "beforeUserCreated(") ||
-			strings.Contains(jsFile.Content, "beforeUserSignedIn(")):
-			function.InvocationType = FaaSInvocationTypeOther
-			data.Functions = append(data.Functions, function)
-		default:
-		}
+			// NOTE: functions can be grouped in objects, which are deployed as "group-function"
+			definitions := make(map[string]JsValue)
+			value := module.ResolveValue(export.Value)
+			switch value.Kind {
+			case JsValueCall:
+				definitions[export.Name] = value
+			case JsValueObject:
+				for _, property := range value.Properties {
+					definitions[export.Name+"-"+property.Key] = module.ResolveValue(property.Value)
+				}
+			}
 
-		// 1st gen
-		switch {
-		case strings.Contains(jsFile.Content, "https") && (strings.Contains(jsFile.Content, "onRequest(") ||
-			strings.Contains(jsFile.Content, "onCall(")):
-			function.InvocationType = FaaSInvocationTypeHTTP
-			data.Functions = append(data.Functions, function)
-		case strings.Contains(jsFile.Content, "pubsub") && strings.Contains(jsFile.Content, "schedule("):
-			function.InvocationType = FaaSInvocationTypeSchedule
-			data.Functions = append(data.Functions, function)
-		case strings.Contains(jsFile.Content, "pubsub") && strings.Contains(jsFile.Content, "topic("):
-			function.InvocationType = FaaSInvocationTypeTopic
-			data.Functions = append(data.Functions, function)
-		case strings.Contains(jsFile.Content, "database") && (strings.Contains(jsFile.Content, "onCreate(") ||
-			strings.Contains(jsFile.Content, "onDelete(") ||
-			strings.Contains(jsFile.Content, "onUpdate(") ||
-			strings.Contains(jsFile.Content, "onWrite(")):
-			function.InvocationType = FaaSInvocationTypeOther
-			data.Functions = append(data.Functions, function)
-		case strings.Contains(jsFile.Content, "firestore") && (strings.Contains(jsFile.Content, "onCreate(") ||
-			strings.Contains(jsFile.Content, "onDelete(") ||
-			strings.Contains(jsFile.Content, "onUpdate(") ||
-			strings.Contains(jsFile.Content, "onWrite(")):
-			function.InvocationType = FaaSInvocationTypeOther
-			data.Functions = append(data.Functions, function)
-		case strings.Contains(jsFile.Content, "storage") && (strings.Contains(jsFile.Content, "bucket(") ||
-			strings.Contains(jsFile.Content, "object(")):
-			function.InvocationType = FaaSInvocationTypeOther
-			data.Functions = append(data.Functions, function)
-		case strings.Contains(jsFile.Content, "tasks") && strings.Contains(jsFile.Content, "taskQueue("):
-			function.InvocationType = FaaSInvocationTypeOther
-			data.Functions = append(data.Functions, function)
-		case strings.Contains(jsFile.Content, "remoteConfig") && strings.Contains(jsFile.Content, "onUpdate("):
-			function.InvocationType = FaaSInvocationTypeOther
-			data.Functions = append(data.Functions, function)
-		case strings.Contains(jsFile.Content, "analytics") && strings.Contains(jsFile.Content, "onLog("):
-			function.InvocationType = FaaSInvocationTypeOther
-			data.Functions = append(data.Functions, function)
-		case strings.Contains(jsFile.Content, "auth") && (strings.Contains(jsFile.Content, "beforeCreate(") ||
-			strings.Contains(jsFile.Content, "beforeSignIn(") ||
-			strings.Contains(jsFile.Content, "onCreate(") ||
-			strings.Contains(jsFile.Content, "onDelete(")):
-			function.InvocationType = FaaSInvocationTypeOther
-			data.Functions = append(data.Functions, function)
-		default:
+			names := maps.Keys(definitions)
+			slices.Sort(names)
+			for _, name := range names {
+				definition := definitions[name]
+				if definition.Kind != JsValueCall || definition.Call == nil {
+					continue
+				}
+				invocationType, ok := firebaseFunctionTrigger(module, *definition.Call)
+				if !ok {
+					continue
+				}
+				options := firebaseFunctionCallOptions(module, *definition.Call, globalOptions)
+
+				function := defaultFunction
+				function.Name = name
+				function.InvocationType = invocationType
+				function.Regions = options.Regions
+				function.MemoryMB = options.MemoryMB
+				function.TimeoutSeconds = options.TimeoutSeconds
+				function.MinInstances = options.MinInstances
+				function.Runtime = firebaseFunctionRuntime(codebaseRuntimes, module.Path)
+				function.SourceFilePath = module.Path
+				function.SourceFileLine = definition.Call.Line
+				data.Functions = append(data.Functions, function)
+			}
 		}
 	}
+
+	return nil
 }
 
-func scanFastly(data *RepositoryData, files []TextFile) {
+func scanFastly(data *ScannerData, files []TextFile) error {
 	allDependencies := make([]string, len(data.Dependencies)+len(data.DevDependencies))
 	allDependencies = append(allDependencies, data.DevDependencies...)
 	allDependencies = append(allDependencies, data.Dependencies...)
@@ -3325,114 +3993,228 @@ func scanFastly(data *RepositoryData, files []TextFile) {
 
 	fastlyConfigFiles, err := FilterTextFiles(files, "**/fastly.toml")
 	if err != nil {
-		return
+		return err
 	}
 
 	if len(fastlyConfigFiles) == 0 {
-		return
+		return nil
 	}
 
 	data.UsedPlatforms[FaaSPlatformFastly] = true
 	data.UsedFrameworks[FaaSFrameworkFastly] = true
 
-	jsFiles, err := FilterTextFiles(files, "**/*.js", "**/*.jsx", "**/*.mjs")
+	// NOTE: services are named in their fastly.toml, which applies to the source files below it
+	serviceNames := make(map[string]string)
+	for _, fastlyConfigFile := range fastlyConfigFiles {
+		fastlyConfig, err := LoadJsonFromTomlBytes([]byte(fastlyConfigFile.Content))
+		if err != nil {
+			continue
+		}
+		if name, err := JsonResolveString(fastlyConfig, []string{"name"}); err == nil {
+			serviceNames[path.Dir(fastlyConfigFile.Path)] = name
+		}
+	}
+
+	jsFiles, err := FilterJsAndTsFiles(files)
 	if err != nil {
-		return
+		return err
 	}
 	for _, jsFile := range jsFiles {
-		if strings.Contains(jsFile.Content, "addEventListener('fetch'") ||
-			strings.Contains(jsFile.Content, "addEventListener(\"fetch\"") {
+		serviceName := ""
+		serviceDirectory := ""
+		for directory, name := range serviceNames {
+			if (directory == "." || strings.HasPrefix(jsFile.Path, directory+"/")) && len(directory) > len(serviceDirectory) {
+				serviceName = name
+				serviceDirectory = directory
+			}
+		}
+
+		for _, call := range eventListenerCalls(data.JsModules.Module(jsFile), "fetch") {
 			data.Functions = append(data.Functions, RepositoryFaaSFunctionData{
-				Name:           "",
+				Name:           serviceName,
 				Platform:       FaaSPlatformFastly,
 				Framework:      FaaSFrameworkFastly,
 				InvocationType: FaaSInvocationTypeHTTP,
 				Location:       FaaSLocationEdge,
 				TimeoutSeconds: -1,
+				MemoryMB:       -1,
 				SourceFilePath: jsFile.Path,
-				SourceFileLine: -1,
+				SourceFileLine: call.Line,
 			})
 		}
 	}
 
+	return nil
+}
+
+var cloudflareWorkerHandlers = []struct {
+	name           string
+	invocationType FaaSInvocationType
+}{
+	{"fetch", FaaSInvocationTypeHTTP},
+	{"queue", FaaSInvocationTypeQueue},
+	{"scheduled", FaaSInvocationTypeSchedule},
+}
+
+var cloudflarePagesHandlerNames = []string{
+	"onRequest",
+	"onRequestGet",
+	"onRequestPost",
+	"onRequestPatch",
+	"onRequestPut",
+	"onRequestDelete",
+	"onRequestHead",
+	"onRequestOptions",
+}
+
+// NOTE: service worker style handlers, e.g. `addEventListener("fetch", ...)` or `self.addEventListener("fetch", ...)`
+func eventListenerCalls(module *JsModule, eventType string) []JsCall {
+	result := make([]JsCall, 0)
+	for _, call := range module.CallsTo("addEventListener") {
+		if receiver := call.Receiver(); receiver != "" && receiver != "self" && receiver != "globalThis" {
+			continue
+		}
+		if argument, ok := call.StringArgument(0); ok && argument == eventType {
+			result = append(result, call)
+		}
+	}
+	return result
 }
 
-func scanCloudflare(data *RepositoryData, files []TextFile) {
-	wranglerConfigFiles, err := FilterTextFiles(files, "**/wrangler.toml")
+func scanCloudflare(data *ScannerData, files []TextFile) error {
+	wranglerConfigFiles, err := FilterTextFiles(files, "**/wrangler.toml", "**/wrangler.json", "**/wrangler.jsonc")
 	if err != nil || len(wranglerConfigFiles) == 0 {
-		return
+		return nil
 	}
 
 	data.UsedPlatforms[FaaSPlatformCloudflare] = true
 	data.UsedFrameworks[FaaSFrameworkWrangler] = true
 
 	defaultFunction := RepositoryFaaSFunctionData{
-		Name:           "",
+		Name:           "", // set below
 		Platform:       FaaSPlatformCloudflare,
 		Framework:      FaaSFrameworkWrangler,
 		InvocationType: FaaSInvocationTypeUnknown, // set below
 		Location:       FaaSLocationEdge,
 		TimeoutSeconds: -1,
+		MemoryMB:       -1,
 		SourceFilePath: "", // set below
 		SourceFileLine: -1,
 	}
 
-	jsFiles, err := FilterTextFiles(files, "**/*.js", "**/*.jsx", "**/*.mjs")
+	jsFiles, err := FilterJsAndTsFiles(files)
 	if err != nil {
-		return
+		return err
 	}
-
+	jsFilesByPath := make(map[string]TextFile)
 	for _, jsFile := range jsFiles {
-		if (strings.Contains(jsFile.Content, "export default") &&
-			strings.Contains(jsFile.Content, "fetch(")) ||
-			strings.Contains(jsFile.Content, "addEventListener('fetch'") ||
-			strings.Contains(jsFile.Content, "addEventListener(\"fetch\"") {
-			function := defaultFunction
-			function.InvocationType = FaaSInvocationTypeHTTP
-			function.SourceFilePath = jsFile.Path
-			data.Functions = append(data.Functions, function)
+		jsFilesByPath[jsFile.Path] = jsFile
+	}
+
+	for _, wranglerConfigFile := range wranglerConfigFiles {
+		workers, err := LoadWranglerWorkers(wranglerConfigFile)
+		if err != nil {
+			fmt.Printf("Failed to parse %s: %v\n", wranglerConfigFile.Path, err)
+			continue
 		}
 
-		if (strings.Contains(jsFile.Content, "export default") &&
-			strings.Contains(jsFile.Content, "queue(")) ||
-			strings.Contains(jsFile.Content, "addEventListener('queue'") ||
-			strings.Contains(jsFile.Content, "addEventListener(\"queue\"") {
+		for _, worker := range workers {
+			// NOTE: configurations without an entrypoint belong to Pages projects or only serve static assets
+			if worker.Main == "" {
+				continue
+			}
+
 			function := defaultFunction
-			function.InvocationType = FaaSInvocationTypeQueue
-			function.SourceFilePath = jsFile.Path
+			function.Name = worker.Name
+			function.InvocationType = worker.ConfiguredInvocationType()
+			function.Attributes = worker.Attributes()
+			function.Bindings = worker.Bindings
+			function.Resources = wranglerFunctionResources(worker)
+			// NOTE: Workers have no wall-clock timeout, the CPU time limit bounds an invocation instead
+			if worker.CPULimitMs > 0 {
+				function.TimeoutSeconds = (worker.CPULimitMs + 999) / 1000
+			}
+			function.Handler = worker.Main
+			function.SourceFilePath = wranglerConfigFile.Path
+
+			// NOTE: only the entrypoint of a Worker defines its handlers
+			if jsFile, ok := jsFilesByPath[worker.Main]; ok {
+				module := data.JsModules.Module(jsFile)
+				function.SourceFilePath = jsFile.Path
+
+				// Module workers
+				var moduleWorker JsValue
+				if export := module.Export("default"); export != nil {
+					moduleWorker = module.ResolveValue(export.Value)
+					function.SourceFileLine = export.Line
+				}
+
+				handlers := make([]string, 0)
+				for _, handler := range cloudflareWorkerHandlers {
+					line := -1
+					if property := moduleWorker.Property(handler.name); property != nil {
+						line = property.Line
+					}
+
+					// Service workers
+					if calls := eventListenerCalls(module, handler.name); len(calls) > 0 {
+						line = calls[0].Line
+					}
+
+					if line == -1 {
+						continue
+					}
+					if len(handlers) == 0 {
+						function.InvocationType = handler.invocationType
+						function.SourceFileLine = line
+					}
+					handlers = append(handlers, handler.name)
+				}
+
+				// NOTE: default exports without handler properties are apps of frameworks like Hono, which implement fetch
+				if len(handlers) == 0 && module.Export("default") != nil {
+					function.InvocationType = FaaSInvocationTypeHTTP
+					handlers = append(handlers, "fetch")
+				}
+				if len(handlers) > 0 {
+					function.Attributes["handlers"] = strings.Join(handlers, ",")
+				}
+			}
+
 			data.Functions = append(data.Functions, function)
 		}
 
-		if (strings.Contains(jsFile.Content, "export default") &&
-			strings.Contains(jsFile.Content, "scheduled(")) ||
-			strings.Contains(jsFile.Content, "addEventListener('scheduled'") ||
-			strings.Contains(jsFile.Content, "addEventListener(\"scheduled\"") {
-			function := defaultFunction
-			function.InvocationType = FaaSInvocationTypeSchedule
-			function.SourceFilePath = jsFile.Path
-			data.Functions = append(data.Functions, function)
+		// Pages functions
+		functionsDirectory := path.Join(path.Dir(wranglerConfigFile.Path), "functions")
+		pagesFiles, err := FilterJsAndTsFiles(files, functionsDirectory)
+		if err != nil {
+			return err
 		}
+		for _, pagesFile := range pagesFiles {
+			module := data.JsModules.Module(pagesFile)
+
+			for _, export := range module.Exports {
+				if !slices.Contains(cloudflarePagesHandlerNames, export.Name) {
+					continue
+				}
 
-		if strings.Contains(jsFile.Content, "export") && (strings.Contains(jsFile.Content, "onRequest") ||
-			strings.Contains(jsFile.Content, "onRequestGet") ||
-			strings.Contains(jsFile.Content, "onRequestPost") ||
-			strings.Contains(jsFile.Content, "onRequestPatch") ||
-			strings.Contains(jsFile.Content, "onRequestPut") ||
-			strings.Contains(jsFile.Content, "onRequestDelete") ||
-			strings.Contains(jsFile.Content, "onRequestHead") ||
-			strings.Contains(jsFile.Content, "onRequestOptions")) {
-			function := defaultFunction
-			function.InvocationType = FaaSInvocationTypeHTTP
-			function.SourceFilePath = jsFile.Path
-			data.Functions = append(data.Functions, function)
+				function := defaultFunction
+				function.Name = cloudflarePagesRoute(functionsDirectory, pagesFile.Path)
+				function.InvocationType = FaaSInvocationTypeHTTP
+				function.SourceFilePath = pagesFile.Path
+				function.SourceFileLine = export.Line
+				data.Functions = append(data.Functions, function)
+			}
 		}
 	}
+
+	return nil
 }
 
-func scanTencent(data *RepositoryData, files []TextFile) {
+func scanTencent(data *ScannerData, files []TextFile) error {
 	scfConfigFiles, err := FilterTextFiles(files, "**/serverless.yml", "**/serverless.yaml")
 	if err != nil {
-		return
+		return err
 	}
 
 	for _, scfConfigFile := range scfConfigFiles {
@@ -3447,11 +4229,13 @@ func scanTencent(data *RepositoryData, files []TextFile) {
 			continue
 		}
 
-		if _, err := JsonResolveString(scfConfigJson, []string{"inputs", "src"}); err != nil {
+		src, err := JsonResolveString(scfConfigJson, []string{"inputs", "src"})
+		if err != nil {
 			continue
 		}
 
-		if _, err := JsonResolveString(scfConfigJson, []string{"inputs", "handler"}); err != nil {
+		handler, err := JsonResolveString(scfConfigJson, []string{"inputs", "handler"})
+		if err != nil {
 			continue
 		}
 
@@ -3465,69 +4249,111 @@ func scanTencent(data *RepositoryData, files []TextFile) {
 
 		data.UsedPlatforms[FaaSPlatformTencent] = true
 		data.UsedFrameworks[FaaSFrameworkServerlessCloudFramework] = true
+		// NOTE: functions are named by the component instance unless named explicitly, the handler is relative to the
+		// source directory
+		name, err := JsonResolveString(scfConfigJson, []string{"inputs", "name"})
+		if err != nil {
+			name, _ = JsonResolveString(scfConfigJson, []string{"name"})
+		}
+
 		data.Functions = append(data.Functions, RepositoryFaaSFunctionData{
-			Name:           "",
+			Name:           name,
 			Platform:       FaaSPlatformTencent,
 			Framework:      FaaSFrameworkServerlessCloudFramework,
 			InvocationType: FaaSInvocationTypeUnknown,
 			Location:       FaaSLocationRegion,
 			TimeoutSeconds: -1,
+			MemoryMB:       -1,
+			Handler:        path.Join(src, handler),
 			SourceFilePath: scfConfigFile.Path,
-			SourceFileLine: -1,
+			SourceFileLine: YamlKeyLine([]byte(scfConfigFile.Content), []string{"inputs"}),
 		})
 	}
+
+	return nil
 }
 
-func scanOpenFaaS(data *RepositoryData, files []TextFile) {
-	openFaaSConfigFiles, err := FilterTextFiles(
-		files,
-		"**/*.yaml", "**/*.yml",
-	)
+func scanOpenFaaS(data *ScannerData, files []TextFile) error {
+	defaultFunction := RepositoryFaaSFunctionData{
+		Name:           "", // set below
+		Platform:       FaaSPlatformOpenFaaS,
+		Framework:      FaaSFrameworkOpenFaaS,
+		InvocationType: FaaSInvocationTypeUnknown, // set below
+		Location:       FaaSLocationRegion,
+		TimeoutSeconds: -1, // set below
+		MemoryMB:       -1, // set below
+		SourceFilePath: "", // set below
+		SourceFileLine: -1, // set below
+	}
+
+	openFaaSFunctionNames := make([]string, 0)
+
+	openFaaSStackFiles, err := FilterTextFiles(files, "**/*.yaml", "**/*.yml")
 	if err != nil {
-		return
+		return err
 	}
+	for _, openFaaSStackFile := range openFaaSStackFiles {
+		stackFunctions, ok := LoadOpenFaaSStack(openFaaSStackFile, data.KubernetesManifests.Documents(openFaaSStackFile))
+		if !ok {
+			continue
+		}
 
-	for _, openFaaSConfigFile := range openFaaSConfigFiles {
-		openFaaSConfigJsons := LoadJsonsFromYamlBytes([]byte(openFaaSConfigFile.Content))
-		for _, openFaaSConfigJson := range openFaaSConfigJsons {
-			apiVersion, err := JsonResolveString(openFaaSConfigJson, []string{"apiVersion"})
-			if err != nil {
-				continue
-			}
+		data.UsedPlatforms[FaaSPlatformOpenFaaS] = true
+		data.UsedFrameworks[FaaSFrameworkOpenFaaS] = true
 
-			if !strings.HasPrefix(apiVersion, "openfaas.com/") {
-				continue
+		for _, stackFunction := range stackFunctions {
+			function := defaultFunction
+			function.Name = stackFunction.Name
+			function.Runtime = stackFunction.Language
+			function.Handler = stackFunction.Handler
+			function.MemoryMB = stackFunction.MemoryMB
+			function.TimeoutSeconds = stackFunction.TimeoutSeconds
+			function.SourceFilePath = openFaaSStackFile.Path
+			function.SourceFileLine = stackFunction.Line
+
+			invocationType, attributes := openFaaSAnnotationsTrigger(stackFunction.Annotations)
+			function.InvocationType = invocationType
+			if len(attributes) > 0 {
+				function.Attributes = attributes
 			}
 
-			kind, err := JsonResolveString(openFaaSConfigJson, []string{"kind"})
-			if err != nil {
-				continue
-			}
+			data.Functions = append(data.Functions, function)
+			openFaaSFunctionNames = append(openFaaSFunctionNames, stackFunction.Name)
+		}
+	}
 
-			if kind != "Function" {
-				continue
-			}
+	manifests := KubernetesManifests(data.KubernetesManifests.Manifests(files))
 
-			data.UsedPlatforms[FaaSPlatformOpenFaaS] = true
-			data.UsedFrameworks[FaaSFrameworkOpenFaaS] = true
-			data.Functions = append(data.Functions, RepositoryFaaSFunctionData{
-				Name:           "",
-				Platform:       FaaSPlatformOpenFaaS,
-				Framework:      FaaSFrameworkOpenFaaS,
-				InvocationType: FaaSInvocationTypeUnknown,
-				Location:       FaaSLocationRegion,
-				TimeoutSeconds: -1,
-				SourceFilePath: openFaaSConfigFile.Path,
-				SourceFileLine: -1,
-			})
+	for _, openFaaSFunction := range manifests.OpenFaaSFunctions() {
+		data.UsedPlatforms[FaaSPlatformOpenFaaS] = true
+		data.UsedFrameworks[FaaSFrameworkOpenFaaS] = true
+
+		// NOTE: `faas-cli generate` creates the Function resources of a stack file, which are committed alongside
+		if slices.Contains(openFaaSFunctionNames, openFaaSFunction.FunctionName) {
+			continue
+		}
+
+		function := defaultFunction
+		function.Name = openFaaSFunction.FunctionName
+		function.SourceFilePath = openFaaSFunction.Path
+		function.SourceFileLine = openFaaSFunction.Line
+
+		invocationType, attributes := openFaaSAnnotationsTrigger(openFaaSFunction.FunctionAnnotations)
+		function.InvocationType = invocationType
+		if len(attributes) > 0 {
+			function.Attributes = attributes
 		}
+
+		data.Functions = append(data.Functions, function)
 	}
+
+	return nil
 }
 
-func scanDigitalOcean(data *RepositoryData, files []TextFile) {
+func scanDigitalOcean(data *ScannerData, files []TextFile) error {
 	digitalOceanConfigFiles, err := FilterTextFiles(files, "**/project.yml", "**/project.yaml")
 	if err != nil {
-		return
+		return err
 	}
 
 	for _, digitalOceanConfigFile := range digitalOceanConfigFiles {
@@ -3550,31 +4376,50 @@ func scanDigitalOcean(data *RepositoryData, files []TextFile) {
 		data.UsedFrameworks[FaaSFrameworkDigitalOcean] = true
 
 		defaultFunction := RepositoryFaaSFunctionData{
-			Name:           "",
+			Name:           "", // set below
 			Platform:       FaaSPlatformDigitalOcean,
 			Framework:      FaaSFrameworkDigitalOcean,
 			InvocationType: FaaSInvocationTypeUnknown, // set below
 			Location:       FaaSLocationRegion,
 			TimeoutSeconds: -1,
+			MemoryMB:       -1,
 			SourceFilePath: digitalOceanConfigFile.Path,
-			SourceFileLine: -1,
+			SourceFileLine: -1, // set below
 		}
 
-		for _, digitalOceanPackage := range digitalOceanPackages {
+		for packageIndex, digitalOceanPackage := range digitalOceanPackages {
+			packageName, _ := JsonResolveString(digitalOceanPackage, []string{"name"})
 			allDigitalOceanFunctions := make([]interface{}, 0)
+			allDigitalOceanFunctionLines := make([]int, 0)
 
-			digitalOceanFunctions, err := JsonResolveArray(digitalOceanPackage, []string{"functions"})
-			if err == nil {
+			for _, key := range []string{"functions", "actions"} {
+				digitalOceanFunctions, err := JsonResolveArray(digitalOceanPackage, []string{key})
+				if err != nil {
+					continue
+				}
+				itemLines := YamlSequenceItemLines([]byte(digitalOceanConfigFile.Content), []string{"packages", fmt.Sprint(packageIndex), key})
+				for index := range digitalOceanFunctions {
+					if index < len(itemLines) {
+						allDigitalOceanFunctionLines = append(allDigitalOceanFunctionLines, itemLines[index])
+					} else {
+						allDigitalOceanFunctionLines = append(allDigitalOceanFunctionLines, -1)
+					}
+				}
 				allDigitalOceanFunctions = append(allDigitalOceanFunctions, digitalOceanFunctions...)
 			}
 
-			digitalOceanActions, err := JsonResolveArray(digitalOceanPackage, []string{"actions"})
-			if err == nil {
-				allDigitalOceanFunctions = append(allDigitalOceanFunctions, digitalOceanActions...)
-			}
-
-			for _, digitalOceanFunction := range allDigitalOceanFunctions {
+			for functionIndex, digitalOceanFunction := range allDigitalOceanFunctions {
 				function := defaultFunction
+				function.Name, _ = JsonResolveString(digitalOceanFunction, []string{"name"})
+				function.SourceFileLine = allDigitalOceanFunctionLines[functionIndex]
+				// NOTE: the code of a function lives in packages/<package>/<function>, exporting `main` by default
+				if function.Name != "" {
+					main, err := JsonResolveString(digitalOceanFunction, []string{"main"})
+					if err != nil {
+						main = "main"
+					}
+					function.Handler = fmt.Sprintf("%s:%s", path.Join("packages", packageName, function.Name), main)
+				}
 
 				triggers, err := JsonResolveArray(digitalOceanFunction, []string{"triggers"})
 				if err == nil {
@@ -3595,9 +4440,35 @@ func scanDigitalOcean(data *RepositoryData, files []TextFile) {
 			}
 		}
 	}
+
+	return nil
+}
+
+// NOTE: trigger registrations of the Version 4 programming model, e.g. `app.http("name", { ... })`, the trigger of
+// `app.generic` is classified by its type option
+var azureFunctionsTriggers = map[string]FaaSInvocationType{
+	"http":            FaaSInvocationTypeHTTP,
+	"get":             FaaSInvocationTypeHTTP,
+	"put":             FaaSInvocationTypeHTTP,
+	"post":            FaaSInvocationTypeHTTP,
+	"patch":           FaaSInvocationTypeHTTP,
+	"deleteRequest":   FaaSInvocationTypeHTTP,
+	"timer":           FaaSInvocationTypeSchedule,
+	"storageQueue":    FaaSInvocationTypeQueue,
+	"serviceBusQueue": FaaSInvocationTypeQueue,
+	"serviceBusTopic": FaaSInvocationTypeTopic,
+	"eventHub":        FaaSInvocationTypeTopic,
+	"eventGrid":       FaaSInvocationTypeTopic,
+	"storageBlob":     FaaSInvocationTypeOther,
+	"cosmosDB":        FaaSInvocationTypeOther,
+	"sql":             FaaSInvocationTypeOther,
+	"mySql":           FaaSInvocationTypeOther,
+	"webPubSub":       FaaSInvocationTypeWebsocket,
+	"warmup":          FaaSInvocationTypeOther,
+	"generic":         FaaSInvocationTypeUnknown,
 }
 
-func scanAzureFunctionsFramework(data *RepositoryData, files []TextFile) {
+func scanAzureFunctionsFramework(data *ScannerData, files []TextFile) error {
 	allDependencies := make([]string, len(data.Dependencies)+len(data.DevDependencies))
 	allDependencies = append(allDependencies, data.DevDependencies...)
 	allDependencies = append(allDependencies, data.Dependencies...)
@@ -3615,10 +4486,13 @@ func scanAzureFunctionsFramework(data *RepositoryData, files []TextFile) {
 		InvocationType: FaaSInvocationTypeUnknown, // set below
 		Location:       FaaSLocationRegion,
 		TimeoutSeconds: -1,
+		MemoryMB:       -1,
 		SourceFilePath: "", // set below
 		SourceFileLine: -1,
 	}
 
+	functions := make([]RepositoryFaaSFunctionData, 0)
+
 	// Check for Version 3
 
 	affConfigFiles, err := FilterTextFiles(files, "**/function.json")
@@ -3641,8 +4515,17 @@ func scanAzureFunctionsFramework(data *RepositoryData, files []TextFile) {
 			data.UsedPlatforms[FaaSPlatformAzure] = true
 			data.UsedFrameworks[FaaSFrameworkAzureFunctions] = true
 
+			// NOTE: the function is named after its directory
 			function := defaultFunction
+			function.Name = path.Base(path.Dir(affConfigFile.Path))
 			function.SourceFilePath = affConfigFile.Path
+			function.Attributes = make(map[string]string)
+			if affScriptFile, err := JsonResolveString(affConfig, []string{"scriptFile"}); err == nil {
+				function.Handler = affScriptFile
+			}
+			if affEntryPoint, err := JsonResolveString(affConfig, []string{"entryPoint"}); err == nil {
+				function.Handler = strings.TrimPrefix(fmt.Sprintf("%s:%s", function.Handler, affEntryPoint), ":")
+			}
 
 			for _, affBinding := range affBindings {
 				affBindingDirection, err := JsonResolveString(affBinding, []string{"direction"})
@@ -3659,75 +4542,142 @@ func scanAzureFunctionsFramework(data *RepositoryData, files []TextFile) {
 					continue
 				}
 
-				switch affBindingType {
-				case "httpTrigger":
-					function.InvocationType = FaaSInvocationTypeHTTP
-				case "timerTrigger":
-					function.InvocationType = FaaSInvocationTypeSchedule
-				case "queueTrigger":
-					function.InvocationType = FaaSInvocationTypeQueue
-				case "serviceBusTrigger":
+				switch {
+				case affBindingType == "serviceBusTrigger":
 					if _, err := JsonResolveString(affBinding, []string{"queueName"}); err == nil {
 						function.InvocationType = FaaSInvocationTypeQueue
 					}
 					if _, err := JsonResolveString(affBinding, []string{"topicName"}); err == nil {
 						function.InvocationType = FaaSInvocationTypeTopic
 					}
+				case strings.HasSuffix(affBindingType, "Trigger"):
+					function.InvocationType = azureFunctionsBindingTriggerInvocationType(affBindingType)
+				default:
+					continue
+				}
+
+				for _, attribute := range azureFunctionsTriggerAttributes {
+					affBindingAttribute, err := JsonResolve(affBinding, []string{attribute})
+					if err != nil {
+						continue
+					}
+					switch affBindingAttribute := affBindingAttribute.(type) {
+					case string:
+						function.Attributes[attribute] = affBindingAttribute
+					case []interface{}:
+						values := make([]string, 0, len(affBindingAttribute))
+						for _, value := range affBindingAttribute {
+							if value, ok := value.(string); ok {
+								values = append(values, value)
+							}
+						}
+						function.Attributes[attribute] = strings.Join(values, ",")
+					}
 				}
 			}
+			function.Resources = azureFunctionsJsonBindingResources(affBindings)
 
-			data.Functions = append(data.Functions, function)
+			functions = append(functions, function)
 		}
 	}
 
 	// Check for Version 4
 
-	if !slices.Contains(allDependencies, "@azure/functions") {
-		return
-	}
+	if slices.Contains(allDependencies, "@azure/functions") {
+		jsFiles, err := FilterJsAndTsFiles(files)
+		if err != nil {
+			return err
+		}
 
-	jsFiles, err := FilterTextFiles(files, "**/*.js", "**/*.jsx", "**/*.mjs")
-	if err != nil {
-		return
-	}
+		for _, jsFile := range jsFiles {
+			module := data.JsModules.Module(jsFile)
 
-	for _, jsFile := range jsFiles {
-		if !strings.Contains(jsFile.Content, "@azure/functions") {
-			continue
+			for _, call := range module.Calls {
+				invocationType, ok := azureFunctionsTriggers[call.CalleeName()]
+				if !ok || !module.CallsImport(call, "@azure/functions", "app", call.CalleeName()) {
+					continue
+				}
+
+				// NOTE: functions are registered by name with an options object, e.g.
+				// app.http("name", { methods: ["GET"], route: "a", handler: fn })
+				function := defaultFunction
+				function.SourceFilePath = jsFile.Path
+				function.SourceFileLine = call.Line
+				function.InvocationType = invocationType
+				function.Attributes = make(map[string]string)
+				if name := call.Argument(0); name != nil {
+					if name := module.ResolveValue(*name); name.Kind == JsValueString {
+						function.Name = name.Text
+					}
+				}
+				if options := call.Argument(1); options != nil {
+					options := module.ResolveValue(*options)
+					function.Attributes = jsAttributes(options, azureFunctionsTriggerAttributes)
+					function.Resources = azureFunctionsJsBindingResources(module, call.CalleeName(), options)
+					if handler := options.Property("handler"); handler != nil && handler.Kind == JsValueIdentifier {
+						function.Handler = handler.Text
+					}
+					if trigger := options.Property("trigger"); trigger != nil && call.CalleeName() == "generic" {
+						if triggerType, ok := module.ResolveValue(*trigger).StringProperty("type"); ok {
+							function.InvocationType = azureFunctionsBindingTriggerInvocationType(triggerType)
+						}
+					}
+				}
+				functions = append(functions, function)
+			}
 		}
+	}
 
-		function := defaultFunction
-		function.SourceFilePath = jsFile.Path
+	// Check for the Python v2 programming model
 
-		switch {
-		case strings.Contains(jsFile.Content, ".http("):
-			function.InvocationType = FaaSInvocationTypeHTTP
-			data.Functions = append(data.Functions, function)
-		case strings.Contains(jsFile.Content, ".timer("):
-			function.InvocationType = FaaSInvocationTypeSchedule
-			data.Functions = append(data.Functions, function)
-		case strings.Contains(jsFile.Content, ".storageQueue("),
-			strings.Contains(jsFile.Content, ".serviceBusQueue(") &&
-				strings.Contains(jsFile.Content, "queueName"):
-			function.InvocationType = FaaSInvocationTypeQueue
-			data.Functions = append(data.Functions, function)
-		case strings.Contains(jsFile.Content, ".serviceBusQueue(") &&
-			strings.Contains(jsFile.Content, "topicName"):
-			function.InvocationType = FaaSInvocationTypeTopic
-			data.Functions = append(data.Functions, function)
-		default:
+	pythonFiles, err := FilterTextFiles(files, "**/*.py")
+	if err == nil {
+		for _, pythonFile := range pythonFiles {
+			for _, pythonFunction := range ParseAzureFunctionsPython(pythonFile.Content) {
+				data.UsedPlatforms[FaaSPlatformAzure] = true
+				data.UsedFrameworks[FaaSFrameworkAzureFunctions] = true
+
+				function := defaultFunction
+				function.Name = pythonFunction.Name
+				function.SourceFilePath = pythonFile.Path
+				function.SourceFileLine = pythonFunction.Line
+				function.InvocationType = pythonFunction.InvocationType
+				function.Attributes = pythonFunction.Attributes
+				function.Handler = pythonFunction.Handler
+				functions = append(functions, function)
+			}
 		}
+	}
 
+	// NOTE: host.json applies the timeout, extension bundle and runtime to all functions of a function app
+	hostConfigs := LoadAzureFunctionsHostConfigs(files)
+	if len(hostConfigs) > 0 {
+		data.UsedPlatforms[FaaSPlatformAzure] = true
+		data.UsedFrameworks[FaaSFrameworkAzureFunctions] = true
+	}
+	for _, function := range functions {
+		if hostConfig, ok := azureFunctionsHostConfig(hostConfigs, function.SourceFilePath); ok {
+			function.TimeoutSeconds = hostConfig.FunctionTimeoutSeconds
+			if hostConfig.Runtime != "" && function.Runtime == "" {
+				function.Runtime = hostConfig.Runtime
+			}
+			if hostConfig.ExtensionBundle != "" {
+				function.Attributes["extensionBundle"] = hostConfig.ExtensionBundle
+			}
+		}
+		data.Functions = append(data.Functions, function)
 	}
+
+	return nil
 }
 
-func scanGCPFunctionsFramework(data *RepositoryData, files []TextFile) {
+func scanGCPFunctionsFramework(data *ScannerData, files []TextFile) error {
 	allDependencies := make([]string, len(data.Dependencies)+len(data.DevDependencies))
 	allDependencies = append(allDependencies, data.DevDependencies...)
 	allDependencies = append(allDependencies, data.Dependencies...)
 
 	if !slices.Contains(allDependencies, "@google-cloud/functions-framework") {
-		return
+		return nil
 	}
 
 	data.UsedPlatforms[FaaSPlatformGCP] = true
@@ -3740,42 +4690,300 @@ func scanGCPFunctionsFramework(data *RepositoryData, files []TextFile) {
 		InvocationType: FaaSInvocationTypeUnknown, // set below
 		Location:       FaaSLocationRegion,
 		TimeoutSeconds: -1,
+		MemoryMB:       -1,
 		SourceFilePath: "", // set below
 		SourceFileLine: -1,
 	}
 
-	jsFiles, err := FilterTextFiles(files, "**/*.js", "**/*.jsx", "**/*.mjs")
-	if err != nil {
-		return
+	jsFiles, err := FilterJsAndTsFiles(files)
+	if err != nil {
+		return err
+	}
+
+	for _, jsFile := range jsFiles {
+		module := data.JsModules.Module(jsFile)
+
+		for _, call := range module.Calls {
+			function := defaultFunction
+			function.SourceFilePath = jsFile.Path
+			function.SourceFileLine = call.Line
+
+			switch {
+			case module.CallsImport(call, "@google-cloud/functions-framework", "http"):
+				function.Name, _ = call.StringArgument(0)
+				function.InvocationType = FaaSInvocationTypeHTTP
+				data.Functions = append(data.Functions, function)
+			case module.CallsImport(call, "@google-cloud/functions-framework", "cloudEvent"):
+				function.Name, _ = call.StringArgument(0)
+				function.InvocationType = gcpCloudEventInvocationType(jsFile.Content)
+				data.Functions = append(data.Functions, function)
+			default:
+			}
+		}
+	}
+
+	return nil
+}
+
+func scanGCPCloudRun(data *ScannerData, files []TextFile) error {
+	manifestFiles, err := FilterTextFiles(
+		files,
+		"**/*.yaml", "**/*.yml",
+	)
+	if err != nil {
+		return err
+	}
+
+	defaultFunction := RepositoryFaaSFunctionData{
+		Name:           "", // set below
+		Platform:       FaaSPlatformGCP,
+		Framework:      FaaSFrameworkGCPCloudRun,
+		InvocationType: FaaSInvocationTypeUnknown, // set below
+		Location:       FaaSLocationRegion,
+		TimeoutSeconds: -1, // set below
+		MemoryMB:       -1, // set below
+		SourceFilePath: "", // set below
+		SourceFileLine: -1, // set below
+	}
+
+	for _, manifestFile := range manifestFiles {
+		manifestJsons := LoadJsonsFromYamlBytes([]byte(manifestFile.Content))
+		for _, manifestJson := range manifestJsons {
+			apiVersion, err := JsonResolveString(manifestJson, []string{"apiVersion"})
+			if err != nil {
+				continue
+			}
+			kind, err := JsonResolveString(manifestJson, []string{"kind"})
+			if err != nil {
+				continue
+			}
+
+			function := defaultFunction
+			function.SourceFilePath = manifestFile.Path
+
+			// NOTE: jobs wrap the task template in an execution template
+			templatePath := []string{"spec", "template", "spec"}
+			switch {
+			case kind == "Service" && (strings.HasPrefix(apiVersion, "serving.knative.dev/") || strings.HasPrefix(apiVersion, "run.googleapis.com/")):
+				function.InvocationType = FaaSInvocationTypeHTTP
+			case kind == "Job" && strings.HasPrefix(apiVersion, "run.googleapis.com/"):
+				function.InvocationType = FaaSInvocationTypeOther
+				templatePath = []string{"spec", "template", "spec", "template", "spec"}
+			default:
+				continue
+			}
+
+			if !isCloudRunManifest(manifestJson) {
+				continue
+			}
+
+			data.UsedPlatforms[FaaSPlatformGCP] = true
+			data.UsedFrameworks[FaaSFrameworkGCPCloudRun] = true
+
+			function.Name, _ = JsonResolveString(manifestJson, []string{"metadata", "name"})
+			if memorySize, err := JsonResolveString(manifestJson, slices.Concat(templatePath, []string{"containers", "0", "resources", "limits", "memory"})); err == nil {
+				if memorySizeMB, ok := ParseMemorySizeMB(memorySize); ok {
+					function.MemoryMB = memorySizeMB
+				}
+			}
+			if timeoutSeconds, err := JsonResolveInt(manifestJson, slices.Concat(templatePath, []string{"timeoutSeconds"})); err == nil {
+				function.TimeoutSeconds = timeoutSeconds
+			}
+			// NOTE: services are scaled by Knative, which reads the minimum from an annotation of the revision template
+			if minScale, err := JsonResolveString(manifestJson, []string{"spec", "template", "metadata", "annotations", "autoscaling.knative.dev/minScale"}); err == nil {
+				if minInstances, err := strconv.Atoi(minScale); err == nil {
+					function.MinInstances = minInstances
+				}
+			}
+			if len(manifestJsons) == 1 {
+				function.SourceFileLine = YamlKeyLine([]byte(manifestFile.Content), []string{"kind"})
+			}
+
+			data.Functions = append(data.Functions, function)
+		}
+	}
+
+	return nil
+}
+
+// NOTE: workflow definitions deployed with `gcloud workflows deploy --source` or Terraform, the functions they call
+// are found by the other scanners
+func scanGCPWorkflows(data *ScannerData, files []TextFile) error {
+	definitionFiles, err := FilterTextFiles(
+		files,
+		"**/*.yaml", "**/*.yml",
+		"**/*.json",
+	)
+	if err != nil {
+		return err
+	}
+
+	for _, definitionFile := range definitionFiles {
+		documents := LoadYamlDocuments([]byte(definitionFile.Content))
+		if len(documents) != 1 || !isGCPWorkflowDefinition(documents[0].Json) {
+			continue
+		}
+
+		data.UsedPlatforms[FaaSPlatformGCP] = true
+		data.UsedFrameworks[FaaSFrameworkGCPWorkflows] = true
+
+		workflow := RepositoryWorkflowData{
+			Name:           gcpWorkflowName(definitionFile),
+			Platform:       FaaSPlatformGCP,
+			Framework:      FaaSFrameworkGCPWorkflows,
+			Steps:          ParseGCPWorkflowSteps(documents[0].Json),
+			SourceFilePath: definitionFile.Path,
+			SourceFileLine: documents[0].Line,
+		}
+		workflow.Functions = workflowStepFunctions(workflow.Steps)
+		data.Workflows = append(data.Workflows, workflow)
+	}
+
+	return nil
+}
+
+func scanGCloudCLI(data *ScannerData, files []TextFile) error {
+	scriptFiles, err := FilterTextFiles(
+		files,
+		"**/*.sh", "**/*.bash", "**/Makefile", "**/*.mk",
+		"**/*.yaml", "**/*.yml",
+		"**/package.json",
+	)
+	if err != nil {
+		return err
+	}
+
+	defaultFunction := RepositoryFaaSFunctionData{
+		Name:           "", // set below
+		Platform:       FaaSPlatformGCP,
+		Framework:      FaaSFrameworkGCloudCLI,
+		InvocationType: FaaSInvocationTypeUnknown, // set below
+		Location:       FaaSLocationRegion,
+		TimeoutSeconds: -1, // set below
+		MemoryMB:       -1, // set below
+		SourceFilePath: "", // set below
+		SourceFileLine: -1, // set below
+	}
+
+	type locatedCommand struct {
+		GCloudCommand
+		path string
+	}
+	commands := make([]locatedCommand, 0)
+
+	for _, scriptFile := range scriptFiles {
+		if !strings.Contains(scriptFile.Content, "gcloud") {
+			continue
+		}
+
+		switch {
+		case path.Base(scriptFile.Path) == "package.json":
+			packageJson, err := LoadJsonFromBytes([]byte(scriptFile.Content))
+			if err != nil {
+				continue
+			}
+			scripts, err := JsonResolveMap(packageJson, []string{"scripts"})
+			if err != nil {
+				continue
+			}
+			scriptNames := maps.Keys(scripts)
+			slices.Sort(scriptNames)
+			for _, scriptName := range scriptNames {
+				script, ok := scripts[scriptName].(string)
+				if !ok {
+					continue
+				}
+				for _, command := range ParseGCloudCommands(script) {
+					command.Line = YamlKeyLine([]byte(scriptFile.Content), []string{"scripts", scriptName})
+					commands = append(commands, locatedCommand{command, scriptFile.Path})
+				}
+			}
+			continue
+		case scriptFile.Extension == ".yaml" || scriptFile.Extension == ".yml":
+			for _, cloudBuildJson := range LoadJsonsFromYamlBytes([]byte(scriptFile.Content)) {
+				for _, commandLine := range cloudBuildGCloudCommandLines(cloudBuildJson) {
+					for _, command := range ParseGCloudCommands(commandLine) {
+						command.Line = -1
+						commands = append(commands, locatedCommand{command, scriptFile.Path})
+					}
+				}
+			}
+		}
+
+		for _, command := range ParseGCloudCommands(scriptFile.Content) {
+			commands = append(commands, locatedCommand{command, scriptFile.Path})
+		}
 	}
 
-	for _, jsFile := range jsFiles {
-		if !strings.Contains(jsFile.Content, "@google-cloud/functions-framework") {
+	// NOTE: the same function is often deployed by several scripts, e.g. per environment, and is counted once
+	functionIndices := make(map[string]int)
+	for _, command := range commands {
+		if command.Group == "eventarc triggers" || command.Name == "" {
 			continue
 		}
+		key := command.Group + "/" + command.Name
+		if _, ok := functionIndices[key]; ok {
+			continue
+		}
+
+		data.UsedPlatforms[FaaSPlatformGCP] = true
+		data.UsedFrameworks[FaaSFrameworkGCloudCLI] = true
 
 		function := defaultFunction
-		function.SourceFilePath = jsFile.Path
+		function.Name = command.Name
+		function.InvocationType = command.InvocationType()
+		function.Runtime = command.Flags["--runtime"]
+		function.Handler = command.Flags["--entry-point"]
+		if memorySizeMB, ok := ParseMemorySizeMB(command.Flags["--memory"]); ok {
+			function.MemoryMB = memorySizeMB
+		}
+		for _, flag := range []string{"--timeout", "--task-timeout"} {
+			if timeoutSeconds, ok := ParseDurationSeconds(command.Flags[flag]); ok {
+				function.TimeoutSeconds = timeoutSeconds
+			}
+		}
+		if minInstances, err := strconv.Atoi(command.Flags["--min-instances"]); err == nil {
+			function.MinInstances = minInstances
+		}
+		function.SourceFilePath = command.path
+		function.SourceFileLine = command.Line
 
-		switch {
-		case strings.Contains(jsFile.Content, ".http("):
-			function.InvocationType = FaaSInvocationTypeHTTP
-			data.Functions = append(data.Functions, function)
-		case strings.Contains(jsFile.Content, ".cloudEvent("):
-			function.InvocationType = FaaSInvocationTypeUnknown
-			data.Functions = append(data.Functions, function)
-		default:
+		functionIndices[key] = len(data.Functions)
+		data.Functions = append(data.Functions, function)
+	}
+
+	// NOTE: Eventarc triggers route events to Cloud Run services and 2nd gen functions deployed elsewhere
+	for _, command := range commands {
+		if command.Group != "eventarc triggers" {
+			continue
+		}
+		eventType, ok := command.EventType()
+		if !ok {
+			continue
+		}
+		for _, flag := range []string{"--destination-run-service", "--destination-function"} {
+			destination, ok := command.Flags[flag]
+			if !ok {
+				continue
+			}
+			for _, group := range []string{"run", "functions"} {
+				if index, ok := functionIndices[group+"/"+destination]; ok {
+					data.Functions[index].InvocationType = gcpEventTypeInvocationType(eventType)
+				}
+			}
 		}
 	}
+
+	return nil
 }
 
-func scanDurableFunctionsFramework(data *RepositoryData, files []TextFile) {
+func scanDurableFunctionsFramework(data *ScannerData, files []TextFile) error {
 	allDependencies := make([]string, len(data.Dependencies)+len(data.DevDependencies))
 	allDependencies = append(allDependencies, data.DevDependencies...)
 	allDependencies = append(allDependencies, data.Dependencies...)
 
 	if !slices.Contains(allDependencies, "durable-functions") {
-		return
+		return nil
 	}
 
 	data.UsedPlatforms[FaaSPlatformAzure] = true
@@ -3788,96 +4996,106 @@ func scanDurableFunctionsFramework(data *RepositoryData, files []TextFile) {
 		InvocationType: FaaSInvocationTypeUnknown, // set below
 		Location:       FaaSLocationRegion,
 		TimeoutSeconds: -1,
+		MemoryMB:       -1,
 		SourceFilePath: "", // set below
 		SourceFileLine: -1,
 	}
 
-	jsFiles, err := FilterTextFiles(files, "**/*.js", "**/*.jsx", "**/*.mjs")
+	jsFiles, err := FilterJsAndTsFiles(files)
 	if err != nil {
-		return
+		return err
 	}
 
 	for _, jsFile := range jsFiles {
-		if !strings.Contains(jsFile.Content, "durable-functions") {
-			continue
-		}
-
-		function := defaultFunction
-		function.SourceFilePath = jsFile.Path
+		module := data.JsModules.Module(jsFile)
 
-		switch {
-		// Check for Version 3
-		case strings.Contains(jsFile.Content, ".orchestration("),
-			strings.Contains(jsFile.Content, ".activity("):
-			fallthrough
-		// Check for Version 2
-		case strings.Contains(jsFile.Content, ".orchestrator("):
-			function.InvocationType = FaaSInvocationTypeOther
-			data.Functions = append(data.Functions, function)
-		case strings.Contains(jsFile.Content, "client.http("):
-			function.InvocationType = FaaSInvocationTypeHTTP
-			data.Functions = append(data.Functions, function)
-		case strings.Contains(jsFile.Content, "client.timer("):
-			function.InvocationType = FaaSInvocationTypeSchedule
-			data.Functions = append(data.Functions, function)
-		case strings.Contains(jsFile.Content, "client.storageQueue("),
-			strings.Contains(jsFile.Content, "client.serviceBusQueue(") &&
-				strings.Contains(jsFile.Content, "queueName"):
-			function.InvocationType = FaaSInvocationTypeQueue
-			data.Functions = append(data.Functions, function)
-		case strings.Contains(jsFile.Content, "client.serviceBusQueue(") &&
-			strings.Contains(jsFile.Content, "topicName"):
-			function.InvocationType = FaaSInvocationTypeTopic
-			data.Functions = append(data.Functions, function)
-		default:
+		for _, call := range module.Calls {
+			function := defaultFunction
+			function.SourceFilePath = jsFile.Path
+			function.SourceFileLine = call.Line
+
+			switch {
+			// Check for Version 3
+			case module.CallsImport(call, "durable-functions", "app", "orchestration"),
+				module.CallsImport(call, "durable-functions", "app", "activity"):
+				function.Name, _ = call.StringArgument(0)
+				function.InvocationType = FaaSInvocationTypeOther
+				data.Functions = append(data.Functions, function)
+			// Check for Version 2
+			case module.CallsImport(call, "durable-functions", "orchestrator"):
+				function.Name, _ = durableOrchestratorName(module, call)
+				function.InvocationType = FaaSInvocationTypeOther
+				data.Functions = append(data.Functions, function)
+			case module.CallsImport(call, "durable-functions", "app", "client", call.CalleeName()):
+				invocationType, ok := azureFunctionsTriggers[call.CalleeName()]
+				if !ok {
+					continue
+				}
+				function.InvocationType = invocationType
+				data.Functions = append(data.Functions, function)
+			default:
+			}
 		}
+
+		data.Workflows = append(data.Workflows, ParseDurableOrchestrations(module)...)
 	}
+
+	return nil
 }
 
-func scanAlexaSkillsKit(data *RepositoryData, files []TextFile) {
+func scanAlexaSkillsKit(data *ScannerData, files []TextFile) error {
 	allDependencies := make([]string, len(data.Dependencies)+len(data.DevDependencies))
 	allDependencies = append(allDependencies, data.DevDependencies...)
 	allDependencies = append(allDependencies, data.Dependencies...)
 
 	if !slices.Contains(allDependencies, "ask-sdk-core") {
-		return
+		return nil
 	}
 
-	jsFiles, err := FilterTextFiles(files, "**/*.js", "**/*.jsx", "**/*.mjs")
+	jsFiles, err := FilterJsAndTsFiles(files)
 	if err != nil {
-		return
+		return err
 	}
 
 	for _, jsFile := range jsFiles {
-		if !(strings.Contains(jsFile.Content, "ask-sdk-core") &&
-			strings.Contains(jsFile.Content, ".SkillBuilders") &&
-			strings.Contains(jsFile.Content, ".lambda(")) {
+		module := data.JsModules.Module(jsFile)
+		if !module.ImportsModule("ask-sdk-core") {
 			continue
 		}
 
-		data.UsedPlatforms[FaaSPlatformAWS] = true
-		data.UsedFrameworks[FaaSFrameworkAlexaSkillsKit] = true
+		// NOTE: e.g. Alexa.SkillBuilders.custom().addRequestHandlers(...).lambda()
+		for _, call := range module.CallsTo("lambda") {
+			if !slices.Contains(call.Callee, "SkillBuilders") {
+				continue
+			}
 
-		data.Functions = append(data.Functions, RepositoryFaaSFunctionData{
-			Name:           "",
-			Platform:       FaaSPlatformAWS,
-			Framework:      FaaSFrameworkAlexaSkillsKit,
-			InvocationType: FaaSInvocationTypeOther,
-			Location:       FaaSLocationRegion,
-			TimeoutSeconds: -1,
-			SourceFilePath: jsFile.Path,
-			SourceFileLine: -1,
-		})
+			data.UsedPlatforms[FaaSPlatformAWS] = true
+			data.UsedFrameworks[FaaSFrameworkAlexaSkillsKit] = true
+
+			data.Functions = append(data.Functions, RepositoryFaaSFunctionData{
+				Name:           "",
+				Platform:       FaaSPlatformAWS,
+				Framework:      FaaSFrameworkAlexaSkillsKit,
+				InvocationType: FaaSInvocationTypeOther,
+				Location:       FaaSLocationRegion,
+				TimeoutSeconds: -1,
+				MemoryMB:       -1,
+				SourceFilePath: jsFile.Path,
+				SourceFileLine: call.Line,
+			})
+		}
 	}
+
+	return nil
 }
 
-func scanHono(data *RepositoryData, files []TextFile) {
+func scanHono(data *ScannerData, files []TextFile) error {
 	allDependencies := make([]string, len(data.Dependencies)+len(data.DevDependencies))
 	allDependencies = append(allDependencies, data.DevDependencies...)
 	allDependencies = append(allDependencies, data.Dependencies...)
 
 	if !slices.Contains(allDependencies, "hono") {
-		return
+		return nil
 	}
 
 	data.UsedPlatforms[FaaSPlatformCloudflare] = true
@@ -3885,18 +5103,19 @@ func scanHono(data *RepositoryData, files []TextFile) {
 	data.UsedPlatforms[FaaSPlatformAWS] = true
 	data.UsedFrameworks[FaaSFrameworkHono] = true
 
-	jsFiles, err := FilterTextFiles(files, "**/*.js", "**/*.jsx", "**/*.mjs")
+	jsFiles, err := FilterJsAndTsFiles(files)
 	if err != nil {
-		return
+		return err
 	}
 
 	for _, jsFile := range jsFiles {
-		if !(strings.Contains(jsFile.Content, "\"hono\"") ||
-			strings.Contains(jsFile.Content, "'hono'")) {
-			continue
-		}
+		module := data.JsModules.Module(jsFile)
 
-		if !strings.Contains(jsFile.Content, "new Hono") {
+		// NOTE: sub-applications are mounted into the same function, so only the first app of a file counts
+		index := slices.IndexFunc(module.Calls, func(call JsCall) bool {
+			return call.IsNew && module.CallsImport(call, "hono", "Hono")
+		})
+		if index == -1 {
 			continue
 		}
 
@@ -3907,10 +5126,335 @@ func scanHono(data *RepositoryData, files []TextFile) {
 			InvocationType: FaaSInvocationTypeHTTP,
 			Location:       FaaSLocationUnknown,
 			TimeoutSeconds: -1,
+			MemoryMB:       -1,
 			SourceFilePath: jsFile.Path,
-			SourceFileLine: -1,
+			SourceFileLine: module.Calls[index].Line,
+		})
+	}
+
+	return nil
+}
+
+func scanSupabase(data *ScannerData, files []TextFile) error {
+	defaultFunction := RepositoryFaaSFunctionData{
+		Name:           "", // set below
+		Platform:       FaaSPlatformSupabase,
+		Framework:      FaaSFrameworkSupabase,
+		InvocationType: FaaSInvocationTypeHTTP, // set below
+		Location:       FaaSLocationEdge,
+		Runtime:        "deno",
+		TimeoutSeconds: -1,
+		MemoryMB:       -1,
+		SourceFilePath: "", // set below
+		SourceFileLine: -1, // set below
+	}
+
+	supabaseConfigs := make(map[string]map[string]SupabaseFunctionConfig)
+	supabaseConfigFiles, err := FilterTextFiles(files, "**/supabase/config.toml")
+	if err != nil {
+		return err
+	}
+	for _, supabaseConfigFile := range supabaseConfigFiles {
+		supabaseConfigs[path.Dir(supabaseConfigFile.Path)] = LoadSupabaseConfig(supabaseConfigFile)
+	}
+
+	invocations := make([]SupabaseFunctionInvocation, 0)
+	supabaseSqlFiles, err := FilterTextFiles(files, "**/supabase/**/*.sql")
+	if err != nil {
+		return err
+	}
+	for _, supabaseSqlFile := range supabaseSqlFiles {
+		invocations = append(invocations, ParseSupabaseFunctionInvocations(supabaseSqlFile)...)
+	}
+
+	jsFiles, err := FilterJsAndTsFiles(files)
+	if err != nil {
+		return err
+	}
+
+	// NOTE: functions with an `entrypoint` in config.toml may live outside of supabase/functions
+	type supabaseFunction struct {
+		name   string
+		config SupabaseFunctionConfig
+		file   TextFile
+	}
+	supabaseFunctions := make([]supabaseFunction, 0)
+	for _, jsFile := range jsFiles {
+		match := supabaseFunctionFileRegexp.FindStringSubmatch(jsFile.Path)
+		if match == nil {
+			continue
+		}
+		supabaseDirectory := path.Dir(path.Dir(path.Dir(jsFile.Path)))
+		config, ok := supabaseConfigs[supabaseDirectory][match[1]]
+		if ok && config.Entrypoint != "" && config.Entrypoint != jsFile.Path {
+			continue
+		}
+		supabaseFunctions = append(supabaseFunctions, supabaseFunction{match[1], config, jsFile})
+	}
+	for _, configs := range supabaseConfigs {
+		for name, config := range configs {
+			if config.Entrypoint == "" {
+				continue
+			}
+			index := slices.IndexFunc(jsFiles, func(jsFile TextFile) bool {
+				return jsFile.Path == config.Entrypoint
+			})
+			if index != -1 && !supabaseFunctionFileRegexp.MatchString(config.Entrypoint) {
+				supabaseFunctions = append(supabaseFunctions, supabaseFunction{name, config, jsFiles[index]})
+			}
+		}
+	}
+
+	for _, supabaseFunction := range supabaseFunctions {
+		if supabaseFunction.config.Name != "" && !supabaseFunction.config.Enabled {
+			continue
+		}
+
+		data.UsedPlatforms[FaaSPlatformSupabase] = true
+		data.UsedFrameworks[FaaSFrameworkSupabase] = true
+
+		function := defaultFunction
+		function.Name = supabaseFunction.name
+		function.SourceFilePath = supabaseFunction.file.Path
+		if triggers := ParseDenoTriggers(data.JsModules.Module(supabaseFunction.file)); len(triggers) > 0 {
+			function.SourceFileLine = triggers[0].Line
+		}
+
+		function.Attributes = make(map[string]string)
+		if supabaseFunction.config.VerifyJwt != "" {
+			function.Attributes["verifyJwt"] = supabaseFunction.config.VerifyJwt
+		}
+
+		// NOTE: every function is reachable through its URL, the first cron job or webhook calling it is reported
+		for _, invocation := range invocations {
+			if invocation.Function != supabaseFunction.name {
+				continue
+			}
+			if function.InvocationType == FaaSInvocationTypeHTTP {
+				function.InvocationType = invocation.InvocationType
+			}
+			for attribute, value := range invocation.Attributes {
+				if function.Attributes[attribute] != "" {
+					function.Attributes[attribute] += ","
+				}
+				function.Attributes[attribute] += value
+			}
+		}
+		if len(function.Attributes) == 0 {
+			function.Attributes = nil
+		}
+
+		data.Functions = append(data.Functions, function)
+	}
+
+	return nil
+}
+
+func scanDenoDeploy(data *ScannerData, files []TextFile) error {
+	defaultFunction := RepositoryFaaSFunctionData{
+		Name:           "", // set below
+		Platform:       FaaSPlatformDenoDeploy,
+		Framework:      FaaSFrameworkDenoDeploy,
+		InvocationType: FaaSInvocationTypeHTTP, // set below
+		Location:       FaaSLocationEdge,
+		Runtime:        "deno",
+		TimeoutSeconds: -1,
+		MemoryMB:       -1,
+		SourceFilePath: "", // set below
+		SourceFileLine: -1, // set below
+	}
+
+	denoDeployProjects := make([]DenoDeployProject, 0)
+
+	denoConfigFiles, err := FilterTextFiles(files, "**/deno.json", "**/deno.jsonc")
+	if err != nil {
+		return err
+	}
+	for _, denoConfigFile := range denoConfigFiles {
+		if denoDeployProject, ok := LoadDenoDeployConfig(denoConfigFile); ok {
+			denoDeployProjects = append(denoDeployProjects, denoDeployProject)
+		}
+	}
+
+	scriptFiles, err := FilterTextFiles(files, "**/deno.json", "**/deno.jsonc", "**/package.json", "**/*.sh", "**/Makefile", "**/*.yml", "**/*.yaml")
+	if err != nil {
+		return err
+	}
+	for _, scriptFile := range scriptFiles {
+		denoDeployProjects = append(denoDeployProjects, ParseDeployctlCommands(scriptFile)...)
+		if strings.HasSuffix(scriptFile.Path, ".yml") || strings.HasSuffix(scriptFile.Path, ".yaml") {
+			denoDeployProjects = append(denoDeployProjects, ParseDenoDeployActions(scriptFile, LoadYamlDocuments([]byte(scriptFile.Content)))...)
+		}
+	}
+
+	// NOTE: the same project is usually configured in deno.json and deployed by a script or workflow, the first
+	// occurrence with an entrypoint is kept
+	projectKey := func(denoDeployProject DenoDeployProject) string {
+		if denoDeployProject.Project != "" {
+			return denoDeployProject.Project
+		}
+		return denoDeployProject.Entrypoint
+	}
+	slices.SortStableFunc(denoDeployProjects, func(a, b DenoDeployProject) int {
+		if (a.Entrypoint == "") == (b.Entrypoint == "") {
+			return 0
+		}
+		if a.Entrypoint != "" {
+			return -1
+		}
+		return 1
+	})
+	seenProjects := make([]string, 0)
+
+	jsFiles, err := FilterJsAndTsFiles(files)
+	if err != nil {
+		return err
+	}
+
+	for _, denoDeployProject := range denoDeployProjects {
+		key := projectKey(denoDeployProject)
+		if key == "" || slices.Contains(seenProjects, key) {
+			continue
+		}
+		seenProjects = append(seenProjects, key)
+
+		data.UsedPlatforms[FaaSPlatformDenoDeploy] = true
+		data.UsedFrameworks[FaaSFrameworkDenoDeploy] = true
+
+		function := defaultFunction
+		function.Name = denoDeployProject.Project
+		if function.Name == "" {
+			function.Name = strings.TrimSuffix(path.Base(denoDeployProject.Entrypoint), path.Ext(denoDeployProject.Entrypoint))
+		}
+		function.Handler = denoDeployProject.Entrypoint
+		function.SourceFilePath = denoDeployProject.ConfigPath
+		function.SourceFileLine = denoDeployProject.Line
+
+		index := slices.IndexFunc(jsFiles, func(jsFile TextFile) bool {
+			return jsFile.Path == denoDeployProject.Entrypoint
 		})
+		if index != -1 {
+			if triggers := ParseDenoTriggers(data.JsModules.Module(jsFiles[index])); len(triggers) > 0 {
+				function.InvocationType = triggers[0].InvocationType
+				for _, trigger := range triggers {
+					for attribute, value := range trigger.Attributes {
+						if function.Attributes == nil {
+							function.Attributes = make(map[string]string)
+						}
+						if function.Attributes[attribute] != "" {
+							function.Attributes[attribute] += ","
+						}
+						function.Attributes[attribute] += value
+					}
+				}
+			}
+		}
+
+		data.Functions = append(data.Functions, function)
+	}
+
+	return nil
+}
+
+func scanAmplify(data *ScannerData, files []TextFile) error {
+	defaultFunction := RepositoryFaaSFunctionData{
+		Name:           "", // set below
+		Platform:       FaaSPlatformAWS,
+		Framework:      FaaSFrameworkAmplify,
+		InvocationType: FaaSInvocationTypeUnknown, // set below
+		Location:       FaaSLocationRegion,
+		TimeoutSeconds: -1, // set below
+		MemoryMB:       -1, // set below
+		SourceFilePath: "", // set below
+		SourceFileLine: -1, // set below
+	}
+
+	amplifyFunctions := make([]AmplifyFunction, 0)
+
+	backendConfigFiles, err := FilterTextFiles(files, "**/amplify/backend/backend-config.json")
+	if err != nil {
+		return err
+	}
+	for _, backendConfigFile := range backendConfigFiles {
+		amplifyFunctions = append(amplifyFunctions, LoadAmplifyBackendFunctions(backendConfigFile, files)...)
+	}
+
+	// NOTE: Gen 2 resources can be defined anywhere in the amplify directory, usually amplify/functions/<name>/resource.ts
+	jsFiles, err := FilterJsAndTsFiles(files, "**/amplify")
+	if err != nil {
+		return err
+	}
+	modules := make([]*JsModule, 0, len(jsFiles))
+	for _, jsFile := range jsFiles {
+		modules = append(modules, data.JsModules.Module(jsFile))
+	}
+	references := amplifyFunctionReferences(modules)
+	for _, module := range modules {
+		for _, amplifyFunction := range ParseAmplifyFunctionDefinitions(module) {
+			amplifyFunction.addTriggers(references[amplifyFunctionVariable(module, amplifyFunction)])
+			amplifyFunctions = append(amplifyFunctions, amplifyFunction)
+		}
+	}
+
+	for _, amplifyFunction := range amplifyFunctions {
+		data.UsedPlatforms[FaaSPlatformAWS] = true
+		data.UsedFrameworks[FaaSFrameworkAmplify] = true
+
+		function := defaultFunction
+		function.Name = amplifyFunction.Name
+		function.InvocationType = amplifyFunction.InvocationType
+		function.Runtime = amplifyFunction.Runtime
+		function.Handler = amplifyFunction.Handler
+		function.MemoryMB = amplifyFunction.MemoryMB
+		function.TimeoutSeconds = amplifyFunction.TimeoutSeconds
+		function.Attributes = amplifyFunction.Attributes
+		function.SourceFilePath = amplifyFunction.Path
+		function.SourceFileLine = amplifyFunction.Line
+		data.Functions = append(data.Functions, function)
+	}
+
+	return nil
+}
+
+func scanAppwrite(data *ScannerData, files []TextFile) error {
+	defaultFunction := RepositoryFaaSFunctionData{
+		Name:           "", // set below
+		Platform:       FaaSPlatformAppwrite,
+		Framework:      FaaSFrameworkAppwrite,
+		InvocationType: FaaSInvocationTypeUnknown, // set below
+		Location:       FaaSLocationRegion,
+		TimeoutSeconds: -1, // set below
+		MemoryMB:       -1,
+		SourceFilePath: "", // set below
+		SourceFileLine: -1, // set below
+	}
+
+	appwriteConfigFiles, err := FilterTextFiles(files, "**/appwrite.json", "**/appwrite.config.json")
+	if err != nil {
+		return err
+	}
+
+	for _, appwriteConfigFile := range appwriteConfigFiles {
+		for _, appwriteFunction := range LoadAppwriteFunctions(appwriteConfigFile) {
+			data.UsedPlatforms[FaaSPlatformAppwrite] = true
+			data.UsedFrameworks[FaaSFrameworkAppwrite] = true
+
+			function := defaultFunction
+			function.Name = appwriteFunction.Name
+			if function.Name == "" {
+				function.Name = appwriteFunction.Id
+			}
+			function.InvocationType, function.Attributes = appwriteFunction.Trigger()
+			function.Runtime = appwriteFunction.Runtime
+			function.Handler = appwriteFunction.Entrypoint
+			function.TimeoutSeconds = appwriteFunction.TimeoutSeconds
+			function.SourceFilePath = appwriteConfigFile.Path
+			function.SourceFileLine = appwriteFunction.Line
+			data.Functions = append(data.Functions, function)
+		}
 	}
+
+	return nil
 }
 
 func SaveRepositoryData(repositoryData RepositoryData, outPath string) error {
@@ -3950,7 +5494,9 @@ func AggregateRepositoryData(
 	repositoriesDirectory string,
 	repositoryInfosDirectory string,
 	repositoryIssuesCommitsAndContributorsDirectory string,
+	repositoryManifestsDirectory string,
 	excludeDirectories []string,
+	scannerRegistry *ScannerRegistry,
 ) (RepositoryData, error) {
 	var result RepositoryData
 
@@ -3974,6 +5520,16 @@ func AggregateRepositoryData(
 
 	result.RepositoryId = repositoryId
 	result.Url = repositoryInfo.GetHTMLURL()
+
+	manifestPath := path.Join(repositoryManifestsDirectory, fmt.Sprintf("%d.json", repositoryId))
+	if manifest, err := LoadRepositoryManifest(manifestPath); err == nil {
+		result.CommitSHA = manifest.CommitSHA
+		result.ManifestPath = manifestPath
+		result.Sparse = manifest.Sparse
+	} else {
+		// NOTE: repositories cloned before manifests were introduced can't be pinned to a commit
+		fmt.Printf("error loading manifest of repository %d: %v\n", repositoryId, err)
+	}
 	result.Name = repositoryInfo.GetName()
 	result.Description = repositoryInfo.GetDescription()
 	result.License = repositoryInfo.GetLicense().GetName()
@@ -4026,6 +5582,8 @@ func AggregateRepositoryData(
 		return RepositoryData{}, err
 	}
 
+	jsModules := NewJsModuleCache()
+
 	result.Packages = make([]RepositoryPackageData, 0)
 	for _, packageJsonFile := range packageJsonFiles {
 		var packageData RepositoryPackageData
@@ -4048,7 +5606,7 @@ func AggregateRepositoryData(
 
 		//packageData.NumFilesByExtension = countFilesByExtension(applicationFiles)
 		//packageData.LinesOfTextByExtension = countNumberOfLinesByExtension(applicationFiles)
-		scanFaaSHandlers(&packageData, applicationFiles)
+		scanFaaSHandlers(&packageData, applicationFiles, jsModules)
 		scanPackageJsons(&packageData, applicationFiles)
 
 		result.Packages = append(result.Packages, packageData)
@@ -4077,43 +5635,35 @@ func AggregateRepositoryData(
 
 	result.NumFaaSRuntimeDependencies = len(result.FaaSRuntimeDependencies)
 
-	result.Complexity = extractComplexity(repositoryFiles)
-
-	result.UsedFrameworks = make(map[FaaSFramework]bool)
-	result.UsedPlatforms = make(map[FaaSPlatform]bool)
-	result.Functions = make([]RepositoryFaaSFunctionData, 0)
-
-	scanVercel(&result, repositoryFiles)
-	scanNetlify(&result, repositoryFiles)
-	scanServerless(&result, repositoryFiles)
-	scanNitric(&result, repositoryFiles)
-	scanArchitect(&result, repositoryFiles)
-	scanAWSCDKAndSST(&result, repositoryFiles)
-	scanTerraform(&result, repositoryFiles)
-	scanPulumi(&result, repositoryFiles)
-	scanAWSCloudFormationAndSAM(&result, repositoryFiles)
-	scanAzureResourceManager(&result, repositoryFiles)
-	scanGCPCloudDeploymentManager(&result, repositoryFiles)
-	scanAlibabaResourceOrchestrationService(&result, repositoryFiles)
-	scanFnProject(&result, repositoryFiles)
-	scanNuclio(&result, repositoryFiles)
-	scanOpenWhisk(&result, repositoryFiles)
-	scanFission(&result, repositoryFiles)
-	scanKubeless(&result, repositoryFiles)
-	scanKnative(&result, repositoryFiles)
-	scanFirebase(&result, repositoryFiles)
-	scanFastly(&result, repositoryFiles)
-	scanCloudflare(&result, repositoryFiles)
-	scanTencent(&result, repositoryFiles)
-	scanOpenFaaS(&result, repositoryFiles)
-	scanDigitalOcean(&result, repositoryFiles)
-	scanAzureFunctionsFramework(&result, repositoryFiles)
-	scanGCPFunctionsFramework(&result, repositoryFiles)
-	scanDurableFunctionsFramework(&result, repositoryFiles)
-	scanAlexaSkillsKit(&result, repositoryFiles)
-	scanHono(&result, repositoryFiles)
+	// NOTE: sparse clones only contain the files the analysis needs, so their lines of code and files would be undercounted
+	if !result.Sparse {
+		result.Complexity = extractComplexity(repositoryFiles)
+	}
+
+	result.NumJavaScriptFiles, result.NumTypeScriptFiles = countSourceFilesByLanguage(repositoryFiles)
+	result.SourceLanguage = sourceLanguageOfRepository(result.NumJavaScriptFiles, result.NumTypeScriptFiles)
+
+	findings, scannerReports := scannerRegistry.Scan(repositoryFiles, result.Dependencies, result.DevDependencies, jsModules, NewKubernetesManifestCache())
+	for _, scannerReport := range scannerReports {
+		if scannerReport.Error != "" {
+			fmt.Printf("error running scanner %s on repository %d: %s\n", scannerReport.Scanner, repositoryId, scannerReport.Error)
+		}
+		for _, warning := range scannerReport.Warnings {
+			fmt.Printf("warning of scanner %s on repository %d: %s\n", scannerReport.Scanner, repositoryId, warning)
+		}
+	}
+
+	result.UsedFrameworks = findings.UsedFrameworks
+	result.UsedPlatforms = findings.UsedPlatforms
+	result.Functions = findings.Functions
+	result.Workflows = findings.Workflows
+	resolveFunctionHandlers(result.Functions, repositoryFiles, jsModules)
+	result.Functions = deduplicateFunctions(result.Functions)
+	scanFunctionSDKResources(result.Functions, repositoryFiles, jsModules)
+	result.ScannerReports = scannerReports
 
 	result.NumFunctions = len(result.Functions)
+	result.NumWorkflows = len(result.Workflows)
 
 	return result, nil
 }
@@ -4124,7 +5674,9 @@ func AggregateRepositoriesData(
 	repositoriesDirectory string,
 	repositoryInfosDirectory string,
 	repositoryIssuesCommitsAndContributorsDirectory string,
+	repositoryManifestsDirectory string,
 	excludeDirectories []string,
+	scannerRegistry *ScannerRegistry,
 	outDirectory string,
 ) {
 	ProcessInParallel(
@@ -4136,7 +5688,9 @@ func AggregateRepositoriesData(
 				repositoriesDirectory,
 				repositoryInfosDirectory,
 				repositoryIssuesCommitsAndContributorsDirectory,
+				repositoryManifestsDirectory,
 				excludeDirectories,
+				scannerRegistry,
 			)
 			if err != nil {
 				fmt.Printf("error aggregating repository data: %v\n", err)
@@ -8,6 +8,7 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"strings"
 	"time"
 )
 
@@ -28,12 +29,16 @@ func RepositoriesDataToCSV(repositoriesData []RepositoryData, outPath string) er
 		"active_days",
 		"last_commit_at",
 		"num_functions",
+		"num_workflows",
 		"used_platforms",
 		"used_frameworks",
 		"num_packages",
 		"num_published_packages",
 		"num_faas_handlers",
 		"num_faas_runtime_dependencies",
+		"source_language",
+		"num_javascript_files",
+		"num_typescript_files",
 	})
 
 	for _, repositoryData := range repositoriesData {
@@ -50,12 +55,16 @@ func RepositoriesDataToCSV(repositoriesData []RepositoryData, outPath string) er
 			fmt.Sprintf("%d", repositoryData.ActiveHumanDays),
 			repositoryData.LastHumanCommitAt.Format(time.RFC3339),
 			fmt.Sprintf("%d", repositoryData.NumFunctions),
+			fmt.Sprintf("%d", repositoryData.NumWorkflows),
 			usedPlatformsToString(repositoryData.UsedPlatforms),
 			usedFrameworksToString(repositoryData.UsedFrameworks),
 			fmt.Sprintf("%d", repositoryData.NumPackages),
 			fmt.Sprintf("%d", repositoryData.NumPublishedToNPM),
 			fmt.Sprintf("%d", repositoryData.NumFaaSHandlers),
 			fmt.Sprintf("%d", repositoryData.NumFaaSRuntimeDependencies),
+			string(repositoryData.SourceLanguage),
+			fmt.Sprintf("%d", repositoryData.NumJavaScriptFiles),
+			fmt.Sprintf("%d", repositoryData.NumTypeScriptFiles),
 		})
 	}
 
@@ -76,6 +85,193 @@ func RepositoriesDataToCSV(repositoriesData []RepositoryData, outPath string) er
 	return nil
 }
 
+// NOTE: the resource graph of every repository as node and edge lists, functions are identified by their index
+// within the repository, resources by their type and name, e.g. "dynamodb:Table"
+func RepositoriesResourceGraphToCSV(repositoriesData []RepositoryData, nodesOutPath string, edgesOutPath string) error {
+	var nodesBuffer bytes.Buffer
+	nodesWriter := csv.NewWriter(&nodesBuffer)
+	var edgesBuffer bytes.Buffer
+	edgesWriter := csv.NewWriter(&edgesBuffer)
+
+	_ = nodesWriter.Write([]string{
+		"repository_id",
+		"node_id",
+		"kind",
+		"type",
+		"name",
+		"platform",
+		"framework",
+	})
+	_ = edgesWriter.Write([]string{
+		"repository_id",
+		"source",
+		"target",
+		"access",
+		"origin",
+	})
+
+	for _, repositoryData := range repositoriesData {
+		repositoryId := fmt.Sprintf("%d", repositoryData.RepositoryId)
+		resourceNodes := make(map[string]bool)
+
+		for index, function := range repositoryData.Functions {
+			functionNode := fmt.Sprintf("function:%d", index)
+			_ = nodesWriter.Write([]string{
+				repositoryId,
+				functionNode,
+				"function",
+				string(function.InvocationType),
+				function.Name,
+				string(function.Platform),
+				string(function.Framework),
+			})
+
+			for _, resource := range function.Resources {
+				resourceNode := fmt.Sprintf("%s:%s", resource.Type, resource.Name)
+				if !resourceNodes[resourceNode] {
+					resourceNodes[resourceNode] = true
+					_ = nodesWriter.Write([]string{
+						repositoryId,
+						resourceNode,
+						"resource",
+						string(resource.Type),
+						resource.Name,
+						"",
+						"",
+					})
+				}
+
+				// NOTE: edges point in the direction data flows, triggers and reads from the resource to the function
+				source, target := functionNode, resourceNode
+				if resource.Access == FaaSResourceAccessTrigger || resource.Access == FaaSResourceAccessRead {
+					source, target = resourceNode, functionNode
+				}
+				_ = edgesWriter.Write([]string{
+					repositoryId,
+					source,
+					target,
+					string(resource.Access),
+					string(resource.Origin),
+				})
+			}
+		}
+	}
+
+	nodesWriter.Flush()
+	edgesWriter.Flush()
+
+	if err := nodesWriter.Error(); err != nil {
+		return err
+	}
+	if err := edgesWriter.Error(); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(path.Dir(nodesOutPath), 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(nodesOutPath, nodesBuffer.Bytes(), 0644); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(path.Dir(edgesOutPath), 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(edgesOutPath, edgesBuffer.Bytes(), 0644); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// NOTE: links a file of a repository on GitHub for checking findings manually, pinned to the commit scanned if known,
+// files are stored below a directory named by the repository ID
+func repositoryFileUrl(repositoryData RepositoryData, filePath string, line int) string {
+	if filePath == "" {
+		return ""
+	}
+	_, relativePath, ok := strings.Cut(filePath, fmt.Sprintf("/%d/", repositoryData.RepositoryId))
+	if !ok {
+		return ""
+	}
+	ref := repositoryData.CommitSHA
+	if ref == "" {
+		ref = "HEAD"
+	}
+	url := fmt.Sprintf("%s/blob/%s/%s", repositoryData.Url, ref, relativePath)
+	if line > 0 {
+		url += fmt.Sprintf("#L%d", line)
+	}
+	return url
+}
+
+// NOTE: one row per function, indexed like the function nodes of the resource graph
+func RepositoriesFunctionsToCSV(repositoriesData []RepositoryData, outPath string) error {
+	var buffer bytes.Buffer
+	csvWriter := csv.NewWriter(&buffer)
+
+	_ = csvWriter.Write([]string{
+		"repository_id",
+		"function_index",
+		"name",
+		"platform",
+		"framework",
+		"duplicate_frameworks",
+		"invocation_type",
+		"runtime",
+		"handler",
+		"source_file_path",
+		"source_file_line",
+		"source_file_url",
+		"handler_file_path",
+		"handler_export",
+		"handler_file_line",
+		"handler_file_url",
+	})
+
+	for _, repositoryData := range repositoriesData {
+		for index, function := range repositoryData.Functions {
+			duplicateFrameworks := make([]string, 0, len(function.DuplicateFrameworks))
+			for _, framework := range function.DuplicateFrameworks {
+				duplicateFrameworks = append(duplicateFrameworks, string(framework))
+			}
+
+			_ = csvWriter.Write([]string{
+				fmt.Sprintf("%d", repositoryData.RepositoryId),
+				fmt.Sprintf("%d", index),
+				function.Name,
+				string(function.Platform),
+				string(function.Framework),
+				strings.Join(duplicateFrameworks, ","),
+				string(function.InvocationType),
+				function.Runtime,
+				function.Handler,
+				function.SourceFilePath,
+				fmt.Sprintf("%d", function.SourceFileLine),
+				repositoryFileUrl(repositoryData, function.SourceFilePath, function.SourceFileLine),
+				function.HandlerFilePath,
+				function.HandlerExport,
+				fmt.Sprintf("%d", function.HandlerFileLine),
+				repositoryFileUrl(repositoryData, function.HandlerFilePath, function.HandlerFileLine),
+			})
+		}
+	}
+
+	csvWriter.Flush()
+
+	if err := csvWriter.Error(); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(path.Dir(outPath), 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(outPath, buffer.Bytes(), 0644); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func RepositoriesDataToJSON(repositoriesData []RepositoryData, outPath string) error {
 	repositoriesDataBytes, err := json.Marshal(repositoriesData)
 	if err != nil {
@@ -111,9 +307,42 @@ type RepositoryStatistics struct {
 
 	TotalNumFunctionsByFrameworkByInvocationType map[FaaSInvocationType]map[FaaSFramework]int
 
+	// NOTE: functions are counted by the language of their handler, unknown if it can't be resolved
+	TotalNumApplicationsBySourceLanguage map[SourceLanguage]int
+	TotalNumFunctionsBySourceLanguage    map[SourceLanguage]int
+
 	AverageNumFunctionsPerApplication float64
 	MinNumFunctionsPerApplication     int
 	MaxNumFunctionsPerApplication     int
+
+	TotalNumApplicationsWithWorkflows int
+	TotalNumWorkflows                 int
+	TotalNumWorkflowsByPlatform       map[FaaSPlatform]int
+	TotalNumWorkflowsByFramework      map[FaaSFramework]int
+	TotalNumWorkflowSteps             int
+	// NOTE: functions of an application invoked by at least one of its workflows
+	TotalNumFunctionsInWorkflows int
+
+	TotalNumFunctionsWithResources int
+	// NOTE: distinct resources per application, bindings are the edges between functions and resources
+	TotalNumResourcesByType          map[FaaSResourceType]int
+	TotalNumResourceBindingsByAccess map[FaaSResourceAccess]int
+	TotalNumResourceBindingsByOrigin map[FaaSResourceOrigin]int
+
+	// NOTE: functions without a configured value are counted as "" respectively -1
+	TotalNumFunctionsByRuntime        map[string]int
+	TotalNumFunctionsByArchitecture   map[string]int
+	TotalNumFunctionsByMemoryMB       map[int]int
+	TotalNumFunctionsByTimeoutSeconds map[int]int
+
+	TotalNumFunctionsWithProvisionedConcurrency int
+	TotalNumFunctionsWithReservedConcurrency    int
+	TotalNumFunctionsWithMinInstances           int
+
+	TotalNumFunctionsWithName                  int
+	TotalNumFunctionsWithHandlerFile           int
+	TotalNumFunctionsWithHandlerExport         int
+	TotalNumFunctionsFoundByMultipleFrameworks int
 }
 
 func RepositoriesDataStatisticsToJSON(repositoriesData []RepositoryData, outPath string) error {
@@ -131,6 +360,17 @@ func RepositoriesDataStatisticsToJSON(repositoriesData []RepositoryData, outPath
 		TotalNumFunctionsByLocationByFramework:       make(map[FaaSFramework]map[FaaSLocation]int),
 		TotalNumFunctionsByInvocationTypeByFramework: make(map[FaaSFramework]map[FaaSInvocationType]int),
 		TotalNumFunctionsByFrameworkByInvocationType: make(map[FaaSInvocationType]map[FaaSFramework]int),
+		TotalNumApplicationsBySourceLanguage:         make(map[SourceLanguage]int),
+		TotalNumFunctionsBySourceLanguage:            make(map[SourceLanguage]int),
+		TotalNumWorkflowsByPlatform:                  make(map[FaaSPlatform]int),
+		TotalNumWorkflowsByFramework:                 make(map[FaaSFramework]int),
+		TotalNumResourcesByType:                      make(map[FaaSResourceType]int),
+		TotalNumResourceBindingsByAccess:             make(map[FaaSResourceAccess]int),
+		TotalNumResourceBindingsByOrigin:             make(map[FaaSResourceOrigin]int),
+		TotalNumFunctionsByRuntime:                   make(map[string]int),
+		TotalNumFunctionsByArchitecture:              make(map[string]int),
+		TotalNumFunctionsByMemoryMB:                  make(map[int]int),
+		TotalNumFunctionsByTimeoutSeconds:            make(map[int]int),
 	}
 	for _, data := range repositoriesData {
 		result.TotalNumApplications += 1
@@ -140,11 +380,14 @@ func RepositoriesDataStatisticsToJSON(repositoriesData []RepositoryData, outPath
 		result.MinNumFunctionsPerApplication = min(result.MinNumFunctionsPerApplication, data.NumFunctions)
 		result.MaxNumFunctionsPerApplication = max(result.MaxNumFunctionsPerApplication, data.NumFunctions)
 
+		result.TotalNumApplicationsBySourceLanguage[data.SourceLanguage] += 1
+
 		for _, function := range data.Functions {
 			result.TotalNumFunctionsByPlatform[function.Platform] += 1
 			result.TotalNumFunctionsByFramework[function.Framework] += 1
 			result.TotalNumFunctionsByLocation[function.Location] += 1
 			result.TotalNumFunctionsByInvocationType[function.InvocationType] += 1
+			result.TotalNumFunctionsBySourceLanguage[sourceLanguageOfFile(function.HandlerFilePath)] += 1
 
 			if _, ok := result.TotalNumFunctionsByFrameworkByPlatform[function.Platform]; !ok {
 				result.TotalNumFunctionsByFrameworkByPlatform[function.Platform] = make(map[FaaSFramework]int)
@@ -183,6 +426,69 @@ func RepositoriesDataStatisticsToJSON(repositoriesData []RepositoryData, outPath
 			}
 
 			result.TotalNumFunctionsByFrameworkByInvocationType[function.InvocationType][function.Framework] += 1
+
+			result.TotalNumFunctionsByRuntime[function.Runtime] += 1
+			result.TotalNumFunctionsByArchitecture[function.Architecture] += 1
+			result.TotalNumFunctionsByMemoryMB[function.MemoryMB] += 1
+			result.TotalNumFunctionsByTimeoutSeconds[function.TimeoutSeconds] += 1
+
+			if function.ProvisionedConcurrency > 0 {
+				result.TotalNumFunctionsWithProvisionedConcurrency += 1
+			}
+			if function.ReservedConcurrency > 0 {
+				result.TotalNumFunctionsWithReservedConcurrency += 1
+			}
+			if function.MinInstances > 0 {
+				result.TotalNumFunctionsWithMinInstances += 1
+			}
+
+			if function.Name != "" {
+				result.TotalNumFunctionsWithName += 1
+			}
+			if function.HandlerFilePath != "" {
+				result.TotalNumFunctionsWithHandlerFile += 1
+			}
+			if function.HandlerExport != "" {
+				result.TotalNumFunctionsWithHandlerExport += 1
+			}
+			if len(function.DuplicateFrameworks) > 0 {
+				result.TotalNumFunctionsFoundByMultipleFrameworks += 1
+			}
+		}
+
+		if data.NumWorkflows > 0 {
+			result.TotalNumApplicationsWithWorkflows += 1
+		}
+		result.TotalNumWorkflows += data.NumWorkflows
+
+		workflowFunctions := make(map[string]bool)
+		for _, workflow := range data.Workflows {
+			result.TotalNumWorkflowsByPlatform[workflow.Platform] += 1
+			result.TotalNumWorkflowsByFramework[workflow.Framework] += 1
+			result.TotalNumWorkflowSteps += len(workflow.Steps)
+			for _, function := range workflow.Functions {
+				workflowFunctions[function] = true
+			}
+		}
+		for _, function := range data.Functions {
+			if function.Name != "" && workflowFunctions[function.Name] {
+				result.TotalNumFunctionsInWorkflows += 1
+			}
+		}
+
+		resources := make(map[string]bool)
+		for _, function := range data.Functions {
+			if len(function.Resources) > 0 {
+				result.TotalNumFunctionsWithResources += 1
+			}
+			for _, resource := range function.Resources {
+				result.TotalNumResourceBindingsByAccess[resource.Access] += 1
+				result.TotalNumResourceBindingsByOrigin[resource.Origin] += 1
+				if resourceNode := fmt.Sprintf("%s:%s", resource.Type, resource.Name); !resources[resourceNode] {
+					resources[resourceNode] = true
+					result.TotalNumResourcesByType[resource.Type] += 1
+				}
+			}
 		}
 	}
 	result.AverageNumFunctionsPerApplication = result.AverageNumFunctionsPerApplication / float64(result.TotalNumApplications)
@@ -243,6 +549,14 @@ func ExportRepositories(
 		return err
 	}
 
+	if err := RepositoriesResourceGraphToCSV(faasRepositories, path.Join(outDirectory, "resourceGraphNodes.csv"), path.Join(outDirectory, "resourceGraphEdges.csv")); err != nil {
+		return err
+	}
+
+	if err := RepositoriesFunctionsToCSV(faasRepositories, path.Join(outDirectory, "functions.csv")); err != nil {
+		return err
+	}
+
 	if err := RepositoriesDataToJSON(repositories, path.Join(outDirectory, "repositories.json")); err != nil {
 		return err
 	}
@@ -6,8 +6,69 @@ import (
 	"path"
 	"slices"
 	"time"
+
+	"github.com/google/go-github/github"
 )
 
+type RepositoryRelevanceScore struct {
+	NumInfoMatches          int
+	NumEventMatches         int
+	NumDocumentationMatches int
+}
+
+func (s RepositoryRelevanceScore) Total() int {
+	return s.NumInfoMatches + s.NumEventMatches + s.NumDocumentationMatches
+}
+
+func (s RepositoryRelevanceScore) IsRelevant() bool {
+	return s.NumInfoMatches >= 1 || s.NumEventMatches+s.NumDocumentationMatches >= 2
+}
+
+func ScoreRepositoryRelevance(
+	info *github.Repository,
+	keywords []string,
+	documentationFiles []string,
+	repositoryEventsPath string,
+	repositoriesPath string,
+) (RepositoryRelevanceScore, error) {
+	repositoryId := RepositoryId(info.GetID())
+
+	var score RepositoryRelevanceScore
+
+	score.NumInfoMatches = checkForKeywords(info.GetFullName(), keywords)
+	score.NumInfoMatches += checkForKeywords(info.GetDescription(), keywords)
+	for _, topic := range info.Topics {
+		score.NumInfoMatches += checkForKeywords(topic, keywords)
+	}
+
+	events, err := LoadRepositoryEventsOrDefault(
+		path.Join(repositoryEventsPath, fmt.Sprintf("%d.json", repositoryId)),
+		make([]RepositoryEvent, 0),
+	)
+	if err != nil {
+		return RepositoryRelevanceScore{}, err
+	}
+
+	for _, event := range events {
+		score.NumEventMatches += event.CountKeywordMatches(keywords)
+	}
+
+	for _, documentationFile := range documentationFiles {
+		fileBytes, err := os.ReadFile(path.Join(
+			repositoriesPath,
+			fmt.Sprintf("%d", repositoryId),
+			documentationFile,
+		))
+		if err != nil {
+			continue
+		}
+
+		score.NumDocumentationMatches += checkForKeywords(string(fileBytes), keywords)
+	}
+
+	return score, nil
+}
+
 func FilterRelevantRepositoryIds(
 	repositoryIds []RepositoryId,
 	keywords []string,
@@ -65,43 +126,19 @@ func FilterRelevantRepositoryIds(
 			continue
 		}
 
-		numInfoMatches := checkForKeywords(info.GetFullName(), keywords)
-		numInfoMatches += checkForKeywords(info.GetDescription(), keywords)
-		for _, topic := range info.Topics {
-			numInfoMatches += checkForKeywords(topic, keywords)
-		}
-
-		events, err := LoadRepositoryEventsOrDefault(
-			path.Join(repositoryEventsPath, fmt.Sprintf("%d.json", repositoryId)),
-			make([]RepositoryEvent, 0),
+		score, err := ScoreRepositoryRelevance(
+			&info,
+			keywords,
+			documentationFiles,
+			repositoryEventsPath,
+			repositoriesPath,
 		)
 		if err != nil {
 			fmt.Printf("Error loading events: %v\n", err)
 			continue
 		}
 
-		numEventMatches := 0
-		for _, event := range events {
-			numEventMatches += event.CountKeywordMatches(keywords)
-		}
-
-		numDocumentationMatches := 0
-		for _, documentationFile := range documentationFiles {
-			fileBytes, err := os.ReadFile(path.Join(
-				repositoriesPath,
-				fmt.Sprintf("%d", repositoryId),
-				documentationFile,
-			))
-			if err != nil {
-				continue
-			}
-
-			fileString := string(fileBytes)
-
-			numDocumentationMatches += checkForKeywords(fileString, keywords)
-		}
-
-		if numInfoMatches >= 1 || numEventMatches+numDocumentationMatches >= 2 {
+		if score.IsRelevant() {
 			results = append(results, repositoryId)
 		}
 	}
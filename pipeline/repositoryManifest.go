@@ -0,0 +1,57 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+type RepositoryManifest struct {
+	RepositoryId  RepositoryId
+	FullName      string
+	DefaultBranch string
+
+	CommitSHA        string
+	ArchiveURL       string
+	ArchiveSizeBytes int64
+	ClonedAt         time.Time
+
+	// NOTE: sparse clones only contain the files the analysis needs, see AnalysisFilePatterns
+	Sparse             bool
+	NumExtractedFiles  int
+	NumFilteredEntries int
+	ExtractedSizeBytes int64
+	SkippedEntries     []SkippedArchiveEntry
+}
+
+func SaveRepositoryManifest(manifest RepositoryManifest, outPath string) error {
+	manifestBytes, err := json.Marshal(manifest)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(outPath), os.ModePerm); err != nil {
+		return err
+	}
+	if err := os.WriteFile(outPath, manifestBytes, 0644); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func LoadRepositoryManifest(inPath string) (RepositoryManifest, error) {
+	manifestBytes, err := os.ReadFile(inPath)
+	if err != nil {
+		return RepositoryManifest{}, err
+	}
+
+	var manifest RepositoryManifest
+	err = json.Unmarshal(manifestBytes, &manifest)
+	if err != nil {
+		return RepositoryManifest{}, err
+	}
+
+	return manifest, nil
+}
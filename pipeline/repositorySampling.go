@@ -0,0 +1,585 @@
+package main
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"golang.org/x/exp/maps"
+)
+
+type RepositorySampleStratification string
+
+const (
+	RepositorySampleStratificationNone        RepositorySampleStratification = "none"
+	RepositorySampleStratificationPlatform    RepositorySampleStratification = "platform"
+	RepositorySampleStratificationFramework   RepositorySampleStratification = "framework"
+	RepositorySampleStratificationStarsBucket RepositorySampleStratification = "stars_bucket"
+	RepositorySampleStratificationFilterScore RepositorySampleStratification = "filter_score"
+)
+
+type RepositorySampleLabel string
+
+const (
+	RepositorySampleLabelNone RepositorySampleLabel = ""
+	RepositorySampleLabelHit  RepositorySampleLabel = "hit"
+	RepositorySampleLabelMiss RepositorySampleLabel = "miss"
+)
+
+type RepositorySampleCandidate struct {
+	RepositoryId RepositoryId
+	Url          string
+	Name         string
+	Description  string
+	Stars        int
+	Platforms    []string
+	Frameworks   []string
+	FilterScore  int
+}
+
+type RepositorySampleItem struct {
+	RepositorySampleCandidate
+
+	Stratum     string
+	StratumSize int
+	Label       RepositorySampleLabel
+	Note        string
+}
+
+type RepositorySampleStratumEvaluation struct {
+	Stratum        string
+	StratumSize    int
+	NumLabelled    int
+	NumHits        int
+	NumMisses      int
+	Precision      float64
+	PrecisionLower float64
+	PrecisionUpper float64
+}
+
+type RepositorySampleEvaluation struct {
+	Confidence float64
+
+	NumItems       int
+	NumLabelled    int
+	NumUnlabelled  int
+	NumHits        int
+	NumMisses      int
+	PopulationSize int
+
+	// Precision over all labelled items, ignoring the strata
+	PooledPrecision      float64
+	PooledPrecisionLower float64
+	PooledPrecisionUpper float64
+
+	// Precision weighted by the population size of each stratum
+	StratifiedPrecision      float64
+	StratifiedPrecisionLower float64
+	StratifiedPrecisionUpper float64
+
+	Strata []RepositorySampleStratumEvaluation
+}
+
+var repositorySampleSheetHeader = []string{
+	"id",
+	"url",
+	"name",
+	"description",
+	"stratum",
+	"stratum_size",
+	"stars",
+	"platforms",
+	"frameworks",
+	"filter_score",
+	"label",
+	"note",
+}
+
+func starsBucket(stars int) string {
+	switch {
+	case stars < 10:
+		return "0-9"
+	case stars < 50:
+		return "10-49"
+	case stars < 100:
+		return "50-99"
+	case stars < 500:
+		return "100-499"
+	case stars < 1000:
+		return "500-999"
+	default:
+		return "1000+"
+	}
+}
+
+func filterScoreBucket(score int) string {
+	switch {
+	case score <= 2:
+		return strconv.Itoa(score)
+	case score < 5:
+		return "3-4"
+	case score < 10:
+		return "5-9"
+	default:
+		return "10+"
+	}
+}
+
+func joinedStratum(values []string) string {
+	if len(values) == 0 {
+		return "none"
+	}
+	sortedValues := slices.Clone(values)
+	slices.Sort(sortedValues)
+	return strings.Join(sortedValues, "+")
+}
+
+func (c RepositorySampleCandidate) Stratum(stratification RepositorySampleStratification) (string, error) {
+	switch stratification {
+	case RepositorySampleStratificationNone:
+		return "all", nil
+	case RepositorySampleStratificationPlatform:
+		return joinedStratum(c.Platforms), nil
+	case RepositorySampleStratificationFramework:
+		return joinedStratum(c.Frameworks), nil
+	case RepositorySampleStratificationStarsBucket:
+		return starsBucket(c.Stars), nil
+	case RepositorySampleStratificationFilterScore:
+		return filterScoreBucket(c.FilterScore), nil
+	default:
+		return "", fmt.Errorf("unknown sample stratification: %s", stratification)
+	}
+}
+
+func LoadRepositorySampleCandidate(
+	repositoryId RepositoryId,
+	keywords []string,
+	documentationFiles []string,
+	repositoryInfosDirectory string,
+	repositoryEventsDirectory string,
+	repositoriesDirectory string,
+	repositoriesDataDirectory string,
+) (RepositorySampleCandidate, error) {
+	info, err := LoadRepositoryInfo(path.Join(repositoryInfosDirectory, fmt.Sprintf("%d.json", repositoryId)))
+	if err != nil {
+		return RepositorySampleCandidate{}, err
+	}
+
+	score, err := ScoreRepositoryRelevance(
+		&info,
+		keywords,
+		documentationFiles,
+		repositoryEventsDirectory,
+		repositoriesDirectory,
+	)
+	if err != nil {
+		return RepositorySampleCandidate{}, err
+	}
+
+	candidate := RepositorySampleCandidate{
+		RepositoryId: repositoryId,
+		Url:          info.GetHTMLURL(),
+		Name:         info.GetFullName(),
+		Description:  info.GetDescription(),
+		Stars:        info.GetStargazersCount(),
+		Platforms:    make([]string, 0),
+		Frameworks:   make([]string, 0),
+		FilterScore:  score.Total(),
+	}
+
+	// NOTE: early pipeline stages have no repository data yet, their candidates end up in the "none" strata
+	repositoryData, err := LoadRepositoryData(path.Join(repositoriesDataDirectory, fmt.Sprintf("%d.json", repositoryId)))
+	if err == nil {
+		for platform, isUsed := range repositoryData.UsedPlatforms {
+			if isUsed {
+				candidate.Platforms = append(candidate.Platforms, string(platform))
+			}
+		}
+		for framework, isUsed := range repositoryData.UsedFrameworks {
+			if isUsed {
+				candidate.Frameworks = append(candidate.Frameworks, string(framework))
+			}
+		}
+	}
+
+	return candidate, nil
+}
+
+func DrawStratifiedRepositorySample(
+	candidates []RepositorySampleCandidate,
+	stratification RepositorySampleStratification,
+	numSamplesPerStratum int,
+	seed int64,
+) ([]RepositorySampleItem, error) {
+	candidatesByStratum := make(map[string][]RepositorySampleCandidate)
+	for _, candidate := range candidates {
+		stratum, err := candidate.Stratum(stratification)
+		if err != nil {
+			return nil, err
+		}
+		candidatesByStratum[stratum] = append(candidatesByStratum[stratum], candidate)
+	}
+
+	strata := maps.Keys(candidatesByStratum)
+	slices.Sort(strata)
+
+	random := rand.New(rand.NewSource(seed))
+
+	items := make([]RepositorySampleItem, 0)
+	for _, stratum := range strata {
+		stratumCandidates := candidatesByStratum[stratum]
+		slices.SortFunc(stratumCandidates, func(lhs, rhs RepositorySampleCandidate) int {
+			return cmp.Compare(lhs.RepositoryId, rhs.RepositoryId)
+		})
+		random.Shuffle(len(stratumCandidates), func(i, j int) {
+			stratumCandidates[i], stratumCandidates[j] = stratumCandidates[j], stratumCandidates[i]
+		})
+
+		numSamples := min(numSamplesPerStratum, len(stratumCandidates))
+		for _, candidate := range stratumCandidates[:numSamples] {
+			items = append(items, RepositorySampleItem{
+				RepositorySampleCandidate: candidate,
+				Stratum:                   stratum,
+				StratumSize:               len(stratumCandidates),
+				Label:                     RepositorySampleLabelNone,
+			})
+		}
+	}
+
+	return items, nil
+}
+
+func SaveRepositorySampleSheet(items []RepositorySampleItem, outPath string) error {
+	var buffer bytes.Buffer
+	csvWriter := csv.NewWriter(&buffer)
+
+	_ = csvWriter.Write(repositorySampleSheetHeader)
+
+	for _, item := range items {
+		_ = csvWriter.Write([]string{
+			fmt.Sprintf("%d", item.RepositoryId),
+			item.Url,
+			item.Name,
+			item.Description,
+			item.Stratum,
+			fmt.Sprintf("%d", item.StratumSize),
+			fmt.Sprintf("%d", item.Stars),
+			strings.Join(item.Platforms, ";"),
+			strings.Join(item.Frameworks, ";"),
+			fmt.Sprintf("%d", item.FilterScore),
+			string(item.Label),
+			item.Note,
+		})
+	}
+
+	csvWriter.Flush()
+
+	if err := csvWriter.Error(); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(path.Dir(outPath), 0755); err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(outPath, buffer.Bytes(), 0644); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func parseRepositorySampleLabel(rawLabel string) (RepositorySampleLabel, error) {
+	switch strings.ToLower(strings.TrimSpace(rawLabel)) {
+	case "":
+		return RepositorySampleLabelNone, nil
+	case "hit", "1", "y", "yes", "true", "x":
+		return RepositorySampleLabelHit, nil
+	case "miss", "0", "n", "no", "false":
+		return RepositorySampleLabelMiss, nil
+	default:
+		return RepositorySampleLabelNone, fmt.Errorf("invalid label \"%s\"", rawLabel)
+	}
+}
+
+func LoadRepositorySampleSheet(inPath string) ([]RepositorySampleItem, error) {
+	file, err := os.Open(inPath)
+	if err != nil {
+		return nil, err
+	}
+	defer func(file *os.File) {
+		err := file.Close()
+		if err != nil {
+			panic(err)
+		}
+	}(file)
+
+	rows, err := csv.NewReader(file).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("sample sheet %s is empty", inPath)
+	}
+
+	columns := make(map[string]int)
+	for index, column := range rows[0] {
+		columns[strings.TrimSpace(column)] = index
+	}
+	for _, column := range []string{"id", "stratum", "stratum_size", "label"} {
+		if _, ok := columns[column]; !ok {
+			return nil, fmt.Errorf("sample sheet %s is missing column \"%s\"", inPath, column)
+		}
+	}
+
+	value := func(row []string, column string) string {
+		index, ok := columns[column]
+		if !ok || index >= len(row) {
+			return ""
+		}
+		return row[index]
+	}
+
+	items := make([]RepositorySampleItem, 0, len(rows)-1)
+	for rowIndex, row := range rows[1:] {
+		repositoryId, err := strconv.ParseInt(value(row, "id"), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("row %d: invalid id: %v", rowIndex+2, err)
+		}
+
+		stratumSize, err := strconv.Atoi(value(row, "stratum_size"))
+		if err != nil {
+			return nil, fmt.Errorf("row %d: invalid stratum size: %v", rowIndex+2, err)
+		}
+
+		label, err := parseRepositorySampleLabel(value(row, "label"))
+		if err != nil {
+			return nil, fmt.Errorf("row %d: %v", rowIndex+2, err)
+		}
+
+		stars, _ := strconv.Atoi(value(row, "stars"))
+		filterScore, _ := strconv.Atoi(value(row, "filter_score"))
+
+		items = append(items, RepositorySampleItem{
+			RepositorySampleCandidate: RepositorySampleCandidate{
+				RepositoryId: RepositoryId(repositoryId),
+				Url:          value(row, "url"),
+				Name:         value(row, "name"),
+				Description:  value(row, "description"),
+				Stars:        stars,
+				Platforms:    strings.FieldsFunc(value(row, "platforms"), func(r rune) bool { return r == ';' }),
+				Frameworks:   strings.FieldsFunc(value(row, "frameworks"), func(r rune) bool { return r == ';' }),
+				FilterScore:  filterScore,
+			},
+			Stratum:     value(row, "stratum"),
+			StratumSize: stratumSize,
+			Label:       label,
+			Note:        value(row, "note"),
+		})
+	}
+
+	return items, nil
+}
+
+// https://en.wikipedia.org/wiki/Binomial_proportion_confidence_interval#Wilson_score_interval
+func wilsonScoreInterval(numHits int, numTrials int, z float64) (float64, float64) {
+	if numTrials == 0 {
+		return 0, 1
+	}
+
+	n := float64(numTrials)
+	p := float64(numHits) / n
+
+	denominator := 1 + z*z/n
+	center := (p + z*z/(2*n)) / denominator
+	halfWidth := z * math.Sqrt(p*(1-p)/n+z*z/(4*n*n)) / denominator
+
+	return math.Max(0, center-halfWidth), math.Min(1, center+halfWidth)
+}
+
+func EvaluateRepositorySample(items []RepositorySampleItem, z float64) RepositorySampleEvaluation {
+	evaluation := RepositorySampleEvaluation{
+		Confidence: math.Erf(z / math.Sqrt2),
+		NumItems:   len(items),
+		Strata:     make([]RepositorySampleStratumEvaluation, 0),
+	}
+
+	strataByName := make(map[string]*RepositorySampleStratumEvaluation)
+	for _, item := range items {
+		stratum, ok := strataByName[item.Stratum]
+		if !ok {
+			stratum = &RepositorySampleStratumEvaluation{Stratum: item.Stratum, StratumSize: item.StratumSize}
+			strataByName[item.Stratum] = stratum
+		}
+
+		switch item.Label {
+		case RepositorySampleLabelHit:
+			stratum.NumHits += 1
+			evaluation.NumHits += 1
+		case RepositorySampleLabelMiss:
+			stratum.NumMisses += 1
+			evaluation.NumMisses += 1
+		default:
+			evaluation.NumUnlabelled += 1
+			continue
+		}
+		stratum.NumLabelled += 1
+		evaluation.NumLabelled += 1
+	}
+
+	strata := maps.Keys(strataByName)
+	slices.Sort(strata)
+
+	for _, name := range strata {
+		stratum := strataByName[name]
+		evaluation.PopulationSize += stratum.StratumSize
+	}
+
+	stratifiedVariance := 0.0
+	populationCovered := 0
+	for _, name := range strata {
+		stratum := strataByName[name]
+		if stratum.NumLabelled > 0 {
+			stratum.Precision = float64(stratum.NumHits) / float64(stratum.NumLabelled)
+		}
+		stratum.PrecisionLower, stratum.PrecisionUpper = wilsonScoreInterval(stratum.NumHits, stratum.NumLabelled, z)
+		evaluation.Strata = append(evaluation.Strata, *stratum)
+
+		if stratum.NumLabelled == 0 {
+			continue
+		}
+
+		// NOTE: strata without any labelled items can't contribute to the estimate, their weight is
+		//       redistributed to the remaining strata
+		populationCovered += stratum.StratumSize
+	}
+
+	for _, stratum := range evaluation.Strata {
+		if stratum.NumLabelled == 0 || populationCovered == 0 {
+			continue
+		}
+
+		weight := float64(stratum.StratumSize) / float64(populationCovered)
+		evaluation.StratifiedPrecision += weight * stratum.Precision
+
+		finitePopulationCorrection := 1.0
+		if stratum.StratumSize > 0 {
+			finitePopulationCorrection = 1 - float64(stratum.NumLabelled)/float64(stratum.StratumSize)
+		}
+		if stratum.NumLabelled > 1 {
+			stratifiedVariance += weight * weight * finitePopulationCorrection *
+				stratum.Precision * (1 - stratum.Precision) / float64(stratum.NumLabelled-1)
+		}
+	}
+
+	stratifiedHalfWidth := z * math.Sqrt(stratifiedVariance)
+	evaluation.StratifiedPrecisionLower = math.Max(0, evaluation.StratifiedPrecision-stratifiedHalfWidth)
+	evaluation.StratifiedPrecisionUpper = math.Min(1, evaluation.StratifiedPrecision+stratifiedHalfWidth)
+
+	if evaluation.NumLabelled > 0 {
+		evaluation.PooledPrecision = float64(evaluation.NumHits) / float64(evaluation.NumLabelled)
+	}
+	evaluation.PooledPrecisionLower, evaluation.PooledPrecisionUpper = wilsonScoreInterval(
+		evaluation.NumHits,
+		evaluation.NumLabelled,
+		z,
+	)
+
+	return evaluation
+}
+
+func SampleRepositories(
+	repositoryIds []RepositoryId,
+	stratification RepositorySampleStratification,
+	numSamplesPerStratum int,
+	seed int64,
+	keywords []string,
+	documentationFiles []string,
+	repositoryInfosDirectory string,
+	repositoryEventsDirectory string,
+	repositoriesDirectory string,
+	repositoriesDataDirectory string,
+	outPath string,
+) error {
+	candidates := make([]RepositorySampleCandidate, 0, len(repositoryIds))
+	for _, repositoryId := range repositoryIds {
+		candidate, err := LoadRepositorySampleCandidate(
+			repositoryId,
+			keywords,
+			documentationFiles,
+			repositoryInfosDirectory,
+			repositoryEventsDirectory,
+			repositoriesDirectory,
+			repositoriesDataDirectory,
+		)
+		if err != nil {
+			fmt.Printf("Failed to load sample candidate %d: %s\n", repositoryId, err)
+			continue
+		}
+		candidates = append(candidates, candidate)
+	}
+
+	items, err := DrawStratifiedRepositorySample(candidates, stratification, numSamplesPerStratum, seed)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Drew %d samples from %d repositories\n", len(items), len(candidates))
+
+	return SaveRepositorySampleSheet(items, outPath)
+}
+
+func EvaluateRepositorySampleSheet(inPath string, z float64, outPath string) error {
+	items, err := LoadRepositorySampleSheet(inPath)
+	if err != nil {
+		return err
+	}
+
+	evaluation := EvaluateRepositorySample(items, z)
+
+	for _, stratum := range evaluation.Strata {
+		fmt.Printf(
+			"%s: %d/%d hits, precision %.2f [%.2f, %.2f]\n",
+			stratum.Stratum,
+			stratum.NumHits,
+			stratum.NumLabelled,
+			stratum.Precision,
+			stratum.PrecisionLower,
+			stratum.PrecisionUpper,
+		)
+	}
+	fmt.Printf(
+		"total: %d/%d hits (%d unlabelled), stratified precision %.2f [%.2f, %.2f]\n",
+		evaluation.NumHits,
+		evaluation.NumLabelled,
+		evaluation.NumUnlabelled,
+		evaluation.StratifiedPrecision,
+		evaluation.StratifiedPrecisionLower,
+		evaluation.StratifiedPrecisionUpper,
+	)
+
+	evaluationBytes, err := json.Marshal(evaluation)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(outPath), os.ModePerm); err != nil {
+		return err
+	}
+	if err := os.WriteFile(outPath, evaluationBytes, 0644); err != nil {
+		return err
+	}
+
+	return nil
+}
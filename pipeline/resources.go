@@ -0,0 +1,792 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"golang.org/x/exp/maps"
+)
+
+// NOTE: bindings of the same resource found the same way are merged, reading and writing it becomes read_write
+func addFunctionResource(resources []RepositoryFunctionResourceData, resource RepositoryFunctionResourceData) []RepositoryFunctionResourceData {
+	for index, existing := range resources {
+		if existing.Type != resource.Type || existing.Name != resource.Name || existing.Origin != resource.Origin {
+			continue
+		}
+		if (existing.Access == FaaSResourceAccessTrigger) != (resource.Access == FaaSResourceAccessTrigger) {
+			continue
+		}
+		resources[index].Access = mergeResourceAccess(existing.Access, resource.Access)
+		return resources
+	}
+	return append(resources, resource)
+}
+
+func addFunctionResources(resources []RepositoryFunctionResourceData, others []RepositoryFunctionResourceData) []RepositoryFunctionResourceData {
+	for _, other := range others {
+		resources = addFunctionResource(resources, other)
+	}
+	return resources
+}
+
+func mergeResourceAccess(a FaaSResourceAccess, b FaaSResourceAccess) FaaSResourceAccess {
+	switch {
+	case a == b || b == FaaSResourceAccessUnknown:
+		return a
+	case a == FaaSResourceAccessUnknown:
+		return b
+	default:
+		return FaaSResourceAccessReadWrite
+	}
+}
+
+var cloudFormationResourceTypes = map[string]FaaSResourceType{
+	"AWS::DynamoDB::Table":             FaaSResourceTypeDynamoDB,
+	"AWS::DynamoDB::GlobalTable":       FaaSResourceTypeDynamoDB,
+	"AWS::Serverless::SimpleTable":     FaaSResourceTypeDynamoDB,
+	"AWS::S3::Bucket":                  FaaSResourceTypeS3,
+	"AWS::SQS::Queue":                  FaaSResourceTypeSQS,
+	"AWS::SNS::Topic":                  FaaSResourceTypeSNS,
+	"AWS::Kinesis::Stream":             FaaSResourceTypeKinesis,
+	"AWS::Events::EventBus":            FaaSResourceTypeEventBridge,
+	"AWS::SecretsManager::Secret":      FaaSResourceTypeSecretsManager,
+	"AWS::SSM::Parameter":              FaaSResourceTypeSSMParameter,
+	"AWS::StepFunctions::StateMachine": FaaSResourceTypeStepFunctions,
+	"AWS::Serverless::StateMachine":    FaaSResourceTypeStepFunctions,
+	"AWS::Lambda::Function":            FaaSResourceTypeLambda,
+	"AWS::Serverless::Function":        FaaSResourceTypeLambda,
+	"AWS::RDS::DBCluster":              FaaSResourceTypeRDS,
+	"AWS::RDS::DBInstance":             FaaSResourceTypeRDS,
+}
+
+// NOTE: the services of IAM actions and ARNs, e.g. `dynamodb:GetItem` or `arn:aws:states:...`
+var awsServiceResourceTypes = map[string]FaaSResourceType{
+	"dynamodb":       FaaSResourceTypeDynamoDB,
+	"s3":             FaaSResourceTypeS3,
+	"sqs":            FaaSResourceTypeSQS,
+	"sns":            FaaSResourceTypeSNS,
+	"kinesis":        FaaSResourceTypeKinesis,
+	"events":         FaaSResourceTypeEventBridge,
+	"secretsmanager": FaaSResourceTypeSecretsManager,
+	"ssm":            FaaSResourceTypeSSMParameter,
+	"states":         FaaSResourceTypeStepFunctions,
+	"lambda":         FaaSResourceTypeLambda,
+	"rds":            FaaSResourceTypeRDS,
+	"rds-data":       FaaSResourceTypeRDS,
+}
+
+var awsReadActionPrefixes = []string{"Get", "BatchGet", "TransactGet", "List", "Describe", "Query", "Scan", "Receive", "Read", "Select", "PartiQLSelect", "Head"}
+var awsWriteActionPrefixes = []string{"Put", "BatchWrite", "TransactWrite", "Update", "Delete", "Send", "Publish", "Create", "Start", "Invoke", "Write", "Restore", "ChangeMessageVisibility", "PartiQLInsert", "PartiQLUpdate", "PartiQLDelete", "ExecuteStatement", "BatchExecuteStatement"}
+
+// NOTE: the access an IAM action grants, e.g. read for `dynamodb:GetItem` and read_write for `dynamodb:*`
+func awsActionAccess(action string) FaaSResourceAccess {
+	_, name, _ := strings.Cut(action, ":")
+	if name == "*" {
+		return FaaSResourceAccessReadWrite
+	}
+	for _, prefix := range awsReadActionPrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return FaaSResourceAccessRead
+		}
+	}
+	for _, prefix := range awsWriteActionPrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return FaaSResourceAccessWrite
+		}
+	}
+	return FaaSResourceAccessUnknown
+}
+
+var awsArnResourceRegexp = regexp.MustCompile(`^arn:[^:]*:([a-z0-9-]+):[^:]*:[^:]*:(.+)$`)
+var awsArnResourcePrefixes = []string{"table/", "stream/", "event-bus/", "parameter/", "secret:", "stateMachine:", "function:", "cluster:", "db:"}
+
+// NOTE: e.g. "orders" for `arn:aws:dynamodb:eu-west-1:123456789012:table/orders/stream/*` or "uploads" for
+// `arn:aws:s3:::uploads/*`
+func awsArnResource(arn string) (FaaSResourceType, string, bool) {
+	match := awsArnResourceRegexp.FindStringSubmatch(arn)
+	if match == nil {
+		return FaaSResourceTypeUnknown, "", false
+	}
+	resourceType, ok := awsServiceResourceTypes[match[1]]
+	if !ok {
+		return FaaSResourceTypeUnknown, "", false
+	}
+
+	name := match[2]
+	for _, prefix := range awsArnResourcePrefixes {
+		if after, ok := strings.CutPrefix(name, prefix); ok {
+			name = after
+			break
+		}
+	}
+	if resourceType != FaaSResourceTypeSSMParameter {
+		name, _, _ = strings.Cut(name, "/")
+	}
+	if resourceType == FaaSResourceTypeStepFunctions || resourceType == FaaSResourceTypeLambda {
+		name, _, _ = strings.Cut(name, ":")
+	}
+	return resourceType, name, true
+}
+
+// NOTE: a resource referenced with intrinsic functions like `!Ref Table` or `!GetAtt Queue.Arn` or given by its ARN
+func (template *CloudFormationTemplate) referencedFunctionResource(value interface{}) (FaaSResourceType, string, bool) {
+	if resource, ok := template.ReferencedResource(value); ok {
+		resourceType, ok := cloudFormationResourceTypes[resource.Type]
+		return resourceType, resource.LogicalId, ok
+	}
+	if arn, ok := template.ResolveValue(value).(string); ok {
+		return awsArnResource(arn)
+	}
+	return FaaSResourceTypeUnknown, "", false
+}
+
+// NOTE: the resources of the allowing statements of an IAM policy document, grouped by the services of their actions
+func (template *CloudFormationTemplate) PolicyStatementResources(statements interface{}) []RepositoryFunctionResourceData {
+	resources := make([]RepositoryFunctionResourceData, 0)
+
+	statementArray, ok := statements.([]interface{})
+	if !ok {
+		statementArray = []interface{}{statements}
+	}
+	for _, statement := range statementArray {
+		if effect, err := JsonResolveString(statement, []string{"Effect"}); err == nil && effect != "Allow" {
+			continue
+		}
+
+		serviceAccess := make(map[FaaSResourceType]FaaSResourceAccess)
+		for _, action := range jsonStrings(statement, []string{"Action"}) {
+			service, _, _ := strings.Cut(action, ":")
+			if resourceType, ok := awsServiceResourceTypes[service]; ok {
+				if access, ok := serviceAccess[resourceType]; ok {
+					serviceAccess[resourceType] = mergeResourceAccess(access, awsActionAccess(action))
+				} else {
+					serviceAccess[resourceType] = awsActionAccess(action)
+				}
+			}
+		}
+		if len(serviceAccess) == 0 {
+			continue
+		}
+
+		values, err := JsonResolve(statement, []string{"Resource"})
+		if err != nil {
+			continue
+		}
+		valueArray, ok := values.([]interface{})
+		if !ok {
+			valueArray = []interface{}{values}
+		}
+		for _, value := range valueArray {
+			if value == "*" {
+				for _, resourceType := range maps.Keys(serviceAccess) {
+					resources = addFunctionResource(resources, RepositoryFunctionResourceData{resourceType, "*", serviceAccess[resourceType], FaaSResourceOriginIAM})
+				}
+				continue
+			}
+			resourceType, name, ok := template.referencedFunctionResource(value)
+			if !ok {
+				continue
+			}
+			access, ok := serviceAccess[resourceType]
+			if !ok {
+				access = FaaSResourceAccessUnknown
+			}
+			resources = addFunctionResource(resources, RepositoryFunctionResourceData{resourceType, name, access, FaaSResourceOriginIAM})
+		}
+	}
+
+	slices.SortFunc(resources, compareFunctionResources)
+	return resources
+}
+
+func compareFunctionResources(a, b RepositoryFunctionResourceData) int {
+	if a.Type != b.Type {
+		return strings.Compare(string(a.Type), string(b.Type))
+	}
+	return strings.Compare(a.Name, b.Name)
+}
+
+// NOTE: SAM policy templates grant access to the resource given as parameter, e.g. `DynamoDBCrudPolicy: { TableName: !Ref Table }`
+var samPolicyTemplates = map[string]struct {
+	resourceType FaaSResourceType
+	access       FaaSResourceAccess
+	parameter    string
+}{
+	"DynamoDBCrudPolicy":                    {FaaSResourceTypeDynamoDB, FaaSResourceAccessReadWrite, "TableName"},
+	"DynamoDBReadPolicy":                    {FaaSResourceTypeDynamoDB, FaaSResourceAccessRead, "TableName"},
+	"DynamoDBWritePolicy":                   {FaaSResourceTypeDynamoDB, FaaSResourceAccessWrite, "TableName"},
+	"DynamoDBStreamReadPolicy":              {FaaSResourceTypeDynamoDB, FaaSResourceAccessRead, "TableName"},
+	"DynamoDBReconfigurePolicy":             {FaaSResourceTypeDynamoDB, FaaSResourceAccessWrite, "TableName"},
+	"S3CrudPolicy":                          {FaaSResourceTypeS3, FaaSResourceAccessReadWrite, "BucketName"},
+	"S3FullAccessPolicy":                    {FaaSResourceTypeS3, FaaSResourceAccessReadWrite, "BucketName"},
+	"S3ReadPolicy":                          {FaaSResourceTypeS3, FaaSResourceAccessRead, "BucketName"},
+	"S3WritePolicy":                         {FaaSResourceTypeS3, FaaSResourceAccessWrite, "BucketName"},
+	"SQSSendMessagePolicy":                  {FaaSResourceTypeSQS, FaaSResourceAccessWrite, "QueueName"},
+	"SQSPollerPolicy":                       {FaaSResourceTypeSQS, FaaSResourceAccessRead, "QueueName"},
+	"SNSPublishMessagePolicy":               {FaaSResourceTypeSNS, FaaSResourceAccessWrite, "TopicName"},
+	"SNSCrudPolicy":                         {FaaSResourceTypeSNS, FaaSResourceAccessReadWrite, "TopicName"},
+	"KinesisStreamReadPolicy":               {FaaSResourceTypeKinesis, FaaSResourceAccessRead, "StreamName"},
+	"KinesisCrudPolicy":                     {FaaSResourceTypeKinesis, FaaSResourceAccessReadWrite, "StreamName"},
+	"EventBridgePutEventsPolicy":            {FaaSResourceTypeEventBridge, FaaSResourceAccessWrite, "EventBusName"},
+	"AWSSecretsManagerGetSecretValuePolicy": {FaaSResourceTypeSecretsManager, FaaSResourceAccessRead, "SecretArn"},
+	"SSMParameterReadPolicy":                {FaaSResourceTypeSSMParameter, FaaSResourceAccessRead, "ParameterName"},
+	"SSMParameterWithSlashPrefixReadPolicy": {FaaSResourceTypeSSMParameter, FaaSResourceAccessRead, "ParameterName"},
+	"StepFunctionsExecutionPolicy":          {FaaSResourceTypeStepFunctions, FaaSResourceAccessWrite, "StateMachineName"},
+	"LambdaInvokePolicy":                    {FaaSResourceTypeLambda, FaaSResourceAccessWrite, "FunctionName"},
+}
+
+// NOTE: SAM event sources pointing to the resource invoking the function, e.g. `Type: SQS` with `Queue: !GetAtt Queue.Arn`
+var samEventSourceProperties = map[string]string{
+	"SQS":      "Queue",
+	"SNS":      "Topic",
+	"S3":       "Bucket",
+	"Kinesis":  "Stream",
+	"DynamoDB": "Stream",
+}
+
+// NOTE: the statements of the inline policies of a role and of the policies attached to it
+func (template *CloudFormationTemplate) rolePolicyStatements(role CloudFormationResource) []interface{} {
+	statements := make([]interface{}, 0)
+
+	policies, _ := JsonResolveArray(role.Properties, []string{"Policies"})
+	for _, policy := range policies {
+		if statement, err := JsonResolve(policy, []string{"PolicyDocument", "Statement"}); err == nil {
+			statements = append(statements, statement)
+		}
+	}
+
+	managedPolicyArns, _ := JsonResolveArray(role.Properties, []string{"ManagedPolicyArns"})
+	for _, logicalId := range template.LogicalIds() {
+		resource := template.Resources[logicalId]
+		if resource.Type != "AWS::IAM::Policy" && resource.Type != "AWS::IAM::ManagedPolicy" {
+			continue
+		}
+		attached := slices.ContainsFunc(managedPolicyArns, func(arn interface{}) bool {
+			referenced, ok := template.ReferencedResource(arn)
+			return ok && referenced.LogicalId == logicalId
+		})
+		roles, _ := JsonResolveArray(resource.Properties, []string{"Roles"})
+		attached = attached || slices.ContainsFunc(roles, func(value interface{}) bool {
+			referenced, ok := template.ReferencedResource(value)
+			return ok && referenced.LogicalId == role.LogicalId
+		})
+		if !attached {
+			continue
+		}
+		if statement, err := JsonResolve(resource.Properties, []string{"PolicyDocument", "Statement"}); err == nil {
+			statements = append(statements, statement)
+		}
+	}
+
+	return statements
+}
+
+// NOTE: resources passed as environment variables, granted by policies, SAM policy templates, the role of the function
+// and connectors, and the event sources invoking it
+func (template *CloudFormationTemplate) FunctionResources(resource CloudFormationResource) []RepositoryFunctionResourceData {
+	resources := make([]RepositoryFunctionResourceData, 0)
+	properties := template.FunctionProperties(resource)
+
+	variables, _ := JsonResolveMap(properties, []string{"Environment", "Variables"})
+	variableNames := maps.Keys(variables)
+	slices.Sort(variableNames)
+	for _, name := range variableNames {
+		if resourceType, resourceName, ok := template.referencedFunctionResource(variables[name]); ok {
+			resources = addFunctionResource(resources, RepositoryFunctionResourceData{resourceType, resourceName, FaaSResourceAccessUnknown, FaaSResourceOriginEnvironment})
+		}
+	}
+
+	policies, err := JsonResolve(properties, []string{"Policies"})
+	if err == nil {
+		policyArray, ok := policies.([]interface{})
+		if !ok {
+			policyArray = []interface{}{policies}
+		}
+		for _, policy := range policyArray {
+			policyMap, ok := policy.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if statement, ok := policyMap["Statement"]; ok {
+				resources = addFunctionResources(resources, template.PolicyStatementResources(statement))
+				continue
+			}
+			for name, parameters := range policyMap {
+				policyTemplate, ok := samPolicyTemplates[name]
+				if !ok {
+					continue
+				}
+				value, err := JsonResolve(parameters, []string{policyTemplate.parameter})
+				if err != nil {
+					continue
+				}
+				resourceName := ""
+				if referenced, ok := template.ReferencedResource(value); ok {
+					resourceName = referenced.LogicalId
+				} else if name, ok := template.ResolveValue(value).(string); ok {
+					resourceName = name
+				}
+				resources = addFunctionResource(resources, RepositoryFunctionResourceData{policyTemplate.resourceType, resourceName, policyTemplate.access, FaaSResourceOriginIAM})
+			}
+		}
+	}
+
+	if role, ok := template.ReferencedResource(properties["Role"]); ok && role.Type == "AWS::IAM::Role" {
+		for _, statement := range template.rolePolicyStatements(role) {
+			resources = addFunctionResources(resources, template.PolicyStatementResources(statement))
+		}
+	}
+
+	// NOTE: connectors are either resources with the function as source or embedded in the function
+	connectors := make([]interface{}, 0)
+	embeddedConnectors, _ := JsonResolveMap(properties, []string{"Connectors"})
+	connectorNames := maps.Keys(embeddedConnectors)
+	slices.Sort(connectorNames)
+	for _, name := range connectorNames {
+		if connector, err := JsonResolveMap(embeddedConnectors[name], []string{"Properties"}); err == nil {
+			connectors = append(connectors, connector)
+		}
+	}
+	for _, logicalId := range template.LogicalIds() {
+		connector := template.Resources[logicalId]
+		if connector.Type != "AWS::Serverless::Connector" {
+			continue
+		}
+		sourceId, _ := JsonResolve(connector.Properties, []string{"Source", "Id"})
+		if source, ok := template.ReferencedResource(sourceId); ok && source.LogicalId == resource.LogicalId {
+			connectors = append(connectors, connector.Properties)
+		}
+	}
+	for _, connector := range connectors {
+		access := FaaSResourceAccessUnknown
+		for _, permission := range jsonStrings(connector, []string{"Permissions"}) {
+			switch permission {
+			case "Read":
+				access = mergeResourceAccess(access, FaaSResourceAccessRead)
+			case "Write":
+				access = mergeResourceAccess(access, FaaSResourceAccessWrite)
+			}
+		}
+		destinations, err := JsonResolve(connector, []string{"Destination"})
+		if err != nil {
+			continue
+		}
+		destinationArray, ok := destinations.([]interface{})
+		if !ok {
+			destinationArray = []interface{}{destinations}
+		}
+		for _, destination := range destinationArray {
+			destinationId, _ := JsonResolve(destination, []string{"Id"})
+			if resourceType, resourceName, ok := template.referencedFunctionResource(destinationId); ok {
+				resources = addFunctionResource(resources, RepositoryFunctionResourceData{resourceType, resourceName, access, FaaSResourceOriginIaC})
+			}
+		}
+	}
+
+	events, _ := JsonResolveMap(properties, []string{"Events"})
+	eventNames := maps.Keys(events)
+	slices.Sort(eventNames)
+	for _, name := range eventNames {
+		eventType, _ := JsonResolveString(events[name], []string{"Type"})
+		property, ok := samEventSourceProperties[eventType]
+		if !ok {
+			continue
+		}
+		value, _ := JsonResolve(events[name], []string{"Properties", property})
+		if resourceType, resourceName, ok := template.referencedFunctionResource(value); ok {
+			resources = addFunctionResource(resources, RepositoryFunctionResourceData{resourceType, resourceName, FaaSResourceAccessTrigger, FaaSResourceOriginIaC})
+		}
+	}
+
+	for _, logicalId := range template.LogicalIds() {
+		mapping := template.Resources[logicalId]
+		if mapping.Type != "AWS::Lambda::EventSourceMapping" {
+			continue
+		}
+		if function, ok := template.ReferencedResource(mapping.Properties["FunctionName"]); !ok || function.LogicalId != resource.LogicalId {
+			continue
+		}
+		if resourceType, resourceName, ok := template.referencedFunctionResource(mapping.Properties["EventSourceArn"]); ok {
+			resources = addFunctionResource(resources, RepositoryFunctionResourceData{resourceType, resourceName, FaaSResourceAccessTrigger, FaaSResourceOriginIaC})
+		}
+	}
+
+	return resources
+}
+
+// NOTE: the Serverless Framework deploys the `resources` section as part of its CloudFormation template
+func serverlessResourcesTemplate(configPath string, config map[string]interface{}) *CloudFormationTemplate {
+	template := &CloudFormationTemplate{
+		Path:       configPath,
+		Resources:  make(map[string]CloudFormationResource),
+		Parameters: make(map[string]interface{}),
+	}
+	resources, _ := JsonResolveMap(config, []string{"resources", "Resources"})
+	for logicalId, resource := range resources {
+		resourceType, _ := JsonResolveString(resource, []string{"Type"})
+		properties, err := JsonResolveMap(resource, []string{"Properties"})
+		if err != nil {
+			properties = map[string]interface{}{}
+		}
+		template.Resources[logicalId] = CloudFormationResource{LogicalId: logicalId, Type: resourceType, Properties: properties, Line: -1}
+	}
+	return template
+}
+
+// NOTE: serverless events pointing to the resource invoking the function, given as ARN, name or intrinsic function,
+// e.g. `- sqs: { arn: !GetAtt Queue.Arn }`, `- sns: topic` or `- s3: { bucket: uploads }`
+var serverlessEventSources = map[string]struct {
+	resourceType FaaSResourceType
+	keys         []string
+}{
+	"sqs":         {FaaSResourceTypeSQS, []string{"arn"}},
+	"sns":         {FaaSResourceTypeSNS, []string{"arn", "topicName"}},
+	"s3":          {FaaSResourceTypeS3, []string{"bucket"}},
+	"stream":      {FaaSResourceTypeUnknown, []string{"arn"}},
+	"kinesis":     {FaaSResourceTypeKinesis, []string{"arn"}},
+	"eventBridge": {FaaSResourceTypeEventBridge, []string{"eventBus"}},
+}
+
+// NOTE: the IAM statements of the provider apply to all functions, those of the function itself are supported by the
+// serverless-iam-roles-per-function plugin
+func serverlessFunctionResources(template *CloudFormationTemplate, config map[string]interface{}, serverlessFunction interface{}) []RepositoryFunctionResourceData {
+	resources := make([]RepositoryFunctionResourceData, 0)
+
+	for _, variablesPath := range [][]string{{"provider", "environment"}, {"environment"}} {
+		var source interface{} = config
+		if variablesPath[0] == "environment" {
+			source = serverlessFunction
+		}
+		variables, _ := JsonResolveMap(source, variablesPath)
+		variableNames := maps.Keys(variables)
+		slices.Sort(variableNames)
+		for _, name := range variableNames {
+			if resourceType, resourceName, ok := template.referencedFunctionResource(variables[name]); ok {
+				resources = addFunctionResource(resources, RepositoryFunctionResourceData{resourceType, resourceName, FaaSResourceAccessUnknown, FaaSResourceOriginEnvironment})
+			}
+		}
+	}
+
+	for _, statementsPath := range [][]string{{"provider", "iam", "role", "statements"}, {"provider", "iamRoleStatements"}} {
+		if statements, err := JsonResolve(config, statementsPath); err == nil {
+			resources = addFunctionResources(resources, template.PolicyStatementResources(statements))
+		}
+	}
+	if statements, err := JsonResolve(serverlessFunction, []string{"iamRoleStatements"}); err == nil {
+		resources = addFunctionResources(resources, template.PolicyStatementResources(statements))
+	}
+
+	events, _ := JsonResolveArray(serverlessFunction, []string{"events"})
+	for _, event := range events {
+		eventMap, ok := event.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for eventName, eventValue := range eventMap {
+			eventSource, ok := serverlessEventSources[eventName]
+			if !ok {
+				continue
+			}
+			value := eventValue
+			if valueMap, ok := eventValue.(map[string]interface{}); ok {
+				value = nil
+				for _, key := range eventSource.keys {
+					if keyValue, ok := valueMap[key]; ok {
+						value = keyValue
+						break
+					}
+				}
+			}
+			if value == nil {
+				continue
+			}
+
+			resourceType, resourceName, ok := template.referencedFunctionResource(value)
+			if !ok {
+				// NOTE: SNS topics and S3 buckets can be given by their name and are created by the framework
+				name, isString := value.(string)
+				if !isString || eventSource.resourceType == FaaSResourceTypeUnknown || strings.HasPrefix(name, "arn:") {
+					continue
+				}
+				resourceType, resourceName = eventSource.resourceType, name
+			}
+			resources = addFunctionResource(resources, RepositoryFunctionResourceData{resourceType, resourceName, FaaSResourceAccessTrigger, FaaSResourceOriginIaC})
+		}
+	}
+
+	return resources
+}
+
+var terraformResourceTypes = map[string]FaaSResourceType{
+	"aws_dynamodb_table":              FaaSResourceTypeDynamoDB,
+	"aws_s3_bucket":                   FaaSResourceTypeS3,
+	"aws_sqs_queue":                   FaaSResourceTypeSQS,
+	"aws_sns_topic":                   FaaSResourceTypeSNS,
+	"aws_kinesis_stream":              FaaSResourceTypeKinesis,
+	"aws_cloudwatch_event_bus":        FaaSResourceTypeEventBridge,
+	"aws_secretsmanager_secret":       FaaSResourceTypeSecretsManager,
+	"aws_ssm_parameter":               FaaSResourceTypeSSMParameter,
+	"aws_sfn_state_machine":           FaaSResourceTypeStepFunctions,
+	"aws_lambda_function":             FaaSResourceTypeLambda,
+	"aws_db_instance":                 FaaSResourceTypeRDS,
+	"aws_rds_cluster":                 FaaSResourceTypeRDS,
+	"google_firestore_database":       FaaSResourceTypeFirestore,
+	"google_storage_bucket":           FaaSResourceTypeCloudStorage,
+	"google_pubsub_topic":             FaaSResourceTypePubSub,
+	"google_pubsub_subscription":      FaaSResourceTypePubSub,
+	"google_secret_manager_secret":    FaaSResourceTypeSecretManager,
+	"azurerm_cosmosdb_account":        FaaSResourceTypeCosmosDB,
+	"azurerm_cosmosdb_sql_container":  FaaSResourceTypeCosmosDB,
+	"azurerm_storage_container":       FaaSResourceTypeBlobStorage,
+	"azurerm_storage_queue":           FaaSResourceTypeQueueStorage,
+	"azurerm_storage_table":           FaaSResourceTypeTableStorage,
+	"azurerm_servicebus_queue":        FaaSResourceTypeServiceBus,
+	"azurerm_servicebus_topic":        FaaSResourceTypeServiceBus,
+	"azurerm_eventhub":                FaaSResourceTypeEventHub,
+	"azurerm_key_vault":               FaaSResourceTypeKeyVault,
+	"cloudflare_workers_kv_namespace": FaaSResourceTypeKV,
+	"cloudflare_r2_bucket":            FaaSResourceTypeR2,
+	"cloudflare_d1_database":          FaaSResourceTypeD1,
+	"cloudflare_queue":                FaaSResourceTypeWorkersQueue,
+}
+
+// NOTE: the expression of a nested attribute, e.g. `environment.variables` of an aws_lambda_function
+func terraformAttributeExpression(body *hclsyntax.Body, attributePath ...string) hcl.Expression {
+	if len(attributePath) == 0 {
+		return nil
+	}
+	if attribute, ok := body.Attributes[attributePath[0]]; ok && len(attributePath) == 1 {
+		return attribute.Expr
+	}
+	for _, block := range body.Blocks {
+		if block.Type == attributePath[0] {
+			return terraformAttributeExpression(block.Body, attributePath[1:]...)
+		}
+	}
+	return nil
+}
+
+var terraformEnvironmentPaths = [][]string{
+	{"environment", "variables"},
+	{"environment_variables"},
+	{"service_config", "environment_variables"},
+	{"app_settings"},
+}
+
+func (configuration *TerraformConfiguration) referencedFunctionResources(expression hcl.Expression, access FaaSResourceAccess, origin FaaSResourceOrigin) []RepositoryFunctionResourceData {
+	resources := make([]RepositoryFunctionResourceData, 0)
+	for _, address := range configuration.ReferencedAddresses(expression) {
+		block, _ := configuration.Block(address)
+		resourceType, ok := terraformResourceTypes[block.Type]
+		if !ok {
+			continue
+		}
+		name := block.Name
+		for _, nameAttribute := range []string{"name", "bucket", "title", "function_name"} {
+			if configuredName, ok := configuration.String(block.Body, nameAttribute); ok {
+				name = configuredName
+				break
+			}
+		}
+		resources = addFunctionResource(resources, RepositoryFunctionResourceData{resourceType, name, access, origin})
+	}
+	return resources
+}
+
+// NOTE: resources referenced by the environment variables of a function and event source mappings invoking it
+func (configuration *TerraformConfiguration) FunctionResources(address string) []RepositoryFunctionResourceData {
+	resources := make([]RepositoryFunctionResourceData, 0)
+	block, ok := configuration.Block(address)
+	if !ok {
+		return resources
+	}
+
+	for _, environmentPath := range terraformEnvironmentPaths {
+		if expression := terraformAttributeExpression(block.Body, environmentPath...); expression != nil {
+			resources = addFunctionResources(resources, configuration.referencedFunctionResources(expression, FaaSResourceAccessUnknown, FaaSResourceOriginEnvironment))
+		}
+	}
+
+	for _, mappingAddress := range configuration.ResourceAddresses() {
+		mapping := configuration.Resources[mappingAddress]
+		if mapping.Type != "aws_lambda_event_source_mapping" {
+			continue
+		}
+		functionName := terraformAttributeExpression(mapping.Body, "function_name")
+		if functionName == nil {
+			continue
+		}
+		if referenced, ok := configuration.ReferencedFunction(functionName); !ok || referenced != address {
+			continue
+		}
+		if eventSourceArn := terraformAttributeExpression(mapping.Body, "event_source_arn"); eventSourceArn != nil {
+			resources = addFunctionResources(resources, configuration.referencedFunctionResources(eventSourceArn, FaaSResourceAccessTrigger, FaaSResourceOriginIaC))
+		}
+	}
+
+	return resources
+}
+
+// NOTE: the binding types of function.json and the types of the input, output and trigger helpers of the v4
+// programming model, e.g. `input.cosmosDB(...)` or `app.storageQueue(...)`, with the options naming the resource
+var azureFunctionsBindingResources = map[string]struct {
+	resourceType FaaSResourceType
+	nameKeys     []string
+}{
+	"cosmosDB":        {FaaSResourceTypeCosmosDB, []string{"containerName", "collectionName"}},
+	"blob":            {FaaSResourceTypeBlobStorage, []string{"path"}},
+	"storageBlob":     {FaaSResourceTypeBlobStorage, []string{"path"}},
+	"queue":           {FaaSResourceTypeQueueStorage, []string{"queueName"}},
+	"storageQueue":    {FaaSResourceTypeQueueStorage, []string{"queueName"}},
+	"table":           {FaaSResourceTypeTableStorage, []string{"tableName"}},
+	"serviceBus":      {FaaSResourceTypeServiceBus, []string{"queueName", "topicName"}},
+	"serviceBusQueue": {FaaSResourceTypeServiceBus, []string{"queueName"}},
+	"serviceBusTopic": {FaaSResourceTypeServiceBus, []string{"topicName"}},
+	"eventHub":        {FaaSResourceTypeEventHub, []string{"eventHubName"}},
+	"eventGrid":       {FaaSResourceTypeEventGrid, []string{"topicEndpointUri"}},
+	"sql":             {FaaSResourceTypeAzureSQL, []string{"tableName", "commandText"}},
+}
+
+// NOTE: e.g. `{ "type": "cosmosDBTrigger", "direction": "in", "databaseName": "db", "containerName": "items" }`,
+// triggers invoke the function, other bindings with direction `in` are read and with direction `out` written
+func azureFunctionsBindingResource(bindingType string, direction string, options map[string]string) (RepositoryFunctionResourceData, bool) {
+	kind, isTrigger := strings.CutSuffix(bindingType, "Trigger")
+	bindingResource, ok := azureFunctionsBindingResources[kind]
+	if !ok {
+		return RepositoryFunctionResourceData{}, false
+	}
+
+	resource := RepositoryFunctionResourceData{Type: bindingResource.resourceType, Access: FaaSResourceAccessUnknown, Origin: FaaSResourceOriginIaC}
+	for _, key := range bindingResource.nameKeys {
+		if name := options[key]; name != "" {
+			resource.Name = name
+			break
+		}
+	}
+	switch resource.Type {
+	case FaaSResourceTypeBlobStorage:
+		// NOTE: blob paths start with the container, e.g. `samples-workitems/{name}`
+		resource.Name, _, _ = strings.Cut(resource.Name, "/")
+	case FaaSResourceTypeCosmosDB:
+		if databaseName := options["databaseName"]; databaseName != "" && resource.Name != "" {
+			resource.Name = fmt.Sprintf("%s/%s", databaseName, resource.Name)
+		}
+	}
+
+	switch {
+	case isTrigger:
+		resource.Access = FaaSResourceAccessTrigger
+	case direction == "in":
+		resource.Access = FaaSResourceAccessRead
+	case direction == "out":
+		resource.Access = FaaSResourceAccessWrite
+	case direction == "inout":
+		resource.Access = FaaSResourceAccessReadWrite
+	}
+	return resource, true
+}
+
+func azureFunctionsJsonBindingResources(bindings []interface{}) []RepositoryFunctionResourceData {
+	resources := make([]RepositoryFunctionResourceData, 0)
+	for _, binding := range bindings {
+		bindingMap, ok := binding.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		options := make(map[string]string, len(bindingMap))
+		for key, value := range bindingMap {
+			if value, ok := value.(string); ok {
+				options[key] = value
+			}
+		}
+		if resource, ok := azureFunctionsBindingResource(options["type"], options["direction"], options); ok {
+			resources = addFunctionResource(resources, resource)
+		}
+	}
+	return resources
+}
+
+// NOTE: the v4 programming model declares the trigger by the registering method, e.g. `app.storageQueue("name",
+// { queueName: "a", handler })`, and further bindings as `extraInputs`, `extraOutputs` and `return`
+func azureFunctionsJsBindingResources(module *JsModule, trigger string, options JsValue) []RepositoryFunctionResourceData {
+	resources := make([]RepositoryFunctionResourceData, 0)
+
+	jsOptions := func(value JsValue) map[string]string {
+		result := make(map[string]string)
+		for _, property := range value.Properties {
+			if property.Value.Kind == JsValueString {
+				result[property.Key] = property.Value.Text
+			}
+		}
+		return result
+	}
+
+	if resource, ok := azureFunctionsBindingResource(trigger+"Trigger", "in", jsOptions(options)); ok {
+		resources = addFunctionResource(resources, resource)
+	}
+
+	bindings := make([]JsValue, 0)
+	for _, key := range []string{"extraInputs", "extraOutputs"} {
+		if value := options.Property(key); value != nil {
+			bindings = append(bindings, module.ResolveValue(*value).Elements...)
+		}
+	}
+	if value := options.Property("return"); value != nil {
+		bindings = append(bindings, *value)
+	}
+	for _, binding := range bindings {
+		binding = module.ResolveValue(binding)
+		if binding.Kind != JsValueCall || binding.Call == nil || len(binding.Call.Callee) < 2 {
+			continue
+		}
+		direction := ""
+		switch binding.Call.Callee[len(binding.Call.Callee)-2] {
+		case "input":
+			direction = "in"
+		case "output":
+			direction = "out"
+		default:
+			continue
+		}
+		bindingOptions := binding.Call.Argument(0)
+		if bindingOptions == nil {
+			continue
+		}
+		if resource, ok := azureFunctionsBindingResource(binding.Call.CalleeName(), direction, jsOptions(module.ResolveValue(*bindingOptions))); ok {
+			resources = addFunctionResource(resources, resource)
+		}
+	}
+
+	return resources
+}
+
+// NOTE: the binding types of wrangler that refer to resources, see wranglerBindingTypes
+var wranglerBindingResourceTypes = map[string]FaaSResourceType{
+	"kv":             FaaSResourceTypeKV,
+	"d1":             FaaSResourceTypeD1,
+	"r2":             FaaSResourceTypeR2,
+	"queue":          FaaSResourceTypeWorkersQueue,
+	"durable_object": FaaSResourceTypeDurableObject,
+	"service":        FaaSResourceTypeService,
+	"hyperdrive":     FaaSResourceTypeHyperdrive,
+	"vectorize":      FaaSResourceTypeVectorize,
+}
+
+// NOTE: bindings are named by the variable the Worker accesses them with, queue consumers by the queue
+func wranglerFunctionResources(worker WranglerWorker) []RepositoryFunctionResourceData {
+	resources := make([]RepositoryFunctionResourceData, 0)
+	for _, binding := range worker.Bindings {
+		bindingType, name, _ := strings.Cut(binding, ":")
+		if resourceType, ok := wranglerBindingResourceTypes[bindingType]; ok {
+			resources = addFunctionResource(resources, RepositoryFunctionResourceData{resourceType, name, FaaSResourceAccessUnknown, FaaSResourceOriginIaC})
+		}
+	}
+	for _, queue := range worker.QueueConsumers {
+		resources = addFunctionResource(resources, RepositoryFunctionResourceData{FaaSResourceTypeWorkersQueue, queue, FaaSResourceAccessTrigger, FaaSResourceOriginIaC})
+	}
+	return resources
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestScanAWSCloudFormationAndSAMExtractsResources(t *testing.T) {
+	findings := scanTestRepository(t, "aws_cloudformation_and_sam", map[string]string{
+		"template.yaml": `AWSTemplateFormatVersion: "2010-09-09"
+Transform: AWS::Serverless-2016-10-31
+Resources:
+  OrdersFunction:
+    Type: AWS::Serverless::Function
+    Properties:
+      Handler: src/orders.handler
+      Runtime: nodejs20.x
+      Environment:
+        Variables:
+          TABLE_NAME: !Ref OrdersTable
+          STAGE: prod
+      Policies:
+        - DynamoDBCrudPolicy:
+            TableName: !Ref OrdersTable
+        - Statement:
+            - Effect: Allow
+              Action: sns:Publish
+              Resource: !Ref NotificationsTopic
+            - Effect: Allow
+              Action: s3:GetObject
+              Resource: "*"
+      Events:
+        Orders:
+          Type: SQS
+          Properties:
+            Queue: !GetAtt OrdersQueue.Arn
+  OrdersTable:
+    Type: AWS::DynamoDB::Table
+  OrdersQueue:
+    Type: AWS::SQS::Queue
+  NotificationsTopic:
+    Type: AWS::SNS::Topic
+`,
+	})
+
+	functions := testFunctionsByName(t, findings)
+	function, ok := functions["OrdersFunction"]
+	if !ok {
+		t.Fatalf("function OrdersFunction was not found, got %+v", findings.Functions)
+	}
+
+	expected := []RepositoryFunctionResourceData{
+		{FaaSResourceTypeDynamoDB, "OrdersTable", FaaSResourceAccessUnknown, FaaSResourceOriginEnvironment},
+		{FaaSResourceTypeDynamoDB, "OrdersTable", FaaSResourceAccessReadWrite, FaaSResourceOriginIAM},
+		{FaaSResourceTypeS3, "*", FaaSResourceAccessRead, FaaSResourceOriginIAM},
+		{FaaSResourceTypeSNS, "NotificationsTopic", FaaSResourceAccessWrite, FaaSResourceOriginIAM},
+		{FaaSResourceTypeSQS, "OrdersQueue", FaaSResourceAccessTrigger, FaaSResourceOriginIaC},
+	}
+	if !slices.Equal(function.Resources, expected) {
+		t.Errorf("expected resources %v, got %v", expected, function.Resources)
+	}
+}
+
+func TestScanFunctionSDKResources(t *testing.T) {
+	files := []TextFile{
+		{
+			Path:      testRepositoryDirectory + "/src/orders.ts",
+			Extension: ".ts",
+			Content: `import { DynamoDBClient, GetItemCommand, PutItemCommand } from "@aws-sdk/client-dynamodb";
+import { SQSClient, SendMessageCommand } from "@aws-sdk/client-sqs";
+
+const client = new DynamoDBClient({});
+const tableName = process.env.TABLE_NAME;
+
+export const handler = async (event) => {
+  await client.send(new GetItemCommand({ TableName: tableName, Key: {} }));
+  await client.send(new PutItemCommand({ TableName: tableName, Item: {} }));
+  // await client.send(new DeleteItemCommand({ TableName: "archive" }));
+  await new SQSClient({}).send(new SendMessageCommand({ QueueUrl: "https://sqs.eu-west-1.amazonaws.com/123456789012/shipments", MessageBody: "" }));
+};
+`,
+		},
+		{
+			Path:      testRepositoryDirectory + "/worker/index.ts",
+			Extension: ".ts",
+			Content: `export default {
+  async fetch(request, env) {
+    const cached = await env.CACHE.get("key");
+    await env.UPLOADS.put("key", cached);
+    return new Response(cached);
+  },
+};
+`,
+		},
+	}
+
+	functions := []RepositoryFaaSFunctionData{
+		{
+			Name:            "orders",
+			HandlerFilePath: files[0].Path,
+			Resources: []RepositoryFunctionResourceData{
+				{FaaSResourceTypeDynamoDB, "OrdersTable", FaaSResourceAccessUnknown, FaaSResourceOriginEnvironment},
+			},
+		},
+		{
+			Name:            "worker",
+			HandlerFilePath: files[1].Path,
+			Resources: []RepositoryFunctionResourceData{
+				{FaaSResourceTypeKV, "CACHE", FaaSResourceAccessUnknown, FaaSResourceOriginIaC},
+				{FaaSResourceTypeR2, "UPLOADS", FaaSResourceAccessUnknown, FaaSResourceOriginIaC},
+			},
+		},
+		{Name: "unresolved", HandlerFilePath: ""},
+	}
+	scanFunctionSDKResources(functions, files, NewJsModuleCache())
+
+	tests := []struct {
+		name      string
+		resources []RepositoryFunctionResourceData
+	}{
+		{
+			"orders",
+			[]RepositoryFunctionResourceData{
+				{FaaSResourceTypeDynamoDB, "OrdersTable", FaaSResourceAccessUnknown, FaaSResourceOriginEnvironment},
+				{FaaSResourceTypeDynamoDB, "${TABLE_NAME}", FaaSResourceAccessReadWrite, FaaSResourceOriginSDK},
+				{FaaSResourceTypeSQS, "shipments", FaaSResourceAccessWrite, FaaSResourceOriginSDK},
+			},
+		},
+		{
+			"worker",
+			[]RepositoryFunctionResourceData{
+				{FaaSResourceTypeKV, "CACHE", FaaSResourceAccessUnknown, FaaSResourceOriginIaC},
+				{FaaSResourceTypeR2, "UPLOADS", FaaSResourceAccessUnknown, FaaSResourceOriginIaC},
+				{FaaSResourceTypeKV, "CACHE", FaaSResourceAccessRead, FaaSResourceOriginSDK},
+				{FaaSResourceTypeR2, "UPLOADS", FaaSResourceAccessWrite, FaaSResourceOriginSDK},
+			},
+		},
+		{"unresolved", nil},
+	}
+
+	for index, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if !slices.Equal(functions[index].Resources, test.resources) {
+				t.Errorf("expected resources %v, got %v", test.resources, functions[index].Resources)
+			}
+		})
+	}
+}
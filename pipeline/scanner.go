@@ -0,0 +1,311 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+type ScannerInput struct {
+	Dependencies    []string
+	DevDependencies []string
+	Files           []TextFile
+	// NOTE: shared between the scanners of a repository, so every source file is only parsed once
+	JsModules           *JsModuleCache
+	KubernetesManifests *KubernetesManifestCache
+}
+
+type ScannerFindings struct {
+	UsedPlatforms  map[FaaSPlatform]bool
+	UsedFrameworks map[FaaSFramework]bool
+	Functions      []RepositoryFaaSFunctionData
+	Workflows      []RepositoryWorkflowData
+	// NOTE: reported per scanner, see ScannerReport, and not merged
+	Warnings []string
+}
+
+// NOTE: the scan functions read the dependencies and write the findings through the same value
+type ScannerData struct {
+	ScannerFindings
+
+	Dependencies        []string
+	DevDependencies     []string
+	JsModules           *JsModuleCache
+	KubernetesManifests *KubernetesManifestCache
+}
+
+// NOTE: warnings don't fail the scanner, they are recorded in its report, e.g. for configuration it doesn't know
+func (data *ScannerData) Warnf(format string, args ...interface{}) {
+	data.Warnings = append(data.Warnings, fmt.Sprintf(format, args...))
+}
+
+type ScannerReport struct {
+	Scanner      string
+	DurationMs   int64
+	NumFiles     int
+	NumFunctions int
+	NumWorkflows int
+	Error        string
+	Warnings     []string
+}
+
+type Scanner interface {
+	Name() string
+	FilePatterns() []string
+	Scan(input ScannerInput) (ScannerFindings, error)
+}
+
+type ScanFunc func(data *ScannerData, files []TextFile) error
+
+type funcScanner struct {
+	name         string
+	filePatterns []string
+	scan         ScanFunc
+}
+
+func NewScanner(name string, filePatterns []string, scan ScanFunc) Scanner {
+	return &funcScanner{
+		name:         name,
+		filePatterns: filePatterns,
+		scan:         scan,
+	}
+}
+
+func (s *funcScanner) Name() string {
+	return s.name
+}
+
+func (s *funcScanner) FilePatterns() []string {
+	return s.filePatterns
+}
+
+func (s *funcScanner) Scan(input ScannerInput) (ScannerFindings, error) {
+	data := ScannerData{
+		ScannerFindings:     NewScannerFindings(),
+		Dependencies:        input.Dependencies,
+		DevDependencies:     input.DevDependencies,
+		JsModules:           input.JsModules,
+		KubernetesManifests: input.KubernetesManifests,
+	}
+	if data.JsModules == nil {
+		data.JsModules = NewJsModuleCache()
+	}
+	if data.KubernetesManifests == nil {
+		data.KubernetesManifests = NewKubernetesManifestCache()
+	}
+
+	err := s.scan(&data, input.Files)
+
+	return data.ScannerFindings, err
+}
+
+func NewScannerFindings() ScannerFindings {
+	return ScannerFindings{
+		UsedPlatforms:  make(map[FaaSPlatform]bool),
+		UsedFrameworks: make(map[FaaSFramework]bool),
+		Functions:      make([]RepositoryFaaSFunctionData, 0),
+		Workflows:      make([]RepositoryWorkflowData, 0),
+	}
+}
+
+func (findings *ScannerFindings) Merge(other ScannerFindings) {
+	for platform, isUsed := range other.UsedPlatforms {
+		if isUsed {
+			findings.UsedPlatforms[platform] = true
+		}
+	}
+	for framework, isUsed := range other.UsedFrameworks {
+		if isUsed {
+			findings.UsedFrameworks[framework] = true
+		}
+	}
+	findings.Functions = append(findings.Functions, other.Functions...)
+	findings.Workflows = append(findings.Workflows, other.Workflows...)
+}
+
+type ScannerRegistry struct {
+	scanners []Scanner
+}
+
+func NewScannerRegistry() *ScannerRegistry {
+	return &ScannerRegistry{
+		scanners: make([]Scanner, 0),
+	}
+}
+
+func (registry *ScannerRegistry) Register(scanner Scanner) {
+	if registry.Lookup(scanner.Name()) != nil {
+		panic(fmt.Sprintf("scanner %s is already registered", scanner.Name()))
+	}
+	registry.scanners = append(registry.scanners, scanner)
+}
+
+func (registry *ScannerRegistry) Lookup(name string) Scanner {
+	for _, scanner := range registry.scanners {
+		if scanner.Name() == name {
+			return scanner
+		}
+	}
+	return nil
+}
+
+func (registry *ScannerRegistry) Scanners() []Scanner {
+	return slices.Clone(registry.scanners)
+}
+
+func (registry *ScannerRegistry) Names() []string {
+	names := make([]string, 0, len(registry.scanners))
+	for _, scanner := range registry.scanners {
+		names = append(names, scanner.Name())
+	}
+	return names
+}
+
+func (registry *ScannerRegistry) Without(disabledScanners ...string) (*ScannerRegistry, error) {
+	for _, name := range disabledScanners {
+		if registry.Lookup(name) == nil {
+			return nil, fmt.Errorf("unknown scanner %s", name)
+		}
+	}
+
+	result := NewScannerRegistry()
+	for _, scanner := range registry.scanners {
+		if !slices.Contains(disabledScanners, scanner.Name()) {
+			result.Register(scanner)
+		}
+	}
+	return result, nil
+}
+
+func (registry *ScannerRegistry) FilePatterns() []string {
+	patterns := make([]string, 0)
+	for _, scanner := range registry.scanners {
+		for _, pattern := range scanner.FilePatterns() {
+			if !slices.Contains(patterns, pattern) {
+				patterns = append(patterns, pattern)
+			}
+		}
+	}
+	return patterns
+}
+
+func runScanner(scanner Scanner, input ScannerInput) (findings ScannerFindings, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			findings = ScannerFindings{}
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+
+	return scanner.Scan(input)
+}
+
+func (registry *ScannerRegistry) Scan(
+	files []TextFile,
+	dependencies []string,
+	devDependencies []string,
+	jsModules *JsModuleCache,
+	kubernetesManifests *KubernetesManifestCache,
+) (ScannerFindings, []ScannerReport) {
+	findings := NewScannerFindings()
+	reports := make([]ScannerReport, 0, len(registry.scanners))
+
+	for _, scanner := range registry.scanners {
+		report := ScannerReport{Scanner: scanner.Name()}
+
+		scannerFiles, err := FilterTextFiles(files, scanner.FilePatterns()...)
+		if err != nil {
+			report.Error = err.Error()
+			reports = append(reports, report)
+			continue
+		}
+		report.NumFiles = len(scannerFiles)
+
+		startedAt := time.Now()
+		scannerFindings, err := runScanner(scanner, ScannerInput{
+			Dependencies:        dependencies,
+			DevDependencies:     devDependencies,
+			Files:               scannerFiles,
+			JsModules:           jsModules,
+			KubernetesManifests: kubernetesManifests,
+		})
+		report.DurationMs = time.Since(startedAt).Milliseconds()
+
+		if err != nil {
+			// NOTE: findings of a failed scanner are still kept, most scanners only fail on a single file
+			report.Error = err.Error()
+		}
+
+		report.NumFunctions = len(scannerFindings.Functions)
+		report.NumWorkflows = len(scannerFindings.Workflows)
+		report.Warnings = scannerFindings.Warnings
+		findings.Merge(scannerFindings)
+		reports = append(reports, report)
+	}
+
+	return findings, reports
+}
+
+var (
+	jsAndTsFilePatterns = JsAndTsFilePatterns("")
+	yamlFilePatterns    = []string{"**/*.yaml", "**/*.yml"}
+	// NOTE: manifests including Helm charts, whose partials are .tpl files, and kustomizations without extension
+	kubernetesFilePatterns = slices.Concat(yamlFilePatterns, []string{"**/*.tpl", "**/Kustomization"})
+)
+
+func DefaultScannerRegistry() *ScannerRegistry {
+	registry := NewScannerRegistry()
+
+	registry.Register(NewScanner("vercel", slices.Concat([]string{"**/vercel.json"}, jsAndTsFilePatterns), scanVercel))
+	registry.Register(NewScanner("netlify", slices.Concat([]string{"**/netlify.toml", "**/package.json"}, jsAndTsFilePatterns), scanNetlify))
+	registry.Register(NewScanner("serverless", slices.Concat(yamlFilePatterns, []string{"**/*.json"}, jsAndTsFilePatterns), scanServerless))
+	registry.Register(NewScanner("nitric", slices.Concat([]string{
+		"**/nitric.yaml", "**/nitric.yml", "**/nitric.*.yaml", "**/nitric.*.yml",
+		"**/*.py", "**/*.go", "**/*.dart", "**/*.cs", "**/*.java",
+	}, jsAndTsFilePatterns), scanNitric))
+	registry.Register(NewScanner("architect", []string{
+		"**/.arc", "**/app.arc",
+		"**/arc.yaml", "**/arc.yml",
+		"**/arc.json",
+	}, scanArchitect))
+	registry.Register(NewScanner("aws_cdk_and_sst", jsAndTsFilePatterns, scanAWSCDKAndSST))
+	registry.Register(NewScanner("terraform", []string{"**/*.tf"}, scanTerraform))
+	registry.Register(NewScanner("pulumi", slices.Concat([]string{"**/Pulumi.yaml", "**/Pulumi.yml"}, jsAndTsFilePatterns), scanPulumi))
+	registry.Register(NewScanner("aws_cloudformation_and_sam", slices.Concat(yamlFilePatterns, []string{"**/*.json"}), scanAWSCloudFormationAndSAM))
+	registry.Register(NewScanner("azure_resource_manager", []string{"**/*.json", "**/*.bicep"}, scanAzureResourceManager))
+	registry.Register(NewScanner("gcp_cloud_deployment_manager", slices.Concat(yamlFilePatterns, []string{"**/*.jinja", "**/*.py"}), scanGCPCloudDeploymentManager))
+	registry.Register(NewScanner("alibaba_resource_orchestration_service", slices.Concat(yamlFilePatterns, []string{"**/*.json"}), scanAlibabaResourceOrchestrationService))
+	registry.Register(NewScanner("fn_project", jsAndTsFilePatterns, scanFnProject))
+	registry.Register(NewScanner("nuclio", yamlFilePatterns, scanNuclio))
+	registry.Register(NewScanner("openwhisk", yamlFilePatterns, scanOpenWhisk))
+	registry.Register(NewScanner("fission", kubernetesFilePatterns, scanFission))
+	registry.Register(NewScanner("kubeless", kubernetesFilePatterns, scanKubeless))
+	registry.Register(NewScanner("knative", kubernetesFilePatterns, scanKnative))
+	registry.Register(NewScanner("firebase", slices.Concat([]string{"**/firebase.json"}, jsAndTsFilePatterns), scanFirebase))
+	registry.Register(NewScanner("fastly", slices.Concat([]string{"**/fastly.toml"}, jsAndTsFilePatterns), scanFastly))
+	registry.Register(NewScanner("cloudflare", slices.Concat([]string{"**/wrangler.toml", "**/wrangler.json", "**/wrangler.jsonc"}, jsAndTsFilePatterns), scanCloudflare))
+	registry.Register(NewScanner("tencent", []string{"**/serverless.yml", "**/serverless.yaml"}, scanTencent))
+	registry.Register(NewScanner("openfaas", kubernetesFilePatterns, scanOpenFaaS))
+	registry.Register(NewScanner("digital_ocean", []string{"**/project.yml", "**/project.yaml"}, scanDigitalOcean))
+	registry.Register(NewScanner("azure_functions_framework", slices.Concat([]string{"**/function.json", "**/host.json", "**/local.settings.json", "**/*.py"}, jsAndTsFilePatterns), scanAzureFunctionsFramework))
+	registry.Register(NewScanner("gcp_functions_framework", jsAndTsFilePatterns, scanGCPFunctionsFramework))
+	registry.Register(NewScanner("gcp_cloud_run", yamlFilePatterns, scanGCPCloudRun))
+	registry.Register(NewScanner("gcloud_cli", slices.Concat([]string{"**/*.sh", "**/*.bash", "**/Makefile", "**/*.mk", "**/package.json"}, yamlFilePatterns), scanGCloudCLI))
+	registry.Register(NewScanner("gcp_workflows", slices.Concat(yamlFilePatterns, []string{"**/*.json"}), scanGCPWorkflows))
+	registry.Register(NewScanner("durable_functions_framework", jsAndTsFilePatterns, scanDurableFunctionsFramework))
+	registry.Register(NewScanner("alexa_skills_kit", jsAndTsFilePatterns, scanAlexaSkillsKit))
+	registry.Register(NewScanner("hono", jsAndTsFilePatterns, scanHono))
+	registry.Register(NewScanner("supabase", slices.Concat([]string{"**/supabase/config.toml", "**/supabase/**/*.sql"}, JsAndTsFilePatterns("**/supabase")), scanSupabase))
+	registry.Register(NewScanner("deno_deploy", slices.Concat([]string{
+		"**/deno.json", "**/deno.jsonc", "**/package.json", "**/*.sh", "**/Makefile",
+		"**/.github/workflows/*.yml", "**/.github/workflows/*.yaml",
+	}, jsAndTsFilePatterns), scanDenoDeploy))
+	registry.Register(NewScanner("amplify", slices.Concat([]string{
+		"**/amplify/backend/backend-config.json",
+		"**/amplify/backend/function/*/parameters.json", "**/amplify/backend/function/*/*-cloudformation-template.json",
+		"**/amplify/backend/api/**/*.graphql",
+	}, JsAndTsFilePatterns("**/amplify")), scanAmplify))
+	registry.Register(NewScanner("appwrite", []string{"**/appwrite.json", "**/appwrite.config.json"}, scanAppwrite))
+
+	return registry
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"path"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestScannerReportsWarnings(t *testing.T) {
+	registry := NewScannerRegistry()
+	registry.Register(DefaultScannerRegistry().Lookup("serverless"))
+
+	files := []TextFile{{
+		Path:      "data/repositories/1/serverless.yml",
+		Extension: ".yml",
+		Content: `service: hello
+provider:
+  name: aws
+functions:
+  hello:
+    handler: handler.hello
+    events:
+      - unknownEvent: {}
+`,
+	}}
+	findings, reports := registry.Scan(files, nil, nil, NewJsModuleCache(), NewKubernetesManifestCache())
+
+	if len(findings.Functions) != 1 || findings.Functions[0].InvocationType != FaaSInvocationTypeUnknown {
+		t.Fatalf("expected a function with an unknown invocation type, got %+v", findings.Functions)
+	}
+	expected := []string{"unknown serverless event unknownEvent of function hello in data/repositories/1/serverless.yml"}
+	if len(reports) != 1 || reports[0].Error != "" || !slices.Equal(reports[0].Warnings, expected) {
+		t.Errorf("expected the warnings %v, got %+v", expected, reports)
+	}
+}
+
+const testRepositoryDirectory = "data/repositories/1"
+
+// NOTE: scans files of a cloned repository with a scanner of the default registry, paths are relative to the repository
+func scanTestRepository(t *testing.T, scannerName string, files map[string]string, dependencies ...string) ScannerFindings {
+	t.Helper()
+
+	registry := NewScannerRegistry()
+	registry.Register(DefaultScannerRegistry().Lookup(scannerName))
+
+	findings, reports := registry.Scan(testRepositoryFiles(files), dependencies, nil, NewJsModuleCache(), NewKubernetesManifestCache())
+	for _, report := range reports {
+		if report.Error != "" {
+			t.Fatalf("scanner %s failed: %s", report.Scanner, report.Error)
+		}
+	}
+	return findings
+}
+
+func testRepositoryFiles(files map[string]string) []TextFile {
+	textFiles := make([]TextFile, 0, len(files))
+	for filePath, content := range files {
+		textFiles = append(textFiles, TextFile{
+			Path:      path.Join(testRepositoryDirectory, filePath),
+			Extension: path.Ext(filePath),
+			Content:   content,
+			NumLines:  strings.Count(content, "\n") + 1,
+		})
+	}
+	slices.SortFunc(textFiles, func(a, b TextFile) int { return strings.Compare(a.Path, b.Path) })
+	return textFiles
+}
+
+func testFunctionsByName(t *testing.T, findings ScannerFindings) map[string]RepositoryFaaSFunctionData {
+	t.Helper()
+
+	functions := make(map[string]RepositoryFaaSFunctionData, len(findings.Functions))
+	for _, function := range findings.Functions {
+		if _, ok := functions[function.Name]; ok {
+			t.Errorf("function %s was found more than once", function.Name)
+		}
+		functions[function.Name] = function
+	}
+	return functions
+}
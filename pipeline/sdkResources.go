@@ -0,0 +1,303 @@
+package main
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+)
+
+// NOTE: the parameters of AWS SDK calls naming the resource they access, e.g. `new GetItemCommand({ TableName })` or
+// `s3.putObject({ Bucket, Key, Body })`
+var awsSDKResourceParameters = []struct {
+	parameter    string
+	resourceType FaaSResourceType
+}{
+	{"TableName", FaaSResourceTypeDynamoDB},
+	{"Bucket", FaaSResourceTypeS3},
+	{"QueueUrl", FaaSResourceTypeSQS},
+	{"TopicArn", FaaSResourceTypeSNS},
+	{"StreamName", FaaSResourceTypeKinesis},
+	{"StreamARN", FaaSResourceTypeKinesis},
+	{"SecretId", FaaSResourceTypeSecretsManager},
+	{"stateMachineArn", FaaSResourceTypeStepFunctions},
+	{"FunctionName", FaaSResourceTypeLambda},
+	{"resourceArn", FaaSResourceTypeRDS},
+	{"Name", FaaSResourceTypeSSMParameter},
+}
+
+// NOTE: the methods called on clients or the chains following the call naming the resource, e.g.
+// `db.collection("users").doc(id).get()` reads and `bucket.file(name).save(data)` writes
+var sdkResourceMethods = map[FaaSResourceType]struct {
+	read  []string
+	write []string
+}{
+	FaaSResourceTypeFirestore:        {[]string{"get", "onSnapshot", "listDocuments", "stream"}, []string{"add", "set", "update", "delete", "create"}},
+	FaaSResourceTypeFirebaseDatabase: {[]string{"get", "once", "on"}, []string{"set", "update", "push", "remove", "transaction"}},
+	FaaSResourceTypeCloudStorage:     {[]string{"download", "getFiles", "createReadStream", "exists", "getMetadata", "getSignedUrl"}, []string{"upload", "save", "delete", "createWriteStream", "deleteFiles", "makePublic"}},
+	FaaSResourceTypePubSub:           {[]string{"on", "pull"}, []string{"publish", "publishMessage", "publishJSON"}},
+	FaaSResourceTypeCosmosDB:         {[]string{"query", "readAll", "read", "fetchAll", "fetchNext"}, []string{"create", "upsert", "replace", "delete", "patch", "batch"}},
+	FaaSResourceTypeBlobStorage:      {[]string{"download", "downloadToBuffer", "downloadToFile", "listBlobsFlat", "listBlobsByHierarchy", "exists", "getProperties"}, []string{"upload", "uploadData", "uploadFile", "uploadBlockBlob", "deleteBlob", "deleteIfExists", "create", "createIfNotExists", "setMetadata"}},
+	FaaSResourceTypeQueueStorage:     {[]string{"receiveMessages", "peekMessages"}, []string{"sendMessage", "deleteMessage", "updateMessage", "create", "createIfNotExists", "clearMessages"}},
+	FaaSResourceTypeTableStorage:     {[]string{"getEntity", "listEntities"}, []string{"createEntity", "upsertEntity", "updateEntity", "deleteEntity", "submitTransaction", "createTable"}},
+	FaaSResourceTypeServiceBus:       {[]string{"receiveMessages", "subscribe", "peekMessages"}, []string{"sendMessages", "scheduleMessages", "createMessageBatch"}},
+	FaaSResourceTypeKV:               {[]string{"get", "getWithMetadata", "list"}, []string{"put", "delete"}},
+	FaaSResourceTypeD1:               {[]string{"first", "all", "raw"}, []string{"run", "exec", "batch"}},
+	FaaSResourceTypeR2:               {[]string{"get", "head", "list"}, []string{"put", "delete", "createMultipartUpload"}},
+	FaaSResourceTypeWorkersQueue:     {[]string{}, []string{"send", "sendBatch"}},
+	FaaSResourceTypeVectorize:        {[]string{"query", "getByIds", "describe"}, []string{"insert", "upsert", "deleteByIds"}},
+}
+
+// NOTE: the methods of clients of the Google Cloud and Azure SDKs and Firebase naming the resource as argument,
+// e.g. `storage.bucket("uploads")` or `serviceBusClient.createSender("orders")`
+var sdkResourceCalls = []struct {
+	sources      []string
+	method       string
+	isNew        bool
+	argument     int
+	resourceType FaaSResourceType
+	access       FaaSResourceAccess
+}{
+	{[]string{"@google-cloud/firestore", "firebase-admin", "firebase/firestore"}, "collection", false, 0, FaaSResourceTypeFirestore, FaaSResourceAccessUnknown},
+	{[]string{"firebase/firestore"}, "collection", false, 1, FaaSResourceTypeFirestore, FaaSResourceAccessUnknown},
+	{[]string{"firebase-admin", "firebase/database"}, "ref", false, 0, FaaSResourceTypeFirebaseDatabase, FaaSResourceAccessUnknown},
+	{[]string{"firebase/database"}, "ref", false, 1, FaaSResourceTypeFirebaseDatabase, FaaSResourceAccessUnknown},
+	{[]string{"@google-cloud/storage", "firebase-admin"}, "bucket", false, 0, FaaSResourceTypeCloudStorage, FaaSResourceAccessUnknown},
+	{[]string{"@google-cloud/pubsub"}, "topic", false, 0, FaaSResourceTypePubSub, FaaSResourceAccessUnknown},
+	{[]string{"@google-cloud/pubsub"}, "subscription", false, 0, FaaSResourceTypePubSub, FaaSResourceAccessRead},
+	{[]string{"@azure/cosmos"}, "container", false, 0, FaaSResourceTypeCosmosDB, FaaSResourceAccessUnknown},
+	{[]string{"@azure/storage-blob"}, "getContainerClient", false, 0, FaaSResourceTypeBlobStorage, FaaSResourceAccessUnknown},
+	{[]string{"@azure/storage-queue"}, "getQueueClient", false, 0, FaaSResourceTypeQueueStorage, FaaSResourceAccessUnknown},
+	{[]string{"@azure/data-tables"}, "fromConnectionString", false, 1, FaaSResourceTypeTableStorage, FaaSResourceAccessUnknown},
+	{[]string{"@azure/data-tables"}, "TableClient", true, 1, FaaSResourceTypeTableStorage, FaaSResourceAccessUnknown},
+	{[]string{"@azure/service-bus"}, "createSender", false, 0, FaaSResourceTypeServiceBus, FaaSResourceAccessWrite},
+	{[]string{"@azure/service-bus"}, "createReceiver", false, 0, FaaSResourceTypeServiceBus, FaaSResourceAccessRead},
+	{[]string{"@azure/event-hubs"}, "EventHubProducerClient", true, 1, FaaSResourceTypeEventHub, FaaSResourceAccessWrite},
+	{[]string{"@azure/event-hubs"}, "EventHubConsumerClient", true, 2, FaaSResourceTypeEventHub, FaaSResourceAccessRead},
+	{[]string{"@azure/keyvault-secrets"}, "getSecret", false, 0, FaaSResourceTypeKeyVault, FaaSResourceAccessRead},
+	{[]string{"@azure/keyvault-secrets"}, "setSecret", false, 0, FaaSResourceTypeKeyVault, FaaSResourceAccessWrite},
+}
+
+// NOTE: resource names are string literals or environment variables, e.g. "${TABLE_NAME}" for
+// `process.env.TABLE_NAME`, possibly assigned to a constant before
+func sdkResourceName(module *JsModule, value *JsValue) (string, bool) {
+	if value == nil {
+		return "", false
+	}
+	resolved := module.ResolveValue(*value)
+	switch resolved.Kind {
+	case JsValueString:
+		return resolved.Text, resolved.Text != ""
+	case JsValueIdentifier:
+		if variable, ok := strings.CutPrefix(resolved.Text, "process.env."); ok {
+			return fmt.Sprintf("${%s}", variable), true
+		}
+		if variable, ok := strings.CutPrefix(resolved.Text, "Deno.env."); ok {
+			return fmt.Sprintf("${%s}", variable), true
+		}
+	}
+	return "", false
+}
+
+// NOTE: queue URLs end with the name of the queue, ARNs are reduced to the name of their resource
+func awsSDKResourceName(resourceType FaaSResourceType, name string) string {
+	if _, arnName, ok := awsArnResource(name); ok {
+		return arnName
+	}
+	if resourceType == FaaSResourceTypeSQS && strings.HasPrefix(name, "https://") {
+		return path.Base(name)
+	}
+	return name
+}
+
+// NOTE: the access of a chain of method calls following the call naming the resource, e.g. read for
+// `.doc(id).get()` after `db.collection("users")`
+func sdkChainAccess(module *JsModule, call JsCall, resourceType FaaSResourceType) FaaSResourceAccess {
+	methods, ok := sdkResourceMethods[resourceType]
+	if !ok || len(call.Callee) == 0 {
+		return FaaSResourceAccessUnknown
+	}
+
+	last := len(call.Callee) - 1
+	prefix := slices.Concat(call.Callee[:last], []string{call.Callee[last] + "()"})
+	access := FaaSResourceAccessUnknown
+	for _, other := range module.Calls {
+		if other.Line != call.Line || len(other.Callee) <= len(prefix) || !slices.Equal(other.Callee[:len(prefix)], prefix) {
+			continue
+		}
+		access = mergeResourceAccess(access, sdkMethodAccess(methods.read, methods.write, other.CalleeName()))
+	}
+	return access
+}
+
+func sdkMethodAccess(read []string, write []string, method string) FaaSResourceAccess {
+	switch {
+	case slices.Contains(read, method):
+		return FaaSResourceAccessRead
+	case slices.Contains(write, method):
+		return FaaSResourceAccessWrite
+	default:
+		return FaaSResourceAccessUnknown
+	}
+}
+
+// NOTE: the AWS SDK v3 sends commands like `new PutObjectCommand({ Bucket })` with a client, the v2 SDK and the
+// aggregated v3 clients call methods like `s3.putObject({ Bucket })`, the access is derived from the action
+func awsSDKResources(module *JsModule) []RepositoryFunctionResourceData {
+	resources := make([]RepositoryFunctionResourceData, 0)
+	if !module.ImportsModule("aws-sdk", "@aws-sdk/client-dynamodb", "@aws-sdk/lib-dynamodb", "@aws-sdk/client-s3",
+		"@aws-sdk/client-sqs", "@aws-sdk/client-sns", "@aws-sdk/client-kinesis", "@aws-sdk/client-eventbridge",
+		"@aws-sdk/client-secrets-manager", "@aws-sdk/client-ssm", "@aws-sdk/client-sfn", "@aws-sdk/client-lambda",
+		"@aws-sdk/client-rds-data") {
+		return resources
+	}
+
+	for _, call := range module.Calls {
+		action := call.CalleeName()
+		switch {
+		case call.IsNew && strings.HasSuffix(action, "Command"):
+			action = strings.TrimSuffix(action, "Command")
+		case !call.IsNew && action != "":
+			action = strings.ToUpper(action[:1]) + action[1:]
+		default:
+			continue
+		}
+
+		argument := call.Argument(0)
+		if argument == nil {
+			continue
+		}
+		parameters := module.ResolveValue(*argument)
+		if parameters.Kind != JsValueObject {
+			continue
+		}
+
+		// NOTE: events are put on the buses named by their entries, e.g. `{ Entries: [{ EventBusName, Detail }] }`
+		if entries := parameters.Property("Entries"); entries != nil && action == "PutEvents" {
+			for _, entry := range module.ResolveValue(*entries).Elements {
+				if name, ok := sdkResourceName(module, entry.Property("EventBusName")); ok {
+					resources = addFunctionResource(resources, RepositoryFunctionResourceData{FaaSResourceTypeEventBridge, awsSDKResourceName(FaaSResourceTypeEventBridge, name), FaaSResourceAccessWrite, FaaSResourceOriginSDK})
+				}
+			}
+			continue
+		}
+
+		for _, parameter := range awsSDKResourceParameters {
+			// NOTE: `Name` is only specific enough for SSM parameters, e.g. `new GetParameterCommand({ Name })`
+			if parameter.resourceType == FaaSResourceTypeSSMParameter && !strings.Contains(action, "Parameter") {
+				continue
+			}
+			name, ok := sdkResourceName(module, parameters.Property(parameter.parameter))
+			if !ok {
+				continue
+			}
+			resources = addFunctionResource(resources, RepositoryFunctionResourceData{parameter.resourceType, awsSDKResourceName(parameter.resourceType, name), awsActionAccess(":" + action), FaaSResourceOriginSDK})
+			break
+		}
+	}
+
+	return resources
+}
+
+// NOTE: the resources accessed by a module through the SDKs of the cloud providers
+func SDKResources(module *JsModule) []RepositoryFunctionResourceData {
+	resources := awsSDKResources(module)
+
+	for _, resourceCall := range sdkResourceCalls {
+		if !module.ImportsModule(resourceCall.sources...) {
+			continue
+		}
+		for _, call := range module.Calls {
+			if call.IsNew != resourceCall.isNew || call.CalleeName() != resourceCall.method {
+				continue
+			}
+			name, ok := sdkResourceName(module, call.Argument(resourceCall.argument))
+			if !ok {
+				continue
+			}
+
+			switch resourceCall.resourceType {
+			case FaaSResourceTypeFirebaseDatabase:
+				// NOTE: references point to paths within the database, e.g. "users/{id}" is named after "users"
+				name, _, _ = strings.Cut(strings.TrimPrefix(name, "/"), "/")
+			case FaaSResourceTypeCosmosDB:
+				// NOTE: containers are named with their database like the bindings, e.g. "db/items" for
+				// `client.database("db").container("items")`
+				if database := cosmosDatabaseName(module, call); database != "" {
+					name = fmt.Sprintf("%s/%s", database, name)
+				}
+			}
+
+			access := resourceCall.access
+			if access == FaaSResourceAccessUnknown {
+				access = sdkChainAccess(module, call, resourceCall.resourceType)
+			}
+			resources = addFunctionResource(resources, RepositoryFunctionResourceData{resourceCall.resourceType, name, access, FaaSResourceOriginSDK})
+		}
+	}
+
+	slices.SortFunc(resources, compareFunctionResources)
+	return resources
+}
+
+func cosmosDatabaseName(module *JsModule, call JsCall) string {
+	last := len(call.Callee) - 1
+	if last < 1 || call.Callee[last-1] != "database()" {
+		return ""
+	}
+	callee := slices.Concat(call.Callee[:last-1], []string{"database"})
+	for _, other := range module.Calls {
+		if other.Line == call.Line && slices.Equal(other.Callee, callee) {
+			name, _ := sdkResourceName(module, other.Argument(0))
+			return name
+		}
+	}
+	return ""
+}
+
+// NOTE: Workers access their bindings through the environment, e.g. `env.CACHE.get(key)` reads the KV namespace
+// bound as CACHE and `env.DB.prepare(query).all()` reads the D1 database bound as DB
+func bindingSDKResources(module *JsModule, resources []RepositoryFunctionResourceData) []RepositoryFunctionResourceData {
+	result := make([]RepositoryFunctionResourceData, 0)
+	for _, resource := range resources {
+		methods, ok := sdkResourceMethods[resource.Type]
+		if !ok || resource.Origin != FaaSResourceOriginIaC || resource.Access == FaaSResourceAccessTrigger {
+			continue
+		}
+		for _, call := range module.Calls {
+			index := slices.Index(call.Callee, resource.Name)
+			if index < 1 || index == len(call.Callee)-1 {
+				continue
+			}
+			access := FaaSResourceAccessUnknown
+			for _, method := range call.Callee[index+1:] {
+				access = mergeResourceAccess(access, sdkMethodAccess(methods.read, methods.write, strings.TrimSuffix(method, "()")))
+			}
+			if access != FaaSResourceAccessUnknown {
+				result = addFunctionResource(result, RepositoryFunctionResourceData{resource.Type, resource.Name, access, FaaSResourceOriginSDK})
+			}
+		}
+	}
+	return result
+}
+
+// NOTE: adds the resources accessed through SDKs in the source files of the functions
+func scanFunctionSDKResources(functions []RepositoryFaaSFunctionData, files []TextFile, jsModules *JsModuleCache) {
+	filesByPath := make(map[string]TextFile, len(files))
+	for _, file := range files {
+		filesByPath[file.Path] = file
+	}
+
+	for index, function := range functions {
+		file, ok := filesByPath[function.HandlerFilePath]
+		if !ok || sourceLanguageOfFile(file.Path) == SourceLanguageUnknown {
+			continue
+		}
+		module := jsModules.Module(file)
+		if functions[index].Resources == nil {
+			functions[index].Resources = make([]RepositoryFunctionResourceData, 0)
+		}
+		sdkResources := slices.Concat(SDKResources(module), bindingSDKResources(module, function.Resources))
+		functions[index].Resources = addFunctionResources(functions[index].Resources, sdkResources)
+	}
+}
@@ -0,0 +1,507 @@
+package main
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// NOTE: variables are resolved statically, so sources that need the AWS account or the shell environment
+//       (env, ssm, cf, s3, ...) are only resolved through their fallback values
+
+const (
+	serverlessDefaultStage          = "dev"
+	serverlessDefaultRegion         = "us-east-1"
+	serverlessDefaultMemorySizeMB   = 1024
+	serverlessDefaultTimeoutSeconds = 6
+	serverlessMaxResolutionDepth    = 16
+)
+
+var serverlessProviderPlatforms = map[string]FaaSPlatform{
+	"aws":        FaaSPlatformAWS,
+	"azure":      FaaSPlatformAzure,
+	"google":     FaaSPlatformGCP,
+	"aliyun":     FaaSPlatformAlibaba,
+	"tencent":    FaaSPlatformTencent,
+	"openwhisk":  FaaSPlatformOpenWhisk,
+	"kubeless":   FaaSPlatformKubeless,
+	"knative":    FaaSPlatformKnative,
+	"fn":         FaaSPlatformFnProject,
+	"cloudflare": FaaSPlatformCloudflare,
+}
+
+var serverlessFileReferenceRegexp = regexp.MustCompile(`^file\((.+)\)(?::(.*))?$`)
+
+type serverlessResolver struct {
+	configFile TextFile
+	files      map[string]TextFile
+	jsModules  *JsModuleCache
+
+	// NOTE: the unresolved configuration, `self:` references are resolved lazily against it
+	config interface{}
+}
+
+// NOTE: returns the configuration with all resolvable variables substituted, unresolvable ones are kept as they are
+func ResolveServerlessConfig(configFile TextFile, files []TextFile, jsModules *JsModuleCache) (map[string]interface{}, error) {
+	documents := LoadJsonsFromYamlBytes([]byte(configFile.Content))
+	if len(documents) == 0 {
+		return nil, fmt.Errorf("no valid yaml document in %s", configFile.Path)
+	}
+
+	var config interface{} = map[string]interface{}{}
+	for _, document := range documents {
+		config = mergeJsons(config, document)
+	}
+
+	resolver := &serverlessResolver{
+		configFile: configFile,
+		files:      make(map[string]TextFile, len(files)),
+		jsModules:  jsModules,
+		config:     config,
+	}
+	for _, file := range files {
+		resolver.files[file.Path] = file
+	}
+
+	resolvedConfig, ok := resolver.resolveValue(config, path.Dir(configFile.Path), 0).(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("configuration in %s is not a map", configFile.Path)
+	}
+
+	// NOTE: these sections can be split into multiple files, e.g. `functions: [${file(a.yml)}, ${file(b.yml)}]`
+	for _, key := range []string{"custom", "functions", "resources", "constructs"} {
+		if sections, ok := resolvedConfig[key].([]interface{}); ok {
+			var merged interface{} = map[string]interface{}{}
+			for _, section := range sections {
+				merged = mergeJsons(merged, section)
+			}
+			resolvedConfig[key] = merged
+		}
+	}
+
+	return resolvedConfig, nil
+}
+
+// NOTE: maps are merged recursively, everything else is replaced
+func mergeJsons(base interface{}, override interface{}) interface{} {
+	baseMap, ok1 := base.(map[string]interface{})
+	overrideMap, ok2 := override.(map[string]interface{})
+	if !ok1 || !ok2 {
+		return override
+	}
+
+	result := make(map[string]interface{}, len(baseMap)+len(overrideMap))
+	for key, value := range baseMap {
+		result[key] = value
+	}
+	for key, value := range overrideMap {
+		if baseValue, ok := result[key]; ok {
+			result[key] = mergeJsons(baseValue, value)
+		} else {
+			result[key] = value
+		}
+	}
+	return result
+}
+
+func (r *serverlessResolver) resolveValue(value interface{}, baseDirectory string, depth int) interface{} {
+	if depth > serverlessMaxResolutionDepth {
+		return value
+	}
+
+	switch value := value.(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(value))
+		for key, child := range value {
+			result[key] = r.resolveValue(child, baseDirectory, depth)
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, 0, len(value))
+		for _, child := range value {
+			result = append(result, r.resolveValue(child, baseDirectory, depth))
+		}
+		return result
+	case string:
+		return r.resolveString(value, baseDirectory, depth)
+	default:
+		return value
+	}
+}
+
+// NOTE: returns the index of the "}" closing the variable starting at start
+func findServerlessVariableEnd(value string, start int) int {
+	depth := 0
+	for index := start; index < len(value); index++ {
+		switch {
+		case strings.HasPrefix(value[index:], "${"):
+			depth++
+			index++
+		case value[index] == '}':
+			depth--
+			if depth == 0 {
+				return index
+			}
+		}
+	}
+	return -1
+}
+
+func (r *serverlessResolver) resolveString(value string, baseDirectory string, depth int) interface{} {
+	start := strings.Index(value, "${")
+	if start == -1 {
+		return value
+	}
+
+	// NOTE: a value consisting of a single variable keeps the type of the referenced value, e.g. a map
+	if end := findServerlessVariableEnd(value, start); start == 0 && end == len(value)-1 {
+		if resolved, ok := r.resolveVariable(value[2:end], baseDirectory, depth); ok {
+			return resolved
+		}
+		return value
+	}
+
+	var builder strings.Builder
+	for {
+		start := strings.Index(value, "${")
+		if start == -1 {
+			builder.WriteString(value)
+			break
+		}
+		end := findServerlessVariableEnd(value, start)
+		if end == -1 {
+			builder.WriteString(value)
+			break
+		}
+
+		builder.WriteString(value[:start])
+		if resolved, ok := r.resolveVariable(value[start+2:end], baseDirectory, depth); ok {
+			switch resolved.(type) {
+			case map[string]interface{}, []interface{}:
+				builder.WriteString(value[start : end+1])
+			default:
+				builder.WriteString(fmt.Sprint(resolved))
+			}
+		} else {
+			builder.WriteString(value[start : end+1])
+		}
+		value = value[end+1:]
+	}
+	return builder.String()
+}
+
+// NOTE: splits the alternatives of a variable like `opt:stage, self:provider.stage, 'dev'` at the top level
+func splitServerlessAlternatives(expression string) []string {
+	alternatives := make([]string, 0)
+	depth := 0
+	var quote rune
+	start := 0
+	for index, character := range expression {
+		switch {
+		case quote != 0:
+			if character == quote {
+				quote = 0
+			}
+		case character == '\'' || character == '"':
+			quote = character
+		case character == '(' || character == '{':
+			depth++
+		case character == ')' || character == '}':
+			depth--
+		case character == ',' && depth == 0:
+			alternatives = append(alternatives, strings.TrimSpace(expression[start:index]))
+			start = index + 1
+		}
+	}
+	return append(alternatives, strings.TrimSpace(expression[start:]))
+}
+
+func (r *serverlessResolver) resolveVariable(expression string, baseDirectory string, depth int) (interface{}, bool) {
+	if depth > serverlessMaxResolutionDepth {
+		return nil, false
+	}
+
+	// NOTE: nested variables are part of the address, e.g. `self:custom.${opt:stage}.memorySize`
+	if strings.Contains(expression, "${") {
+		resolvedExpression, ok := r.resolveString(expression, baseDirectory, depth+1).(string)
+		if !ok {
+			return nil, false
+		}
+		expression = resolvedExpression
+	}
+
+	alternatives := splitServerlessAlternatives(expression)
+	for _, alternative := range alternatives {
+		if resolved, ok := r.resolveSource(alternative, baseDirectory, depth); ok {
+			return resolved, true
+		}
+	}
+
+	// NOTE: without a fallback, the CLI defaults apply
+	for _, alternative := range alternatives {
+		switch alternative {
+		case "opt:stage":
+			return r.stage(depth), true
+		case "opt:region":
+			return r.region(depth), true
+		}
+	}
+
+	return nil, false
+}
+
+func (r *serverlessResolver) resolveSource(source string, baseDirectory string, depth int) (interface{}, bool) {
+	if source == "" || strings.Contains(source, "${") {
+		return nil, false
+	}
+
+	if len(source) >= 2 && (source[0] == '\'' || source[0] == '"') && source[len(source)-1] == source[0] {
+		return source[1 : len(source)-1], true
+	}
+	if number, err := strconv.Atoi(source); err == nil {
+		return number, true
+	}
+	if source == "true" || source == "false" {
+		return source == "true", true
+	}
+
+	if match := serverlessFileReferenceRegexp.FindStringSubmatch(source); match != nil {
+		return r.resolveFileReference(strings.Trim(strings.TrimSpace(match[1]), `'"`), match[2], baseDirectory, depth)
+	}
+
+	sourceType, address, ok := strings.Cut(source, ":")
+	if !ok {
+		return nil, false
+	}
+
+	switch sourceType {
+	case "self":
+		return r.lookupSelf(address, depth)
+	case "sls":
+		if address == "stage" {
+			return r.stage(depth), true
+		}
+	case "aws":
+		if address == "region" {
+			return r.region(depth), true
+		}
+	}
+
+	// NOTE: opt, env, ssm, cf, s3, param, ... are not known statically
+	return nil, false
+}
+
+func (r *serverlessResolver) resolveFileReference(filePath string, address string, baseDirectory string, depth int) (interface{}, bool) {
+	// NOTE: paths are relative to the file containing the reference, with the service directory as a fallback
+	candidates := []string{path.Join(baseDirectory, filePath), path.Join(path.Dir(r.configFile.Path), filePath)}
+
+	for _, candidate := range candidates {
+		file, ok := r.files[candidate]
+		if !ok {
+			continue
+		}
+
+		var value interface{}
+		switch path.Ext(candidate) {
+		case ".yml", ".yaml", ".json":
+			documents := LoadJsonsFromYamlBytes([]byte(file.Content))
+			if len(documents) == 0 {
+				return nil, false
+			}
+			value = documents[0]
+		case ".js", ".cjs", ".mjs", ".ts":
+			// NOTE: only static exports can be read, exported functions would have to be executed
+			module := r.jsModules.Module(file)
+			export := module.Export("default")
+			if export == nil {
+				return nil, false
+			}
+			if value, ok = jsValueToJson(module, module.ResolveValue(export.Value)); !ok {
+				return nil, false
+			}
+		default:
+			return nil, false
+		}
+
+		if address != "" {
+			if value, ok = lookupServerlessAddress(value, address); !ok {
+				return nil, false
+			}
+		}
+		return r.resolveValue(value, path.Dir(candidate), depth+1), true
+	}
+
+	return nil, false
+}
+
+// NOTE: intermediate values are resolved while walking the address, e.g. for `self:custom.memory` with `custom: ${file(custom.yml)}`
+func (r *serverlessResolver) lookupSelf(address string, depth int) (interface{}, bool) {
+	baseDirectory := path.Dir(r.configFile.Path)
+
+	value := r.config
+	if address != "" {
+		for _, key := range strings.Split(address, ".") {
+			if valueString, ok := value.(string); ok {
+				value = r.resolveString(valueString, baseDirectory, depth+1)
+			}
+
+			child, ok := lookupServerlessAddress(value, key)
+			if !ok {
+				return nil, false
+			}
+			value = child
+		}
+	}
+
+	return r.resolveValue(value, baseDirectory, depth+1), true
+}
+
+func (r *serverlessResolver) stage(depth int) string {
+	if value, ok := lookupServerlessAddress(r.config, "provider.stage"); ok {
+		if stage, ok := r.resolveValue(value, path.Dir(r.configFile.Path), depth+1).(string); ok && !strings.Contains(stage, "${") {
+			return stage
+		}
+	}
+	return serverlessDefaultStage
+}
+
+func (r *serverlessResolver) region(depth int) string {
+	if value, ok := lookupServerlessAddress(r.config, "provider.region"); ok {
+		if region, ok := r.resolveValue(value, path.Dir(r.configFile.Path), depth+1).(string); ok && !strings.Contains(region, "${") {
+			return region
+		}
+	}
+	return serverlessDefaultRegion
+}
+
+func lookupServerlessAddress(value interface{}, address string) (interface{}, bool) {
+	if address == "" {
+		return value, true
+	}
+
+	for _, key := range strings.Split(address, ".") {
+		switch current := value.(type) {
+		case map[string]interface{}:
+			child, ok := current[key]
+			if !ok {
+				return nil, false
+			}
+			value = child
+		case []interface{}:
+			index, err := strconv.Atoi(key)
+			if err != nil || index < 0 || index >= len(current) {
+				return nil, false
+			}
+			value = current[index]
+		default:
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+// NOTE: converts literal values, functions and other expressions are not representable
+func jsValueToJson(module *JsModule, value JsValue) (interface{}, bool) {
+	switch value.Kind {
+	case JsValueString:
+		return value.Text, true
+	case JsValueNumber:
+		if number, err := strconv.Atoi(value.Text); err == nil {
+			return number, true
+		}
+		if number, err := strconv.ParseFloat(value.Text, 64); err == nil {
+			return number, true
+		}
+		return nil, false
+	case JsValueBoolean:
+		return value.Text == "true", true
+	case JsValueNull:
+		return nil, true
+	case JsValueObject:
+		result := make(map[string]interface{}, len(value.Properties))
+		for _, property := range value.Properties {
+			if propertyValue, ok := jsValueToJson(module, module.ResolveValue(property.Value)); ok {
+				result[property.Key] = propertyValue
+			}
+		}
+		return result, true
+	case JsValueArray:
+		result := make([]interface{}, 0, len(value.Elements))
+		for _, element := range value.Elements {
+			if elementValue, ok := jsValueToJson(module, module.ResolveValue(element)); ok {
+				result = append(result, elementValue)
+			}
+		}
+		return result, true
+	default:
+		return nil, false
+	}
+}
+
+func serverlessPlugins(config map[string]interface{}) []string {
+	plugins := make([]string, 0)
+
+	// NOTE: plugins are either a list or a map with the list under `modules`
+	pluginList, err := JsonResolveArray(config, []string{"plugins"})
+	if err != nil {
+		pluginList, err = JsonResolveArray(config, []string{"plugins", "modules"})
+		if err != nil {
+			return plugins
+		}
+	}
+
+	for _, plugin := range pluginList {
+		if pluginName, ok := plugin.(string); ok {
+			plugins = append(plugins, pluginName)
+		}
+	}
+	return plugins
+}
+
+func serverlessEventInvocationType(eventName string) (FaaSInvocationType, FaaSLocation, bool) {
+	switch eventName {
+	case "httpApi", "http":
+		return FaaSInvocationTypeHTTP, FaaSLocationRegion, true
+	case "websocket":
+		return FaaSInvocationTypeWebsocket, FaaSLocationRegion, true
+	case "schedule":
+		return FaaSInvocationTypeSchedule, FaaSLocationRegion, true
+	case "sns", "stream", "msk", "kafka":
+		return FaaSInvocationTypeTopic, FaaSLocationRegion, true
+	case "sqs", "activemq", "rabbitmq":
+		return FaaSInvocationTypeQueue, FaaSLocationRegion, true
+	case "s3", "alexa", "alexaSkill", "alexaSmartHome", "iot", "iotFleetProvisioning",
+		"cloudwatchEvent", "cloudwatchLog", "cognitoUserPool",
+		"alb", "eventBridge":
+		return FaaSInvocationTypeOther, FaaSLocationRegion, true
+	case "cloudFront":
+		return FaaSInvocationTypeOther, FaaSLocationEdge, true
+	default:
+		return FaaSInvocationTypeUnknown, FaaSLocationUnknown, false
+	}
+}
+
+// NOTE: functions configured in resources as plain CloudFormation, everything else in resources is not a function
+func serverlessResourceFunctions(config map[string]interface{}) map[string]map[string]interface{} {
+	result := make(map[string]map[string]interface{})
+
+	resources, err := JsonResolveMap(config, []string{"resources", "Resources"})
+	if err != nil {
+		return result
+	}
+
+	for logicalId, resource := range resources {
+		resourceType, err := JsonResolveString(resource, []string{"Type"})
+		if err != nil || resourceType != "AWS::Lambda::Function" && resourceType != "AWS::Serverless::Function" {
+			continue
+		}
+		properties, err := JsonResolveMap(resource, []string{"Properties"})
+		if err != nil {
+			properties = map[string]interface{}{}
+		}
+		result[logicalId] = properties
+	}
+	return result
+}
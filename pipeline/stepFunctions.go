@@ -0,0 +1,362 @@
+package main
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+
+	"golang.org/x/exp/maps"
+)
+
+var awsLambdaArnRegexp = regexp.MustCompile(`^arn:[^:]*:lambda:[^:]*:[^:]*:function:([^:]+)`)
+
+// NOTE: the states of an Amazon States Language definition sorted by their names, including the states of Parallel
+// branches and Map iterators, `resolveFunction` maps the Lambda function of a Task state to its name
+func ParseAmazonStatesLanguage(definition interface{}, resolveFunction func(interface{}) string) []RepositoryWorkflowStepData {
+	steps := make([]RepositoryWorkflowStepData, 0)
+
+	states, err := JsonResolveMap(definition, []string{"States"})
+	if err != nil {
+		return steps
+	}
+	names := maps.Keys(states)
+	slices.Sort(names)
+
+	for _, name := range names {
+		state := states[name]
+
+		step := RepositoryWorkflowStepData{Name: name}
+		step.Type, _ = JsonResolveString(state, []string{"Type"})
+		if step.Type == "Task" {
+			if function := amazonStatesTaskFunction(state); function != nil {
+				step.Function = resolveFunction(function)
+			}
+		}
+		steps = append(steps, step)
+
+		branches, _ := JsonResolveArray(state, []string{"Branches"})
+		for _, branch := range branches {
+			steps = append(steps, ParseAmazonStatesLanguage(branch, resolveFunction)...)
+		}
+		// NOTE: Iterator was replaced by ItemProcessor with distributed maps
+		for _, key := range []string{"Iterator", "ItemProcessor"} {
+			if processor, err := JsonResolveMap(state, []string{key}); err == nil {
+				steps = append(steps, ParseAmazonStatesLanguage(processor, resolveFunction)...)
+			}
+		}
+	}
+
+	return steps
+}
+
+// NOTE: Task states invoke a Lambda function either directly by its ARN as `Resource` or through the optimized
+// integration `arn:aws:states:::lambda:invoke` with the function as `FunctionName` parameter, other integrations like
+// `arn:aws:states:::sqs:sendMessage` invoke no function
+func amazonStatesTaskFunction(state interface{}) interface{} {
+	resource, err := JsonResolve(state, []string{"Resource"})
+	if err != nil {
+		return nil
+	}
+	resourceString, ok := resource.(string)
+	if !ok {
+		return resource
+	}
+
+	if integration, ok := strings.CutPrefix(resourceString, "arn:aws:states:::"); ok {
+		if !strings.HasPrefix(integration, "lambda:invoke") {
+			return nil
+		}
+		// NOTE: states using JSONata pass `Arguments` instead of `Parameters`
+		for _, key := range []string{"Parameters", "Arguments"} {
+			if functionName, err := JsonResolve(state, []string{key, "FunctionName"}); err == nil {
+				return functionName
+			}
+		}
+		return nil
+	}
+	return resource
+}
+
+// NOTE: the distinct functions invoked by the steps of a workflow
+func workflowStepFunctions(steps []RepositoryWorkflowStepData) []string {
+	functions := make([]string, 0)
+	for _, step := range steps {
+		if step.Function != "" && !slices.Contains(functions, step.Function) {
+			functions = append(functions, step.Function)
+		}
+	}
+	slices.Sort(functions)
+	return functions
+}
+
+// NOTE: state machines are defined inline with `Definition`, as JSON string with `DefinitionString` or in a separate
+// file of the repository with `DefinitionUri` (SAM), `DefinitionSubstitutions` replace `${Name}` in the definition
+func (template *CloudFormationTemplate) StateMachineDefinition(resource CloudFormationResource, files []TextFile) (interface{}, map[string]interface{}, bool) {
+	substitutions, err := JsonResolveMap(resource.Properties, []string{"DefinitionSubstitutions"})
+	if err != nil {
+		substitutions = make(map[string]interface{})
+	}
+
+	if definition, err := JsonResolveMap(resource.Properties, []string{"Definition"}); err == nil {
+		return definition, substitutions, true
+	}
+
+	if definitionString, ok := resource.Properties["DefinitionString"]; ok {
+		text := ""
+		switch value := definitionString.(type) {
+		case string:
+			text = value
+		case map[string]interface{}:
+			switch {
+			case value["Fn::Sub"] != nil:
+				if sub, ok := value["Fn::Sub"].(string); ok {
+					text = sub
+				} else if sub, ok := value["Fn::Sub"].([]interface{}); ok && len(sub) == 2 {
+					text, _ = sub[0].(string)
+					variables, _ := sub[1].(map[string]interface{})
+					for name, variable := range variables {
+						substitutions[name] = variable
+					}
+				}
+			case value["Fn::Join"] != nil:
+				// NOTE: intrinsic functions between the parts become substitutions, e.g. `{"Fn::GetAtt": ["Hello", "Arn"]}`
+				joined, _ := value["Fn::Join"].([]interface{})
+				if len(joined) != 2 {
+					break
+				}
+				separator, _ := joined[0].(string)
+				parts, _ := joined[1].([]interface{})
+				texts := make([]string, 0, len(parts))
+				for index, part := range parts {
+					if partString, ok := part.(string); ok {
+						texts = append(texts, partString)
+						continue
+					}
+					name := fmt.Sprintf("DefinitionStringPart%d", index)
+					substitutions[name] = part
+					texts = append(texts, fmt.Sprintf("${%s}", name))
+				}
+				text = strings.Join(texts, separator)
+			}
+		}
+		definition, err := LoadJsonFromBytes([]byte(text))
+		if err != nil {
+			return nil, substitutions, false
+		}
+		return definition, substitutions, true
+	}
+
+	if definitionUri, ok := template.ResolveValue(resource.Properties["DefinitionUri"]).(string); ok {
+		definitionPath := path.Join(path.Dir(template.Path), definitionUri)
+		for _, file := range files {
+			if file.Path != definitionPath {
+				continue
+			}
+			if documents := LoadYamlDocuments([]byte(file.Content)); len(documents) == 1 {
+				return documents[0].Json, substitutions, true
+			}
+		}
+	}
+
+	return nil, substitutions, false
+}
+
+// NOTE: the logical ID of the Lambda function of a Task state, referenced with intrinsic functions like
+// `!GetAtt Hello.Arn`, through substitutions like `${HelloArn}` or by an ARN containing the name of the function
+func (template *CloudFormationTemplate) StateMachineFunction(value interface{}, substitutions map[string]interface{}) string {
+	if text, ok := value.(string); ok {
+		if match := cloudFormationSubReferenceRegexp.FindStringSubmatch(text); match != nil {
+			if substitution, ok := substitutions[match[1]]; ok {
+				value = substitution
+			} else {
+				value = map[string]interface{}{"Fn::Sub": text}
+			}
+		}
+	}
+
+	if resource, ok := template.ReferencedResource(value); ok {
+		if resource.Type == "AWS::Lambda::Function" || resource.Type == "AWS::Serverless::Function" {
+			return resource.LogicalId
+		}
+		return ""
+	}
+
+	text, ok := template.ResolveValue(value).(string)
+	if !ok {
+		return ""
+	}
+	match := awsLambdaArnRegexp.FindStringSubmatch(text)
+	if match == nil {
+		return ""
+	}
+	for _, logicalId := range template.LogicalIds() {
+		resource := template.Resources[logicalId]
+		if resource.Type != "AWS::Lambda::Function" && resource.Type != "AWS::Serverless::Function" {
+			continue
+		}
+		if functionName, ok := template.ResolveValue(resource.Properties["FunctionName"]).(string); ok && functionName == match[1] {
+			return logicalId
+		}
+	}
+	return match[1]
+}
+
+// NOTE: the Serverless Framework names the CloudFormation resource of a function e.g. HelloDashworldLambdaFunction
+// for `hello-world`
+func serverlessFunctionLogicalId(functionName string) string {
+	if functionName == "" {
+		return ""
+	}
+	normalized := strings.NewReplacer("-", "Dash", "_", "Underscore").Replace(functionName)
+	return strings.ToUpper(normalized[:1]) + normalized[1:] + "LambdaFunction"
+}
+
+// NOTE: the serverless-step-functions plugin refers to functions with `Fn::GetAtt: [hello, Arn]`, by their logical ID
+// like `Fn::GetAtt: [HelloLambdaFunction, Arn]` or by their ARN ending in `${self:service}-${sls:stage}-hello`
+func serverlessStateMachineFunction(value interface{}, functionNames []string) string {
+	reference := ""
+	switch value := value.(type) {
+	case string:
+		// NOTE: the short form `!GetAtt hello.Arn` is read as string
+		reference, _, _ = strings.Cut(value, ".")
+		if match := awsLambdaArnRegexp.FindStringSubmatch(value); match != nil {
+			reference = match[1]
+		}
+	case map[string]interface{}:
+		if getAtt, ok := value["Fn::GetAtt"].([]interface{}); ok && len(getAtt) > 0 {
+			reference, _ = getAtt[0].(string)
+		} else if getAtt, ok := value["Fn::GetAtt"].(string); ok {
+			reference, _, _ = strings.Cut(getAtt, ".")
+		} else if ref, ok := value["Ref"].(string); ok {
+			reference = ref
+		}
+	}
+	if reference == "" {
+		return ""
+	}
+
+	result := ""
+	for _, functionName := range functionNames {
+		if reference == functionName || reference == serverlessFunctionLogicalId(functionName) {
+			return functionName
+		}
+		if strings.HasSuffix(reference, "-"+functionName) && len(functionName) > len(result) {
+			result = functionName
+		}
+	}
+	if result == "" {
+		return reference
+	}
+	return result
+}
+
+// NOTE: constructs of aws-stepfunctions and aws-stepfunctions-tasks that are no states of a state machine
+var awsCDKStepFunctionsNonStateConstructs = []string{"StateMachine", "StateMachineFragment", "Activity"}
+
+// NOTE: e.g. `new sfn.StateMachine(...)` or `new tasks.LambdaInvoke(...)` with `import * as sfn from
+// "aws-cdk-lib/aws-stepfunctions"`, `import { aws_stepfunctions as sfn } from "aws-cdk-lib"` or the CDK v1 packages
+func awsCDKStepFunctionsConstruct(module *JsModule, call JsCall) (string, bool) {
+	if !call.IsNew {
+		return "", false
+	}
+	for _, source := range []string{"aws-cdk-lib", "@aws-cdk"} {
+		memberPath, ok := module.ImportedMemberPath(call, source)
+		if !ok || len(memberPath) != 2 {
+			continue
+		}
+		switch strings.ReplaceAll(memberPath[0], "_", "-") {
+		case "aws-stepfunctions", "aws-stepfunctions-tasks":
+			return memberPath[1], true
+		}
+	}
+	return "", false
+}
+
+// NOTE: the ID of a construct, e.g. "Hello" for `new lambda.Function(this, "Hello", { ... })`
+func awsCDKConstructId(call JsCall) string {
+	id, _ := call.StringArgument(1)
+	return id
+}
+
+// NOTE: Lambda functions are passed to LambdaInvoke as `lambdaFunction`, they are named by their construct ID if
+// declared in the same module and by their variable otherwise
+func awsCDKStateFunction(module *JsModule, call JsCall) string {
+	options := call.Argument(2)
+	if options == nil || options.Kind != JsValueObject {
+		return ""
+	}
+	function := options.Property("lambdaFunction")
+	if function == nil || function.Kind != JsValueIdentifier {
+		return ""
+	}
+
+	variable := strings.TrimPrefix(function.Text, "this.")
+	for _, declaration := range module.Declarations {
+		if declaration.Name == variable && declaration.Value.Kind == JsValueCall && declaration.Value.Call != nil {
+			if id := awsCDKConstructId(*declaration.Value.Call); id != "" {
+				return id
+			}
+		}
+	}
+	return variable
+}
+
+// NOTE: CDK defines the states of a state machine before chaining them into its definition, so every state belongs to
+// the next state machine of its module, e.g.
+// `const hello = new tasks.LambdaInvoke(this, "Hello", { lambdaFunction: fn })` and
+// `new sfn.StateMachine(this, "Workflow", { definitionBody: sfn.DefinitionBody.fromChainable(hello) })`
+func ParseAWSCDKStateMachines(module *JsModule) []RepositoryWorkflowData {
+	workflows := make([]RepositoryWorkflowData, 0)
+	steps := make([]RepositoryWorkflowStepData, 0)
+	stepLines := make([]int, 0)
+
+	for _, call := range module.Calls {
+		construct, ok := awsCDKStepFunctionsConstruct(module, call)
+		if !ok {
+			continue
+		}
+
+		if construct == "StateMachine" {
+			workflows = append(workflows, RepositoryWorkflowData{
+				Name:           awsCDKConstructId(call),
+				Platform:       FaaSPlatformAWS,
+				Framework:      FaaSFrameworkAWSCDKAndSST,
+				Steps:          make([]RepositoryWorkflowStepData, 0),
+				SourceFilePath: module.Path,
+				SourceFileLine: call.Line,
+			})
+			continue
+		}
+		if slices.Contains(awsCDKStepFunctionsNonStateConstructs, construct) {
+			continue
+		}
+
+		step := RepositoryWorkflowStepData{Name: awsCDKConstructId(call), Type: construct}
+		if construct == "LambdaInvoke" {
+			step.Function = awsCDKStateFunction(module, call)
+		}
+		steps = append(steps, step)
+		stepLines = append(stepLines, call.Line)
+	}
+
+	if len(workflows) == 0 {
+		return workflows
+	}
+
+	for index, step := range steps {
+		workflowIndex := slices.IndexFunc(workflows, func(workflow RepositoryWorkflowData) bool {
+			return workflow.SourceFileLine >= stepLines[index]
+		})
+		if workflowIndex == -1 {
+			workflowIndex = len(workflows) - 1
+		}
+		workflows[workflowIndex].Steps = append(workflows[workflowIndex].Steps, step)
+	}
+	for index := range workflows {
+		workflows[index].Functions = workflowStepFunctions(workflows[index].Steps)
+	}
+
+	return workflows
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// NOTE: every directory of supabase/functions is a function, directories starting with an underscore like `_shared`
+// hold shared code, e.g. `supabase/functions/hello/index.ts`
+var supabaseFunctionFileRegexp = regexp.MustCompile(`(?:^|/)supabase/functions/([^/_.][^/]*)/index\.(?:ts|js|mjs|tsx|jsx)$`)
+
+type SupabaseFunctionConfig struct {
+	Name       string
+	Enabled    bool
+	VerifyJwt  string
+	Entrypoint string
+	Line       int
+}
+
+var supabaseConfigFunctionRegexp = regexp.MustCompile(`(?m)^\s*\[functions\."?([\w-]+)"?\]`)
+
+// NOTE: the `[functions.<name>]` sections of supabase/config.toml, entrypoints are relative to the supabase directory
+func LoadSupabaseConfig(file TextFile) map[string]SupabaseFunctionConfig {
+	configs := make(map[string]SupabaseFunctionConfig)
+
+	configJson, err := LoadJsonFromTomlBytes([]byte(file.Content))
+	if err != nil {
+		return configs
+	}
+	functions, err := JsonResolveMap(configJson, []string{"functions"})
+	if err != nil {
+		return configs
+	}
+
+	for name, function := range functions {
+		config := SupabaseFunctionConfig{Name: name, Enabled: true, Line: -1}
+		if enabled, err := JsonResolve(function, []string{"enabled"}); err == nil && enabled == false {
+			config.Enabled = false
+		}
+		if verifyJwt, err := JsonResolve(function, []string{"verify_jwt"}); err == nil {
+			if verifyJwt, ok := verifyJwt.(bool); ok {
+				config.VerifyJwt = strconv.FormatBool(verifyJwt)
+			}
+		}
+		if entrypoint, err := JsonResolveString(function, []string{"entrypoint"}); err == nil {
+			config.Entrypoint = path.Join(path.Dir(file.Path), entrypoint)
+		}
+		configs[name] = config
+	}
+
+	for _, match := range supabaseConfigFunctionRegexp.FindAllStringSubmatchIndex(file.Content, -1) {
+		name := file.Content[match[2]:match[3]]
+		if config, ok := configs[name]; ok {
+			config.Line = strings.Count(file.Content[:match[0]], "\n") + 1
+			configs[name] = config
+		}
+	}
+
+	return configs
+}
+
+type SupabaseFunctionInvocation struct {
+	Function       string
+	InvocationType FaaSInvocationType
+	Attributes     map[string]string
+	Path           string
+	Line           int
+}
+
+var supabaseFunctionUrlRegexp = regexp.MustCompile(`/functions/v1/([\w-]+)`)
+var supabaseCronScheduleRegexp = regexp.MustCompile(`(?i)\bcron\.schedule\s*\(`)
+var supabaseSqlStringRegexp = regexp.MustCompile(`'((?:[^']|'')*)'`)
+var supabaseCronExpressionRegexp = regexp.MustCompile(`^(?:[\d*/,\-]+(?:\s+[\d*/,\-?LW#A-Za-z]+){4,5}|\d+\s+seconds?|@\w+)$`)
+
+// NOTE: database webhooks are triggers calling `supabase_functions.http_request` with the URL of the function
+var supabaseWebhookRegexp = regexp.MustCompile(`(?is)create\s+(?:or\s+replace\s+)?trigger\s+"?[\w-]+"?\s+(?:after|before)\s+([\w\s,]+?)\s+on\s+([\w".]+)\s+for\s+each\s+\w+\s+execute\s+(?:function|procedure)\s+"?supabase_functions"?\."?http_request"?\s*\(\s*'([^']*)'`)
+
+// NOTE: functions are invoked by pg_cron jobs posting to them, e.g.
+// `select cron.schedule('nightly', '0 0 * * *', $$ select net.http_post(url := '.../functions/v1/hello') $$)`,
+// and by database webhooks of the migrations
+func ParseSupabaseFunctionInvocations(file TextFile) []SupabaseFunctionInvocation {
+	invocations := make([]SupabaseFunctionInvocation, 0)
+
+	for _, match := range supabaseCronScheduleRegexp.FindAllStringIndex(file.Content, -1) {
+		call := balancedParentheses(file.Content[match[1]-1:])
+		functionMatch := supabaseFunctionUrlRegexp.FindStringSubmatch(call)
+		if functionMatch == nil {
+			continue
+		}
+		invocation := SupabaseFunctionInvocation{
+			Function:       functionMatch[1],
+			InvocationType: FaaSInvocationTypeSchedule,
+			Attributes:     make(map[string]string),
+			Path:           file.Path,
+			Line:           strings.Count(file.Content[:match[0]], "\n") + 1,
+		}
+		for _, stringMatch := range supabaseSqlStringRegexp.FindAllStringSubmatch(call, -1) {
+			if supabaseCronExpressionRegexp.MatchString(strings.TrimSpace(stringMatch[1])) {
+				invocation.Attributes["schedule"] = strings.TrimSpace(stringMatch[1])
+				break
+			}
+		}
+		invocations = append(invocations, invocation)
+	}
+
+	for _, match := range supabaseWebhookRegexp.FindAllStringSubmatchIndex(file.Content, -1) {
+		functionMatch := supabaseFunctionUrlRegexp.FindStringSubmatch(file.Content[match[6]:match[7]])
+		if functionMatch == nil {
+			continue
+		}
+		events := strings.Fields(strings.ToLower(file.Content[match[2]:match[3]]))
+		events = slices.DeleteFunc(events, func(event string) bool {
+			return event == "or"
+		})
+		invocations = append(invocations, SupabaseFunctionInvocation{
+			Function:       functionMatch[1],
+			InvocationType: FaaSInvocationTypeOther,
+			Attributes: map[string]string{
+				"table":  strings.ReplaceAll(file.Content[match[4]:match[5]], `"`, ""),
+				"events": strings.Join(events, ","),
+			},
+			Path: file.Path,
+			Line: strings.Count(file.Content[:match[0]], "\n") + 1,
+		})
+	}
+
+	return invocations
+}
@@ -0,0 +1,911 @@
+package main
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+	"github.com/zclconf/go-cty/cty/function"
+	"github.com/zclconf/go-cty/cty/function/stdlib"
+	"golang.org/x/exp/maps"
+)
+
+const terraformMaxLocalsPasses = 4
+
+type TerraformBlock struct {
+	Type string
+	Name string
+	Path string
+	Line int
+	Body *hclsyntax.Body
+}
+
+// NOTE: all .tf files of a directory form one configuration, resources are addressed as `type.name` and module
+// calls as `module.name` like in Terraform itself
+type TerraformConfiguration struct {
+	Directory string
+
+	Resources   map[string]*TerraformBlock
+	ModuleCalls map[string]*TerraformBlock
+	// NOTE: addressed as `type.name` like resources, without the `data.` prefix
+	DataSources map[string]*TerraformBlock
+	Variables   map[string]cty.Value
+	Locals      map[string]hcl.Expression
+
+	// NOTE: number of instances of a local module, i.e. the sum of the multiplicities of all calls to it
+	Multiplicity int
+
+	evalContext *hcl.EvalContext
+}
+
+var terraformFunctions = map[string]function.Function{
+	"coalesce":  stdlib.CoalesceFunc,
+	"concat":    stdlib.ConcatFunc,
+	"distinct":  stdlib.DistinctFunc,
+	"element":   stdlib.ElementFunc,
+	"flatten":   stdlib.FlattenFunc,
+	"format":    stdlib.FormatFunc,
+	"join":      stdlib.JoinFunc,
+	"keys":      stdlib.KeysFunc,
+	"length":    stdlib.LengthFunc,
+	"lookup":    stdlib.LookupFunc,
+	"lower":     stdlib.LowerFunc,
+	"max":       stdlib.MaxFunc,
+	"merge":     stdlib.MergeFunc,
+	"min":       stdlib.MinFunc,
+	"range":     stdlib.RangeFunc,
+	"replace":   stdlib.ReplaceFunc,
+	"split":     stdlib.SplitFunc,
+	"tolist":    stdlib.MakeToFunc(cty.List(cty.DynamicPseudoType)),
+	"tomap":     stdlib.MakeToFunc(cty.Map(cty.DynamicPseudoType)),
+	"toset":     stdlib.MakeToFunc(cty.Set(cty.DynamicPseudoType)),
+	"trimspace": stdlib.TrimSpaceFunc,
+	"upper":     stdlib.UpperFunc,
+	"values":    stdlib.ValuesFunc,
+}
+
+func LoadTerraformConfigurations(files []TextFile) map[string]*TerraformConfiguration {
+	configurations := make(map[string]*TerraformConfiguration)
+
+	for _, file := range files {
+		parsedFile, diagnostics := hclsyntax.ParseConfig([]byte(file.Content), file.Path, hcl.Pos{Line: 1, Column: 1})
+		if diagnostics.HasErrors() {
+			fmt.Printf("failed to parse terraform file %s: %s\n", file.Path, diagnostics.Error())
+			continue
+		}
+		body, ok := parsedFile.Body.(*hclsyntax.Body)
+		if !ok {
+			continue
+		}
+
+		directory := path.Dir(file.Path)
+		configuration, ok := configurations[directory]
+		if !ok {
+			configuration = &TerraformConfiguration{
+				Directory:    directory,
+				Resources:    make(map[string]*TerraformBlock),
+				ModuleCalls:  make(map[string]*TerraformBlock),
+				DataSources:  make(map[string]*TerraformBlock),
+				Variables:    make(map[string]cty.Value),
+				Locals:       make(map[string]hcl.Expression),
+				Multiplicity: 1,
+			}
+			configurations[directory] = configuration
+		}
+
+		for _, block := range body.Blocks {
+			switch block.Type {
+			case "resource":
+				if len(block.Labels) != 2 {
+					continue
+				}
+				configuration.Resources[block.Labels[0]+"."+block.Labels[1]] = &TerraformBlock{
+					Type: block.Labels[0],
+					Name: block.Labels[1],
+					Path: file.Path,
+					Line: block.DefRange().Start.Line,
+					Body: block.Body,
+				}
+			case "data":
+				if len(block.Labels) != 2 {
+					continue
+				}
+				configuration.DataSources[block.Labels[0]+"."+block.Labels[1]] = &TerraformBlock{
+					Type: block.Labels[0],
+					Name: block.Labels[1],
+					Path: file.Path,
+					Line: block.DefRange().Start.Line,
+					Body: block.Body,
+				}
+			case "module":
+				if len(block.Labels) != 1 {
+					continue
+				}
+				configuration.ModuleCalls["module."+block.Labels[0]] = &TerraformBlock{
+					Type: "module",
+					Name: block.Labels[0],
+					Path: file.Path,
+					Line: block.DefRange().Start.Line,
+					Body: block.Body,
+				}
+			case "variable":
+				if len(block.Labels) != 1 {
+					continue
+				}
+				value := cty.DynamicVal
+				if defaultAttribute, ok := block.Body.Attributes["default"]; ok {
+					if defaultValue, diagnostics := defaultAttribute.Expr.Value(nil); !diagnostics.HasErrors() {
+						value = defaultValue
+					}
+				}
+				configuration.Variables[block.Labels[0]] = value
+			case "locals":
+				for name, attribute := range block.Body.Attributes {
+					configuration.Locals[name] = attribute.Expr
+				}
+			}
+		}
+	}
+
+	resolveTerraformModuleCalls(configurations)
+
+	return configurations
+}
+
+// NOTE: local modules get the inputs of their first caller and are instantiated once per call, registry modules are
+// handled like resources by the scanner
+func resolveTerraformModuleCalls(configurations map[string]*TerraformConfiguration) {
+	callers := make(map[string][]*TerraformBlock)
+	callerConfigurations := make(map[*TerraformBlock]*TerraformConfiguration)
+
+	directories := maps.Keys(configurations)
+	slices.Sort(directories)
+
+	for _, directory := range directories {
+		configuration := configurations[directory]
+		for _, address := range configuration.ModuleCallAddresses() {
+			moduleCall := configuration.ModuleCalls[address]
+			source, ok := configuration.String(moduleCall.Body, "source")
+			if !ok || !(strings.HasPrefix(source, "./") || strings.HasPrefix(source, "../")) {
+				continue
+			}
+			moduleDirectory := path.Join(directory, source)
+			if _, ok := configurations[moduleDirectory]; !ok || moduleDirectory == directory {
+				continue
+			}
+			callers[moduleDirectory] = append(callers[moduleDirectory], moduleCall)
+			callerConfigurations[moduleCall] = configuration
+		}
+	}
+
+	for _, directory := range directories {
+		moduleCalls, ok := callers[directory]
+		if !ok {
+			continue
+		}
+		configuration := configurations[directory]
+		for name, attribute := range moduleCalls[0].Body.Attributes {
+			if _, ok := configuration.Variables[name]; !ok {
+				continue
+			}
+			if value, ok := callerConfigurations[moduleCalls[0]].Value(attribute.Expr); ok {
+				configuration.Variables[name] = value
+			}
+		}
+		configuration.evalContext = nil
+	}
+
+	var multiplicity func(directory string, visited map[string]bool) int
+	multiplicity = func(directory string, visited map[string]bool) int {
+		moduleCalls, ok := callers[directory]
+		if !ok || visited[directory] {
+			return 1
+		}
+		visited[directory] = true
+		defer delete(visited, directory)
+
+		result := 0
+		for _, moduleCall := range moduleCalls {
+			callerConfiguration := callerConfigurations[moduleCall]
+			result += multiplicity(callerConfiguration.Directory, visited) * callerConfiguration.BlockMultiplicity(moduleCall)
+		}
+		return result
+	}
+	for _, directory := range directories {
+		configurations[directory].Multiplicity = multiplicity(directory, map[string]bool{})
+	}
+}
+
+func (configuration *TerraformConfiguration) ResourceAddresses() []string {
+	addresses := maps.Keys(configuration.Resources)
+	slices.Sort(addresses)
+	return addresses
+}
+
+func (configuration *TerraformConfiguration) ModuleCallAddresses() []string {
+	addresses := maps.Keys(configuration.ModuleCalls)
+	slices.Sort(addresses)
+	return addresses
+}
+
+func (configuration *TerraformConfiguration) Block(address string) (*TerraformBlock, bool) {
+	if block, ok := configuration.Resources[address]; ok {
+		return block, true
+	}
+	block, ok := configuration.ModuleCalls[address]
+	return block, ok
+}
+
+// NOTE: variables without a default and everything depending on other resources evaluate to unknown values
+func (configuration *TerraformConfiguration) EvalContext() *hcl.EvalContext {
+	if configuration.evalContext != nil {
+		return configuration.evalContext
+	}
+
+	locals := make(map[string]cty.Value, len(configuration.Locals))
+	for name := range configuration.Locals {
+		locals[name] = cty.DynamicVal
+	}
+	// NOTE: paths are evaluated relative to the directory of the configuration
+	configuration.evalContext = &hcl.EvalContext{
+		Variables: map[string]cty.Value{
+			"var":   cty.ObjectVal(configuration.Variables),
+			"local": cty.ObjectVal(locals),
+			"path": cty.ObjectVal(map[string]cty.Value{
+				"module": cty.StringVal("."),
+				"root":   cty.StringVal("."),
+				"cwd":    cty.StringVal("."),
+			}),
+		},
+		Functions: terraformFunctions,
+	}
+
+	// NOTE: locals may refer to each other, so they are evaluated until they do not change anymore
+	for pass := 0; pass < terraformMaxLocalsPasses; pass++ {
+		changed := false
+		for name, expression := range configuration.Locals {
+			value, diagnostics := expression.Value(configuration.evalContext)
+			if diagnostics.HasErrors() || value.RawEquals(locals[name]) {
+				continue
+			}
+			locals[name] = value
+			changed = true
+		}
+		configuration.evalContext.Variables["local"] = cty.ObjectVal(locals)
+		if !changed {
+			break
+		}
+	}
+
+	return configuration.evalContext
+}
+
+func (configuration *TerraformConfiguration) Value(expression hcl.Expression) (cty.Value, bool) {
+	value, diagnostics := expression.Value(configuration.EvalContext())
+	if diagnostics.HasErrors() || value.IsNull() || !value.IsWhollyKnown() {
+		return cty.NilVal, false
+	}
+	return value, true
+}
+
+// NOTE: the path consists of nested blocks followed by an attribute and optionally keys of an object within it,
+// e.g. `build_config.runtime` or `service_config.available_memory` of a module input
+func (configuration *TerraformConfiguration) AttributeValue(body *hclsyntax.Body, attributePath ...string) (cty.Value, bool) {
+	if len(attributePath) == 0 {
+		return cty.NilVal, false
+	}
+
+	if attribute, ok := body.Attributes[attributePath[0]]; ok {
+		value, ok := configuration.Value(attribute.Expr)
+		for _, key := range attributePath[1:] {
+			if !ok || !(value.Type().IsObjectType() || value.Type().IsMapType()) {
+				return cty.NilVal, false
+			}
+			if value.Type().IsObjectType() && !value.Type().HasAttribute(key) {
+				return cty.NilVal, false
+			}
+			if value.Type().IsMapType() && !value.HasIndex(cty.StringVal(key)).True() {
+				return cty.NilVal, false
+			}
+			value = value.GetAttr(key)
+			if value.Type().IsMapType() {
+				value = value.Index(cty.StringVal(key))
+			}
+		}
+		return value, ok && !value.IsNull()
+	}
+
+	for _, block := range body.Blocks {
+		if block.Type == attributePath[0] {
+			return configuration.AttributeValue(block.Body, attributePath[1:]...)
+		}
+	}
+	return cty.NilVal, false
+}
+
+func (configuration *TerraformConfiguration) String(body *hclsyntax.Body, attributePath ...string) (string, bool) {
+	value, ok := configuration.AttributeValue(body, attributePath...)
+	if !ok {
+		return "", false
+	}
+	value, err := convert.Convert(value, cty.String)
+	if err != nil {
+		return "", false
+	}
+	return value.AsString(), true
+}
+
+func (configuration *TerraformConfiguration) Int(body *hclsyntax.Body, attributePath ...string) (int, bool) {
+	value, ok := configuration.AttributeValue(body, attributePath...)
+	if !ok {
+		return 0, false
+	}
+	value, err := convert.Convert(value, cty.Number)
+	if err != nil {
+		return 0, false
+	}
+	valueInt, _ := value.AsBigFloat().Int64()
+	return int(valueInt), true
+}
+
+// NOTE: the first element of a list attribute, e.g. `architectures = ["arm64"]`
+func (configuration *TerraformConfiguration) FirstString(body *hclsyntax.Body, attributePath ...string) (string, bool) {
+	value, ok := configuration.AttributeValue(body, attributePath...)
+	if !ok || !(value.Type().IsListType() || value.Type().IsTupleType()) || value.LengthInt() == 0 {
+		return "", false
+	}
+	value, err := convert.Convert(value.Index(cty.NumberIntVal(0)), cty.String)
+	if err != nil || value.IsNull() {
+		return "", false
+	}
+	return value.AsString(), true
+}
+
+func (configuration *TerraformConfiguration) Bool(body *hclsyntax.Body, attributePath ...string) (bool, bool) {
+	value, ok := configuration.AttributeValue(body, attributePath...)
+	if !ok {
+		return false, false
+	}
+	value, err := convert.Convert(value, cty.Bool)
+	if err != nil {
+		return false, false
+	}
+	return value.True(), true
+}
+
+// NOTE: `count` and `for_each` that cannot be evaluated, e.g. because they depend on other resources, count as one
+func (configuration *TerraformConfiguration) BlockMultiplicity(block *TerraformBlock) int {
+	if count, ok := configuration.Int(block.Body, "count"); ok {
+		return max(count, 0)
+	}
+	if forEach, ok := configuration.AttributeValue(block.Body, "for_each"); ok && forEach.CanIterateElements() {
+		return forEach.LengthInt()
+	}
+	return 1
+}
+
+// NOTE: addresses of the resources and modules an expression refers to, e.g. `aws_lambda_function.a` for
+// `aws_lambda_function.a.arn` or `module.b` for `module.b.lambda_function_arn`
+func (configuration *TerraformConfiguration) ReferencedAddresses(expression hcl.Expression) []string {
+	addresses := make([]string, 0)
+	for _, traversal := range expression.Variables() {
+		if len(traversal) < 2 {
+			continue
+		}
+		attribute, ok := traversal[1].(hcl.TraverseAttr)
+		if !ok {
+			continue
+		}
+		address := traversal.RootName() + "." + attribute.Name
+		if _, ok := configuration.Block(address); ok && !slices.Contains(addresses, address) {
+			addresses = append(addresses, address)
+		}
+	}
+	return addresses
+}
+
+func (configuration *TerraformConfiguration) ReferencedBlock(expression hcl.Expression, types ...string) (*TerraformBlock, bool) {
+	for _, address := range configuration.ReferencedAddresses(expression) {
+		block, _ := configuration.Block(address)
+		if slices.Contains(types, block.Type) {
+			return block, true
+		}
+	}
+	return nil, false
+}
+
+func (configuration *TerraformConfiguration) NestedBlocks(body *hclsyntax.Body, types ...string) []*hclsyntax.Block {
+	blocks := make([]*hclsyntax.Block, 0)
+	for _, block := range body.Blocks {
+		if slices.Contains(types, block.Type) {
+			blocks = append(blocks, block)
+		}
+	}
+	return blocks
+}
+
+type terraformFunctionType struct {
+	platform       FaaSPlatform
+	location       FaaSLocation
+	invocationType FaaSInvocationType
+	name           []string
+	runtime        []string
+	memoryMB       []string
+	timeoutSeconds []string
+	handler        []string
+	// NOTE: the architecture is the first element of a list, provisioned concurrency of AWS Lambda functions is
+	//       configured by aws_lambda_provisioned_concurrency_config resources instead
+	architecture           []string
+	provisionedConcurrency []string
+	reservedConcurrency    []string
+	minInstances           []string
+}
+
+var terraformFunctionResources = map[string]terraformFunctionType{
+	"aws_lambda_function":             {FaaSPlatformAWS, FaaSLocationRegion, FaaSInvocationTypeUnknown, []string{"function_name"}, []string{"runtime"}, []string{"memory_size"}, []string{"timeout"}, []string{"handler"}, []string{"architectures"}, nil, []string{"reserved_concurrent_executions"}, nil},
+	"aws_cloudfront_function":         {FaaSPlatformAWS, FaaSLocationEdge, FaaSInvocationTypeHTTP, []string{"name"}, []string{"runtime"}, nil, nil, nil, nil, nil, nil, nil},
+	"google_cloudfunctions_function":  {FaaSPlatformGCP, FaaSLocationRegion, FaaSInvocationTypeUnknown, []string{"name"}, []string{"runtime"}, []string{"available_memory_mb"}, []string{"timeout"}, []string{"entry_point"}, nil, nil, nil, []string{"min_instances"}},
+	"google_cloudfunctions2_function": {FaaSPlatformGCP, FaaSLocationRegion, FaaSInvocationTypeHTTP, []string{"name"}, []string{"build_config", "runtime"}, []string{"service_config", "available_memory"}, []string{"service_config", "timeout_seconds"}, []string{"build_config", "entry_point"}, nil, nil, nil, []string{"service_config", "min_instance_count"}},
+	"google_cloud_run_service":        {FaaSPlatformGCP, FaaSLocationRegion, FaaSInvocationTypeHTTP, []string{"name"}, nil, []string{"template", "spec", "containers", "resources", "limits", "memory"}, []string{"template", "spec", "timeout_seconds"}, nil, nil, nil, nil, []string{"template", "metadata", "annotations", "autoscaling.knative.dev/minScale"}},
+	"google_cloud_run_v2_service":     {FaaSPlatformGCP, FaaSLocationRegion, FaaSInvocationTypeHTTP, []string{"name"}, nil, []string{"template", "containers", "resources", "limits", "memory"}, []string{"template", "timeout"}, nil, nil, nil, nil, []string{"template", "scaling", "min_instance_count"}},
+	"google_cloud_run_v2_job":         {FaaSPlatformGCP, FaaSLocationRegion, FaaSInvocationTypeOther, []string{"name"}, nil, []string{"template", "template", "containers", "resources", "limits", "memory"}, []string{"template", "template", "timeout"}, nil, nil, nil, nil, nil},
+	"azurerm_function_app":            {FaaSPlatformAzure, FaaSLocationRegion, FaaSInvocationTypeUnknown, []string{"name"}, nil, nil, nil, nil, nil, nil, nil, nil},
+	"azurerm_linux_function_app":      {FaaSPlatformAzure, FaaSLocationRegion, FaaSInvocationTypeUnknown, []string{"name"}, nil, nil, nil, nil, nil, []string{"site_config", "pre_warmed_instance_count"}, nil, []string{"site_config", "elastic_instance_minimum"}},
+	"azurerm_windows_function_app":    {FaaSPlatformAzure, FaaSLocationRegion, FaaSInvocationTypeUnknown, []string{"name"}, nil, nil, nil, nil, nil, []string{"site_config", "pre_warmed_instance_count"}, nil, []string{"site_config", "elastic_instance_minimum"}},
+	"ibm_function_action":             {FaaSPlatformIBM, FaaSLocationRegion, FaaSInvocationTypeUnknown, []string{"name"}, []string{"exec", "kind"}, []string{"limits", "memory"}, nil, nil, nil, nil, nil, nil},
+	"oci_functions_function":          {FaaSPlatformOracle, FaaSLocationRegion, FaaSInvocationTypeUnknown, []string{"display_name"}, nil, []string{"memory_in_mbs"}, []string{"timeout_in_seconds"}, nil, nil, []string{"provisioned_concurrency_config", "count"}, nil, nil},
+	"alicloud_fc_function":            {FaaSPlatformAlibaba, FaaSLocationRegion, FaaSInvocationTypeUnknown, []string{"name"}, []string{"runtime"}, []string{"memory_size"}, []string{"timeout"}, []string{"handler"}, nil, nil, nil, nil},
+}
+
+// NOTE: registry modules are matched by their source without the registry host and version
+var terraformFunctionModules = map[string]terraformFunctionType{
+	"terraform-aws-modules/lambda/aws":               {FaaSPlatformAWS, FaaSLocationRegion, FaaSInvocationTypeUnknown, []string{"function_name"}, []string{"runtime"}, []string{"memory_size"}, []string{"timeout"}, []string{"handler"}, []string{"architectures"}, []string{"provisioned_concurrent_executions"}, []string{"reserved_concurrent_executions"}, nil},
+	"GoogleCloudPlatform/cloud-functions/google":     {FaaSPlatformGCP, FaaSLocationRegion, FaaSInvocationTypeHTTP, []string{"function_name"}, []string{"runtime"}, []string{"service_config", "available_memory"}, []string{"service_config", "timeout_seconds"}, []string{"entrypoint"}, nil, nil, nil, []string{"service_config", "min_instance_count"}},
+	"terraform-google-modules/event-function/google": {FaaSPlatformGCP, FaaSLocationRegion, FaaSInvocationTypeOther, []string{"name"}, []string{"runtime"}, []string{"available_memory_mb"}, []string{"timeout_s"}, []string{"entry_point"}, nil, nil, nil, nil},
+}
+
+var terraformModuleSourceRegexp = regexp.MustCompile(`^(?:registry\.terraform\.io/)?([^/]+/[^/]+/[^/]+?)(?://.*)?$`)
+
+// NOTE: returns the function type of a resource or a call of a known registry module
+func (configuration *TerraformConfiguration) FunctionType(block *TerraformBlock) (terraformFunctionType, bool) {
+	if block.Type != "module" {
+		functionType, ok := terraformFunctionResources[block.Type]
+		return functionType, ok
+	}
+
+	source, ok := configuration.String(block.Body, "source")
+	if !ok {
+		return terraformFunctionType{}, false
+	}
+	match := terraformModuleSourceRegexp.FindStringSubmatch(source)
+	if match == nil {
+		return terraformFunctionType{}, false
+	}
+	functionType, ok := terraformFunctionModules[match[1]]
+	if !ok {
+		return terraformFunctionType{}, false
+	}
+
+	for _, flag := range []string{"create", "create_function"} {
+		if create, ok := configuration.Bool(block.Body, flag); ok && !create {
+			return terraformFunctionType{}, false
+		}
+	}
+	return functionType, true
+}
+
+var terraformEventSourceInvocationTypes = map[string]FaaSInvocationType{
+	"aws_sqs_queue":              FaaSInvocationTypeQueue,
+	"aws_mq_broker":              FaaSInvocationTypeQueue,
+	"aws_kinesis_stream":         FaaSInvocationTypeTopic,
+	"aws_msk_cluster":            FaaSInvocationTypeTopic,
+	"aws_msk_serverless_cluster": FaaSInvocationTypeTopic,
+	"aws_sns_topic":              FaaSInvocationTypeTopic,
+	"aws_dynamodb_table":         FaaSInvocationTypeOther,
+	"aws_docdb_cluster":          FaaSInvocationTypeOther,
+	"aws_s3_bucket":              FaaSInvocationTypeOther,
+	"aws_apigatewayv2_api":       FaaSInvocationTypeHTTP,
+	"aws_api_gateway_rest_api":   FaaSInvocationTypeHTTP,
+}
+
+func (configuration *TerraformConfiguration) eventSourceInvocationType(expression hcl.Expression) FaaSInvocationType {
+	if block, ok := configuration.ReferencedBlock(expression, "aws_cloudwatch_event_rule"); ok {
+		return configuration.eventRuleInvocationType(block)
+	}
+	for _, address := range configuration.ReferencedAddresses(expression) {
+		block, _ := configuration.Block(address)
+		if invocationType, ok := terraformEventSourceInvocationTypes[block.Type]; ok {
+			return invocationType
+		}
+	}
+	if arn, ok := configuration.Value(expression); ok && arn.Type() == cty.String {
+		return awsArnInvocationType(arn.AsString())
+	}
+	return FaaSInvocationTypeUnknown
+}
+
+func (configuration *TerraformConfiguration) eventRuleInvocationType(rule *TerraformBlock) FaaSInvocationType {
+	if _, ok := rule.Body.Attributes["schedule_expression"]; ok {
+		return FaaSInvocationTypeSchedule
+	}
+	return FaaSInvocationTypeOther
+}
+
+// NOTE: functions are referred to by expressions like `aws_lambda_function.a.arn`, `aws_lambda_alias.b.arn`,
+// `module.c.lambda_function_name` or by their plain name
+func (configuration *TerraformConfiguration) ReferencedFunction(expression hcl.Expression) (string, bool) {
+	for _, address := range configuration.ReferencedAddresses(expression) {
+		block, _ := configuration.Block(address)
+		if block.Type == "aws_lambda_alias" {
+			if functionName, ok := block.Body.Attributes["function_name"]; ok {
+				return configuration.ReferencedFunction(functionName.Expr)
+			}
+		}
+		if _, ok := configuration.FunctionType(block); ok {
+			return address, true
+		}
+	}
+
+	name, ok := configuration.Value(expression)
+	if !ok || name.Type() != cty.String {
+		return "", false
+	}
+	for _, address := range slices.Concat(configuration.ResourceAddresses(), configuration.ModuleCallAddresses()) {
+		block, _ := configuration.Block(address)
+		functionType, ok := configuration.FunctionType(block)
+		if !ok {
+			continue
+		}
+		if functionName, ok := configuration.String(block.Body, functionType.name...); ok && functionName == name.AsString() {
+			return address, true
+		}
+	}
+	return "", false
+}
+
+// NOTE: triggers are separate resources pointing to the function, except for the inputs of registry modules and the
+// event triggers of GCP functions
+func (configuration *TerraformConfiguration) FunctionTriggers() map[string]FaaSInvocationType {
+	result := make(map[string]FaaSInvocationType)
+
+	setTrigger := func(expression hcl.Expression, invocationType FaaSInvocationType) {
+		if expression == nil || invocationType == FaaSInvocationTypeUnknown {
+			return
+		}
+		address, ok := configuration.ReferencedFunction(expression)
+		if !ok || result[address] == FaaSInvocationTypeHTTP {
+			return
+		}
+		result[address] = invocationType
+	}
+	attributeExpression := func(body *hclsyntax.Body, name string) hcl.Expression {
+		if attribute, ok := body.Attributes[name]; ok {
+			return attribute.Expr
+		}
+		return nil
+	}
+	nestedAttributeExpressions := func(body *hclsyntax.Body, blockType string, name string) []hcl.Expression {
+		expressions := make([]hcl.Expression, 0)
+		for _, block := range configuration.NestedBlocks(body, blockType) {
+			if expression := attributeExpression(block.Body, name); expression != nil {
+				expressions = append(expressions, expression)
+			}
+		}
+		return expressions
+	}
+
+	for _, address := range configuration.ResourceAddresses() {
+		resource := configuration.Resources[address]
+		body := resource.Body
+
+		switch resource.Type {
+		case "aws_lambda_event_source_mapping":
+			invocationType := FaaSInvocationTypeUnknown
+			if eventSourceArn := attributeExpression(body, "event_source_arn"); eventSourceArn != nil {
+				invocationType = configuration.eventSourceInvocationType(eventSourceArn)
+			}
+			if len(configuration.NestedBlocks(body, "self_managed_event_source", "amazon_managed_kafka_event_source_config")) > 0 {
+				invocationType = FaaSInvocationTypeTopic
+			}
+			setTrigger(attributeExpression(body, "function_name"), invocationType)
+		case "aws_lambda_permission":
+			principal, _ := configuration.String(body, "principal")
+			invocationType := awsPrincipalInvocationTypes[principal]
+			if principal == "events.amazonaws.com" {
+				invocationType = FaaSInvocationTypeOther
+				if sourceArn := attributeExpression(body, "source_arn"); sourceArn != nil {
+					if rule, ok := configuration.ReferencedBlock(sourceArn, "aws_cloudwatch_event_rule"); ok {
+						invocationType = configuration.eventRuleInvocationType(rule)
+					}
+				}
+			}
+			setTrigger(attributeExpression(body, "function_name"), invocationType)
+		case "aws_lambda_function_url":
+			setTrigger(attributeExpression(body, "function_name"), FaaSInvocationTypeHTTP)
+		case "aws_apigatewayv2_integration":
+			setTrigger(attributeExpression(body, "integration_uri"), FaaSInvocationTypeHTTP)
+		case "aws_api_gateway_integration":
+			setTrigger(attributeExpression(body, "uri"), FaaSInvocationTypeHTTP)
+		case "aws_lb_target_group_attachment":
+			setTrigger(attributeExpression(body, "target_id"), FaaSInvocationTypeHTTP)
+		case "aws_cloudwatch_event_target":
+			invocationType := FaaSInvocationTypeOther
+			if ruleExpression := attributeExpression(body, "rule"); ruleExpression != nil {
+				if rule, ok := configuration.ReferencedBlock(ruleExpression, "aws_cloudwatch_event_rule"); ok {
+					invocationType = configuration.eventRuleInvocationType(rule)
+				}
+			}
+			setTrigger(attributeExpression(body, "arn"), invocationType)
+		case "aws_scheduler_schedule":
+			for _, arn := range nestedAttributeExpressions(body, "target", "arn") {
+				setTrigger(arn, FaaSInvocationTypeSchedule)
+			}
+		case "aws_sns_topic_subscription":
+			if protocol, _ := configuration.String(body, "protocol"); protocol == "lambda" {
+				setTrigger(attributeExpression(body, "endpoint"), FaaSInvocationTypeTopic)
+			}
+		case "aws_s3_bucket_notification":
+			for _, arn := range nestedAttributeExpressions(body, "lambda_function", "lambda_function_arn") {
+				setTrigger(arn, FaaSInvocationTypeOther)
+			}
+		case "aws_appsync_datasource":
+			for _, lambdaConfig := range configuration.NestedBlocks(body, "lambda_config") {
+				setTrigger(attributeExpression(lambdaConfig.Body, "function_arn"), FaaSInvocationTypeGraphQL)
+			}
+		case "google_cloud_scheduler_job":
+			for _, uri := range nestedAttributeExpressions(body, "http_target", "uri") {
+				setTrigger(uri, FaaSInvocationTypeSchedule)
+			}
+		case "google_pubsub_subscription":
+			for _, pushEndpoint := range nestedAttributeExpressions(body, "push_config", "push_endpoint") {
+				setTrigger(pushEndpoint, FaaSInvocationTypeTopic)
+			}
+		case "google_eventarc_trigger":
+			invocationType := FaaSInvocationTypeOther
+			for _, matchingCriteria := range configuration.NestedBlocks(body, "matching_criteria") {
+				if attribute, _ := configuration.String(matchingCriteria.Body, "attribute"); attribute != "type" {
+					continue
+				}
+				if eventType, ok := configuration.String(matchingCriteria.Body, "value"); ok {
+					invocationType = gcpEventTypeInvocationType(eventType)
+				}
+			}
+			for _, destination := range configuration.NestedBlocks(body, "destination") {
+				for _, service := range nestedAttributeExpressions(destination.Body, "cloud_run_service", "service") {
+					setTrigger(service, invocationType)
+				}
+				setTrigger(attributeExpression(destination.Body, "cloud_function"), invocationType)
+			}
+		case "google_cloudfunctions_function":
+			if triggerHttp, ok := configuration.Bool(body, "trigger_http"); ok && triggerHttp {
+				result[address] = FaaSInvocationTypeHTTP
+			} else if eventType, ok := configuration.String(body, "event_trigger", "event_type"); ok {
+				result[address] = gcpEventTypeInvocationType(eventType)
+			} else if len(configuration.NestedBlocks(body, "event_trigger")) > 0 {
+				result[address] = FaaSInvocationTypeOther
+			}
+		case "google_cloudfunctions2_function":
+			if eventType, ok := configuration.String(body, "event_trigger", "event_type"); ok {
+				result[address] = gcpEventTypeInvocationType(eventType)
+			} else if len(configuration.NestedBlocks(body, "event_trigger")) > 0 {
+				result[address] = FaaSInvocationTypeOther
+			}
+		}
+	}
+
+	for _, address := range configuration.ModuleCallAddresses() {
+		moduleCall := configuration.ModuleCalls[address]
+		functionType, ok := configuration.FunctionType(moduleCall)
+		if !ok {
+			continue
+		}
+		body := moduleCall.Body
+
+		switch functionType.platform {
+		case FaaSPlatformAWS:
+			if createLambdaFunctionUrl, ok := configuration.Bool(body, "create_lambda_function_url"); ok && createLambdaFunctionUrl {
+				result[address] = FaaSInvocationTypeHTTP
+				continue
+			}
+			// NOTE: both inputs are maps of objects, which usually refer to other resources and thus are walked
+			//       syntactically instead of being evaluated
+			for _, trigger := range terraformObjectItems(attributeExpression(body, "allowed_triggers")) {
+				service, _ := configuration.Value(trigger["service"])
+				principal, _ := configuration.Value(trigger["principal"])
+				invocationType := FaaSInvocationTypeUnknown
+				if service.Type() == cty.String {
+					invocationType = awsPrincipalInvocationTypes[service.AsString()+".amazonaws.com"]
+					if service.AsString() == "events" || service.AsString() == "scheduler" {
+						invocationType = FaaSInvocationTypeSchedule
+						if sourceArn, ok := trigger["source_arn"]; ok {
+							invocationType = configuration.eventSourceInvocationType(sourceArn)
+						}
+					}
+				} else if principal.Type() == cty.String {
+					invocationType = awsPrincipalInvocationTypes[principal.AsString()]
+				}
+				if invocationType != FaaSInvocationTypeUnknown && result[address] != FaaSInvocationTypeHTTP {
+					result[address] = invocationType
+				}
+			}
+			for _, mapping := range terraformObjectItems(attributeExpression(body, "event_source_mapping")) {
+				invocationType := FaaSInvocationTypeUnknown
+				if eventSourceArn, ok := mapping["event_source_arn"]; ok {
+					invocationType = configuration.eventSourceInvocationType(eventSourceArn)
+				}
+				if _, ok := mapping["self_managed_event_source"]; ok {
+					invocationType = FaaSInvocationTypeTopic
+				}
+				if invocationType != FaaSInvocationTypeUnknown && result[address] != FaaSInvocationTypeHTTP {
+					result[address] = invocationType
+				}
+			}
+		case FaaSPlatformGCP:
+			if eventType, ok := configuration.String(body, "event_trigger", "event_type"); ok {
+				result[address] = gcpEventTypeInvocationType(eventType)
+			}
+		}
+	}
+
+	return result
+}
+
+// NOTE: the directory the code of a function is packaged from, either configured directly like the `source_path` of
+// the Lambda module or by an archive, e.g. `filename = data.archive_file.lambda.output_path`
+func (configuration *TerraformConfiguration) FunctionCodeDirectory(body *hclsyntax.Body) (string, bool) {
+	if sourcePath, ok := configuration.String(body, "source_path"); ok {
+		if path.Ext(sourcePath) != "" {
+			sourcePath = path.Dir(sourcePath)
+		}
+		return sourcePath, true
+	}
+
+	for _, attribute := range []string{"filename", "source_archive_file"} {
+		expression, ok := body.Attributes[attribute]
+		if !ok {
+			continue
+		}
+		for _, traversal := range expression.Expr.Variables() {
+			if traversal.RootName() != "data" || len(traversal) < 3 {
+				continue
+			}
+			dataType, ok1 := traversal[1].(hcl.TraverseAttr)
+			dataName, ok2 := traversal[2].(hcl.TraverseAttr)
+			if !ok1 || !ok2 || dataType.Name != "archive_file" {
+				continue
+			}
+			archive, ok := configuration.DataSources[dataType.Name+"."+dataName.Name]
+			if !ok {
+				continue
+			}
+			if sourceDirectory, ok := configuration.String(archive.Body, "source_dir"); ok {
+				return sourceDirectory, true
+			}
+			if sourceFile, ok := configuration.String(archive.Body, "source_file"); ok {
+				return path.Dir(sourceFile), true
+			}
+		}
+	}
+	return "", false
+}
+
+// NOTE: provisioned concurrency is configured for a published version or alias of a function, e.g.
+// `resource "aws_lambda_provisioned_concurrency_config" "a" { function_name = aws_lambda_function.a.function_name }`
+func (configuration *TerraformConfiguration) FunctionProvisionedConcurrency(functionAddress string) (int, bool) {
+	for _, address := range configuration.ResourceAddresses() {
+		resource := configuration.Resources[address]
+		if resource.Type != "aws_lambda_provisioned_concurrency_config" {
+			continue
+		}
+		functionName, ok := resource.Body.Attributes["function_name"]
+		if !ok {
+			continue
+		}
+		if function, ok := configuration.ReferencedFunction(functionName.Expr); !ok || function != functionAddress {
+			continue
+		}
+		if provisionedConcurrency, ok := configuration.Int(resource.Body, "provisioned_concurrent_executions"); ok {
+			return provisionedConcurrency, true
+		}
+	}
+	return 0, false
+}
+
+// NOTE: Lambda@Edge functions are attached to CloudFront distributions by their qualified ARN
+func (configuration *TerraformConfiguration) EdgeFunctions() map[string]bool {
+	result := make(map[string]bool)
+
+	for _, address := range configuration.ResourceAddresses() {
+		resource := configuration.Resources[address]
+		if resource.Type != "aws_cloudfront_distribution" {
+			continue
+		}
+		for _, cacheBehavior := range configuration.NestedBlocks(resource.Body, "default_cache_behavior", "ordered_cache_behavior") {
+			for _, lambdaFunctionAssociation := range configuration.NestedBlocks(cacheBehavior.Body, "lambda_function_association") {
+				lambdaArn, ok := lambdaFunctionAssociation.Body.Attributes["lambda_arn"]
+				if !ok {
+					continue
+				}
+				if function, ok := configuration.ReferencedFunction(lambdaArn.Expr); ok {
+					result[function] = true
+				}
+			}
+		}
+	}
+
+	for _, address := range configuration.ModuleCallAddresses() {
+		moduleCall := configuration.ModuleCalls[address]
+		if functionType, ok := configuration.FunctionType(moduleCall); ok && functionType.platform == FaaSPlatformAWS {
+			if lambdaAtEdge, ok := configuration.Bool(moduleCall.Body, "lambda_at_edge"); ok && lambdaAtEdge {
+				result[address] = true
+			}
+		}
+	}
+
+	return result
+}
+
+// NOTE: the attributes of the objects in a map or list literal, e.g. `{ a = { service = "sqs" } }`
+func terraformObjectItems(expression hcl.Expression) []map[string]hcl.Expression {
+	result := make([]map[string]hcl.Expression, 0)
+	if expression == nil {
+		return result
+	}
+
+	values := make([]hcl.Expression, 0)
+	if items, diagnostics := hcl.ExprMap(expression); !diagnostics.HasErrors() {
+		for _, item := range items {
+			values = append(values, item.Value)
+		}
+	} else if elements, diagnostics := hcl.ExprList(expression); !diagnostics.HasErrors() {
+		values = elements
+	}
+
+	for _, value := range values {
+		attributes, diagnostics := hcl.ExprMap(value)
+		if diagnostics.HasErrors() {
+			continue
+		}
+		item := make(map[string]hcl.Expression, len(attributes))
+		for _, attribute := range attributes {
+			if key := hcl.ExprAsKeyword(attribute.Key); key != "" {
+				item[key] = attribute.Value
+			} else if keyValue, diagnostics := attribute.Key.Value(nil); !diagnostics.HasErrors() && keyValue.Type() == cty.String {
+				item[keyValue.AsString()] = attribute.Value
+			}
+		}
+		result = append(result, item)
+	}
+	return result
+}
+
+var memorySizeRegexp = regexp.MustCompile(`^(\d+(?:\.\d+)?)\s*([KMGT]i?)?B?$`)
+
+// NOTE: parses memory sizes like `256M`, `512Mi` or `1Gi`, plain numbers are taken as megabytes
+func ParseMemorySizeMB(memorySize string) (int, bool) {
+	match := memorySizeRegexp.FindStringSubmatch(strings.TrimSpace(memorySize))
+	if match == nil {
+		return 0, false
+	}
+	value, err := strconv.ParseFloat(match[1], 64)
+	if err != nil {
+		return 0, false
+	}
+
+	switch match[2] {
+	case "K", "Ki":
+		value /= 1024
+	case "G", "Gi":
+		value *= 1024
+	case "T", "Ti":
+		value *= 1024 * 1024
+	}
+	return int(value), true
+}
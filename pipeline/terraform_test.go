@@ -0,0 +1,91 @@
+package main
+
+import "testing"
+
+func TestScanTerraformLinksTriggersToFunctions(t *testing.T) {
+	findings := scanTestRepository(t, "terraform", map[string]string{
+		"infra/main.tf": `
+variable "memory_size" {
+  default = 512
+}
+
+resource "aws_lambda_function" "api" {
+  function_name = "api"
+  handler       = "index.handler"
+  runtime       = "nodejs20.x"
+  memory_size   = var.memory_size
+  timeout       = 30
+}
+
+resource "aws_lambda_function_url" "api" {
+  function_name = aws_lambda_function.api.function_name
+}
+
+resource "aws_lambda_function" "worker" {
+  function_name = "worker"
+  handler       = "worker.handler"
+  runtime       = "nodejs20.x"
+}
+
+resource "aws_sqs_queue" "jobs" {
+  name = "jobs"
+}
+
+resource "aws_lambda_event_source_mapping" "jobs" {
+  event_source_arn = aws_sqs_queue.jobs.arn
+  function_name    = aws_lambda_function.worker.arn
+}
+`,
+		"infra/schedule.tf": `
+resource "aws_lambda_function" "cleanup" {
+  function_name = "cleanup"
+  handler       = "cleanup.handler"
+  runtime       = "python3.12"
+}
+
+resource "aws_cloudwatch_event_rule" "nightly" {
+  schedule_expression = "cron(0 3 * * ? *)"
+}
+
+resource "aws_cloudwatch_event_target" "nightly" {
+  rule = aws_cloudwatch_event_rule.nightly.name
+  arn  = aws_lambda_function.cleanup.arn
+}
+`,
+	})
+
+	if !findings.UsedFrameworks[FaaSFrameworkTerraform] || !findings.UsedPlatforms[FaaSPlatformAWS] {
+		t.Errorf("expected Terraform and AWS to be used, got %v and %v", findings.UsedFrameworks, findings.UsedPlatforms)
+	}
+
+	tests := []struct {
+		name           string
+		invocationType FaaSInvocationType
+		runtime        string
+		memoryMB       int
+		timeoutSeconds int
+	}{
+		{"api", FaaSInvocationTypeHTTP, "nodejs20.x", 512, 30},
+		{"worker", FaaSInvocationTypeQueue, "nodejs20.x", -1, -1},
+		{"cleanup", FaaSInvocationTypeSchedule, "python3.12", -1, -1},
+	}
+
+	functions := testFunctionsByName(t, findings)
+	if len(functions) != len(tests) {
+		t.Errorf("expected %d functions, got %d", len(tests), len(functions))
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			function, ok := functions[test.name]
+			if !ok {
+				t.Fatalf("function %s was not found", test.name)
+			}
+			if function.InvocationType != test.invocationType {
+				t.Errorf("expected invocation type %s, got %s", test.invocationType, function.InvocationType)
+			}
+			if function.Runtime != test.runtime || function.MemoryMB != test.memoryMB || function.TimeoutSeconds != test.timeoutSeconds {
+				t.Errorf("expected %s with %d MB and %d s, got %s with %d MB and %d s", test.runtime, test.memoryMB, test.timeoutSeconds, function.Runtime, function.MemoryMB, function.TimeoutSeconds)
+			}
+		})
+	}
+}
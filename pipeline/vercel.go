@@ -0,0 +1,195 @@
+package main
+
+import (
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+)
+
+type VercelFunctionConfig struct {
+	Pattern        string
+	MemoryMB       int
+	TimeoutSeconds int
+	Runtime        string
+	Regions        []string
+}
+
+type VercelCron struct {
+	Path     string
+	Schedule string
+}
+
+type VercelConfig struct {
+	// NOTE: the directory of vercel.json, all paths of the configuration are relative to it
+	Directory string
+	Regions   []string
+	Functions []VercelFunctionConfig
+	Crons     []VercelCron
+}
+
+func LoadVercelConfig(file TextFile) (VercelConfig, error) {
+	config := VercelConfig{Directory: path.Dir(file.Path)}
+
+	vercelJson, err := LoadJsonFromBytes([]byte(file.Content))
+	if err != nil {
+		return config, err
+	}
+
+	config.Regions = jsonStrings(vercelJson, []string{"regions"})
+
+	if functions, err := JsonResolveMap(vercelJson, []string{"functions"}); err == nil {
+		patterns := make([]string, 0, len(functions))
+		for pattern := range functions {
+			patterns = append(patterns, pattern)
+		}
+		slices.Sort(patterns)
+
+		for _, pattern := range patterns {
+			functionConfig := VercelFunctionConfig{Pattern: pattern, MemoryMB: -1, TimeoutSeconds: -1}
+			if memory, err := JsonResolveFloat64(functions[pattern], []string{"memory"}); err == nil {
+				functionConfig.MemoryMB = int(memory)
+			}
+			if maxDuration, err := JsonResolveFloat64(functions[pattern], []string{"maxDuration"}); err == nil {
+				functionConfig.TimeoutSeconds = int(maxDuration)
+			}
+			functionConfig.Runtime, _ = JsonResolveString(functions[pattern], []string{"runtime"})
+			functionConfig.Regions = jsonStrings(functions[pattern], []string{"regions"})
+			config.Functions = append(config.Functions, functionConfig)
+		}
+	}
+
+	if crons, err := JsonResolveArray(vercelJson, []string{"crons"}); err == nil {
+		for _, cron := range crons {
+			cronPath, err := JsonResolveString(cron, []string{"path"})
+			if err != nil {
+				continue
+			}
+			schedule, _ := JsonResolveString(cron, []string{"schedule"})
+			cronPath, _, _ = strings.Cut(cronPath, "?")
+			config.Crons = append(config.Crons, VercelCron{Path: cronPath, Schedule: schedule})
+		}
+	}
+
+	return config, nil
+}
+
+func jsonStrings(obj interface{}, path []string) []string {
+	if value, err := JsonResolveString(obj, path); err == nil {
+		return []string{value}
+	}
+	values, err := JsonResolveArray(obj, path)
+	if err != nil {
+		return nil
+	}
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		if value, ok := value.(string); ok {
+			result = append(result, value)
+		}
+	}
+	return result
+}
+
+// NOTE: the keys of `functions` are globs relative to the project, e.g. `api/**/*.ts` or `app/api/*/route.ts`
+func (config VercelConfig) FunctionConfig(filePath string) (VercelFunctionConfig, bool) {
+	relativePath := strings.TrimPrefix(strings.TrimPrefix(filePath, config.Directory), "/")
+	for _, functionConfig := range config.Functions {
+		if matches, err := doublestar.Match(strings.TrimPrefix(functionConfig.Pattern, "/"), relativePath); err == nil && matches {
+			return functionConfig, true
+		}
+	}
+	return VercelFunctionConfig{}, false
+}
+
+func (config VercelConfig) CronSchedules(route string) []string {
+	schedules := make([]string, 0)
+	for _, cron := range config.Crons {
+		if cron.Path == route {
+			schedules = append(schedules, cron.Schedule)
+		}
+	}
+	return schedules
+}
+
+// NOTE: API routes of the Pages Router and of plain Vercel functions, the App Router is handled separately as only
+// its `route` files are functions
+var vercelApiDirectories = []string{"api", "pages/api", "src/pages/api"}
+var vercelAppDirectories = []string{"app", "src/app"}
+
+var vercelRouteFileRegexp = regexp.MustCompile(`^route\.(?:js|jsx|mjs|ts|tsx|mts|cts)$`)
+var vercelMiddlewareFileRegexp = regexp.MustCompile(`^(?:src/)?middleware\.(?:js|mjs|ts|mts)$`)
+
+// NOTE: route groups like `(marketing)` and parallel routes like `@modal` are not part of the URL
+func vercelRoute(routeDirectory string, filePath string) string {
+	relativePath := strings.TrimPrefix(filePath, routeDirectory)
+	relativePath = strings.TrimSuffix(relativePath, path.Ext(relativePath))
+
+	segments := make([]string, 0)
+	for _, segment := range strings.Split(relativePath, "/") {
+		if segment == "" || segment == "index" || segment == "route" || strings.HasPrefix(segment, "@") ||
+			strings.HasPrefix(segment, "(") && strings.HasSuffix(segment, ")") {
+			continue
+		}
+		segments = append(segments, segment)
+	}
+
+	if path.Base(routeDirectory) == "api" {
+		segments = append([]string{"api"}, segments...)
+	}
+	return "/" + strings.Join(segments, "/")
+}
+
+type vercelSegmentConfig struct {
+	IsEdge         bool
+	Runtime        string
+	TimeoutSeconds int
+	Regions        []string
+}
+
+// NOTE: route segment config of the App Router, e.g. `export const maxDuration = 30` or
+// `export const preferredRegion = ["iad1"]`, and `export const config = { ... }` of the Pages Router
+func vercelModuleSegmentConfig(module *JsModule) vercelSegmentConfig {
+	segmentConfig := vercelSegmentConfig{TimeoutSeconds: -1}
+
+	var config JsValue
+	if export := module.Export("config"); export != nil {
+		config = module.ResolveValue(export.Value)
+	}
+	exportedValue := func(name string, configName string) *JsValue {
+		if export := module.Export(name); export != nil {
+			value := module.ResolveValue(export.Value)
+			return &value
+		}
+		if property := config.Property(configName); property != nil {
+			value := module.ResolveValue(*property)
+			return &value
+		}
+		return nil
+	}
+
+	if runtime := exportedValue("runtime", "runtime"); runtime != nil {
+		segmentConfig.IsEdge = runtime.Kind == JsValueString && (runtime.Text == "edge" || runtime.Text == "experimental-edge")
+		if runtime.Kind == JsValueString {
+			segmentConfig.Runtime = runtime.Text
+		}
+	}
+	if maxDuration := exportedValue("maxDuration", "maxDuration"); maxDuration != nil && maxDuration.Kind == JsValueNumber {
+		if maxDurationInt, err := strconv.Atoi(maxDuration.Text); err == nil {
+			segmentConfig.TimeoutSeconds = maxDurationInt
+		}
+	}
+	// NOTE: "auto", "global" and "home" are no regions but strategies of Vercel
+	if regions := exportedValue("preferredRegion", "regions"); regions != nil {
+		for _, region := range jsStrings(*regions) {
+			if !slices.Contains([]string{"auto", "global", "home", "all"}, region) {
+				segmentConfig.Regions = append(segmentConfig.Regions, region)
+			}
+		}
+	}
+
+	return segmentConfig
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"maps"
+	"slices"
+	"testing"
+)
+
+func TestScanVercelDetectsRoutes(t *testing.T) {
+	findings := scanTestRepository(t, "vercel", map[string]string{
+		"web/vercel.json": `{
+  "regions": ["fra1"],
+  "functions": {
+    "api/**/*.ts": { "memory": 1024, "maxDuration": 30 }
+  },
+  "crons": [{ "path": "/api/cron?source=vercel", "schedule": "0 5 * * *" }]
+}`,
+		"web/api/hello.ts": `import type { VercelRequest, VercelResponse } from "@vercel/node";
+
+export default function handler(request: VercelRequest, response: VercelResponse) {
+  response.send("hello");
+}
+`,
+		"web/api/cron.ts": `export default async function handler() {}`,
+		"web/app/(shop)/products/[id]/route.ts": `export const runtime = "edge";
+export const preferredRegion = ["iad1", "auto"];
+
+export async function GET(request: Request) {
+  return Response.json({});
+}
+`,
+		"web/app/page.tsx":  `export default function Page() { return null; }`,
+		"web/middleware.ts": "// NOTE: runs in front of all routes\nexport function middleware(request) {}\n",
+		"web/lib/util.ts":   `export default function util() {}`,
+	})
+
+	if !findings.UsedFrameworks[FaaSFrameworkVercel] || !findings.UsedPlatforms[FaaSPlatformVercel] {
+		t.Errorf("expected Vercel to be used")
+	}
+
+	tests := []struct {
+		name           string
+		invocationType FaaSInvocationType
+		location       FaaSLocation
+		sourceFilePath string
+		sourceFileLine int
+		memoryMB       int
+		timeoutSeconds int
+		regions        []string
+		attributes     map[string]string
+	}{
+		{"/api/hello", FaaSInvocationTypeHTTP, FaaSLocationRegion, "web/api/hello.ts", 3, 1024, 30, []string{"fra1"}, nil},
+		{"/api/cron", FaaSInvocationTypeSchedule, FaaSLocationRegion, "web/api/cron.ts", 1, 1024, 30, []string{"fra1"}, map[string]string{"schedule": "0 5 * * *"}},
+		{"/products/[id]", FaaSInvocationTypeHTTP, FaaSLocationEdge, "web/app/(shop)/products/[id]/route.ts", 4, -1, -1, []string{"iad1"}, nil},
+		{"middleware", FaaSInvocationTypeHTTP, FaaSLocationEdge, "web/middleware.ts", 2, -1, -1, nil, nil},
+	}
+
+	functions := testFunctionsByName(t, findings)
+	if len(functions) != len(tests) {
+		t.Errorf("expected %d functions, got %d", len(tests), len(functions))
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			function, ok := functions[test.name]
+			if !ok {
+				t.Fatalf("function %s was not found", test.name)
+			}
+			if function.InvocationType != test.invocationType || function.Location != test.location {
+				t.Errorf("expected %s in %s, got %s in %s", test.invocationType, test.location, function.InvocationType, function.Location)
+			}
+			if function.SourceFilePath != testRepositoryDirectory+"/"+test.sourceFilePath || function.SourceFileLine != test.sourceFileLine {
+				t.Errorf("expected %s:%d, got %s:%d", test.sourceFilePath, test.sourceFileLine, function.SourceFilePath, function.SourceFileLine)
+			}
+			if function.MemoryMB != test.memoryMB || function.TimeoutSeconds != test.timeoutSeconds {
+				t.Errorf("expected %d MB and %d s, got %d MB and %d s", test.memoryMB, test.timeoutSeconds, function.MemoryMB, function.TimeoutSeconds)
+			}
+			if !slices.Equal(function.Regions, test.regions) {
+				t.Errorf("expected regions %v, got %v", test.regions, function.Regions)
+			}
+			if !maps.Equal(function.Attributes, test.attributes) {
+				t.Errorf("expected attributes %v, got %v", test.attributes, function.Attributes)
+			}
+		})
+	}
+}
+
+func TestScanVercelRequiresVercelJson(t *testing.T) {
+	findings := scanTestRepository(t, "vercel", map[string]string{
+		"api/hello.ts": `export default function handler(request, response) {}`,
+	}, "@vercel/analytics", "@vercel/og")
+
+	if findings.UsedFrameworks[FaaSFrameworkVercel] || findings.UsedPlatforms[FaaSPlatformVercel] {
+		t.Errorf("expected Vercel packages not to mark Vercel as used")
+	}
+	if len(findings.Functions) != 0 {
+		t.Errorf("expected no functions, got %+v", findings.Functions)
+	}
+}